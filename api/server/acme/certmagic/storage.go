@@ -88,7 +88,7 @@ func (s *storage) Exists(key string) bool {
 }
 
 func (s *storage) List(prefix string, recursive bool) ([]string, error) {
-	records, err := s.store.List()
+	records, err := s.store.List(store.ListPrefix(prefix), store.ListKeysOnly())
 	if err != nil {
 		return nil, err
 	}
@@ -96,9 +96,7 @@ func (s *storage) List(prefix string, recursive bool) ([]string, error) {
 	//nolint:prealloc
 	var results []string
 	for _, r := range records {
-		if strings.HasPrefix(r.Key, prefix) {
-			results = append(results, r.Key)
-		}
+		results = append(results, r.Key)
 	}
 	if recursive {
 		return results, nil
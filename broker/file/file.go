@@ -0,0 +1,560 @@
+// Package file provides a broker persisting messages to a segment log on disk.
+// Queue subscribers commit their offsets so that a consumer group resumes where
+// it left off after a restart, giving at-least-once delivery on a single node.
+// A queue is consumed by one process, its offsets are locked by the first
+// process subscribing and the others fail to subscribe until it unsubscribes.
+package file
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stack-labs/stack/broker"
+	"github.com/stack-labs/stack/codec/json"
+	"github.com/stack-labs/stack/sync/event"
+	eventfile "github.com/stack-labs/stack/sync/event/file"
+	"github.com/stack-labs/stack/util/file"
+	"github.com/stack-labs/stack/util/log"
+)
+
+var (
+	// DefaultDir is the directory messages are kept in when no address is set
+	DefaultDir = filepath.Join(os.TempDir(), "stack", "broker")
+	// DefaultPollInterval is how often subscribers check for records from other processes
+	DefaultPollInterval = time.Second
+	// DefaultRetryInterval is the wait before redelivering a failed message
+	DefaultRetryInterval = time.Second
+	// DefaultMaxAttempts is how many times a failed message is delivered before it is dropped
+	DefaultMaxAttempts = 10
+
+	errNoSubscribers = errors.New("no subscribers")
+)
+
+type fileBroker struct {
+	opts broker.Options
+
+	sync.RWMutex
+	connected bool
+	dir       string
+	events    event.Event
+	// logs are the handles messages are published with
+	logs map[string]event.Log
+	// groups of subscribers by topic
+	groups map[string][]*group
+}
+
+// group reads a topic and delivers each message to one of its subscribers,
+// either the members of a queue or a single broadcast subscriber
+type group struct {
+	broker *fileBroker
+	topic  string
+	queue  string
+	log    eventfile.Log
+	// lock on the offsets of a queue held while it is consumed
+	lock   *os.File
+	notify chan bool
+	exit   chan bool
+
+	sync.Mutex
+	subs      []*fileSubscriber
+	next      int
+	committed int64
+}
+
+type fileSubscriber struct {
+	id      string
+	topic   string
+	group   *group
+	handler broker.Handler
+	opts    broker.SubscribeOptions
+}
+
+type fileEvent struct {
+	topic   string
+	message *broker.Message
+	ack     func() error
+}
+
+func (f *fileBroker) Init(opts ...broker.Option) error {
+	for _, o := range opts {
+		o(&f.opts)
+	}
+	return nil
+}
+
+func (f *fileBroker) Options() broker.Options {
+	return f.opts
+}
+
+func (f *fileBroker) Address() string {
+	f.RLock()
+	defer f.RUnlock()
+	return f.dir
+}
+
+// Connected reports whether the broker is connected
+func (f *fileBroker) Connected() bool {
+	f.RLock()
+	defer f.RUnlock()
+	return f.connected
+}
+
+func (f *fileBroker) Connect() error {
+	f.Lock()
+	defer f.Unlock()
+
+	if f.connected {
+		return nil
+	}
+
+	f.dir = DefaultDir
+	if len(f.opts.Addrs) > 0 && len(f.opts.Addrs[0]) > 0 {
+		f.dir = f.opts.Addrs[0]
+	}
+
+	if err := os.MkdirAll(f.offsetsDir(), 0755); err != nil {
+		return err
+	}
+
+	opts := []eventfile.Option{eventfile.Dir(filepath.Join(f.dir, "topics"))}
+	if f.opts.Context != nil {
+		if s, ok := f.opts.Context.Value(segmentSizeKey{}).(int64); ok && s > 0 {
+			opts = append(opts, eventfile.SegmentSize(s))
+		}
+	}
+
+	f.events = eventfile.NewEvent(opts...)
+	f.logs = make(map[string]event.Log)
+	f.groups = make(map[string][]*group)
+	f.connected = true
+
+	return nil
+}
+
+func (f *fileBroker) Disconnect() error {
+	f.Lock()
+	defer f.Unlock()
+
+	if !f.connected {
+		return nil
+	}
+
+	for _, groups := range f.groups {
+		for _, g := range groups {
+			g.stop()
+		}
+	}
+	for _, l := range f.logs {
+		l.Close()
+	}
+
+	f.groups = nil
+	f.logs = nil
+	f.connected = false
+
+	return nil
+}
+
+func (f *fileBroker) Publish(topic string, m *broker.Message, opts ...broker.PublishOption) error {
+	b, err := f.opts.Codec.Marshal(m)
+	if err != nil {
+		return err
+	}
+
+	f.Lock()
+	if !f.connected {
+		f.Unlock()
+		return errors.New("not connected")
+	}
+
+	l, ok := f.logs[topic]
+	if !ok {
+		l, err = f.events.Log(topic)
+		if err != nil {
+			f.Unlock()
+			return err
+		}
+		f.logs[topic] = l
+	}
+	groups := f.groups[topic]
+	f.Unlock()
+
+	if err := l.Write(&event.Record{Data: b}); err != nil {
+		return err
+	}
+
+	for _, g := range groups {
+		select {
+		case g.notify <- true:
+		default:
+		}
+	}
+
+	return nil
+}
+
+func (f *fileBroker) Subscribe(topic string, h broker.Handler, opts ...broker.SubscribeOption) (broker.Subscriber, error) {
+	options := broker.NewSubscribeOptions(opts...)
+
+	f.Lock()
+	defer f.Unlock()
+
+	if !f.connected {
+		return nil, errors.New("not connected")
+	}
+
+	sub := &fileSubscriber{
+		id:      uuid.New().String(),
+		topic:   topic,
+		handler: broker.NewRetryHandler(f, topic, h, options),
+		opts:    options,
+	}
+
+	// queue subscribers join the existing group of the queue
+	if len(options.Queue) > 0 {
+		for _, g := range f.groups[topic] {
+			if g.queue == options.Queue {
+				g.Lock()
+				sub.group = g
+				g.subs = append(g.subs, sub)
+				g.Unlock()
+				return sub, nil
+			}
+		}
+	}
+
+	g, err := f.newGroup(topic, options.Queue)
+	if err != nil {
+		return nil, err
+	}
+	sub.group = g
+	g.subs = append(g.subs, sub)
+	f.groups[topic] = append(f.groups[topic], g)
+
+	go g.run()
+
+	return sub, nil
+}
+
+func (f *fileBroker) String() string {
+	return "file"
+}
+
+func (f *fileBroker) newGroup(topic, queue string) (*group, error) {
+	l, err := f.events.Log(topic)
+	if err != nil {
+		return nil, err
+	}
+
+	g := &group{
+		broker: f,
+		topic:  topic,
+		queue:  queue,
+		log:    l.(eventfile.Log),
+		notify: make(chan bool, 1),
+		exit:   make(chan bool),
+	}
+
+	// new subscriptions start at the end of the log while
+	// a queue resumes from the offset it last committed
+	offset := g.log.Size()
+	if len(queue) > 0 {
+		lock, err := f.lockOffset(topic, queue)
+		if err != nil {
+			l.Close()
+			return nil, err
+		}
+		g.lock = lock
+
+		if o, err := f.readOffset(topic, queue); err == nil && o <= offset {
+			offset = o
+		}
+	}
+	g.committed = offset
+
+	if err := g.log.Seek(offset); err != nil {
+		g.unlock()
+		l.Close()
+		return nil, err
+	}
+
+	return g, nil
+}
+
+func (f *fileBroker) unsubscribe(s *fileSubscriber) error {
+	f.Lock()
+	defer f.Unlock()
+
+	g := s.group
+	g.Lock()
+	var subs []*fileSubscriber
+	for _, sub := range g.subs {
+		if sub.id != s.id {
+			subs = append(subs, sub)
+		}
+	}
+	g.subs = subs
+	g.Unlock()
+
+	if len(subs) > 0 {
+		return nil
+	}
+
+	if f.groups != nil {
+		var groups []*group
+		for _, gr := range f.groups[s.topic] {
+			if gr != g {
+				groups = append(groups, gr)
+			}
+		}
+		f.groups[s.topic] = groups
+	}
+
+	return g.stop()
+}
+
+func (f *fileBroker) offsetsDir() string {
+	return filepath.Join(f.dir, "offsets")
+}
+
+func (f *fileBroker) offsetPath(topic, queue string) string {
+	return filepath.Join(f.offsetsDir(), url.PathEscape(topic), url.PathEscape(queue))
+}
+
+// lockOffset locks the offsets of a queue so it is consumed by one process
+func (f *fileBroker) lockOffset(topic, queue string) (*os.File, error) {
+	path := f.offsetPath(topic, queue)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, err
+	}
+
+	lock, err := os.OpenFile(path+".lock", os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return nil, err
+	}
+	if err := file.TryLock(lock); err != nil {
+		lock.Close()
+		return nil, fmt.Errorf("queue %s of topic %s: %v", queue, topic, err)
+	}
+	return lock, nil
+}
+
+func (f *fileBroker) readOffset(topic, queue string) (int64, error) {
+	b, err := ioutil.ReadFile(f.offsetPath(topic, queue))
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseInt(strings.TrimSpace(string(b)), 10, 64)
+}
+
+// writeOffset replaces the committed offset atomically so a crash leaves either the old or new one
+func (f *fileBroker) writeOffset(topic, queue string, offset int64) error {
+	path := f.offsetPath(topic, queue)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	tmp := path + ".tmp"
+	if err := ioutil.WriteFile(tmp, []byte(strconv.FormatInt(offset, 10)), 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+func (g *group) run() {
+	poll := DefaultPollInterval
+	retry := DefaultRetryInterval
+	attempts := DefaultMaxAttempts
+	if ctx := g.broker.opts.Context; ctx != nil {
+		if d, ok := ctx.Value(pollIntervalKey{}).(time.Duration); ok && d > 0 {
+			poll = d
+		}
+		if d, ok := ctx.Value(retryIntervalKey{}).(time.Duration); ok && d > 0 {
+			retry = d
+		}
+		if n, ok := ctx.Value(maxAttemptsKey{}).(int); ok && n > 0 {
+			attempts = n
+		}
+	}
+
+	for {
+		select {
+		case <-g.exit:
+			return
+		default:
+		}
+
+		offset := g.log.Offset()
+		rec, err := g.log.Read()
+		if err == io.EOF {
+			select {
+			case <-g.exit:
+				return
+			case <-g.notify:
+			case <-time.After(poll):
+			}
+			continue
+		}
+		if err != nil {
+			log.Errorf("Error reading topic %s at offset %d: %v", g.topic, offset, err)
+			if !g.wait(retry) {
+				return
+			}
+			continue
+		}
+
+		m := new(broker.Message)
+		if err := g.broker.opts.Codec.Unmarshal(rec.Data, m); err != nil {
+			log.Errorf("Error decoding message on topic %s at offset %d: %v", g.topic, offset, err)
+			g.commit(offset + 1)
+			continue
+		}
+
+		// redeliver until handled or the attempts are exhausted, the subscriber
+		// dead letters the message with its own retry policy first. A message
+		// waits for a subscriber without counting attempts
+		for i := 1; ; {
+			err := g.deliver(offset, m)
+			if err == nil {
+				break
+			}
+			if err != errNoSubscribers {
+				log.Errorf("Error handling message on topic %s at offset %d: %v", g.topic, offset, err)
+				if i >= attempts {
+					log.Errorf("Dropping message on topic %s at offset %d after %d attempts", g.topic, offset, i)
+					g.commit(offset + 1)
+					break
+				}
+				i++
+			}
+			if !g.wait(retry) {
+				return
+			}
+		}
+	}
+}
+
+func (g *group) deliver(offset int64, m *broker.Message) error {
+	g.Lock()
+	if len(g.subs) == 0 {
+		g.Unlock()
+		return errNoSubscribers
+	}
+	sub := g.subs[g.next%len(g.subs)]
+	g.next++
+	g.Unlock()
+
+	ev := &fileEvent{
+		topic:   g.topic,
+		message: m,
+		ack: func() error {
+			return g.commit(offset + 1)
+		},
+	}
+
+	if err := sub.handler(ev); err != nil {
+		return err
+	}
+
+	if sub.opts.AutoAck {
+		if err := g.commit(offset + 1); err != nil {
+			log.Errorf("Error committing offset %d of topic %s: %v", offset+1, g.topic, err)
+		}
+	}
+
+	return nil
+}
+
+// commit records the offset to resume from, only queues persist theirs
+func (g *group) commit(offset int64) error {
+	g.Lock()
+	defer g.Unlock()
+
+	if offset <= g.committed {
+		return nil
+	}
+	g.committed = offset
+
+	if len(g.queue) == 0 {
+		return nil
+	}
+	return g.broker.writeOffset(g.topic, g.queue, offset)
+}
+
+func (g *group) wait(d time.Duration) bool {
+	select {
+	case <-g.exit:
+		return false
+	case <-time.After(d):
+		return true
+	}
+}
+
+func (g *group) stop() error {
+	select {
+	case <-g.exit:
+		return nil
+	default:
+		close(g.exit)
+	}
+	g.unlock()
+	return g.log.Close()
+}
+
+// unlock releases the offsets of a queue to other processes
+func (g *group) unlock() {
+	if g.lock == nil {
+		return
+	}
+	file.Unlock(g.lock)
+	g.lock.Close()
+	g.lock = nil
+}
+
+func (f *fileEvent) Topic() string {
+	return f.topic
+}
+
+func (f *fileEvent) Message() *broker.Message {
+	return f.message
+}
+
+func (f *fileEvent) Ack() error {
+	return f.ack()
+}
+
+func (f *fileSubscriber) Options() broker.SubscribeOptions {
+	return f.opts
+}
+
+func (f *fileSubscriber) Topic() string {
+	return f.topic
+}
+
+func (f *fileSubscriber) Unsubscribe() error {
+	return f.group.broker.unsubscribe(f)
+}
+
+// NewBroker returns a broker persisting messages under the directory of its address
+func NewBroker(opts ...broker.Option) broker.Broker {
+	options := broker.Options{
+		Codec: json.Marshaler{},
+	}
+	for _, o := range opts {
+		o(&options)
+	}
+
+	return &fileBroker{
+		opts: options,
+	}
+}
@@ -0,0 +1,198 @@
+package file
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stack-labs/stack/broker"
+)
+
+func newTestBroker(t *testing.T, dir string) broker.Broker {
+	b := NewBroker(
+		broker.Addrs(dir),
+		PollInterval(10*time.Millisecond),
+		RetryInterval(10*time.Millisecond),
+	)
+	if err := b.Connect(); err != nil {
+		t.Fatalf("Unexpected connect error %v", err)
+	}
+	return b
+}
+
+func receive(t *testing.T, ch chan string, want ...string) {
+	for _, w := range want {
+		select {
+		case got := <-ch:
+			if got != w {
+				t.Fatalf("Expected message %s got %s", w, got)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("Timed out waiting for message %s", w)
+		}
+	}
+}
+
+func TestFileBrokerQueueResume(t *testing.T) {
+	dir, err := ioutil.TempDir("", "broker")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	ch := make(chan string, 10)
+	handler := func(e broker.Event) error {
+		ch <- string(e.Message().Body)
+		return nil
+	}
+
+	b := newTestBroker(t, dir)
+	if _, err := b.Subscribe("test", handler, broker.Queue("q")); err != nil {
+		t.Fatalf("Unexpected subscribe error %v", err)
+	}
+	if err := b.Publish("test", &broker.Message{Body: []byte("1")}); err != nil {
+		t.Fatalf("Unexpected publish error %v", err)
+	}
+	receive(t, ch, "1")
+	b.Disconnect()
+
+	// messages published while the queue is down are delivered on resubscribe
+	b = newTestBroker(t, dir)
+	defer b.Disconnect()
+	for _, body := range []string{"2", "3"} {
+		if err := b.Publish("test", &broker.Message{Body: []byte(body)}); err != nil {
+			t.Fatalf("Unexpected publish error %v", err)
+		}
+	}
+
+	sub, err := b.Subscribe("test", handler, broker.Queue("q"))
+	if err != nil {
+		t.Fatalf("Unexpected subscribe error %v", err)
+	}
+	receive(t, ch, "2", "3")
+
+	// a new broadcast subscriber only sees messages published after it subscribed
+	bch := make(chan string, 10)
+	if _, err := b.Subscribe("test", func(e broker.Event) error {
+		bch <- string(e.Message().Body)
+		return nil
+	}); err != nil {
+		t.Fatalf("Unexpected subscribe error %v", err)
+	}
+	if err := b.Publish("test", &broker.Message{Body: []byte("4")}); err != nil {
+		t.Fatalf("Unexpected publish error %v", err)
+	}
+	receive(t, ch, "4")
+	receive(t, bch, "4")
+
+	if err := sub.Unsubscribe(); err != nil {
+		t.Fatalf("Unexpected unsubscribe error %v", err)
+	}
+}
+
+func TestFileBrokerRedeliver(t *testing.T) {
+	dir, err := ioutil.TempDir("", "broker")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	b := newTestBroker(t, dir)
+	defer b.Disconnect()
+
+	ch := make(chan string, 10)
+	var attempts int
+	if _, err := b.Subscribe("test", func(e broker.Event) error {
+		attempts++
+		if attempts < 3 {
+			return errors.New("not yet")
+		}
+		ch <- string(e.Message().Body)
+		return nil
+	}, broker.Queue("q")); err != nil {
+		t.Fatalf("Unexpected subscribe error %v", err)
+	}
+
+	if err := b.Publish("test", &broker.Message{Body: []byte("hello")}); err != nil {
+		t.Fatalf("Unexpected publish error %v", err)
+	}
+	receive(t, ch, "hello")
+
+	if attempts != 3 {
+		t.Fatalf("Expected 3 attempts got %d", attempts)
+	}
+}
+
+func TestFileBrokerMaxAttempts(t *testing.T) {
+	dir, err := ioutil.TempDir("", "broker")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	b := newTestBroker(t, dir)
+	b.Init(MaxAttempts(2))
+	defer b.Disconnect()
+
+	ch := make(chan string, 10)
+	var attempts int
+	if _, err := b.Subscribe("test", func(e broker.Event) error {
+		if string(e.Message().Body) == "fail" {
+			attempts++
+			return errors.New("failed")
+		}
+		ch <- string(e.Message().Body)
+		return nil
+	}, broker.Queue("q")); err != nil {
+		t.Fatalf("Unexpected subscribe error %v", err)
+	}
+
+	for _, body := range []string{"fail", "hello"} {
+		if err := b.Publish("test", &broker.Message{Body: []byte(body)}); err != nil {
+			t.Fatalf("Unexpected publish error %v", err)
+		}
+	}
+	receive(t, ch, "hello")
+
+	if attempts != 2 {
+		t.Fatalf("Expected 2 attempts got %d", attempts)
+	}
+}
+
+func TestFileBrokerQueueLock(t *testing.T) {
+	dir, err := ioutil.TempDir("", "broker")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	b := newTestBroker(t, dir)
+	defer b.Disconnect()
+
+	other := newTestBroker(t, dir)
+	defer other.Disconnect()
+
+	h := func(e broker.Event) error { return nil }
+
+	sub, err := b.Subscribe("test", h, broker.Queue("q"))
+	if err != nil {
+		t.Fatalf("Unexpected subscribe error %v", err)
+	}
+
+	// the queue is consumed by one broker at a time
+	if _, err := other.Subscribe("test", h, broker.Queue("q")); err == nil {
+		t.Fatal("Expected the queue to be locked by the other broker")
+	}
+	if _, err := other.Subscribe("test", h); err != nil {
+		t.Fatalf("Unexpected broadcast subscribe error %v", err)
+	}
+
+	if err := sub.Unsubscribe(); err != nil {
+		t.Fatalf("Unexpected unsubscribe error %v", err)
+	}
+	if _, err := other.Subscribe("test", h, broker.Queue("q")); err != nil {
+		t.Fatalf("Expected the queue to be released: %v", err)
+	}
+}
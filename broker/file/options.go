@@ -0,0 +1,45 @@
+package file
+
+import (
+	"context"
+	"time"
+
+	"github.com/stack-labs/stack/broker"
+)
+
+type pollIntervalKey struct{}
+type retryIntervalKey struct{}
+type maxAttemptsKey struct{}
+type segmentSizeKey struct{}
+
+// PollInterval sets how often subscribers check the log for records written by
+// other processes, records published through the broker are delivered immediately
+func PollInterval(d time.Duration) broker.Option {
+	return setBrokerOption(pollIntervalKey{}, d)
+}
+
+// RetryInterval sets the wait before a message the handler failed is redelivered
+func RetryInterval(d time.Duration) broker.Option {
+	return setBrokerOption(retryIntervalKey{}, d)
+}
+
+// MaxAttempts sets how many times a message the handler failed is delivered
+// before it is dropped, a subscriber with a dead letter topic publishes it
+// there once its own retries are exhausted
+func MaxAttempts(n int) broker.Option {
+	return setBrokerOption(maxAttemptsKey{}, n)
+}
+
+// SegmentSize sets the size in bytes at which the segments of a topic are rolled
+func SegmentSize(s int64) broker.Option {
+	return setBrokerOption(segmentSizeKey{}, s)
+}
+
+func setBrokerOption(k, v interface{}) broker.Option {
+	return func(o *broker.Options) {
+		if o.Context == nil {
+			o.Context = context.Background()
+		}
+		o.Context = context.WithValue(o.Context, k, v)
+	}
+}
@@ -351,6 +351,13 @@ func (h *httpBroker) Address() string {
 	return h.address
 }
 
+// Connected reports whether the broker is connected
+func (h *httpBroker) Connected() bool {
+	h.RLock()
+	defer h.RUnlock()
+	return h.running
+}
+
 func (h *httpBroker) Connect() error {
 	h.RLock()
 	if h.running {
@@ -676,7 +683,7 @@ func (h *httpBroker) Subscribe(topic string, handler broker.Handler, opts ...bro
 		hb:    h,
 		id:    node.Id,
 		topic: topic,
-		fn:    handler,
+		fn:    broker.NewRetryHandler(h, topic, handler, options),
 		svc:   service,
 	}
 
@@ -43,6 +43,13 @@ func (m *memoryBroker) Address() string {
 	return m.addr
 }
 
+// Connected reports whether the broker is connected
+func (m *memoryBroker) Connected() bool {
+	m.RLock()
+	defer m.RUnlock()
+	return m.connected
+}
+
 func (m *memoryBroker) Connect() error {
 	m.Lock()
 	defer m.Unlock()
@@ -129,7 +136,7 @@ func (m *memoryBroker) Subscribe(topic string, handler broker.Handler, opts ...b
 		exit:    make(chan bool, 1),
 		id:      uuid.New().String(),
 		topic:   topic,
-		handler: handler,
+		handler: broker.NewRetryHandler(m, topic, handler, options),
 		opts:    options,
 	}
 
@@ -3,6 +3,7 @@ package memory
 import (
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stack-labs/stack/broker"
 )
@@ -48,3 +49,108 @@ func TestMemoryBroker(t *testing.T) {
 		t.Fatalf("Unexpected connect error %v", err)
 	}
 }
+
+func TestMemoryBrokerRetry(t *testing.T) {
+	b := NewBroker()
+
+	if err := b.Connect(); err != nil {
+		t.Fatalf("Unexpected connect error %v", err)
+	}
+
+	var attempts int
+	fn := func(p broker.Event) error {
+		attempts++
+		if attempts < 3 {
+			return fmt.Errorf("attempt %d failed", attempts)
+		}
+		return nil
+	}
+
+	sub, err := b.Subscribe("test", fn, broker.Retry(3, time.Millisecond, time.Millisecond))
+	if err != nil {
+		t.Fatalf("Unexpected error subscribing %v", err)
+	}
+	defer sub.Unsubscribe()
+
+	if err := b.Publish("test", &broker.Message{Body: []byte("hello")}); err != nil {
+		t.Fatalf("Unexpected error publishing %v", err)
+	}
+
+	if attempts != 3 {
+		t.Fatalf("Expected 3 attempts got %d", attempts)
+	}
+}
+
+func TestMemoryBrokerDeadLetter(t *testing.T) {
+	b := NewBroker()
+
+	if err := b.Connect(); err != nil {
+		t.Fatalf("Unexpected connect error %v", err)
+	}
+
+	var attempts int
+	fn := func(p broker.Event) error {
+		attempts++
+		return fmt.Errorf("boom")
+	}
+
+	sub, err := b.Subscribe("test", fn,
+		broker.Queue("q"),
+		broker.Retry(2, time.Millisecond, time.Millisecond),
+		broker.DeadLetter("test.dlq"),
+	)
+	if err != nil {
+		t.Fatalf("Unexpected error subscribing %v", err)
+	}
+	defer sub.Unsubscribe()
+
+	var dead []*broker.Message
+	dsub, err := b.Subscribe("test.dlq", func(p broker.Event) error {
+		dead = append(dead, p.Message())
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error subscribing %v", err)
+	}
+	defer dsub.Unsubscribe()
+
+	msg := &broker.Message{
+		Header: map[string]string{"Stack-Topic": "test", "foo": "bar"},
+		Body:   []byte("hello"),
+	}
+	if err := b.Publish("test", msg); err != nil {
+		t.Fatalf("Expected the dead letter to ack the message got %v", err)
+	}
+
+	if attempts != 2 {
+		t.Fatalf("Expected 2 attempts got %d", attempts)
+	}
+	if len(dead) != 1 {
+		t.Fatalf("Expected 1 dead letter got %d", len(dead))
+	}
+
+	hdr := dead[0].Header
+	for k, v := range map[string]string{
+		broker.DeadLetterTopicHeader:    "test",
+		broker.DeadLetterQueueHeader:    "q",
+		broker.DeadLetterErrorHeader:    "boom",
+		broker.DeadLetterAttemptsHeader: "2",
+		"Stack-Topic":                   "test.dlq",
+		"foo":                           "bar",
+	} {
+		if hdr[k] != v {
+			t.Fatalf("Expected header %s=%s got %s", k, v, hdr[k])
+		}
+	}
+
+	topic, replay := broker.NewReplayMessage(dead[0])
+	if topic != "test" {
+		t.Fatalf("Expected replay topic test got %s", topic)
+	}
+	if _, ok := replay.Header[broker.DeadLetterErrorHeader]; ok {
+		t.Fatal("Expected dead letter headers to be stripped")
+	}
+	if replay.Header["Stack-Topic"] != "test" || replay.Header["foo"] != "bar" {
+		t.Fatalf("Unexpected replay headers %v", replay.Header)
+	}
+}
@@ -3,6 +3,7 @@ package broker
 import (
 	"context"
 	"crypto/tls"
+	"time"
 
 	"github.com/stack-labs/stack/codec"
 	"github.com/stack-labs/stack/registry"
@@ -35,6 +36,11 @@ type SubscribeOptions struct {
 	// will create a shared subscription where each
 	// receives a subset of messages.
 	Queue string
+	// Retry is the policy applied when the handler returns an error
+	Retry RetryPolicy
+	// DeadLetter is the topic that receives messages
+	// the handler failed to process after all retries
+	DeadLetter string
 
 	// Other options for implementations of the interface
 	// can be stored in a context
@@ -121,3 +127,24 @@ func SubscribeContext(ctx context.Context) SubscribeOption {
 		o.Context = ctx
 	}
 }
+
+// Retry sets the retry policy of the subscriber. A message whose handler
+// returns an error is redelivered up to maxAttempts times in total, waiting
+// backoff before the first retry and doubling it up to maxBackoff.
+func Retry(maxAttempts int, backoff, maxBackoff time.Duration) SubscribeOption {
+	return func(o *SubscribeOptions) {
+		o.Retry = RetryPolicy{
+			MaxAttempts: maxAttempts,
+			Backoff:     backoff,
+			MaxBackoff:  maxBackoff,
+		}
+	}
+}
+
+// DeadLetter sets the topic messages are published to once
+// the retry policy of the subscriber has been exhausted
+func DeadLetter(topic string) SubscribeOption {
+	return func(o *SubscribeOptions) {
+		o.DeadLetter = topic
+	}
+}
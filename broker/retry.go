@@ -0,0 +1,151 @@
+package broker
+
+import (
+	"strconv"
+	"time"
+)
+
+const (
+	// DeadLetterTopicHeader holds the topic the message was originally published to
+	DeadLetterTopicHeader = "Stack-Dlq-Topic"
+	// DeadLetterQueueHeader holds the queue of the subscriber that failed the message
+	DeadLetterQueueHeader = "Stack-Dlq-Queue"
+	// DeadLetterErrorHeader holds the last error returned by the handler
+	DeadLetterErrorHeader = "Stack-Dlq-Error"
+	// DeadLetterAttemptsHeader holds the number of delivery attempts made
+	DeadLetterAttemptsHeader = "Stack-Dlq-Attempts"
+	// DeadLetterTimeHeader holds the time the message was dead lettered in RFC3339
+	DeadLetterTimeHeader = "Stack-Dlq-Time"
+
+	// topicHeader is the header servers route subscriber messages by
+	topicHeader = "Stack-Topic"
+)
+
+var (
+	// DefaultRetryBackoff is used when a retry policy does not set a backoff
+	DefaultRetryBackoff = 100 * time.Millisecond
+	// DefaultRetryMaxBackoff is used when a retry policy does not set a max backoff
+	DefaultRetryMaxBackoff = 10 * time.Second
+)
+
+// RetryPolicy describes how often and how fast a failed message is redelivered
+type RetryPolicy struct {
+	// MaxAttempts is the total number of deliveries, zero or one disables retries
+	MaxAttempts int
+	// Backoff is the wait before the first retry, doubled on each following one
+	Backoff time.Duration
+	// MaxBackoff caps the wait between retries
+	MaxBackoff time.Duration
+}
+
+// Delay returns the wait before the given retry, starting from 1
+func (r RetryPolicy) Delay(retry int) time.Duration {
+	backoff := r.Backoff
+	if backoff <= 0 {
+		backoff = DefaultRetryBackoff
+	}
+	max := r.MaxBackoff
+	if max <= 0 {
+		max = DefaultRetryMaxBackoff
+	}
+
+	d := backoff
+	for i := 1; i < retry && d < max; i++ {
+		d *= 2
+	}
+	if d > max {
+		d = max
+	}
+	return d
+}
+
+// NewRetryHandler wraps the handler with the retry policy and dead letter topic of
+// the subscribe options. Brokers call it from Subscribe so that every implementation
+// redelivers and dead letters messages in the same way. Once the attempts are exhausted
+// the message is published to the dead letter topic and acknowledged; without a dead
+// letter topic the last error is returned to the broker.
+func NewRetryHandler(b Broker, topic string, h Handler, opts SubscribeOptions) Handler {
+	if opts.Retry.MaxAttempts <= 1 && len(opts.DeadLetter) == 0 {
+		return h
+	}
+
+	return func(e Event) error {
+		attempts := opts.Retry.MaxAttempts
+		if attempts < 1 {
+			attempts = 1
+		}
+
+		var err error
+		for i := 1; i <= attempts; i++ {
+			if i > 1 {
+				time.Sleep(opts.Retry.Delay(i - 1))
+			}
+			if err = h(e); err == nil {
+				return nil
+			}
+		}
+
+		if len(opts.DeadLetter) == 0 {
+			return err
+		}
+
+		return b.Publish(opts.DeadLetter, deadLetter(e, topic, opts.DeadLetter, opts.Queue, attempts, err))
+	}
+}
+
+func deadLetter(e Event, topic, dlq, queue string, attempts int, err error) *Message {
+	msg := e.Message()
+
+	header := make(map[string]string, len(msg.Header)+5)
+	for k, v := range msg.Header {
+		header[k] = v
+	}
+
+	// servers route messages by their topic header so
+	// point it at the dead letter topic for its subscribers
+	if v, ok := header[topicHeader]; ok && len(v) > 0 {
+		topic = v
+		header[topicHeader] = dlq
+	}
+
+	header[DeadLetterTopicHeader] = topic
+	header[DeadLetterErrorHeader] = err.Error()
+	header[DeadLetterAttemptsHeader] = strconv.Itoa(attempts)
+	header[DeadLetterTimeHeader] = time.Now().UTC().Format(time.RFC3339)
+	if len(queue) > 0 {
+		header[DeadLetterQueueHeader] = queue
+	}
+
+	return &Message{
+		Header: header,
+		Body:   msg.Body,
+	}
+}
+
+// NewReplayMessage restores a dead lettered message to the form it was originally
+// published in and returns the topic it should be republished to
+func NewReplayMessage(msg *Message) (string, *Message) {
+	header := make(map[string]string, len(msg.Header))
+	for k, v := range msg.Header {
+		header[k] = v
+	}
+
+	topic := header[DeadLetterTopicHeader]
+	if _, ok := header[topicHeader]; ok {
+		header[topicHeader] = topic
+	}
+	for _, k := range []string{
+		DeadLetterTopicHeader,
+		DeadLetterQueueHeader,
+		DeadLetterErrorHeader,
+		DeadLetterAttemptsHeader,
+		DeadLetterTimeHeader,
+	} {
+		delete(header, k)
+	}
+
+	return topic, &Message{
+		Header: header,
+		Body:   msg.Body,
+	}
+}
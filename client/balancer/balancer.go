@@ -0,0 +1,123 @@
+// Package balancer provides client wrappers routing the calls with the
+// least request and consistent hash selector strategies
+package balancer
+
+import (
+	"context"
+
+	"github.com/stack-labs/stack/client"
+	"github.com/stack-labs/stack/client/selector"
+	"github.com/stack-labs/stack/pkg/metadata"
+	"github.com/stack-labs/stack/registry"
+)
+
+type hashKey struct{}
+
+// NewContext sets the consistent hash key of the calls made with the context
+func NewContext(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, hashKey{}, key)
+}
+
+// FromContext returns the consistent hash key set with NewContext
+func FromContext(ctx context.Context) (string, bool) {
+	key, ok := ctx.Value(hashKey{}).(string)
+	return key, ok
+}
+
+type leastRequestClient struct {
+	client.Client
+	load *selector.Load
+}
+
+// track records the calls in progress to the node
+func (c *leastRequestClient) track(cf client.CallFunc) client.CallFunc {
+	return func(ctx context.Context, node *registry.Node, req client.Request, rsp interface{}, opts client.CallOptions) error {
+		done := c.load.Start(node)
+		defer done()
+		return cf(ctx, node, req, rsp, opts)
+	}
+}
+
+func (c *leastRequestClient) Call(ctx context.Context, req client.Request, rsp interface{}, opts ...client.CallOption) error {
+	opts = append(opts,
+		client.WithSelectOption(selector.WithStrategy(selector.LeastRequest(c.load))),
+		client.WithCallWrapper(c.track),
+	)
+	return c.Client.Call(ctx, req, rsp, opts...)
+}
+
+func (c *leastRequestClient) Stream(ctx context.Context, req client.Request, opts ...client.CallOption) (client.Stream, error) {
+	// streams are not tracked, they are only routed by the load of the calls
+	opts = append(opts, client.WithSelectOption(selector.WithStrategy(selector.LeastRequest(c.load))))
+	return c.Client.Stream(ctx, req, opts...)
+}
+
+type hashClient struct {
+	client.Client
+	key string
+}
+
+// hashKey returns the key of the call from the context or the metadata
+func (c *hashClient) hashKey(ctx context.Context) string {
+	if key, ok := FromContext(ctx); ok {
+		return key
+	}
+	if len(c.key) > 0 {
+		if key, ok := metadata.Get(ctx, c.key); ok {
+			return key
+		}
+	}
+	return ""
+}
+
+func (c *hashClient) Call(ctx context.Context, req client.Request, rsp interface{}, opts ...client.CallOption) error {
+	if key := c.hashKey(ctx); len(key) > 0 {
+		opts = append(opts, client.WithSelectOption(selector.WithStrategy(selector.ConsistentHash(key))))
+	}
+	return c.Client.Call(ctx, req, rsp, opts...)
+}
+
+func (c *hashClient) Stream(ctx context.Context, req client.Request, opts ...client.CallOption) (client.Stream, error) {
+	if key := c.hashKey(ctx); len(key) > 0 {
+		opts = append(opts, client.WithSelectOption(selector.WithStrategy(selector.ConsistentHash(key))))
+	}
+	return c.Client.Stream(ctx, req, opts...)
+}
+
+func newOptions(opts ...Option) Options {
+	options := Options{
+		Load: selector.DefaultLoad,
+	}
+	for _, o := range opts {
+		o(&options)
+	}
+	return options
+}
+
+// NewLeastRequestWrapper returns a client.Wrapper which routes every call
+// to the node with the fewest calls in progress
+func NewLeastRequestWrapper(opts ...Option) client.Wrapper {
+	options := newOptions(opts...)
+
+	return func(c client.Client) client.Client {
+		return &leastRequestClient{
+			Client: c,
+			load:   options.Load,
+		}
+	}
+}
+
+// NewHashWrapper returns a client.Wrapper which routes the calls with the
+// same key to the same node. The key is read from the context, see
+// NewContext, or from the metadata key set with HashKey. Calls without
+// a key use the strategy of the selector.
+func NewHashWrapper(opts ...Option) client.Wrapper {
+	options := newOptions(opts...)
+
+	return func(c client.Client) client.Client {
+		return &hashClient{
+			Client: c,
+			key:    options.HashKey,
+		}
+	}
+}
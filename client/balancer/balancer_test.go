@@ -0,0 +1,127 @@
+package balancer
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stack-labs/stack/client"
+	"github.com/stack-labs/stack/client/selector"
+	"github.com/stack-labs/stack/pkg/metadata"
+	"github.com/stack-labs/stack/registry"
+)
+
+var testServices = []*registry.Service{
+	{
+		Name: "foo",
+		Nodes: []*registry.Node{
+			{Id: "foo-1", Address: "10.0.0.1:1001"},
+			{Id: "foo-2", Address: "10.0.0.2:1002"},
+			{Id: "foo-3", Address: "10.0.0.3:1003"},
+		},
+	},
+}
+
+// testClient selects a node and runs the call wrappers like the rpc client
+type testClient struct {
+	client.Client
+	opts  client.Options
+	nodes []string
+	// block is called with the node while the call is in progress
+	block func(node *registry.Node)
+}
+
+func (t *testClient) Call(ctx context.Context, req client.Request, rsp interface{}, opts ...client.CallOption) error {
+	callOpts := t.opts.CallOptions
+	for _, o := range opts {
+		o(&callOpts)
+	}
+
+	sopts := selector.SelectOptions{Strategy: selector.Random()}
+	for _, o := range callOpts.SelectOptions {
+		o(&sopts)
+	}
+
+	node, err := sopts.Strategy(testServices)
+	if err != nil {
+		return err
+	}
+
+	call := func(ctx context.Context, node *registry.Node, req client.Request, rsp interface{}, opts client.CallOptions) error {
+		t.nodes = append(t.nodes, node.Id)
+		if t.block != nil {
+			t.block(node)
+		}
+		return nil
+	}
+
+	for i := len(callOpts.Wrappers); i > 0; i-- {
+		call = callOpts.Wrappers[i-1](call)
+	}
+
+	return call(ctx, node, req, rsp, callOpts)
+}
+
+func TestHashWrapper(t *testing.T) {
+	tc := &testClient{opts: client.NewOptions()}
+	c := NewHashWrapper(HashKey("User-Id"))(tc)
+
+	for i := 0; i < 10; i++ {
+		key := fmt.Sprintf("user-%d", i)
+		tc.nodes = nil
+
+		for j := 0; j < 10; j++ {
+			ctx := metadata.Set(context.TODO(), "User-Id", key)
+			if err := c.Call(ctx, nil, nil); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		// the context key takes precedence
+		ctx := NewContext(metadata.Set(context.TODO(), "User-Id", "other"), key)
+		if err := c.Call(ctx, nil, nil); err != nil {
+			t.Fatal(err)
+		}
+
+		for _, id := range tc.nodes {
+			if id != tc.nodes[0] {
+				t.Fatalf("Expected the calls of %s to be routed to %s, got %v", key, tc.nodes[0], tc.nodes)
+			}
+		}
+	}
+}
+
+func TestLeastRequestWrapper(t *testing.T) {
+	load := selector.NewLoad()
+	tc := &testClient{opts: client.NewOptions()}
+	c := NewLeastRequestWrapper(WithLoad(load))(tc)
+
+	// nested calls are in progress while the next is selected
+	depth := 0
+	tc.block = func(node *registry.Node) {
+		if load.InFlight(node) != 1 {
+			t.Fatalf("Expected the call to %s to be tracked", node.Id)
+		}
+		if depth++; depth < 3 {
+			c.Call(context.TODO(), nil, nil)
+		}
+	}
+
+	if err := c.Call(context.TODO(), nil, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	seen := make(map[string]bool)
+	for _, id := range tc.nodes {
+		if seen[id] {
+			t.Fatalf("Expected the calls to be spread over the idle nodes, got %v", tc.nodes)
+		}
+		seen[id] = true
+	}
+
+	for _, node := range testServices[0].Nodes {
+		if n := load.InFlight(node); n != 0 {
+			t.Fatalf("Expected no calls in flight to %s, got %d", node.Id, n)
+		}
+	}
+}
@@ -0,0 +1,29 @@
+package balancer
+
+import (
+	"github.com/stack-labs/stack/client/selector"
+)
+
+type Options struct {
+	// Load tracks the calls of the least request strategy
+	Load *selector.Load
+	// HashKey is the metadata key of the consistent hash key of a call
+	HashKey string
+}
+
+type Option func(o *Options)
+
+// WithLoad sets the load tracking the calls, defaults to selector.DefaultLoad
+func WithLoad(l *selector.Load) Option {
+	return func(o *Options) {
+		o.Load = l
+	}
+}
+
+// HashKey sets the metadata key of the consistent hash key of a call.
+// A key set in the context with NewContext takes precedence.
+func HashKey(key string) Option {
+	return func(o *Options) {
+		o.HashKey = key
+	}
+}
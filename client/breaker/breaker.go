@@ -0,0 +1,209 @@
+// Package breaker provides a circuit breaker client wrapper
+package breaker
+
+import (
+	"sync"
+	"time"
+
+	"github.com/stack-labs/stack/util/errors"
+)
+
+// State of a circuit
+type State int
+
+const (
+	// StateClosed lets all the requests through
+	StateClosed State = iota
+	// StateOpen fails all the requests fast
+	StateOpen
+	// StateHalfOpen lets a limited number of trial requests through
+	StateHalfOpen
+)
+
+func (s State) String() string {
+	switch s {
+	case StateClosed:
+		return "closed"
+	case StateOpen:
+		return "open"
+	case StateHalfOpen:
+		return "half-open"
+	}
+	return "unknown"
+}
+
+// Breaker is a single circuit
+type Breaker struct {
+	name string
+	opts Options
+
+	sync.Mutex
+	state State
+	// counters of the current window while closed
+	requests    int
+	failures    int
+	consecutive int
+	windowStart time.Time
+	// time the circuit was opened
+	openedAt time.Time
+	// trial requests while half open
+	inFlight  int
+	successes int
+}
+
+// IsFailure is the default check of whether an error counts against
+// the circuit. Errors returned by the remote handler such as a 400 or
+// 404 don't count, a timeout, a 5xx or a non stack error does.
+func IsFailure(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	e, ok := err.(*errors.Error)
+	if !ok {
+		e = errors.Parse(err.Error())
+	}
+
+	if e.Code == 0 {
+		return true
+	}
+
+	return e.Code == 408 || e.Code >= 500
+}
+
+// New returns a closed circuit
+func New(name string, opts ...Option) *Breaker {
+	options := newOptions(opts...)
+
+	return &Breaker{
+		name:        name,
+		opts:        options,
+		windowStart: time.Now(),
+	}
+}
+
+// Name of the circuit
+func (b *Breaker) Name() string {
+	return b.name
+}
+
+// State returns the current state of the circuit
+func (b *Breaker) State() State {
+	b.Lock()
+	defer b.Unlock()
+	return b.state
+}
+
+// Ready reports whether a request would be let through without taking a trial slot
+func (b *Breaker) Ready() bool {
+	b.Lock()
+	defer b.Unlock()
+
+	switch b.state {
+	case StateOpen:
+		return time.Since(b.openedAt) >= b.opts.Timeout
+	case StateHalfOpen:
+		return b.inFlight < b.opts.HalfOpenRequests
+	}
+
+	return true
+}
+
+// Allow reports whether a request may be made. Every allowed request must
+// be followed by a call to Done with its result.
+func (b *Breaker) Allow() bool {
+	b.Lock()
+	defer b.Unlock()
+
+	switch b.state {
+	case StateOpen:
+		if time.Since(b.openedAt) < b.opts.Timeout {
+			return false
+		}
+		b.setState(StateHalfOpen)
+		fallthrough
+	case StateHalfOpen:
+		if b.inFlight >= b.opts.HalfOpenRequests {
+			return false
+		}
+		b.inFlight++
+	}
+
+	return true
+}
+
+// Done records the result of an allowed request
+func (b *Breaker) Done(err error) {
+	b.Lock()
+	defer b.Unlock()
+
+	failed := b.opts.IsFailure(err)
+	now := time.Now()
+
+	switch b.state {
+	case StateHalfOpen:
+		if b.inFlight > 0 {
+			b.inFlight--
+		}
+		if failed {
+			b.open(now)
+			return
+		}
+		b.successes++
+		if b.successes >= b.opts.HalfOpenRequests {
+			b.setState(StateClosed)
+		}
+		return
+	case StateOpen:
+		// result of a request made before the circuit opened
+		return
+	}
+
+	if now.Sub(b.windowStart) > b.opts.Window {
+		b.requests = 0
+		b.failures = 0
+		b.windowStart = now
+	}
+
+	b.requests++
+
+	if !failed {
+		b.consecutive = 0
+		return
+	}
+
+	b.failures++
+	b.consecutive++
+
+	if b.opts.MaxFailures > 0 && b.consecutive >= b.opts.MaxFailures {
+		b.open(now)
+		return
+	}
+
+	if b.opts.FailureRate > 0 && b.requests >= b.opts.MinRequests &&
+		float64(b.failures)/float64(b.requests) >= b.opts.FailureRate {
+		b.open(now)
+	}
+}
+
+func (b *Breaker) open(now time.Time) {
+	b.openedAt = now
+	b.setState(StateOpen)
+}
+
+// setState resets the counters and reports the transition
+func (b *Breaker) setState(state State) {
+	from := b.state
+
+	b.state = state
+	b.requests = 0
+	b.failures = 0
+	b.consecutive = 0
+	b.inFlight = 0
+	b.successes = 0
+	b.windowStart = time.Now()
+
+	if from != state && b.opts.OnStateChange != nil {
+		b.opts.OnStateChange(b.name, from, state)
+	}
+}
@@ -0,0 +1,180 @@
+package breaker
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stack-labs/stack/client"
+	"github.com/stack-labs/stack/registry"
+	"github.com/stack-labs/stack/util/errors"
+)
+
+// testClient runs the call wrappers and retries like the rpc client
+type testClient struct {
+	client.Client
+	opts  client.Options
+	calls int
+	err   error
+	// nodes are selected in turn, node-1 by default
+	nodes []string
+	// errs overrides err per node
+	errs map[string]error
+}
+
+func (t *testClient) Options() client.Options {
+	return t.opts
+}
+
+func (t *testClient) Call(ctx context.Context, req client.Request, rsp interface{}, opts ...client.CallOption) error {
+	callOpts := t.opts.CallOptions
+	for _, o := range opts {
+		o(&callOpts)
+	}
+
+	call := func(ctx context.Context, node *registry.Node, req client.Request, rsp interface{}, opts client.CallOptions) error {
+		t.calls++
+		if err, ok := t.errs[node.Id]; ok {
+			return err
+		}
+		return t.err
+	}
+
+	for i := len(callOpts.Wrappers); i > 0; i-- {
+		call = callOpts.Wrappers[i-1](call)
+	}
+
+	var err error
+	for i := 0; i <= callOpts.Retries; i++ {
+		node := &registry.Node{Id: "node-1"}
+		if len(t.nodes) > 0 {
+			node.Id = t.nodes[i%len(t.nodes)]
+		}
+		err = call(ctx, node, req, rsp, callOpts)
+		if err == nil {
+			return nil
+		}
+		if retry, _ := callOpts.Retry(ctx, req, i, err); !retry {
+			return err
+		}
+	}
+
+	return err
+}
+
+type testRequest struct {
+	client.Request
+}
+
+func (testRequest) Service() string  { return "foo" }
+func (testRequest) Endpoint() string { return "Foo.Bar" }
+
+func TestBreaker(t *testing.T) {
+	var transitions []State
+
+	b := New("test",
+		MaxFailures(2),
+		Timeout(time.Millisecond*20),
+		OnStateChange(func(name string, from, to State) {
+			transitions = append(transitions, to)
+		}),
+	)
+
+	for i := 0; i < 2; i++ {
+		if !b.Allow() {
+			t.Fatal("Expected closed circuit to allow requests")
+		}
+		b.Done(errors.InternalServerError("test", "error"))
+	}
+
+	if b.State() != StateOpen {
+		t.Fatalf("Expected open circuit, got %s", b.State())
+	}
+	if b.Allow() {
+		t.Fatal("Expected open circuit to reject requests")
+	}
+
+	time.Sleep(time.Millisecond * 30)
+
+	if !b.Allow() {
+		t.Fatal("Expected half open circuit to allow a trial request")
+	}
+	if b.Allow() {
+		t.Fatal("Expected half open circuit to allow a single trial request")
+	}
+	b.Done(nil)
+
+	if b.State() != StateClosed {
+		t.Fatalf("Expected closed circuit, got %s", b.State())
+	}
+
+	expected := []State{StateOpen, StateHalfOpen, StateClosed}
+	if len(transitions) != len(expected) {
+		t.Fatalf("Expected transitions %v, got %v", expected, transitions)
+	}
+	for i, s := range expected {
+		if transitions[i] != s {
+			t.Fatalf("Expected transitions %v, got %v", expected, transitions)
+		}
+	}
+}
+
+func TestBreakerIgnoresClientErrors(t *testing.T) {
+	b := New("test", MaxFailures(1))
+
+	b.Allow()
+	b.Done(errors.NotFound("test", "not found"))
+
+	if b.State() != StateClosed {
+		t.Fatalf("Expected closed circuit, got %s", b.State())
+	}
+}
+
+func TestClientWrapper(t *testing.T) {
+	tc := &testClient{
+		opts: client.NewOptions(client.Retries(3)),
+		err:  errors.InternalServerError("foo", "error"),
+	}
+	c := NewClientWrapper(MaxFailures(2), Timeout(time.Minute))(tc)
+
+	err := c.Call(context.TODO(), testRequest{}, nil)
+	if err == nil {
+		t.Fatal("Expected error")
+	}
+
+	// the retries stop once the circuit opens
+	if tc.calls != 2 {
+		t.Fatalf("Expected 2 calls, got %d", tc.calls)
+	}
+
+	err = c.Call(context.TODO(), testRequest{}, nil)
+	if e := errors.Parse(err.Error()); e.Code != 503 {
+		t.Fatalf("Expected 503 error, got %v", err)
+	}
+	if tc.calls != 2 {
+		t.Fatalf("Expected the open circuit to fail fast, got %d calls", tc.calls)
+	}
+}
+
+func TestClientWrapperPerNode(t *testing.T) {
+	tc := &testClient{
+		opts:  client.NewOptions(client.Retries(3)),
+		err:   errors.InternalServerError("foo", "error"),
+		nodes: []string{"node-1", "node-2"},
+		errs:  map[string]error{"node-2": nil},
+	}
+	c := NewClientWrapper(PerNode(true), MaxFailures(1), Timeout(time.Minute))(tc)
+
+	// the failure of node-1 opens its circuit
+	if err := c.Call(context.TODO(), testRequest{}, nil); err != nil {
+		t.Fatalf("Expected the retry on node-2 to succeed, got %v", err)
+	}
+
+	// the open circuit of node-1 is retried on node-2
+	if err := c.Call(context.TODO(), testRequest{}, nil); err != nil {
+		t.Fatalf("Expected the open circuit to fail over to node-2, got %v", err)
+	}
+	if tc.calls != 3 {
+		t.Fatalf("Expected 3 calls, got %d", tc.calls)
+	}
+}
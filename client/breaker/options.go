@@ -0,0 +1,138 @@
+package breaker
+
+import (
+	"time"
+
+	"github.com/stack-labs/stack/debug/stats"
+)
+
+var (
+	// DefaultMaxFailures is the number of consecutive failures which open the circuit
+	DefaultMaxFailures = 5
+	// DefaultFailureRate is the rate of failures within a window which opens the circuit
+	DefaultFailureRate = 0.5
+	// DefaultMinRequests is the number of requests in a window before the failure rate is checked
+	DefaultMinRequests = 20
+	// DefaultWindow is the period over which requests and failures are counted
+	DefaultWindow = time.Second * 10
+	// DefaultTimeout is the time a circuit stays open before half opening
+	DefaultTimeout = time.Second * 30
+	// DefaultHalfOpenRequests is the number of trial requests let through while half open
+	DefaultHalfOpenRequests = 1
+)
+
+type Options struct {
+	// MaxFailures opens the circuit after n consecutive failures, 0 disables it
+	MaxFailures int
+	// FailureRate opens the circuit once the rate of failures in a window reaches it, 0 disables it
+	FailureRate float64
+	// MinRequests required in a window before the failure rate is checked
+	MinRequests int
+	// Window over which requests and failures are counted
+	Window time.Duration
+	// Timeout is the time the circuit stays open before half opening
+	Timeout time.Duration
+	// HalfOpenRequests is the number of trial requests which must succeed to close the circuit
+	HalfOpenRequests int
+	// PerNode keys the circuits by node as well as service and endpoint
+	PerNode bool
+	// IsFailure decides whether an error counts against the circuit
+	IsFailure func(error) bool
+	// OnStateChange is called on every state transition with the circuit locked
+	OnStateChange func(name string, from, to State)
+}
+
+type Option func(o *Options)
+
+func newOptions(opts ...Option) Options {
+	options := Options{
+		MaxFailures:      DefaultMaxFailures,
+		FailureRate:      DefaultFailureRate,
+		MinRequests:      DefaultMinRequests,
+		Window:           DefaultWindow,
+		Timeout:          DefaultTimeout,
+		HalfOpenRequests: DefaultHalfOpenRequests,
+		IsFailure:        IsFailure,
+		OnStateChange:    recordTransition,
+	}
+
+	for _, o := range opts {
+		o(&options)
+	}
+
+	if options.HalfOpenRequests < 1 {
+		options.HalfOpenRequests = 1
+	}
+
+	return options
+}
+
+// recordTransition writes the transition to the debug stats
+func recordTransition(name string, from, to State) {
+	stats.RecordTransition(&stats.Transition{
+		Timestamp: time.Now().Unix(),
+		Component: "breaker",
+		Name:      name,
+		From:      from.String(),
+		To:        to.String(),
+	})
+}
+
+// MaxFailures sets the number of consecutive failures which open the circuit
+func MaxFailures(n int) Option {
+	return func(o *Options) {
+		o.MaxFailures = n
+	}
+}
+
+// FailureRate sets the rate of failures in a window which opens the
+// circuit once at least min requests were made
+func FailureRate(rate float64, min int) Option {
+	return func(o *Options) {
+		o.FailureRate = rate
+		o.MinRequests = min
+	}
+}
+
+// Window sets the period over which requests and failures are counted
+func Window(d time.Duration) Option {
+	return func(o *Options) {
+		o.Window = d
+	}
+}
+
+// Timeout sets the time a circuit stays open before half opening
+func Timeout(d time.Duration) Option {
+	return func(o *Options) {
+		o.Timeout = d
+	}
+}
+
+// HalfOpenRequests sets the number of trial requests which must succeed to close the circuit
+func HalfOpenRequests(n int) Option {
+	return func(o *Options) {
+		o.HalfOpenRequests = n
+	}
+}
+
+// PerNode keys the circuits by node as well as service and endpoint
+func PerNode(b bool) Option {
+	return func(o *Options) {
+		o.PerNode = b
+	}
+}
+
+// WithIsFailure sets the func deciding whether an error counts against the circuit
+func WithIsFailure(fn func(error) bool) Option {
+	return func(o *Options) {
+		o.IsFailure = fn
+	}
+}
+
+// OnStateChange sets the func called on every state transition. The debug
+// stats are no longer written unless the func does so itself.
+func OnStateChange(fn func(name string, from, to State)) Option {
+	return func(o *Options) {
+		o.OnStateChange = fn
+	}
+}
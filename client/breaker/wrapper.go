@@ -0,0 +1,147 @@
+package breaker
+
+import (
+	"context"
+	"sync"
+
+	"github.com/stack-labs/stack/client"
+	"github.com/stack-labs/stack/registry"
+	"github.com/stack-labs/stack/util/errors"
+)
+
+// ErrorId is the id of the error returned by calls to an open circuit
+const ErrorId = "stack.client.breaker"
+
+type breakerClient struct {
+	client.Client
+	opts []Option
+	// per node circuits are only checked once the node is known
+	perNode bool
+
+	sync.RWMutex
+	breakers map[string]*Breaker
+}
+
+func (c *breakerClient) breaker(key string) *Breaker {
+	c.RLock()
+	b, ok := c.breakers[key]
+	c.RUnlock()
+	if ok {
+		return b
+	}
+
+	c.Lock()
+	defer c.Unlock()
+
+	if b, ok = c.breakers[key]; ok {
+		return b
+	}
+
+	b = New(key, c.opts...)
+	c.breakers[key] = b
+
+	return b
+}
+
+func (c *breakerClient) key(req client.Request, node *registry.Node) string {
+	key := req.Service() + "." + req.Endpoint()
+	if c.perNode && node != nil {
+		key += "@" + node.Id
+	}
+	return key
+}
+
+func (c *breakerClient) unavailable(key string) error {
+	return errors.ServiceUnavailable(ErrorId, "circuit %s is open", key)
+}
+
+// wrapCall checks and records every attempt made by the client
+func (c *breakerClient) wrapCall(cf client.CallFunc) client.CallFunc {
+	return func(ctx context.Context, node *registry.Node, req client.Request, rsp interface{}, opts client.CallOptions) error {
+		key := c.key(req, node)
+		b := c.breaker(key)
+
+		if !b.Allow() {
+			return c.unavailable(key)
+		}
+
+		err := cf(ctx, node, req, rsp, opts)
+		b.Done(err)
+
+		return err
+	}
+}
+
+// wrapRetry stops retrying once the circuit is open, or with per node
+// circuits retries an open circuit on another node
+func (c *breakerClient) wrapRetry(req client.Request, fn client.RetryFunc) client.RetryFunc {
+	return func(ctx context.Context, r client.Request, retryCount int, err error) (bool, error) {
+		if !c.perNode && !c.breaker(c.key(req, nil)).Ready() {
+			return false, nil
+		}
+		if c.perNode && err != nil && errors.Parse(err.Error()).Id == ErrorId {
+			return true, nil
+		}
+		return fn(ctx, r, retryCount, err)
+	}
+}
+
+func (c *breakerClient) Call(ctx context.Context, req client.Request, rsp interface{}, opts ...client.CallOption) error {
+	// fail fast before a node is selected
+	if !c.perNode {
+		key := c.key(req, nil)
+		if !c.breaker(key).Ready() {
+			return c.unavailable(key)
+		}
+	}
+
+	// resolve the retry func the call would use
+	callOpts := c.Client.Options().CallOptions
+	for _, o := range opts {
+		o(&callOpts)
+	}
+
+	retry := callOpts.Retry
+	if retry == nil {
+		retry = client.DefaultRetry
+	}
+
+	opts = append(opts,
+		client.WithCallWrapper(c.wrapCall),
+		client.WithRetry(c.wrapRetry(req, retry)),
+	)
+
+	return c.Client.Call(ctx, req, rsp, opts...)
+}
+
+func (c *breakerClient) Stream(ctx context.Context, req client.Request, opts ...client.CallOption) (client.Stream, error) {
+	// streams are not node aware so are keyed by endpoint
+	key := req.Service() + "." + req.Endpoint()
+	b := c.breaker(key)
+
+	if !b.Allow() {
+		return nil, c.unavailable(key)
+	}
+
+	stream, err := c.Client.Stream(ctx, req, opts...)
+	b.Done(err)
+
+	return stream, err
+}
+
+// NewClientWrapper returns a client.Wrapper which opens a circuit per service
+// and endpoint, or per node with PerNode, once calls keep failing. Calls to an
+// open circuit fail fast with a 503 error which isn't retried, except with
+// PerNode where the call is retried on another node.
+func NewClientWrapper(opts ...Option) client.Wrapper {
+	options := newOptions(opts...)
+
+	return func(c client.Client) client.Client {
+		return &breakerClient{
+			Client:   c,
+			opts:     opts,
+			perNode:  options.PerNode,
+			breakers: make(map[string]*Breaker),
+		}
+	}
+}
@@ -0,0 +1,371 @@
+// Package dynamic calls grpc services without compiled stubs, the methods are
+// resolved with the grpc server reflection service and invoked with JSON
+package dynamic
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/stack-labs/stack/client"
+	"github.com/stack-labs/stack/client/grpc"
+	"github.com/stack-labs/stack/codec/bytes"
+	"github.com/stack-labs/stack/util/errors"
+	rpb "google.golang.org/grpc/reflection/grpc_reflection_v1alpha"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+const (
+	reflectionMethod = "/grpc.reflection.v1alpha.ServerReflection/ServerReflectionInfo"
+	contentType      = "application/grpc+proto"
+)
+
+// Client invokes the methods of grpc services with JSON
+type Client interface {
+	// Services lists the grpc services of a service
+	Services(ctx context.Context, service string) ([]string, error)
+	// Method resolves a method such as Greeter.Hello, helloworld.Greeter.Hello
+	// or /helloworld.Greeter/Hello
+	Method(ctx context.Context, service, method string) (protoreflect.MethodDescriptor, error)
+	// Call invokes a unary method with a JSON request and returns the JSON response
+	Call(ctx context.Context, service, method string, req []byte, opts ...client.CallOption) ([]byte, error)
+	// Stream opens a stream to a streaming method
+	Stream(ctx context.Context, service, method string, opts ...client.CallOption) (Stream, error)
+}
+
+// Stream sends and receives JSON messages
+type Stream interface {
+	// Send a JSON request
+	Send([]byte) error
+	// Recv a JSON response
+	Recv() ([]byte, error)
+	// Close the sending side of the stream
+	Close() error
+}
+
+type dynamicClient struct {
+	opts Options
+
+	sync.Mutex
+	cache map[string]*descriptors
+}
+
+// descriptors of the grpc services of a service
+type descriptors struct {
+	services []string
+	files    *protoregistry.Files
+	expires  time.Time
+}
+
+type dynamicStream struct {
+	method protoreflect.MethodDescriptor
+	stream client.Stream
+}
+
+// path returns the grpc path of a method, e.g /helloworld.Greeter/Hello
+func path(md protoreflect.MethodDescriptor) string {
+	return fmt.Sprintf("/%s/%s", md.Parent().FullName(), md.Name())
+}
+
+// splitMethod splits a method into its service and method names
+func splitMethod(method string) (string, string) {
+	if strings.HasPrefix(method, "/") {
+		if parts := strings.Split(method[1:], "/"); len(parts) == 2 {
+			return parts[0], parts[1]
+		}
+	}
+
+	i := strings.LastIndex(method, ".")
+	if i < 0 {
+		return "", method
+	}
+	return method[:i], method[i+1:]
+}
+
+// query sends a request on the reflection stream and reads its response
+func query(stream client.Stream, req *rpb.ServerReflectionRequest) (*rpb.ServerReflectionResponse, error) {
+	if err := stream.Send(req); err != nil {
+		return nil, err
+	}
+
+	rsp := new(rpb.ServerReflectionResponse)
+	if err := stream.Recv(rsp); err != nil {
+		return nil, err
+	}
+
+	if e := rsp.GetErrorResponse(); e != nil {
+		return nil, errors.NotFound("stack.rpc.client", "%s", e.ErrorMessage)
+	}
+
+	return rsp, nil
+}
+
+// load reads the services and their files with the reflection service
+func (d *dynamicClient) load(ctx context.Context, service string) (*descriptors, error) {
+	req := d.opts.Client.NewRequest(service, reflectionMethod, &rpb.ServerReflectionRequest{}, client.WithContentType(contentType))
+
+	stream, err := d.opts.Client.Stream(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		// the connection is released once the server ends the stream
+		stream.Close()
+		stream.Recv(new(rpb.ServerReflectionResponse))
+	}()
+
+	rsp, err := query(stream, &rpb.ServerReflectionRequest{
+		MessageRequest: &rpb.ServerReflectionRequest_ListServices{},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	files := make(map[string]*descriptorpb.FileDescriptorProto)
+
+	add := func(rsp *rpb.ServerReflectionResponse) error {
+		for _, b := range rsp.GetFileDescriptorResponse().GetFileDescriptorProto() {
+			fd := new(descriptorpb.FileDescriptorProto)
+			if err := proto.Unmarshal(b, fd); err != nil {
+				return err
+			}
+			files[fd.GetName()] = fd
+		}
+		return nil
+	}
+
+	var services []string
+
+	for _, s := range rsp.GetListServicesResponse().GetService() {
+		rsp, err := query(stream, &rpb.ServerReflectionRequest{
+			MessageRequest: &rpb.ServerReflectionRequest_FileContainingSymbol{
+				FileContainingSymbol: s.Name,
+			},
+		})
+		if err != nil {
+			return nil, err
+		}
+		if err := add(rsp); err != nil {
+			return nil, err
+		}
+		services = append(services, s.Name)
+	}
+
+	// fetch the dependencies sent on other streams
+	for {
+		var missing []string
+		for _, fd := range files {
+			for _, dep := range fd.GetDependency() {
+				if _, ok := files[dep]; !ok {
+					missing = append(missing, dep)
+				}
+			}
+		}
+		if len(missing) == 0 {
+			break
+		}
+
+		for _, name := range missing {
+			rsp, err := query(stream, &rpb.ServerReflectionRequest{
+				MessageRequest: &rpb.ServerReflectionRequest_FileByFilename{
+					FileByFilename: name,
+				},
+			})
+			if err != nil {
+				return nil, err
+			}
+			if err := add(rsp); err != nil {
+				return nil, err
+			}
+			if _, ok := files[name]; !ok {
+				return nil, errors.NotFound("stack.rpc.client", "file %s not found in %s", name, service)
+			}
+		}
+	}
+
+	set := new(descriptorpb.FileDescriptorSet)
+	for _, fd := range files {
+		set.File = append(set.File, fd)
+	}
+
+	reg, err := protodesc.NewFiles(set)
+	if err != nil {
+		return nil, err
+	}
+
+	return &descriptors{
+		services: services,
+		files:    reg,
+		expires:  time.Now().Add(d.opts.TTL),
+	}, nil
+}
+
+// descriptors returns the cached descriptors of a service, loading them when expired
+func (d *dynamicClient) descriptors(ctx context.Context, service string) (*descriptors, error) {
+	d.Lock()
+	desc, ok := d.cache[service]
+	d.Unlock()
+
+	if ok && time.Now().Before(desc.expires) {
+		return desc, nil
+	}
+
+	desc, err := d.load(ctx, service)
+	if err != nil {
+		return nil, err
+	}
+
+	d.Lock()
+	d.cache[service] = desc
+	d.Unlock()
+
+	return desc, nil
+}
+
+func (d *dynamicClient) Services(ctx context.Context, service string) ([]string, error) {
+	desc, err := d.descriptors(ctx, service)
+	if err != nil {
+		return nil, err
+	}
+	return desc.services, nil
+}
+
+func (d *dynamicClient) Method(ctx context.Context, service, method string) (protoreflect.MethodDescriptor, error) {
+	desc, err := d.descriptors(ctx, service)
+	if err != nil {
+		return nil, err
+	}
+
+	svc, name := splitMethod(method)
+
+	for _, s := range desc.services {
+		// the package may be omitted
+		if s != svc && !strings.HasSuffix(s, "."+svc) {
+			continue
+		}
+
+		sd, err := desc.files.FindDescriptorByName(protoreflect.FullName(s))
+		if err != nil {
+			continue
+		}
+		sdesc, ok := sd.(protoreflect.ServiceDescriptor)
+		if !ok {
+			continue
+		}
+		if md := sdesc.Methods().ByName(protoreflect.Name(name)); md != nil {
+			return md, nil
+		}
+	}
+
+	return nil, errors.NotFound("stack.rpc.client", "method %s not found in %s", method, service)
+}
+
+// marshal decodes a JSON message of the type and encodes it in protobuf
+func marshal(md protoreflect.MessageDescriptor, b []byte) ([]byte, error) {
+	msg := dynamicpb.NewMessage(md)
+	if len(b) > 0 {
+		if err := protojson.Unmarshal(b, msg); err != nil {
+			return nil, errors.BadRequest("stack.rpc.client", "%v", err)
+		}
+	}
+	return proto.Marshal(msg)
+}
+
+// unmarshal decodes a protobuf message of the type and encodes it in JSON
+func unmarshal(md protoreflect.MessageDescriptor, b []byte) ([]byte, error) {
+	msg := dynamicpb.NewMessage(md)
+	if err := proto.Unmarshal(b, msg); err != nil {
+		return nil, err
+	}
+	return protojson.Marshal(msg)
+}
+
+func (d *dynamicClient) Call(ctx context.Context, service, method string, req []byte, opts ...client.CallOption) ([]byte, error) {
+	md, err := d.Method(ctx, service, method)
+	if err != nil {
+		return nil, err
+	}
+
+	if md.IsStreamingClient() || md.IsStreamingServer() {
+		return nil, errors.BadRequest("stack.rpc.client", "method %s is a stream", method)
+	}
+
+	b, err := marshal(md.Input(), req)
+	if err != nil {
+		return nil, err
+	}
+
+	rsp := new(bytes.Frame)
+	creq := d.opts.Client.NewRequest(service, path(md), &bytes.Frame{Data: b}, client.WithContentType(contentType))
+	if err := d.opts.Client.Call(ctx, creq, rsp, opts...); err != nil {
+		return nil, err
+	}
+
+	return unmarshal(md.Output(), rsp.Data)
+}
+
+func (d *dynamicClient) Stream(ctx context.Context, service, method string, opts ...client.CallOption) (Stream, error) {
+	md, err := d.Method(ctx, service, method)
+	if err != nil {
+		return nil, err
+	}
+
+	creq := d.opts.Client.NewRequest(service, path(md), &bytes.Frame{}, client.WithContentType(contentType))
+	stream, err := d.opts.Client.Stream(ctx, creq, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dynamicStream{
+		method: md,
+		stream: stream,
+	}, nil
+}
+
+func (s *dynamicStream) Send(req []byte) error {
+	b, err := marshal(s.method.Input(), req)
+	if err != nil {
+		return err
+	}
+	return s.stream.Send(&bytes.Frame{Data: b})
+}
+
+func (s *dynamicStream) Recv() ([]byte, error) {
+	rsp := new(bytes.Frame)
+	if err := s.stream.Recv(rsp); err != nil {
+		return nil, err
+	}
+	return unmarshal(s.method.Output(), rsp.Data)
+}
+
+func (s *dynamicStream) Close() error {
+	return s.stream.Close()
+}
+
+// NewClient returns a client calling grpc services with JSON
+func NewClient(opts ...Option) Client {
+	options := Options{
+		TTL: DefaultTTL,
+	}
+	for _, o := range opts {
+		o(&options)
+	}
+
+	if options.Client == nil {
+		options.Client = grpc.NewClient()
+	}
+
+	return &dynamicClient{
+		opts:  options,
+		cache: make(map[string]*descriptors),
+	}
+}
@@ -0,0 +1,79 @@
+package dynamic
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stack-labs/stack/client"
+	"github.com/stack-labs/stack/client/grpc"
+	"github.com/stack-labs/stack/client/selector"
+	selectorR "github.com/stack-labs/stack/client/selector/registry"
+	"github.com/stack-labs/stack/registry/memory"
+	"github.com/stack-labs/stack/server"
+	gserver "github.com/stack-labs/stack/server/grpc"
+	pb "github.com/stack-labs/stack/server/grpc/proto"
+
+	_ "github.com/stack-labs/stack/plugin/stack"
+)
+
+type testHandler struct{}
+
+func (t *testHandler) Call(ctx context.Context, req *pb.Request, rsp *pb.Response) error {
+	rsp.Msg = "Hello " + req.Name
+	return nil
+}
+
+func TestDynamicClient(t *testing.T) {
+	r := memory.NewRegistry()
+
+	s := gserver.NewServer(
+		server.Name("test.dynamic"),
+		server.Registry(r),
+		gserver.Reflection(true),
+	)
+	pb.RegisterTestHandler(s, &testHandler{})
+
+	if err := s.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer s.Stop()
+
+	c := NewClient(WithClient(grpc.NewClient(
+		client.Registry(r),
+		client.Selector(selectorR.NewSelector(selector.Registry(r))),
+	)))
+
+	services, err := c.Services(context.TODO(), "test.dynamic")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var found bool
+	for _, s := range services {
+		if s == "Test" {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatalf("Expected the Test service to be reflected, got %v", services)
+	}
+
+	for _, method := range []string{"Test.Call", "/Test/Call"} {
+		rsp, err := c.Call(context.TODO(), "test.dynamic", method, []byte(`{"name": "John"}`))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if string(rsp) != `{"msg":"Hello John"}` {
+			t.Fatalf("Expected response %s, got %s", `{"msg":"Hello John"}`, rsp)
+		}
+	}
+
+	if _, err := c.Call(context.TODO(), "test.dynamic", "Test.Missing", nil); err == nil {
+		t.Fatal("Expected an error calling a missing method")
+	}
+
+	if _, err := c.Call(context.TODO(), "test.dynamic", "Test.Call", []byte(`{"unknown": 1}`)); err == nil {
+		t.Fatal("Expected an error for an invalid request")
+	}
+}
@@ -0,0 +1,35 @@
+package dynamic
+
+import (
+	"time"
+
+	"github.com/stack-labs/stack/client"
+)
+
+var (
+	// DefaultTTL is how long the descriptors of a service are cached
+	DefaultTTL = time.Minute
+)
+
+type Options struct {
+	// Client used to call the services, it should be a grpc client
+	Client client.Client
+	// TTL of the cached descriptors
+	TTL time.Duration
+}
+
+type Option func(o *Options)
+
+// WithClient sets the grpc client used to call the services
+func WithClient(c client.Client) Option {
+	return func(o *Options) {
+		o.Client = c
+	}
+}
+
+// TTL sets how long the descriptors of a service are cached
+func TTL(d time.Duration) Option {
+	return func(o *Options) {
+		o.TTL = d
+	}
+}
@@ -15,6 +15,8 @@ import (
 	selectorR "github.com/stack-labs/stack/client/selector/registry"
 	"github.com/stack-labs/stack/codec"
 	raw "github.com/stack-labs/stack/codec/bytes"
+	// register the compressors with grpc
+	"github.com/stack-labs/stack/codec/compress"
 	"github.com/stack-labs/stack/env"
 	"github.com/stack-labs/stack/pkg/metadata"
 	"github.com/stack-labs/stack/plugin"
@@ -39,11 +41,16 @@ func init() {
 	encoding.RegisterCodec(wrapCodec{bytesCodec{}})
 }
 
-// secure returns the dial option for whether its a secure or insecure connection
-func (g *grpcClient) secure() grpc.DialOption {
+// secure returns the dial option for whether its a secure or insecure connection,
+// the server is verified against the name of the service called
+func (g *grpcClient) secure(request client.Request) grpc.DialOption {
 	if g.opts.Context != nil {
 		if v := g.opts.Context.Value(tlsAuth{}); v != nil {
 			tls := v.(*tls.Config)
+			if len(tls.ServerName) == 0 {
+				tls = tls.Clone()
+				tls.ServerName = g.serverName(request)
+			}
 			creds := credentials.NewTLS(tls)
 			return grpc.WithTransportCredentials(creds)
 		}
@@ -51,6 +58,15 @@ func (g *grpcClient) secure() grpc.DialOption {
 	return grpc.WithInsecure()
 }
 
+// serverName is the name the certificate of the node called is verified
+// against, the service or the proxy it is called through
+func (g *grpcClient) serverName(request client.Request) string {
+	if prx := os.Getenv(env.StackProxy); len(prx) > 0 {
+		return prx
+	}
+	return request.Service()
+}
+
 func (g *grpcClient) next(request client.Request, opts client.CallOptions) (*registry.Node, error) {
 	service := request.Service()
 
@@ -114,7 +130,7 @@ func (g *grpcClient) call(ctx context.Context, node *registry.Node, req client.R
 	grpcDialOptions := []grpc.DialOption{
 		grpc.WithDefaultCallOptions(grpc.ForceCodec(cf)),
 		grpc.WithTimeout(opts.DialTimeout),
-		g.secure(),
+		g.secure(req),
 		grpc.WithDefaultCallOptions(
 			grpc.MaxCallRecvMsgSize(maxRecvMsgSize),
 			grpc.MaxCallSendMsgSize(maxSendMsgSize),
@@ -138,7 +154,7 @@ func (g *grpcClient) call(ctx context.Context, node *registry.Node, req client.R
 
 	go func() {
 		grpcCallOptions := []grpc.CallOption{grpc.CallContentSubtype(cf.Name())}
-		if opts := g.getGrpcCallOptions(); opts != nil {
+		if opts := g.getGrpcCallOptions(node); opts != nil {
 			grpcCallOptions = append(grpcCallOptions, opts...)
 		}
 		err := cc.Invoke(ctx, methodToGRPC(req.Service(), req.Endpoint()), req.Body(), rsp, grpcCallOptions...)
@@ -191,7 +207,7 @@ func (g *grpcClient) stream(ctx context.Context, node *registry.Node, req client
 
 	grpcDialOptions := []grpc.DialOption{
 		grpc.WithDefaultCallOptions(grpc.ForceCodec(wc)),
-		g.secure(),
+		g.secure(req),
 	}
 
 	if opts := g.getGrpcDialOptions(); opts != nil {
@@ -210,7 +226,7 @@ func (g *grpcClient) stream(ctx context.Context, node *registry.Node, req client
 	}
 
 	grpcCallOptions := []grpc.CallOption{}
-	if opts := g.getGrpcCallOptions(); opts != nil {
+	if opts := g.getGrpcCallOptions(node); opts != nil {
 		grpcCallOptions = append(grpcCallOptions, opts...)
 	}
 	st, err := cc.NewStream(ctx, desc, methodToGRPC(req.Service(), req.Endpoint()), grpcCallOptions...)
@@ -570,24 +586,32 @@ func (g *grpcClient) getGrpcDialOptions() []grpc.DialOption {
 	return opts
 }
 
-func (g *grpcClient) getGrpcCallOptions() []grpc.CallOption {
+func (g *grpcClient) getGrpcCallOptions(node *registry.Node) []grpc.CallOption {
+	var opts []grpc.CallOption
+
+	// grpc compresses every message with the compressor when the server
+	// supports it, the threshold is not supported by grpc
+	if compress.Accepts(node.Metadata, g.opts.Compressor) {
+		opts = append(opts, grpc.UseCompressor(g.opts.Compressor))
+	}
+
 	if g.opts.CallOptions.Context == nil {
-		return nil
+		return opts
 	}
 
 	v := g.opts.CallOptions.Context.Value(grpcCallOptions{})
 
 	if v == nil {
-		return nil
+		return opts
 	}
 
-	opts, ok := v.([]grpc.CallOption)
+	copts, ok := v.([]grpc.CallOption)
 
 	if !ok {
-		return nil
+		return opts
 	}
 
-	return opts
+	return append(opts, copts...)
 }
 
 func newClient(opts ...client.Option) client.Client {
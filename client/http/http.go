@@ -19,6 +19,7 @@ import (
 	"github.com/stack-labs/stack/client/selector"
 	"github.com/stack-labs/stack/codec"
 	raw "github.com/stack-labs/stack/codec/bytes"
+	"github.com/stack-labs/stack/codec/compress"
 	"github.com/stack-labs/stack/pkg/metadata"
 	"github.com/stack-labs/stack/registry"
 	"github.com/stack-labs/stack/transport"
@@ -97,6 +98,21 @@ func (h *httpClient) call(ctx context.Context, node *registry.Node, req client.R
 		return errors.InternalServerError("go.micro.client", err.Error())
 	}
 
+	// accept responses of the encoding and compress the request when the server supports it
+	if len(h.opts.Compressor) > 0 {
+		header.Set(compress.AcceptEncoding, h.opts.Compressor)
+	}
+	if compress.Accepts(node.Metadata, h.opts.Compressor) {
+		cb, enc, err := compress.Message(h.opts.Compressor, h.opts.CompressThreshold, b)
+		if err != nil {
+			return errors.InternalServerError("go.micro.client", err.Error())
+		}
+		if len(enc) > 0 {
+			b = cb
+			header.Set(compress.ContentEncoding, enc)
+		}
+	}
+
 	buf := &buffer{bytes.NewBuffer(b)}
 	defer buf.Close()
 
@@ -126,6 +142,14 @@ func (h *httpClient) call(ctx context.Context, node *registry.Node, req client.R
 		return errors.InternalServerError("go.micro.client", err.Error())
 	}
 
+	// decompress the response unless the transport already did
+	if !hrsp.Uncompressed {
+		b, err = compress.Body(hrsp.Header.Get(compress.ContentEncoding), b, 0)
+		if err != nil {
+			return errors.InternalServerError("go.micro.client", err.Error())
+		}
+	}
+
 	// unmarshal
 	if err := cf.Unmarshal(b, rsp); err != nil {
 		return errors.InternalServerError("go.micro.client", err.Error())
@@ -102,6 +102,7 @@ func (r *rpcClient) call(ctx context.Context, node *registry.Node, req client.Re
 
 	dOpts := []transport.DialOption{
 		transport.WithStream(),
+		transport.WithServerName(r.serverName(req)),
 	}
 
 	if opts.DialTimeout >= 0 {
@@ -114,7 +115,7 @@ func (r *rpcClient) call(ctx context.Context, node *registry.Node, req client.Re
 	}
 
 	seq := r.seq.Inc()
-	codec := newRpcCodec(msg, c, cf, "")
+	codec := newRpcCodec(msg, c, cf, "", r.opts, node)
 
 	rsp := &rpcResponse{
 		socket: c,
@@ -216,6 +217,7 @@ func (r *rpcClient) stream(ctx context.Context, node *registry.Node, req client.
 
 	dOpts := []transport.DialOption{
 		transport.WithStream(),
+		transport.WithServerName(r.serverName(req)),
 	}
 
 	if opts.DialTimeout >= 0 {
@@ -232,7 +234,7 @@ func (r *rpcClient) stream(ctx context.Context, node *registry.Node, req client.
 	id := fmt.Sprintf("%v", seq)
 
 	// create codec with stream id
-	codec := newRpcCodec(msg, c, cf, id)
+	codec := newRpcCodec(msg, c, cf, id, r.opts, node)
 
 	rsp := &rpcResponse{
 		socket: c,
@@ -332,6 +334,15 @@ func (r *rpcClient) hasProxy() bool {
 	return false
 }
 
+// serverName is the name the certificate of the node called is verified
+// against, the service or the proxy it is called through
+func (r *rpcClient) serverName(request client.Request) string {
+	if prx := os.Getenv(env.StackProxy); len(prx) > 0 {
+		return prx
+	}
+	return request.Service()
+}
+
 // next returns an iterator for the next nodes to call
 func (r *rpcClient) next(request client.Request, opts client.CallOptions) (*registry.Node, error) {
 	service := request.Service()
@@ -6,8 +6,10 @@ import (
 
 	codecu "github.com/stack-labs/stack/util/codec"
 
+	"github.com/stack-labs/stack/client"
 	"github.com/stack-labs/stack/codec"
 	raw "github.com/stack-labs/stack/codec/bytes"
+	"github.com/stack-labs/stack/codec/compress"
 	"github.com/stack-labs/stack/registry"
 	"github.com/stack-labs/stack/transport"
 	"github.com/stack-labs/stack/util/errors"
@@ -39,6 +41,12 @@ type rpcCodec struct {
 
 	// signify if its a stream
 	stream string
+
+	// content encoding and threshold of the request bodies, the
+	// bodies are only compressed when the server advertises it
+	compressor   string
+	threshold    int
+	compressBody bool
 }
 
 type readWriteCloser struct {
@@ -126,7 +134,7 @@ func setupProtocol(msg *transport.Message, node *registry.Node) codec.NewCodec {
 	return codecu.DefaultCodecs[msg.Header["Content-Type"]]
 }
 
-func newRpcCodec(req *transport.Message, client transport.Client, c codec.NewCodec, stream string) codec.Codec {
+func newRpcCodec(req *transport.Message, client transport.Client, c codec.NewCodec, stream string, opts client.Options, node *registry.Node) codec.Codec {
 	rwc := &readWriteCloser{
 		wbuf: bytes.NewBuffer(nil),
 		rbuf: bytes.NewBuffer(nil),
@@ -137,6 +145,10 @@ func newRpcCodec(req *transport.Message, client transport.Client, c codec.NewCod
 		codec:  c(rwc),
 		req:    req,
 		stream: stream,
+
+		compressor:   opts.Compressor,
+		threshold:    opts.CompressThreshold,
+		compressBody: compress.Accepts(node.Metadata, opts.Compressor),
 	}
 	return r
 }
@@ -176,6 +188,22 @@ func (c *rpcCodec) Write(m *codec.Message, body interface{}) error {
 		m.Body = c.buf.wbuf.Bytes()
 	}
 
+	// accept responses of the encoding and compress the body when the server supports it
+	delete(m.Header, compress.ContentEncoding)
+	if len(c.compressor) > 0 {
+		m.Header[compress.AcceptEncoding] = c.compressor
+	}
+	if c.compressBody {
+		b, enc, err := compress.Message(c.compressor, c.threshold, m.Body)
+		if err != nil {
+			return errors.InternalServerError("stack.rpc.client.codec", err.Error())
+		}
+		if len(enc) > 0 {
+			m.Body = b
+			m.Header[compress.ContentEncoding] = enc
+		}
+	}
+
 	// create new transport message
 	msg := transport.Message{
 		Header: m.Header,
@@ -196,14 +224,21 @@ func (c *rpcCodec) ReadHeader(m *codec.Message, r codec.MessageType) error {
 		return errors.InternalServerError("stack.rpc.client.transport", err.Error())
 	}
 
+	// decompress the body
+	body, err := compress.Body(tm.Header[compress.ContentEncoding], tm.Body, 0)
+	if err != nil {
+		return errors.InternalServerError("stack.rpc.client.codec", err.Error())
+	}
+	delete(tm.Header, compress.ContentEncoding)
+
 	c.buf.rbuf.Reset()
-	c.buf.rbuf.Write(tm.Body)
+	c.buf.rbuf.Write(body)
 
 	// set headers from transport
 	m.Header = tm.Header
 
 	// read header
-	err := c.codec.ReadHeader(m, r)
+	err = c.codec.ReadHeader(m, r)
 
 	// get headers
 	getHeaders(m)
@@ -33,6 +33,13 @@ type Options struct {
 	PoolSize int
 	PoolTTL  time.Duration
 
+	// Compressor is the content encoding of the request bodies, the
+	// responses are compressed with it as well when set
+	Compressor string
+	// CompressThreshold is the smallest body in bytes which is compressed,
+	// zero uses the default and a negative value disables the compression
+	CompressThreshold int
+
 	// Middleware for client
 	Wrappers []Wrapper
 
@@ -156,6 +163,20 @@ func ContentType(ct string) Option {
 	}
 }
 
+// Compressor sets the content encoding of the request bodies e.g gzip
+func Compressor(name string) Option {
+	return func(o *Options) {
+		o.Compressor = name
+	}
+}
+
+// CompressThreshold sets the smallest body in bytes which is compressed
+func CompressThreshold(n int) Option {
+	return func(o *Options) {
+		o.CompressThreshold = n
+	}
+}
+
 // PoolSize sets the connection pool size
 func PoolSize(d int) Option {
 	return func(o *Options) {
@@ -312,6 +333,15 @@ func WithDialTimeout(d time.Duration) CallOption {
 	}
 }
 
+// Message Options
+
+// WithMessageContentType sets the content type of a published message
+func WithMessageContentType(ct string) MessageOption {
+	return func(o *MessageOptions) {
+		o.ContentType = ct
+	}
+}
+
 // Request Options
 
 func WithContentType(ct string) RequestOption {
@@ -5,9 +5,22 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/stack-labs/stack/debug/metrics"
 	"github.com/stack-labs/stack/transport"
 )
 
+const (
+	poolGets  = "stack_client_pool_gets_total"
+	poolIdle  = "stack_client_pool_idle_conns"
+	poolDials = "stack_client_pool_dial_duration_seconds"
+)
+
+func init() {
+	metrics.DefaultMetrics.Describe(poolGets, "Total connections taken from the pool by result hit, miss or error.")
+	metrics.DefaultMetrics.Describe(poolIdle, "Connections kept in the pool for reuse.")
+	metrics.DefaultMetrics.Describe(poolDials, "Latency of dialing new connections in seconds.")
+}
+
 type pool struct {
 	size int
 	ttl  time.Duration
@@ -38,6 +51,7 @@ func (p *pool) Close() error {
 		for _, conn := range c {
 			conn.Client.Close()
 		}
+		metrics.DefaultMetrics.Gauge(poolIdle).Add(-float64(len(c)))
 		delete(p.conns, k)
 	}
 	p.Unlock()
@@ -67,6 +81,7 @@ func (p *pool) Get(addr string, opts ...transport.DialOption) (Conn, error) {
 		conn := conns[len(conns)-1]
 		conns = conns[:len(conns)-1]
 		p.conns[addr] = conns
+		metrics.DefaultMetrics.Gauge(poolIdle).Dec()
 
 		// if conn is old kill it and move on
 		if d := time.Since(conn.Created()); d > p.ttl {
@@ -76,6 +91,7 @@ func (p *pool) Get(addr string, opts ...transport.DialOption) (Conn, error) {
 
 		// we got a good conn, lets unlock and return it
 		p.Unlock()
+		metrics.DefaultMetrics.Counter(poolGets, "result", "hit").Inc()
 
 		return conn, nil
 	}
@@ -83,10 +99,15 @@ func (p *pool) Get(addr string, opts ...transport.DialOption) (Conn, error) {
 	p.Unlock()
 
 	// create new conn
+	start := time.Now()
 	c, err := p.tr.Dial(addr, opts...)
+	metrics.DefaultMetrics.Histogram(poolDials).Observe(time.Since(start).Seconds())
 	if err != nil {
+		metrics.DefaultMetrics.Counter(poolGets, "result", "error").Inc()
 		return nil, err
 	}
+	metrics.DefaultMetrics.Counter(poolGets, "result", "miss").Inc()
+
 	return &poolConn{
 		Client:  c,
 		id:      uuid.New().String(),
@@ -109,6 +130,7 @@ func (p *pool) Release(conn Conn, err error) error {
 	}
 	p.conns[conn.Remote()] = append(conns, conn.(*poolConn))
 	p.Unlock()
+	metrics.DefaultMetrics.Gauge(poolIdle).Inc()
 
 	return nil
 }
@@ -0,0 +1,93 @@
+package selector
+
+import (
+	"hash/crc32"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/stack-labs/stack/registry"
+)
+
+var (
+	// DefaultReplicas is the number of points of a node on the hash ring
+	DefaultReplicas = 100
+
+	// rings caches the hash ring of a set of nodes
+	rings = &ringCache{rings: make(map[string]*hashRing)}
+	// maxRings cached before the cache is cleared
+	maxRings = 64
+)
+
+type hashRing struct {
+	points []uint32
+	nodes  map[uint32]*registry.Node
+}
+
+type ringCache struct {
+	sync.Mutex
+	rings map[string]*hashRing
+}
+
+func newRing(nodes []*registry.Node) *hashRing {
+	r := &hashRing{
+		points: make([]uint32, 0, len(nodes)*DefaultReplicas),
+		nodes:  make(map[uint32]*registry.Node, len(nodes)*DefaultReplicas),
+	}
+
+	for _, node := range nodes {
+		for i := 0; i < DefaultReplicas; i++ {
+			p := crc32.ChecksumIEEE([]byte(node.Id + "#" + strconv.Itoa(i)))
+			if _, ok := r.nodes[p]; ok {
+				continue
+			}
+			r.nodes[p] = node
+			r.points = append(r.points, p)
+		}
+	}
+
+	sort.Slice(r.points, func(i, j int) bool {
+		return r.points[i] < r.points[j]
+	})
+
+	return r
+}
+
+// get returns the first node clockwise of the key
+func (r *hashRing) get(key string) *registry.Node {
+	h := crc32.ChecksumIEEE([]byte(key))
+	i := sort.Search(len(r.points), func(i int) bool {
+		return r.points[i] >= h
+	})
+	if i == len(r.points) {
+		i = 0
+	}
+	return r.nodes[r.points[i]]
+}
+
+// getRing returns the ring of the nodes, built once per set of nodes
+func getRing(nodes []*registry.Node) *hashRing {
+	ids := make([]string, 0, len(nodes))
+	for _, node := range nodes {
+		ids = append(ids, node.Id+"="+node.Address)
+	}
+	sort.Strings(ids)
+	key := strings.Join(ids, ",")
+
+	rings.Lock()
+	defer rings.Unlock()
+
+	if r, ok := rings.rings[key]; ok {
+		return r
+	}
+
+	if len(rings.rings) >= maxRings {
+		rings.rings = make(map[string]*hashRing)
+	}
+
+	r := newRing(nodes)
+	rings.rings[key] = r
+
+	return r
+}
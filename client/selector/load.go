@@ -0,0 +1,54 @@
+package selector
+
+import (
+	"sync"
+
+	"github.com/stack-labs/stack/registry"
+)
+
+var (
+	// DefaultLoad tracks the calls of the LeastRequest strategy by default
+	DefaultLoad = NewLoad()
+)
+
+// Load counts the calls in progress per node
+type Load struct {
+	sync.RWMutex
+	inFlight map[string]int64
+}
+
+// NewLoad returns a new load tracker
+func NewLoad() *Load {
+	return &Load{
+		inFlight: make(map[string]int64),
+	}
+}
+
+// Start records a call to the node, the returned func must be called once it's done
+func (l *Load) Start(node *registry.Node) func() {
+	l.Lock()
+	l.inFlight[node.Id]++
+	l.Unlock()
+
+	var once sync.Once
+
+	return func() {
+		once.Do(func() {
+			l.Lock()
+			defer l.Unlock()
+
+			if l.inFlight[node.Id] <= 1 {
+				delete(l.inFlight, node.Id)
+				return
+			}
+			l.inFlight[node.Id]--
+		})
+	}
+}
+
+// InFlight returns the number of calls in progress to the node
+func (l *Load) InFlight(node *registry.Node) int64 {
+	l.RLock()
+	defer l.RUnlock()
+	return l.inFlight[node.Id]
+}
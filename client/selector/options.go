@@ -19,6 +19,8 @@ type Options struct {
 type SelectOptions struct {
 	Filters  []Filter
 	Strategy Strategy
+	// Domain to select the nodes from, the registry default when blank
+	Domain string
 
 	// Other options for implementations of the interface
 	// can be stored in a context
@@ -65,3 +67,10 @@ func WithStrategy(fn Strategy) SelectOption {
 		o.Strategy = fn
 	}
 }
+
+// WithDomain selects the nodes of the service from a registry domain
+func WithDomain(d string) SelectOption {
+	return func(o *SelectOptions) {
+		o.Domain = d
+	}
+}
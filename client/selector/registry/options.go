@@ -16,3 +16,48 @@ func TTL(t time.Duration) selector.Option {
 		o.Context = context.WithValue(o.Context, "selector_ttl", t)
 	}
 }
+
+// MaxFailures sets the number of consecutive failures after which a node is ejected.
+// A value of 0 disables consecutive failure ejection.
+func MaxFailures(n int) selector.Option {
+	return func(o *selector.Options) {
+		if o.Context == nil {
+			o.Context = context.Background()
+		}
+		o.Context = context.WithValue(o.Context, "selector_max_failures", n)
+	}
+}
+
+// FailureRate sets the error rate within a window at which a node is ejected
+// once at least min requests were made. A rate of 0 disables rate based ejection.
+func FailureRate(rate float64, min int) selector.Option {
+	return func(o *selector.Options) {
+		if o.Context == nil {
+			o.Context = context.Background()
+		}
+		o.Context = context.WithValue(o.Context, "selector_failure_rate", rate)
+		o.Context = context.WithValue(o.Context, "selector_min_requests", min)
+	}
+}
+
+// FailureWindow sets the period over which node request and failure counts are kept
+func FailureWindow(t time.Duration) selector.Option {
+	return func(o *selector.Options) {
+		if o.Context == nil {
+			o.Context = context.Background()
+		}
+		o.Context = context.WithValue(o.Context, "selector_failure_window", t)
+	}
+}
+
+// EjectTime sets the base and max period a node is ejected for. The period
+// doubles every time a node is ejected again after a failed probe.
+func EjectTime(base, max time.Duration) selector.Option {
+	return func(o *selector.Options) {
+		if o.Context == nil {
+			o.Context = context.Background()
+		}
+		o.Context = context.WithValue(o.Context, "selector_eject_time", base)
+		o.Context = context.WithValue(o.Context, "selector_max_eject_time", max)
+	}
+}
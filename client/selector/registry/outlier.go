@@ -0,0 +1,284 @@
+package registry
+
+import (
+	"sync"
+	"time"
+
+	"github.com/stack-labs/stack/client/breaker"
+	"github.com/stack-labs/stack/registry"
+	"github.com/stack-labs/stack/util/errors"
+)
+
+var (
+	// DefaultMaxFailures is the number of consecutive failures before a node is ejected
+	DefaultMaxFailures = 5
+	// DefaultFailureRate is the error rate within a window at which a node is ejected
+	DefaultFailureRate = 0.5
+	// DefaultMinRequests is the number of requests required in a window before the failure rate is checked
+	DefaultMinRequests = 10
+	// DefaultWindow is the period over which request and failure counts are kept
+	DefaultWindow = time.Second * 10
+	// DefaultEjectTime is the base ejection period, doubled for every repeated ejection
+	DefaultEjectTime = time.Second * 30
+	// DefaultMaxEjectTime caps the ejection period
+	DefaultMaxEjectTime = time.Minute * 5
+)
+
+type nodeState int
+
+const (
+	nodeHealthy nodeState = iota
+	nodeEjected
+	nodeHalfOpen
+)
+
+type outlierOptions struct {
+	MaxFailures  int
+	FailureRate  float64
+	MinRequests  int
+	Window       time.Duration
+	EjectTime    time.Duration
+	MaxEjectTime time.Duration
+}
+
+type nodeStats struct {
+	state nodeState
+	// counters for the current window
+	requests    int
+	failures    int
+	windowStart time.Time
+	// consecutive failures
+	consecutive int
+	// number of times the node was ejected in a row
+	ejections  int
+	ejectUntil time.Time
+	// time the probe request was sent while half open
+	probeAt time.Time
+}
+
+// outlier tracks the error rates of nodes reported through Mark
+// and ejects nodes which cross the failure threshold for a backoff
+// period after which a single probe request is let through.
+type outlier struct {
+	opts outlierOptions
+
+	sync.Mutex
+	// service -> node id -> stats
+	nodes map[string]map[string]*nodeStats
+}
+
+// isFailure reports whether the error is a node failure. Errors
+// returned by the remote handler such as a 400 or 404 don't count.
+func isFailure(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	e, ok := err.(*errors.Error)
+	if !ok {
+		e = errors.Parse(err.Error())
+	}
+
+	// an open circuit of the client breaker says nothing of the node
+	if e.Id == breaker.ErrorId {
+		return false
+	}
+
+	// not a stack error e.g a connection error
+	if e.Code == 0 {
+		return true
+	}
+
+	return e.Code == 408 || e.Code >= 500
+}
+
+func (o *outlier) ejectTime(ejections int) time.Duration {
+	d := o.opts.EjectTime
+	for i := 1; i < ejections; i++ {
+		d *= 2
+		if d >= o.opts.MaxEjectTime {
+			return o.opts.MaxEjectTime
+		}
+	}
+	return d
+}
+
+func (o *outlier) eject(s *nodeStats, now time.Time) {
+	s.ejections++
+	s.state = nodeEjected
+	s.probeAt = time.Time{}
+	s.ejectUntil = now.Add(o.ejectTime(s.ejections))
+	s.consecutive = 0
+	s.requests = 0
+	s.failures = 0
+	s.windowStart = now
+}
+
+// Mark records the result of a call against a node
+func (o *outlier) Mark(service string, node *registry.Node, err error) {
+	if node == nil {
+		return
+	}
+
+	o.Lock()
+	defer o.Unlock()
+
+	nodes, ok := o.nodes[service]
+	if !ok {
+		nodes = make(map[string]*nodeStats)
+		o.nodes[service] = nodes
+	}
+
+	now := time.Now()
+
+	s, ok := nodes[node.Id]
+	if !ok {
+		s = &nodeStats{windowStart: now}
+		nodes[node.Id] = s
+	}
+
+	// roll the window
+	if now.Sub(s.windowStart) > o.opts.Window {
+		s.requests = 0
+		s.failures = 0
+		s.windowStart = now
+	}
+
+	failed := isFailure(err)
+
+	switch s.state {
+	case nodeHalfOpen:
+		// the probe decides the fate of the node
+		if failed {
+			o.eject(s, now)
+			return
+		}
+		s.state = nodeHealthy
+		s.probeAt = time.Time{}
+		s.ejections = 0
+		s.consecutive = 0
+		s.requests = 1
+		s.failures = 0
+		s.windowStart = now
+		return
+	case nodeEjected:
+		// results of calls made before the ejection
+		return
+	}
+
+	s.requests++
+
+	if !failed {
+		s.consecutive = 0
+		return
+	}
+
+	s.failures++
+	s.consecutive++
+
+	if o.opts.MaxFailures > 0 && s.consecutive >= o.opts.MaxFailures {
+		o.eject(s, now)
+		return
+	}
+
+	if o.opts.FailureRate > 0 && s.requests >= o.opts.MinRequests &&
+		float64(s.failures)/float64(s.requests) >= o.opts.FailureRate {
+		o.eject(s, now)
+	}
+}
+
+// Reset clears the state of all nodes of a service
+func (o *outlier) Reset(service string) {
+	o.Lock()
+	delete(o.nodes, service)
+	o.Unlock()
+}
+
+// Filter removes ejected nodes from the list of services. Nodes whose
+// ejection has expired are moved to half open and let through once.
+// If every node would be removed the list is returned as is.
+func (o *outlier) Filter(name string) func([]*registry.Service) []*registry.Service {
+	return func(old []*registry.Service) []*registry.Service {
+		o.Lock()
+		defer o.Unlock()
+
+		nodes, ok := o.nodes[name]
+		if !ok || len(nodes) == 0 {
+			return old
+		}
+
+		now := time.Now()
+
+		var count int
+		var services []*registry.Service
+
+		for _, service := range old {
+			var available []*registry.Node
+
+			for _, node := range service.Nodes {
+				s, ok := nodes[node.Id]
+				if !ok {
+					available = append(available, node)
+					continue
+				}
+
+				switch s.state {
+				case nodeEjected:
+					if now.Before(s.ejectUntil) {
+						continue
+					}
+					s.state = nodeHalfOpen
+					s.probeAt = time.Time{}
+					fallthrough
+				case nodeHalfOpen:
+					// only allow a single probe at a time unless
+					// the previous one was never reported back
+					if !s.probeAt.IsZero() && now.Sub(s.probeAt) < o.opts.Window {
+						continue
+					}
+				}
+
+				available = append(available, node)
+			}
+
+			if len(available) == 0 {
+				continue
+			}
+
+			serv := new(registry.Service)
+			*serv = *service
+			serv.Nodes = available
+			services = append(services, serv)
+			count += len(available)
+		}
+
+		// never eject every node
+		if count == 0 {
+			return old
+		}
+
+		return services
+	}
+}
+
+// Selected is called with the node picked by the strategy so that
+// a half open node only receives a single probe.
+func (o *outlier) Selected(service string, node *registry.Node) {
+	if node == nil {
+		return
+	}
+
+	o.Lock()
+	defer o.Unlock()
+
+	if s, ok := o.nodes[service][node.Id]; ok && s.state == nodeHalfOpen {
+		s.probeAt = time.Now()
+	}
+}
+
+func newOutlier(opts outlierOptions) *outlier {
+	return &outlier{
+		opts:  opts,
+		nodes: make(map[string]map[string]*nodeStats),
+	}
+}
@@ -11,6 +11,7 @@ import (
 type registrySelector struct {
 	opts selector.Options
 	rc   cache.Cache
+	od   *outlier
 }
 
 func (c *registrySelector) newCache() cache.Cache {
@@ -23,6 +24,44 @@ func (c *registrySelector) newCache() cache.Cache {
 	return cache.New(c.opts.Registry, opts...)
 }
 
+func (c *registrySelector) newOutlier() *outlier {
+	opts := outlierOptions{
+		MaxFailures:  DefaultMaxFailures,
+		FailureRate:  DefaultFailureRate,
+		MinRequests:  DefaultMinRequests,
+		Window:       DefaultWindow,
+		EjectTime:    DefaultEjectTime,
+		MaxEjectTime: DefaultMaxEjectTime,
+	}
+
+	if ctx := c.opts.Context; ctx != nil {
+		if n, ok := ctx.Value("selector_max_failures").(int); ok {
+			opts.MaxFailures = n
+		}
+		if r, ok := ctx.Value("selector_failure_rate").(float64); ok {
+			opts.FailureRate = r
+		}
+		if n, ok := ctx.Value("selector_min_requests").(int); ok {
+			opts.MinRequests = n
+		}
+		if t, ok := ctx.Value("selector_failure_window").(time.Duration); ok && t > 0 {
+			opts.Window = t
+		}
+		if t, ok := ctx.Value("selector_eject_time").(time.Duration); ok && t > 0 {
+			opts.EjectTime = t
+		}
+		if t, ok := ctx.Value("selector_max_eject_time").(time.Duration); ok && t > 0 {
+			opts.MaxEjectTime = t
+		}
+	}
+
+	if opts.MaxEjectTime < opts.EjectTime {
+		opts.MaxEjectTime = opts.EjectTime
+	}
+
+	return newOutlier(opts)
+}
+
 func (c *registrySelector) Init(opts ...selector.Option) error {
 	for _, o := range opts {
 		o(&c.opts)
@@ -30,6 +69,7 @@ func (c *registrySelector) Init(opts ...selector.Option) error {
 
 	c.rc.Stop()
 	c.rc = c.newCache()
+	c.od = c.newOutlier()
 
 	return nil
 }
@@ -50,7 +90,7 @@ func (c *registrySelector) Next(service string, opts ...selector.SelectOption) (
 	// get the service
 	// try the cache first
 	// if that fails go directly to the registry
-	services, err := c.rc.GetService(service)
+	services, err := c.rc.GetService(service, registry.GetDomain(sopts.Domain))
 	if err != nil {
 		if err == registry.ErrNotFound {
 			return nil, selector.ErrNotFound
@@ -63,18 +103,33 @@ func (c *registrySelector) Next(service string, opts ...selector.SelectOption) (
 		services = filter(services)
 	}
 
+	// remove the ejected nodes
+	services = c.od.Filter(service)(services)
+
 	// if there's nothing left, return
 	if len(services) == 0 {
 		return nil, selector.ErrNoneAvailable
 	}
 
-	return sopts.Strategy(services)
+	node, err := sopts.Strategy(services)
+	if err != nil {
+		return nil, err
+	}
+
+	c.od.Selected(service, node)
+
+	return node, nil
 }
 
+// Mark records the result of a call to a node. Nodes which keep
+// failing are ejected from selection for a backoff period.
 func (c *registrySelector) Mark(service string, node *registry.Node, err error) {
+	c.od.Mark(service, node, err)
 }
 
+// Reset brings all the ejected nodes of a service back into rotation
 func (c *registrySelector) Reset(service string) {
+	c.od.Reset(service)
 }
 
 // Close stops the watcher and destroys the cache
@@ -101,6 +156,7 @@ func NewSelector(opts ...selector.Option) selector.Selector {
 		opts: sopts,
 	}
 	s.rc = s.newCache()
+	s.od = s.newOutlier()
 
 	return s
 }
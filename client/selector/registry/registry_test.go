@@ -2,11 +2,14 @@ package registry
 
 import (
 	"testing"
+	"time"
 
+	"github.com/stack-labs/stack/client/breaker"
 	"github.com/stack-labs/stack/client/selector"
 	"github.com/stack-labs/stack/registry"
 
 	"github.com/stack-labs/stack/registry/memory"
+	"github.com/stack-labs/stack/util/errors"
 )
 
 var (
@@ -67,3 +70,94 @@ func TestRegistrySelector(t *testing.T) {
 
 	t.Logf("Selector Counts %v", counts)
 }
+
+func TestRegistrySelectorEject(t *testing.T) {
+	r := memory.NewRegistry(memory.Services(testData))
+	sel := NewSelector(
+		selector.Registry(r),
+		MaxFailures(2),
+		EjectTime(time.Millisecond*50, time.Second),
+	)
+
+	bad := &registry.Node{Id: "foo-1.0.0-123"}
+	for i := 0; i < 2; i++ {
+		sel.Mark("foo", bad, errors.InternalServerError("foo", "error"))
+	}
+
+	for i := 0; i < 100; i++ {
+		node, err := sel.Next("foo")
+		if err != nil {
+			t.Fatalf("Expected node, got err: %v", err)
+		}
+		if node.Id == bad.Id {
+			t.Fatalf("Expected node %s to be ejected", bad.Id)
+		}
+	}
+
+	// wait for the node to become half open
+	time.Sleep(time.Millisecond * 60)
+
+	var probes int
+	for i := 0; i < 100; i++ {
+		node, err := sel.Next("foo")
+		if err != nil {
+			t.Fatalf("Expected node, got err: %v", err)
+		}
+		if node.Id == bad.Id {
+			probes++
+		}
+	}
+	if probes != 1 {
+		t.Fatalf("Expected a single probe to %s, got %d", bad.Id, probes)
+	}
+
+	// a successful probe brings the node back
+	sel.Mark("foo", bad, nil)
+
+	var seen bool
+	for i := 0; i < 100; i++ {
+		node, _ := sel.Next("foo")
+		if node.Id == bad.Id {
+			seen = true
+			break
+		}
+	}
+	if !seen {
+		t.Fatalf("Expected node %s to be back in rotation", bad.Id)
+	}
+}
+
+func TestRegistrySelectorIgnoreClientErrors(t *testing.T) {
+	r := memory.NewRegistry(memory.Services(testData))
+	sel := NewSelector(selector.Registry(r), MaxFailures(1))
+
+	node := &registry.Node{Id: "foo-1.0.1-321"}
+	sel.Mark("foo", node, errors.BadRequest("foo", "bad request"))
+	// an open circuit of the client breaker
+	sel.Mark("foo", node, errors.ServiceUnavailable(breaker.ErrorId, "circuit foo.Bar is open"))
+
+	node, err := sel.Next("foo", selector.WithFilter(selector.FilterVersion("1.0.1")))
+	if err != nil {
+		t.Fatalf("Expected node, got err: %v", err)
+	}
+	if node.Id != "foo-1.0.1-321" {
+		t.Fatalf("Expected node foo-1.0.1-321, got %s", node.Id)
+	}
+}
+
+func TestRegistrySelectorNeverEjectAll(t *testing.T) {
+	r := memory.NewRegistry(memory.Services(testData))
+	sel := NewSelector(selector.Registry(r), MaxFailures(1))
+
+	for _, service := range testData["foo"] {
+		for _, node := range service.Nodes {
+			sel.Mark("foo", node, errors.InternalServerError("foo", "error"))
+		}
+	}
+
+	if _, err := sel.Next("foo"); err != nil {
+		t.Fatalf("Expected node, got err: %v", err)
+	}
+
+	sel.Reset("foo")
+}
@@ -2,12 +2,20 @@ package selector
 
 import (
 	"math/rand"
+	"strconv"
 	"sync"
 	"time"
 
 	"github.com/stack-labs/stack/registry"
 )
 
+var (
+	// WeightKey is the node metadata key of the weight used by the Weighted strategy
+	WeightKey = "weight"
+	// DefaultWeight of a node without a weight
+	DefaultWeight = 100
+)
+
 func init() {
 	rand.Seed(time.Now().UnixNano())
 }
@@ -56,3 +64,113 @@ func RoundRobin() Strategy {
 		return nodes[i%len(nodes)], nil
 	}
 }
+
+// Weighted is a random strategy favouring the nodes with a higher weight
+// set in the node metadata, nodes without a valid weight get DefaultWeight
+func Weighted() Strategy {
+	return func(services []*registry.Service) (*registry.Node, error) {
+		nodes := make([]*registry.Node, 0, len(services))
+		weights := make([]int, 0, len(services))
+		total := 0
+
+		for _, service := range services {
+			for _, node := range service.Nodes {
+				w := nodeWeight(node)
+				nodes = append(nodes, node)
+				weights = append(weights, w)
+				total += w
+			}
+		}
+
+		if len(nodes) == 0 {
+			return nil, ErrNoneAvailable
+		}
+
+		if total <= 0 {
+			return nodes[rand.Int()%len(nodes)], nil
+		}
+
+		n := rand.Intn(total)
+		for i, w := range weights {
+			if n < w {
+				return nodes[i], nil
+			}
+			n -= w
+		}
+
+		return nodes[len(nodes)-1], nil
+	}
+}
+
+// LeastRequest is a strategy selecting the node with the fewest calls in
+// progress. The calls must be tracked with the load, DefaultLoad is used if nil.
+func LeastRequest(load *Load) Strategy {
+	if load == nil {
+		load = DefaultLoad
+	}
+
+	return func(services []*registry.Service) (*registry.Node, error) {
+		var least []*registry.Node
+		min := int64(-1)
+
+		for _, service := range services {
+			for _, node := range service.Nodes {
+				n := load.InFlight(node)
+				switch {
+				case min < 0 || n < min:
+					min = n
+					least = append(least[:0], node)
+				case n == min:
+					least = append(least, node)
+				}
+			}
+		}
+
+		if len(least) == 0 {
+			return nil, ErrNoneAvailable
+		}
+
+		return least[rand.Int()%len(least)], nil
+	}
+}
+
+// ConsistentHash is a strategy for sticky routing which always selects
+// the same node for a key while the node is available. An empty key
+// selects a random node.
+func ConsistentHash(key string) Strategy {
+	return func(services []*registry.Service) (*registry.Node, error) {
+		nodes := make([]*registry.Node, 0, len(services))
+
+		for _, service := range services {
+			nodes = append(nodes, service.Nodes...)
+		}
+
+		if len(nodes) == 0 {
+			return nil, ErrNoneAvailable
+		}
+
+		if len(key) == 0 {
+			return nodes[rand.Int()%len(nodes)], nil
+		}
+
+		return getRing(nodes).get(key), nil
+	}
+}
+
+func nodeWeight(node *registry.Node) int {
+	if node.Metadata == nil {
+		return DefaultWeight
+	}
+
+	v, ok := node.Metadata[WeightKey]
+	if !ok {
+		return DefaultWeight
+	}
+
+	w, err := strconv.Atoi(v)
+	if err != nil || w < 0 {
+		return DefaultWeight
+	}
+
+	return w
+}
@@ -1,6 +1,7 @@
 package selector
 
 import (
+	"fmt"
 	"testing"
 
 	"github.com/stack-labs/stack/registry"
@@ -53,3 +54,107 @@ func TestStrategies(t *testing.T) {
 		t.Logf("%s: %+v\n", name, counts)
 	}
 }
+
+func TestWeighted(t *testing.T) {
+	testData := []*registry.Service{
+		{
+			Name: "test1",
+			Nodes: []*registry.Node{
+				{Id: "test1-1", Metadata: map[string]string{"weight": "0"}},
+				{Id: "test1-2", Metadata: map[string]string{"weight": "10"}},
+			},
+		},
+	}
+
+	strategy := Weighted()
+
+	for i := 0; i < 100; i++ {
+		node, err := strategy(testData)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if node.Id != "test1-2" {
+			t.Fatalf("Expected node with zero weight not to be selected, got %s", node.Id)
+		}
+	}
+}
+
+func TestLeastRequest(t *testing.T) {
+	testData := []*registry.Service{
+		{
+			Name: "test1",
+			Nodes: []*registry.Node{
+				{Id: "test1-1"},
+				{Id: "test1-2"},
+			},
+		},
+	}
+
+	load := NewLoad()
+	done := load.Start(testData[0].Nodes[0])
+
+	strategy := LeastRequest(load)
+
+	for i := 0; i < 10; i++ {
+		node, err := strategy(testData)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if node.Id != "test1-2" {
+			t.Fatalf("Expected the least loaded node, got %s", node.Id)
+		}
+	}
+
+	done()
+	done()
+
+	if n := load.InFlight(testData[0].Nodes[0]); n != 0 {
+		t.Fatalf("Expected no calls in flight, got %d", n)
+	}
+}
+
+func TestConsistentHash(t *testing.T) {
+	nodes := []*registry.Node{
+		{Id: "test1-1", Address: "10.0.0.1:1001"},
+		{Id: "test1-2", Address: "10.0.0.2:1002"},
+		{Id: "test1-3", Address: "10.0.0.3:1003"},
+	}
+	testData := []*registry.Service{{Name: "test1", Nodes: nodes}}
+
+	selected := make(map[string]string)
+
+	for i := 0; i < 100; i++ {
+		key := fmt.Sprintf("key-%d", i)
+
+		node, err := ConsistentHash(key)(testData)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		again, err := ConsistentHash(key)(testData)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if node.Id != again.Id {
+			t.Fatalf("Expected key %s to select %s, got %s", key, node.Id, again.Id)
+		}
+		selected[key] = node.Id
+	}
+
+	// removing a node only moves its own keys
+	testData[0].Nodes = nodes[:2]
+
+	for key, id := range selected {
+		if id == "test1-3" {
+			continue
+		}
+		node, err := ConsistentHash(key)(testData)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if node.Id != id {
+			t.Fatalf("Expected key %s to stay on %s, got %s", key, id, node.Id)
+		}
+	}
+}
@@ -0,0 +1,243 @@
+// Package compress provides the compressors of message bodies. The servers
+// advertise the encodings they decompress in the metadata of their node, the
+// client compresses a request to a server advertising its encoding above a
+// size threshold and sets the Content-Encoding header. The Accept-Encoding
+// header tells a server enabling compression how it may compress the
+// response. The compressors are registered with grpc as well
+package compress
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"io/ioutil"
+	"sort"
+	"strings"
+	"sync"
+
+	"google.golang.org/grpc/encoding"
+)
+
+const (
+	// ContentEncoding is the header of the compressor of a message body
+	ContentEncoding = "Content-Encoding"
+	// AcceptEncoding is the header of the compressors a client accepts
+	AcceptEncoding = "Accept-Encoding"
+	// MetadataKey is the node metadata of the encodings a server decompresses,
+	// the clients only compress the request bodies sent to the servers advertising it
+	MetadataKey = "accept-encoding"
+)
+
+var (
+	// DefaultThreshold is the smallest body in bytes which is compressed
+	DefaultThreshold = 1024
+	// DefaultMaxSize is the largest body in bytes which is decompressed, it
+	// also limits the messages decompressed by grpc
+	DefaultMaxSize int64 = 32 << 20
+
+	// ErrTooLarge is returned when a decompressed body is above the max size
+	ErrTooLarge = errors.New("decompressed body too large")
+
+	mtx         sync.RWMutex
+	compressors = map[string]Compressor{}
+)
+
+// Compressor compresses and decompresses message bodies, it is the same as
+// the grpc encoding.Compressor
+type Compressor interface {
+	// Compress writes the data written to the returned writer to w
+	// compressed, the data is flushed when the writer is closed
+	Compress(w io.Writer) (io.WriteCloser, error)
+	// Decompress reads data from r and decompresses it
+	Decompress(r io.Reader) (io.Reader, error)
+	// Name is the content encoding of the compressor, e.g gzip
+	Name() string
+}
+
+// Register a compressor, it is registered with grpc as well and replaces a
+// native grpc compressor of the same name so that grpc messages are limited
+// to DefaultMaxSize too. Register is not thread safe and should be called in
+// an init func
+func Register(c Compressor) {
+	mtx.Lock()
+	compressors[c.Name()] = c
+	mtx.Unlock()
+
+	encoding.RegisterCompressor(&limitCompressor{c})
+}
+
+// limitCompressor limits the messages grpc decompresses to DefaultMaxSize
+type limitCompressor struct {
+	Compressor
+}
+
+func (l *limitCompressor) Decompress(r io.Reader) (io.Reader, error) {
+	d, err := l.Compressor.Decompress(r)
+	if err != nil {
+		return nil, err
+	}
+	return Limit(d, 0), nil
+}
+
+// limitReader fails with ErrTooLarge once more than n bytes are read
+type limitReader struct {
+	r io.Reader
+	n int64
+}
+
+func (l *limitReader) Read(p []byte) (int, error) {
+	if l.n < 0 {
+		return 0, ErrTooLarge
+	}
+	n, err := l.r.Read(p)
+	l.n -= int64(n)
+	if l.n < 0 {
+		return n, ErrTooLarge
+	}
+	return n, err
+}
+
+// Limit returns a reader of r failing with ErrTooLarge after max bytes, a
+// max of zero uses DefaultMaxSize
+func Limit(r io.Reader, max int64) io.Reader {
+	if max <= 0 {
+		max = DefaultMaxSize
+	}
+	return &limitReader{r: io.LimitReader(r, max+1), n: max}
+}
+
+// Get returns the compressor of the content encoding
+func Get(name string) (Compressor, bool) {
+	mtx.RLock()
+	defer mtx.RUnlock()
+	c, ok := compressors[name]
+	return c, ok
+}
+
+// Names returns the content encodings of the registered compressors
+func Names() []string {
+	mtx.RLock()
+	defer mtx.RUnlock()
+
+	names := make([]string, 0, len(compressors))
+	for name := range compressors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Advertise returns the node metadata value of the registered compressors
+func Advertise() string {
+	return strings.Join(Names(), ",")
+}
+
+// Accepts reports whether the node metadata advertises the content encoding
+func Accepts(md map[string]string, name string) bool {
+	if len(name) == 0 {
+		return false
+	}
+	for _, n := range strings.Split(md[MetadataKey], ",") {
+		if strings.TrimSpace(n) == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Negotiate returns the first registered compressor of an Accept-Encoding
+// header such as "zstd, gzip;q=0.5"
+func Negotiate(accept string) (Compressor, bool) {
+	for _, name := range strings.Split(accept, ",") {
+		if i := strings.Index(name, ";"); i >= 0 {
+			name = name[:i]
+		}
+		if c, ok := Get(strings.TrimSpace(name)); ok {
+			return c, true
+		}
+	}
+	return nil, false
+}
+
+// Threshold returns the threshold to compress with, DefaultThreshold when zero
+func Threshold(t int) int {
+	if t == 0 {
+		return DefaultThreshold
+	}
+	return t
+}
+
+// Encode compresses b
+func Encode(c Compressor, b []byte) ([]byte, error) {
+	buf := bytes.NewBuffer(make([]byte, 0, len(b)/2))
+
+	w, err := c.Compress(buf)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(b); err != nil {
+		w.Close()
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// Decode decompresses b, failing with ErrTooLarge above max bytes. A max of
+// zero uses DefaultMaxSize
+func Decode(c Compressor, b []byte, max int64) ([]byte, error) {
+	r, err := c.Decompress(bytes.NewReader(b))
+	if err != nil {
+		return nil, err
+	}
+	return ioutil.ReadAll(Limit(r, max))
+}
+
+// Message compresses a message body with the compressor accepted when it is
+// at least threshold bytes, a negative threshold disables the compression.
+// It returns the body and its content encoding, empty when not compressed
+func Message(accept string, threshold int, b []byte) ([]byte, string, error) {
+	threshold = Threshold(threshold)
+	if threshold < 0 || len(b) < threshold || len(accept) == 0 {
+		return b, "", nil
+	}
+
+	c, ok := Negotiate(accept)
+	if !ok {
+		return b, "", nil
+	}
+
+	cb, err := Encode(c, b)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return cb, c.Name(), nil
+}
+
+// Body decompresses a message body of the content encoding, failing with
+// ErrTooLarge above max bytes. A max of zero uses DefaultMaxSize
+func Body(encoding string, b []byte, max int64) ([]byte, error) {
+	if len(encoding) == 0 || encoding == "identity" {
+		return b, nil
+	}
+
+	c, ok := Get(encoding)
+	if !ok {
+		return nil, &UnsupportedError{Encoding: encoding}
+	}
+
+	return Decode(c, b, max)
+}
+
+// UnsupportedError is returned for a content encoding with no compressor
+type UnsupportedError struct {
+	Encoding string
+}
+
+func (e *UnsupportedError) Error() string {
+	return "unsupported content encoding " + e.Encoding
+}
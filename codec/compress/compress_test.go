@@ -0,0 +1,151 @@
+package compress
+
+import (
+	"bytes"
+	"testing"
+
+	"google.golang.org/grpc/encoding"
+)
+
+func TestCompressors(t *testing.T) {
+	body := bytes.Repeat([]byte("stack "), 1024)
+
+	for _, name := range []string{Gzip, Snappy} {
+		c, ok := Get(name)
+		if !ok {
+			t.Fatalf("Expected the %s compressor", name)
+		}
+
+		// twice to reuse the pooled writers
+		for i := 0; i < 2; i++ {
+			b, err := Encode(c, body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(b) >= len(body) {
+				t.Fatalf("Expected %s to compress the body", name)
+			}
+
+			d, err := Body(name, b, 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(d, body) {
+				t.Fatalf("Unexpected %s body", name)
+			}
+		}
+
+		if encoding.GetCompressor(name) == nil {
+			t.Fatalf("Expected %s to be registered with grpc", name)
+		}
+	}
+}
+
+func TestNegotiate(t *testing.T) {
+	testData := []struct {
+		accept string
+		name   string
+	}{
+		{"gzip", Gzip},
+		{"br, snappy;q=0.5, gzip", Snappy},
+		{" gzip ;q=1", Gzip},
+		{"br", ""},
+		{"", ""},
+	}
+
+	for _, d := range testData {
+		c, ok := Negotiate(d.accept)
+		if len(d.name) == 0 {
+			if ok {
+				t.Fatalf("Expected no compressor for %q got %s", d.accept, c.Name())
+			}
+			continue
+		}
+		if !ok || c.Name() != d.name {
+			t.Fatalf("Expected %s for %q", d.name, d.accept)
+		}
+	}
+}
+
+func TestAccepts(t *testing.T) {
+	md := map[string]string{MetadataKey: Advertise()}
+
+	testData := []struct {
+		md     map[string]string
+		name   string
+		accept bool
+	}{
+		{md, Gzip, true},
+		{md, Snappy, true},
+		{md, "br", false},
+		{md, "", false},
+		{map[string]string{MetadataKey: "br, gzip"}, Gzip, true},
+		{nil, Gzip, false},
+	}
+
+	for _, d := range testData {
+		if a := Accepts(d.md, d.name); a != d.accept {
+			t.Fatalf("Expected %v to accept %q %v", d.md, d.name, d.accept)
+		}
+	}
+}
+
+func TestMessage(t *testing.T) {
+	small := []byte("small")
+	large := bytes.Repeat([]byte("a"), DefaultThreshold)
+
+	testData := []struct {
+		accept    string
+		threshold int
+		body      []byte
+		encoding  string
+	}{
+		{Gzip, 0, small, ""},
+		{Gzip, 0, large, Gzip},
+		{Gzip, 1, small, Gzip},
+		{Gzip, -1, large, ""},
+		{"", 0, large, ""},
+		{"br", 0, large, ""},
+	}
+
+	for _, d := range testData {
+		b, enc, err := Message(d.accept, d.threshold, d.body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if enc != d.encoding {
+			t.Fatalf("Expected encoding %q got %q", d.encoding, enc)
+		}
+
+		b, err = Body(enc, b, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(b, d.body) {
+			t.Fatal("Unexpected body")
+		}
+	}
+
+	if _, err := Body("br", small, 0); err == nil {
+		t.Fatal("Expected an unsupported encoding")
+	}
+}
+
+func TestMaxSize(t *testing.T) {
+	body := bytes.Repeat([]byte("a"), 4096)
+
+	for _, name := range []string{Gzip, Snappy} {
+		c, _ := Get(name)
+		b, err := Encode(c, body)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := Body(name, b, int64(len(body))); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := Body(name, b, int64(len(body)-1)); err != ErrTooLarge {
+			t.Fatalf("Expected %s to be too large got %v", name, err)
+		}
+	}
+}
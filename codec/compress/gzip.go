@@ -0,0 +1,45 @@
+package compress
+
+import (
+	"compress/gzip"
+	"io"
+	"sync"
+)
+
+// Gzip is the content encoding of gzip
+const Gzip = "gzip"
+
+type gzipCompressor struct {
+	pool sync.Pool
+}
+
+type gzipWriter struct {
+	*gzip.Writer
+	pool *sync.Pool
+}
+
+func (w *gzipWriter) Close() error {
+	defer w.pool.Put(w)
+	return w.Writer.Close()
+}
+
+func (g *gzipCompressor) Compress(w io.Writer) (io.WriteCloser, error) {
+	z, ok := g.pool.Get().(*gzipWriter)
+	if !ok {
+		return &gzipWriter{Writer: gzip.NewWriter(w), pool: &g.pool}, nil
+	}
+	z.Reset(w)
+	return z, nil
+}
+
+func (g *gzipCompressor) Decompress(r io.Reader) (io.Reader, error) {
+	return gzip.NewReader(r)
+}
+
+func (g *gzipCompressor) Name() string {
+	return Gzip
+}
+
+func init() {
+	Register(&gzipCompressor{})
+}
@@ -0,0 +1,28 @@
+package compress
+
+import (
+	"io"
+
+	"github.com/golang/snappy"
+)
+
+// Snappy is the content encoding of the snappy framing format
+const Snappy = "snappy"
+
+type snappyCompressor struct{}
+
+func (s *snappyCompressor) Compress(w io.Writer) (io.WriteCloser, error) {
+	return snappy.NewBufferedWriter(w), nil
+}
+
+func (s *snappyCompressor) Decompress(r io.Reader) (io.Reader, error) {
+	return snappy.NewReader(r), nil
+}
+
+func (s *snappyCompressor) Name() string {
+	return Snappy
+}
+
+func init() {
+	Register(&snappyCompressor{})
+}
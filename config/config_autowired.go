@@ -62,6 +62,12 @@ func bindAutowiredValue(obj reflect.Value, path ...string) {
 			log.Errorf("bindAutowiredValue can't assign value due to %s-overflow", v.Kind())
 		}
 		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		n := value.Float64(0)
+		if v.OverflowFloat(n) {
+			log.Errorf("bindAutowiredValue can't assign value due to %s-overflow", v.Kind())
+		}
+		v.SetFloat(n)
 	case reflect.String:
 		valTmp := value.String("")
 		if len(valTmp) == 0 {
@@ -3,6 +3,7 @@ package handler
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"github.com/stack-labs/stack/client"
@@ -11,6 +12,7 @@ import (
 	"github.com/stack-labs/stack/debug/stats"
 	"github.com/stack-labs/stack/debug/trace"
 	"github.com/stack-labs/stack/server"
+	"github.com/stack-labs/stack/sync"
 )
 
 // NewHandler returns an instance of the Debug Handler
@@ -39,6 +41,16 @@ func (d *Debug) Health(ctx context.Context, req *proto.HealthRequest, rsp *proto
 }
 
 func (d *Debug) Stats(ctx context.Context, req *proto.StatsRequest, rsp *proto.StatsResponse) error {
+	for _, t := range stats.ReadTransitions(0) {
+		rsp.Transitions = append(rsp.Transitions, &proto.Transition{
+			Timestamp: t.Timestamp,
+			Component: t.Component,
+			Name:      t.Name,
+			From:      t.From,
+			To:        t.To,
+		})
+	}
+
 	stats, err := d.stats.Read()
 	if err != nil {
 		return err
@@ -74,7 +86,26 @@ func (d *Debug) Trace(ctx context.Context, req *proto.TraceRequest, rsp *proto.T
 			typ = proto.SpanType_INBOUND
 		case trace.SpanTypeRequestOutbound:
 			typ = proto.SpanType_OUTBOUND
+		case trace.SpanTypePublish:
+			typ = proto.SpanType_PUBLISH
+		case trace.SpanTypeConsume:
+			typ = proto.SpanType_CONSUME
+		case trace.SpanTypeInternal:
+			typ = proto.SpanType_INTERNAL
+		}
+
+		// the typed attributes are returned as metadata
+		md := t.Metadata
+		if len(t.Attributes) > 0 {
+			md = make(map[string]string, len(t.Metadata)+len(t.Attributes))
+			for k, v := range t.Metadata {
+				md[k] = v
+			}
+			for k, v := range t.Attributes {
+				md[k] = fmt.Sprintf("%v", v)
+			}
 		}
+
 		rsp.Spans = append(rsp.Spans, &proto.Span{
 			Trace:    t.Trace,
 			Id:       t.Id,
@@ -83,13 +114,58 @@ func (d *Debug) Trace(ctx context.Context, req *proto.TraceRequest, rsp *proto.T
 			Started:  uint64(t.Started.UnixNano()),
 			Duration: uint64(t.Duration.Nanoseconds()),
 			Type:     typ,
-			Metadata: t.Metadata,
+			Metadata: md,
 		})
 	}
 
 	return nil
 }
 
+func (d *Debug) Cron(ctx context.Context, req *proto.CronRequest, rsp *proto.CronResponse) error {
+	return Crons(req, rsp)
+}
+
+// Crons fills the response with the jobs of the crons running in this process
+func Crons(req *proto.CronRequest, rsp *proto.CronResponse) error {
+	for _, c := range sync.Crons() {
+		for _, j := range c.Jobs() {
+			if len(req.Job) > 0 && req.Job != j.Id {
+				continue
+			}
+
+			job := &proto.Job{
+				Id:       j.Id,
+				Name:     j.Name,
+				Schedule: j.Schedule,
+				Leader:   j.Leader,
+			}
+			if !j.Next.IsZero() {
+				job.Next = j.Next.UnixNano()
+			}
+
+			if req.History > 0 {
+				history, err := c.History(j.Id, int(req.History))
+				if err != nil {
+					return err
+				}
+				for _, e := range history {
+					job.History = append(job.History, &proto.Execution{
+						Node:      e.Node,
+						Scheduled: e.Scheduled.UnixNano(),
+						Started:   e.Started.UnixNano(),
+						Ended:     e.Ended.UnixNano(),
+						Error:     e.Error,
+					})
+				}
+			}
+
+			rsp.Jobs = append(rsp.Jobs, job)
+		}
+	}
+
+	return nil
+}
+
 func (d *Debug) Log(ctx context.Context, stream server.Stream) error {
 	req := new(proto.LogRequest)
 	if err := stream.Recv(req); err != nil {
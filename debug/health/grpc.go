@@ -0,0 +1,82 @@
+package health
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	pb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	// WatchInterval is how often a watch checks for a change of status
+	WatchInterval = time.Second
+)
+
+type grpcHealth struct {
+	health *Health
+	// name of the service, checked as a whole like the empty name
+	name string
+}
+
+// NewGRPCHealth returns the grpc.health.v1 service of a Health. The empty
+// service name and the name given report readiness of the whole service,
+// the name of a registered check reports that check alone.
+func NewGRPCHealth(h *Health, name string) pb.HealthServer {
+	return &grpcHealth{
+		health: h,
+		name:   name,
+	}
+}
+
+// status returns the serving status of a service, false if it is unknown
+func (g *grpcHealth) status(ctx context.Context, service string) (pb.HealthCheckResponse_ServingStatus, bool) {
+	if len(service) == 0 || service == g.name {
+		if ready, _ := g.health.Ready(ctx); ready {
+			return pb.HealthCheckResponse_SERVING, true
+		}
+		return pb.HealthCheckResponse_NOT_SERVING, true
+	}
+
+	ok, err := g.health.Check(ctx, service)
+	if !ok {
+		return pb.HealthCheckResponse_SERVICE_UNKNOWN, false
+	}
+	if err != nil {
+		return pb.HealthCheckResponse_NOT_SERVING, true
+	}
+	return pb.HealthCheckResponse_SERVING, true
+}
+
+func (g *grpcHealth) Check(ctx context.Context, req *pb.HealthCheckRequest) (*pb.HealthCheckResponse, error) {
+	st, ok := g.status(ctx, req.Service)
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "unknown service %s", req.Service)
+	}
+	return &pb.HealthCheckResponse{Status: st}, nil
+}
+
+func (g *grpcHealth) Watch(req *pb.HealthCheckRequest, stream pb.Health_WatchServer) error {
+	ctx := stream.Context()
+	last := pb.HealthCheckResponse_ServingStatus(-1)
+
+	t := time.NewTicker(WatchInterval)
+	defer t.Stop()
+
+	for {
+		st, _ := g.status(ctx, req.Service)
+		if st != last {
+			if err := stream.Send(&pb.HealthCheckResponse{Status: st}); err != nil {
+				return err
+			}
+			last = st
+		}
+
+		select {
+		case <-ctx.Done():
+			return status.Error(codes.Canceled, "stream has ended")
+		case <-t.C:
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package health
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+type response struct {
+	Status string            `json:"status"`
+	Checks map[string]string `json:"checks,omitempty"`
+}
+
+// Handler serves /healthz, which succeeds while the process is up,
+// and /readyz, which fails with 503 while the service is not ready
+func Handler(h *Health) http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		writeResponse(w, http.StatusOK, &response{Status: "ok"})
+	})
+
+	mux.HandleFunc("/readyz", func(w http.ResponseWriter, r *http.Request) {
+		ready, results := h.Ready(r.Context())
+
+		rsp := &response{
+			Status: "ok",
+			Checks: make(map[string]string, len(results)),
+		}
+		for name, err := range results {
+			if err != nil {
+				rsp.Checks[name] = err.Error()
+			} else {
+				rsp.Checks[name] = "ok"
+			}
+		}
+
+		code := http.StatusOK
+		if !ready {
+			code = http.StatusServiceUnavailable
+			rsp.Status = "unavailable"
+		}
+
+		writeResponse(w, code, rsp)
+	})
+
+	return mux
+}
+
+func writeResponse(w http.ResponseWriter, code int, rsp *response) {
+	b, _ := json.Marshal(rsp)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	w.Write(b)
+}
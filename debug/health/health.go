@@ -0,0 +1,192 @@
+// Package health reports the liveness and readiness of a service over
+// HTTP and the standard grpc.health.v1 service
+package health
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/stack-labs/stack/broker"
+	"github.com/stack-labs/stack/registry"
+)
+
+var (
+	// DefaultTimeout is how long a check may take before it fails
+	DefaultTimeout = 2 * time.Second
+	// RegistryInterval is how long the result of a registry check is reused
+	RegistryInterval = 5 * time.Second
+
+	// ErrNotReady is reported before the service started and once it is stopping
+	ErrNotReady = errors.New("service not ready")
+	// ErrNotConnected is reported by the broker check for a disconnected broker
+	ErrNotConnected = errors.New("broker not connected")
+)
+
+// Check reports whether a dependency of the service is usable, nil when it is
+type Check func(ctx context.Context) error
+
+// Health holds the readiness of a service and the checks it depends on
+type Health struct {
+	timeout time.Duration
+
+	sync.RWMutex
+	checks map[string]Check
+	ready  bool
+}
+
+// NewHealth returns a Health which is not ready until SetReady is called
+func NewHealth() *Health {
+	return &Health{
+		timeout: DefaultTimeout,
+		checks:  make(map[string]Check),
+	}
+}
+
+// Register adds a readiness check, replacing any check with the same name
+func (h *Health) Register(name string, c Check) {
+	h.Lock()
+	defer h.Unlock()
+	h.checks[name] = c
+}
+
+// SetReady sets whether the service accepts traffic, regardless of its checks
+func (h *Health) SetReady(ready bool) {
+	h.Lock()
+	defer h.Unlock()
+	h.ready = ready
+}
+
+// Names returns the names of the registered checks
+func (h *Health) Names() []string {
+	h.RLock()
+	defer h.RUnlock()
+
+	names := make([]string, 0, len(h.checks))
+	for name := range h.checks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Check runs a single check, it returns false if no check has the name
+func (h *Health) Check(ctx context.Context, name string) (bool, error) {
+	h.RLock()
+	c, ok := h.checks[name]
+	h.RUnlock()
+
+	if !ok {
+		return false, nil
+	}
+	return true, h.run(ctx, c)
+}
+
+// Ready runs all checks and returns their errors by name, the service is
+// ready if it has been set ready and no check failed
+func (h *Health) Ready(ctx context.Context) (bool, map[string]error) {
+	h.RLock()
+	ready := h.ready
+	checks := make(map[string]Check, len(h.checks))
+	for name, c := range h.checks {
+		checks[name] = c
+	}
+	h.RUnlock()
+
+	var mtx sync.Mutex
+	var wg sync.WaitGroup
+	results := make(map[string]error, len(checks))
+
+	for name, c := range checks {
+		wg.Add(1)
+		go func(name string, c Check) {
+			defer wg.Done()
+			err := h.run(ctx, c)
+
+			mtx.Lock()
+			results[name] = err
+			if err != nil {
+				ready = false
+			}
+			mtx.Unlock()
+		}(name, c)
+	}
+
+	wg.Wait()
+
+	return ready, results
+}
+
+// run calls the check, failing it once the timeout passes even if it does not honour the context
+func (h *Health) run(ctx context.Context, c Check) error {
+	ctx, cancel := context.WithTimeout(ctx, h.timeout)
+	defer cancel()
+
+	ch := make(chan error, 1)
+	go func() {
+		ch <- c(ctx)
+	}()
+
+	select {
+	case err := <-ch:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// RegistryCheck fails while the registry cannot list services. Registries do
+// not honour a deadline so a lookup is reused for RegistryInterval and at most
+// one is in flight, probes of a hanging registry time out without piling up.
+func RegistryCheck(r registry.Registry) Check {
+	var (
+		mtx     sync.Mutex
+		checked time.Time
+		last    error
+		pending chan bool
+	)
+
+	return func(ctx context.Context) error {
+		mtx.Lock()
+		if !checked.IsZero() && time.Since(checked) < RegistryInterval {
+			err := last
+			mtx.Unlock()
+			return err
+		}
+		if pending == nil {
+			pending = make(chan bool)
+			go func(done chan bool) {
+				_, err := r.ListServices()
+
+				mtx.Lock()
+				last, checked, pending = err, time.Now(), nil
+				mtx.Unlock()
+				close(done)
+			}(pending)
+		}
+		done := pending
+		mtx.Unlock()
+
+		select {
+		case <-done:
+			mtx.Lock()
+			defer mtx.Unlock()
+			return last
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// BrokerCheck fails while the broker reports it is not connected,
+// brokers which do not report their connection always pass
+func BrokerCheck(b broker.Broker) Check {
+	return func(ctx context.Context) error {
+		if c, ok := b.(interface{ Connected() bool }); ok && !c.Connected() {
+			return ErrNotConnected
+		}
+		return nil
+	}
+}
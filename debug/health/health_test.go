@@ -0,0 +1,163 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stack-labs/stack/broker/memory"
+	"github.com/stack-labs/stack/registry"
+	pb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+func TestReady(t *testing.T) {
+	h := NewHealth()
+	h.timeout = time.Millisecond * 50
+
+	b := memory.NewBroker()
+	h.Register("broker", BrokerCheck(b))
+
+	if ready, _ := h.Ready(context.Background()); ready {
+		t.Fatal("expected not ready before SetReady")
+	}
+
+	h.SetReady(true)
+	ready, results := h.Ready(context.Background())
+	if ready || results["broker"] != ErrNotConnected {
+		t.Fatalf("expected disconnected broker to fail readiness, got %v %v", ready, results)
+	}
+
+	if err := b.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	if ready, results := h.Ready(context.Background()); !ready {
+		t.Fatalf("expected ready, got %v", results)
+	}
+
+	// a check which hangs fails on timeout
+	h.Register("slow", func(ctx context.Context) error {
+		time.Sleep(time.Second)
+		return nil
+	})
+	ready, results = h.Ready(context.Background())
+	if ready || results["slow"] != context.DeadlineExceeded {
+		t.Fatalf("expected slow check to time out, got %v %v", ready, results)
+	}
+}
+
+func TestHandler(t *testing.T) {
+	h := NewHealth()
+	h.Register("db", func(ctx context.Context) error {
+		return errors.New("connection refused")
+	})
+	h.SetReady(true)
+
+	srv := httptest.NewServer(Handler(h))
+	defer srv.Close()
+
+	rsp, err := http.Get(srv.URL + "/healthz")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsp.Body.Close()
+	if rsp.StatusCode != http.StatusOK {
+		t.Fatalf("expected live, got %d", rsp.StatusCode)
+	}
+
+	rsp, err = http.Get(srv.URL + "/readyz")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rsp.Body.Close()
+	if rsp.StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("expected unavailable, got %d", rsp.StatusCode)
+	}
+
+	var body response
+	if err := json.NewDecoder(rsp.Body).Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+	if body.Checks["db"] != "connection refused" {
+		t.Fatalf("expected failed check in body, got %+v", body)
+	}
+}
+
+func TestGRPCHealth(t *testing.T) {
+	h := NewHealth()
+	h.Register("db", func(ctx context.Context) error { return nil })
+	g := NewGRPCHealth(h, "stack.rpc.test")
+
+	testData := []struct {
+		service string
+		ready   bool
+		status  pb.HealthCheckResponse_ServingStatus
+	}{
+		{"", false, pb.HealthCheckResponse_NOT_SERVING},
+		{"", true, pb.HealthCheckResponse_SERVING},
+		{"stack.rpc.test", true, pb.HealthCheckResponse_SERVING},
+		{"db", false, pb.HealthCheckResponse_SERVING},
+	}
+
+	for _, d := range testData {
+		h.SetReady(d.ready)
+		rsp, err := g.Check(context.Background(), &pb.HealthCheckRequest{Service: d.service})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if rsp.Status != d.status {
+			t.Fatalf("service %q ready %v: expected %s, got %s", d.service, d.ready, d.status, rsp.Status)
+		}
+	}
+
+	if _, err := g.Check(context.Background(), &pb.HealthCheckRequest{Service: "unknown"}); err == nil {
+		t.Fatal("expected not found for unknown service")
+	}
+}
+
+type blockingRegistry struct {
+	registry.Registry
+	calls   int32
+	release chan bool
+}
+
+func (r *blockingRegistry) ListServices(...registry.ListOption) ([]*registry.Service, error) {
+	atomic.AddInt32(&r.calls, 1)
+	<-r.release
+	return nil, nil
+}
+
+func TestRegistryCheck(t *testing.T) {
+	r := &blockingRegistry{release: make(chan bool)}
+	check := RegistryCheck(r)
+
+	// probes of a hanging registry time out and share one lookup
+	for i := 0; i < 3; i++ {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*20)
+		err := check(ctx)
+		cancel()
+		if err != context.DeadlineExceeded {
+			t.Fatalf("expected hanging registry to time out, got %v", err)
+		}
+	}
+	if calls := atomic.LoadInt32(&r.calls); calls != 1 {
+		t.Fatalf("expected one lookup in flight, got %d", calls)
+	}
+
+	close(r.release)
+	if err := check(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	// the result is reused
+	if err := check(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if calls := atomic.LoadInt32(&r.calls); calls != 1 {
+		t.Fatalf("expected the lookup to be reused, got %d", calls)
+	}
+}
@@ -0,0 +1,117 @@
+package health
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/stack-labs/stack/util/log"
+	"google.golang.org/grpc"
+	pb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+var (
+	// DefaultAddress of the health server
+	DefaultAddress = ":8081"
+)
+
+// Server serves the health of a service over HTTP and optionally gRPC, on addresses of their own
+type Server struct {
+	address     string
+	grpcAddress string
+	name        string
+	health      *Health
+
+	sync.Mutex
+	srv  *http.Server
+	gsrv *grpc.Server
+}
+
+// NewServer returns a server of the health of the named service. The default
+// address is used for an empty address, gRPC is only served with a grpc address.
+func NewServer(address, grpcAddress, name string, h *Health) *Server {
+	if len(address) == 0 {
+		address = DefaultAddress
+	}
+
+	return &Server{
+		address:     address,
+		grpcAddress: grpcAddress,
+		name:        name,
+		health:      h,
+	}
+}
+
+// Start listening on the addresses
+func (s *Server) Start() error {
+	s.Lock()
+	defer s.Unlock()
+
+	if s.srv != nil {
+		return nil
+	}
+
+	ln, err := net.Listen("tcp", s.address)
+	if err != nil {
+		return err
+	}
+
+	var gln net.Listener
+	if len(s.grpcAddress) > 0 {
+		gln, err = net.Listen("tcp", s.grpcAddress)
+		if err != nil {
+			ln.Close()
+			return err
+		}
+	}
+
+	s.srv = &http.Server{Handler: Handler(s.health)}
+
+	log.Infof("Health server listening on %s", ln.Addr().String())
+
+	go func(srv *http.Server) {
+		if err := srv.Serve(ln); err != nil && err != http.ErrServerClosed {
+			log.Errorf("Health server error: %v", err)
+		}
+	}(s.srv)
+
+	if gln != nil {
+		s.gsrv = grpc.NewServer()
+		pb.RegisterHealthServer(s.gsrv, NewGRPCHealth(s.health, s.name))
+
+		log.Infof("Health gRPC server listening on %s", gln.Addr().String())
+
+		go func(srv *grpc.Server) {
+			if err := srv.Serve(gln); err != nil {
+				log.Errorf("Health gRPC server error: %v", err)
+			}
+		}(s.gsrv)
+	}
+
+	return nil
+}
+
+// Stop the server
+func (s *Server) Stop() error {
+	s.Lock()
+	defer s.Unlock()
+
+	if s.srv == nil {
+		return nil
+	}
+
+	if s.gsrv != nil {
+		s.gsrv.Stop()
+		s.gsrv = nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	err := s.srv.Shutdown(ctx)
+	s.srv = nil
+
+	return err
+}
@@ -0,0 +1,359 @@
+package metrics
+
+import (
+	"bufio"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// ContentType of the prometheus text exposition format
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+type metricType int
+
+const (
+	typeCounter metricType = iota
+	typeGauge
+	typeHistogram
+)
+
+func (t metricType) String() string {
+	switch t {
+	case typeCounter:
+		return "counter"
+	case typeGauge:
+		return "gauge"
+	}
+	return "histogram"
+}
+
+type metrics struct {
+	opts Options
+
+	sync.RWMutex
+	families map[string]*family
+	help     map[string]string
+}
+
+// family is the series of a metric name
+type family struct {
+	name    string
+	typ     metricType
+	buckets []float64
+
+	sync.RWMutex
+	series map[string]*series
+}
+
+type series struct {
+	labels string
+	value  *value
+	hist   *histogram
+}
+
+// value is a float updated atomically
+type value struct {
+	bits uint64
+}
+
+type histogram struct {
+	buckets []float64
+
+	sync.Mutex
+	counts []uint64
+	sum    float64
+	count  uint64
+}
+
+// discard is returned when a name is used with another type
+type discard struct{}
+
+func (v *value) Add(f float64) {
+	for {
+		old := atomic.LoadUint64(&v.bits)
+		n := math.Float64bits(math.Float64frombits(old) + f)
+		if atomic.CompareAndSwapUint64(&v.bits, old, n) {
+			return
+		}
+	}
+}
+
+func (v *value) Set(f float64) {
+	atomic.StoreUint64(&v.bits, math.Float64bits(f))
+}
+
+func (v *value) Inc() {
+	v.Add(1)
+}
+
+func (v *value) Dec() {
+	v.Add(-1)
+}
+
+func (v *value) get() float64 {
+	return math.Float64frombits(atomic.LoadUint64(&v.bits))
+}
+
+func (h *histogram) Observe(f float64) {
+	h.Lock()
+	defer h.Unlock()
+
+	for i, b := range h.buckets {
+		if f <= b {
+			h.counts[i]++
+		}
+	}
+	h.sum += f
+	h.count++
+}
+
+func (discard) Inc()            {}
+func (discard) Dec()            {}
+func (discard) Add(v float64)   {}
+func (discard) Set(v float64)   {}
+func (discard) Observe(float64) {}
+
+func (m *metrics) family(name string, typ metricType) *family {
+	// buckets are set by the name without the namespace
+	key := name
+	if len(m.opts.Namespace) > 0 {
+		name = m.opts.Namespace + "_" + name
+	}
+
+	m.RLock()
+	f, ok := m.families[name]
+	m.RUnlock()
+	if ok {
+		return f
+	}
+
+	m.Lock()
+	defer m.Unlock()
+
+	if f, ok = m.families[name]; ok {
+		return f
+	}
+
+	f = &family{
+		name:   name,
+		typ:    typ,
+		series: make(map[string]*series),
+	}
+
+	if typ == typeHistogram {
+		f.buckets = m.buckets(key)
+	}
+
+	m.families[name] = f
+
+	return f
+}
+
+func (m *metrics) buckets(name string) []float64 {
+	if b, ok := m.opts.Buckets[name]; ok {
+		return b
+	}
+	if strings.HasSuffix(name, "_bytes") {
+		return DefaultSizeBuckets
+	}
+	return DefaultBuckets
+}
+
+func (f *family) get(labels []string) *series {
+	key := formatLabels(labels)
+
+	f.RLock()
+	s, ok := f.series[key]
+	f.RUnlock()
+	if ok {
+		return s
+	}
+
+	f.Lock()
+	defer f.Unlock()
+
+	if s, ok = f.series[key]; ok {
+		return s
+	}
+
+	s = &series{labels: key}
+	if f.typ == typeHistogram {
+		s.hist = &histogram{
+			buckets: f.buckets,
+			counts:  make([]uint64, len(f.buckets)),
+		}
+	} else {
+		s.value = &value{}
+	}
+
+	f.series[key] = s
+
+	return s
+}
+
+func (m *metrics) Counter(name string, labels ...string) Counter {
+	f := m.family(name, typeCounter)
+	if f.typ != typeCounter {
+		return discard{}
+	}
+	return f.get(labels).value
+}
+
+func (m *metrics) Gauge(name string, labels ...string) Gauge {
+	f := m.family(name, typeGauge)
+	if f.typ != typeGauge {
+		return discard{}
+	}
+	return f.get(labels).value
+}
+
+func (m *metrics) Histogram(name string, labels ...string) Histogram {
+	f := m.family(name, typeHistogram)
+	if f.typ != typeHistogram {
+		return discard{}
+	}
+	return f.get(labels).hist
+}
+
+func (m *metrics) Describe(name, help string) {
+	if len(m.opts.Namespace) > 0 {
+		name = m.opts.Namespace + "_" + name
+	}
+
+	m.Lock()
+	m.help[name] = help
+	m.Unlock()
+}
+
+func (m *metrics) Write(w io.Writer) error {
+	m.RLock()
+	families := make([]*family, 0, len(m.families))
+	for _, f := range m.families {
+		families = append(families, f)
+	}
+	help := make(map[string]string, len(m.help))
+	for k, v := range m.help {
+		help[k] = v
+	}
+	m.RUnlock()
+
+	sort.Slice(families, func(i, j int) bool {
+		return families[i].name < families[j].name
+	})
+
+	bw := bufio.NewWriter(w)
+
+	for _, f := range families {
+		f.RLock()
+		series := make([]*series, 0, len(f.series))
+		for _, s := range f.series {
+			series = append(series, s)
+		}
+		f.RUnlock()
+
+		sort.Slice(series, func(i, j int) bool {
+			return series[i].labels < series[j].labels
+		})
+
+		if h, ok := help[f.name]; ok {
+			bw.WriteString("# HELP " + f.name + " " + escapeHelp(h) + "\n")
+		}
+		bw.WriteString("# TYPE " + f.name + " " + f.typ.String() + "\n")
+
+		for _, s := range series {
+			if s.hist == nil {
+				writeSample(bw, f.name, s.labels, "", s.value.get())
+				continue
+			}
+
+			s.hist.Lock()
+			for i, b := range s.hist.buckets {
+				writeSample(bw, f.name+"_bucket", s.labels, `le="`+formatFloat(b)+`"`, float64(s.hist.counts[i]))
+			}
+			writeSample(bw, f.name+"_bucket", s.labels, `le="+Inf"`, float64(s.hist.count))
+			writeSample(bw, f.name+"_sum", s.labels, "", s.hist.sum)
+			writeSample(bw, f.name+"_count", s.labels, "", float64(s.hist.count))
+			s.hist.Unlock()
+		}
+	}
+
+	return bw.Flush()
+}
+
+func writeSample(w *bufio.Writer, name, labels, extra string, v float64) {
+	w.WriteString(name)
+
+	if len(labels) > 0 || len(extra) > 0 {
+		w.WriteByte('{')
+		w.WriteString(labels)
+		if len(labels) > 0 && len(extra) > 0 {
+			w.WriteByte(',')
+		}
+		w.WriteString(extra)
+		w.WriteByte('}')
+	}
+
+	w.WriteByte(' ')
+	w.WriteString(formatFloat(v))
+	w.WriteByte('\n')
+}
+
+// formatLabels formats the label pairs as name="value",... ignoring an odd label
+func formatLabels(labels []string) string {
+	var sb strings.Builder
+
+	for i := 0; i+1 < len(labels); i += 2 {
+		if i > 0 {
+			sb.WriteByte(',')
+		}
+		sb.WriteString(labels[i])
+		sb.WriteString(`="`)
+		sb.WriteString(escapeLabel(labels[i+1]))
+		sb.WriteByte('"')
+	}
+
+	return sb.String()
+}
+
+func formatFloat(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+var (
+	labelEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+	helpEscaper  = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+)
+
+func escapeLabel(v string) string {
+	return labelEscaper.Replace(v)
+}
+
+func escapeHelp(v string) string {
+	return helpEscaper.Replace(v)
+}
+
+func newMetrics(opts ...Option) *metrics {
+	var options Options
+	for _, o := range opts {
+		o(&options)
+	}
+
+	return &metrics{
+		opts:     options,
+		families: make(map[string]*family),
+		help:     make(map[string]string),
+	}
+}
@@ -0,0 +1,66 @@
+// Package metrics provides counters, gauges and histograms exposed in the prometheus text format
+package metrics
+
+import (
+	"io"
+	"net/http"
+)
+
+var (
+	// DefaultMetrics is the registry written to by the wrappers and the client pool
+	DefaultMetrics = NewMetrics()
+
+	// DefaultBuckets are the upper bounds of the buckets of a histogram in seconds
+	DefaultBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+	// DefaultSizeBuckets are the upper bounds of the buckets of a histogram named *_bytes
+	DefaultSizeBuckets = []float64{64, 256, 1024, 4096, 16384, 65536, 262144, 1048576, 4194304}
+)
+
+// Counter is a value which only goes up
+type Counter interface {
+	Inc()
+	Add(v float64)
+}
+
+// Gauge is a value which goes up and down
+type Gauge interface {
+	Set(v float64)
+	Inc()
+	Dec()
+	Add(v float64)
+}
+
+// Histogram counts observations in buckets
+type Histogram interface {
+	Observe(v float64)
+}
+
+// Metrics is a registry of metrics. A metric is created on first use and
+// identified by its name and label pairs e.g "service", "greeter".
+type Metrics interface {
+	// Counter returns the counter of a name and labels
+	Counter(name string, labels ...string) Counter
+	// Gauge returns the gauge of a name and labels
+	Gauge(name string, labels ...string) Gauge
+	// Histogram returns the histogram of a name and labels
+	Histogram(name string, labels ...string) Histogram
+	// Describe sets the help text of a metric
+	Describe(name, help string)
+	// Write the metrics in the prometheus text exposition format
+	Write(w io.Writer) error
+}
+
+// NewMetrics returns a new in memory registry
+func NewMetrics(opts ...Option) Metrics {
+	return newMetrics(opts...)
+}
+
+// Handler returns a http handler serving the metrics to prometheus
+func Handler(m Metrics) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", ContentType)
+		if err := m.Write(w); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	})
+}
@@ -0,0 +1,67 @@
+package metrics
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestWrite(t *testing.T) {
+	m := NewMetrics(Buckets("latency_seconds", 0.1, 1))
+
+	m.Describe("requests_total", "Total requests.")
+	m.Counter("requests_total", "service", "foo", "code", "200").Inc()
+	m.Counter("requests_total", "service", "foo", "code", "200").Add(2)
+	m.Counter("requests_total", "service", "bar\"", "code", "500").Inc()
+	m.Gauge("in_flight").Set(3)
+	m.Gauge("in_flight").Dec()
+	m.Histogram("latency_seconds", "service", "foo").Observe(0.05)
+	m.Histogram("latency_seconds", "service", "foo").Observe(0.5)
+	m.Histogram("latency_seconds", "service", "foo").Observe(5)
+
+	// a name used with another type is discarded
+	m.Gauge("requests_total").Set(10)
+
+	buf := bytes.NewBuffer(nil)
+	if err := m.Write(buf); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `# TYPE in_flight gauge
+in_flight 2
+# TYPE latency_seconds histogram
+latency_seconds_bucket{service="foo",le="0.1"} 1
+latency_seconds_bucket{service="foo",le="1"} 2
+latency_seconds_bucket{service="foo",le="+Inf"} 3
+latency_seconds_sum{service="foo"} 5.55
+latency_seconds_count{service="foo"} 3
+# HELP requests_total Total requests.
+# TYPE requests_total counter
+requests_total{service="bar\"",code="500"} 1
+requests_total{service="foo",code="200"} 3
+`
+
+	if buf.String() != expected {
+		t.Fatalf("Expected:\n%s\ngot:\n%s", expected, buf.String())
+	}
+}
+
+func TestNamespace(t *testing.T) {
+	m := NewMetrics(Namespace("stack"), Buckets("latency_seconds", 0.2))
+	m.Counter("requests_total").Inc()
+	m.Histogram("latency_seconds").Observe(0.05)
+
+	rsp := httptest.NewRecorder()
+	Handler(m).ServeHTTP(rsp, httptest.NewRequest("GET", "/metrics", nil))
+
+	if ct := rsp.Header().Get("Content-Type"); ct != ContentType {
+		t.Fatalf("Expected content type %s, got %s", ContentType, ct)
+	}
+	if !strings.Contains(rsp.Body.String(), "stack_requests_total 1\n") {
+		t.Fatalf("Expected namespaced metric, got %s", rsp.Body.String())
+	}
+	if !strings.Contains(rsp.Body.String(), "stack_latency_seconds_bucket{le=\"0.2\"} 1\n") {
+		t.Fatalf("Expected the buckets of the unprefixed name, got %s", rsp.Body.String())
+	}
+}
@@ -0,0 +1,27 @@
+package metrics
+
+type Options struct {
+	// Namespace is prefixed to the metric names e.g stack
+	Namespace string
+	// Buckets of a histogram by name
+	Buckets map[string][]float64
+}
+
+type Option func(o *Options)
+
+// Namespace sets the prefix of the metric names
+func Namespace(ns string) Option {
+	return func(o *Options) {
+		o.Namespace = ns
+	}
+}
+
+// Buckets sets the upper bounds of the buckets of a histogram
+func Buckets(name string, buckets ...float64) Option {
+	return func(o *Options) {
+		if o.Buckets == nil {
+			o.Buckets = make(map[string][]float64)
+		}
+		o.Buckets[name] = buckets
+	}
+}
@@ -0,0 +1,95 @@
+package metrics
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/stack-labs/stack/util/log"
+)
+
+var (
+	// DefaultAddress of the metrics server
+	DefaultAddress = ":9090"
+	// DefaultPath the metrics are served on
+	DefaultPath = "/metrics"
+)
+
+// Server serves the metrics on an address of its own
+type Server struct {
+	address string
+	path    string
+	metrics Metrics
+
+	sync.Mutex
+	srv *http.Server
+}
+
+// NewServer returns a server of the metrics, the defaults are used for an empty address or path
+func NewServer(address, path string, m Metrics) *Server {
+	if len(address) == 0 {
+		address = DefaultAddress
+	}
+	if len(path) == 0 {
+		path = DefaultPath
+	}
+	if m == nil {
+		m = DefaultMetrics
+	}
+
+	return &Server{
+		address: address,
+		path:    path,
+		metrics: m,
+	}
+}
+
+// Start listening on the address
+func (s *Server) Start() error {
+	s.Lock()
+	defer s.Unlock()
+
+	if s.srv != nil {
+		return nil
+	}
+
+	ln, err := net.Listen("tcp", s.address)
+	if err != nil {
+		return err
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle(s.path, Handler(s.metrics))
+
+	s.srv = &http.Server{Handler: mux}
+
+	log.Infof("Metrics server listening on %s%s", ln.Addr().String(), s.path)
+
+	go func(srv *http.Server) {
+		if err := srv.Serve(ln); err != nil && err != http.ErrServerClosed {
+			log.Errorf("Metrics server error: %v", err)
+		}
+	}(s.srv)
+
+	return nil
+}
+
+// Stop the server
+func (s *Server) Stop() error {
+	s.Lock()
+	defer s.Unlock()
+
+	if s.srv == nil {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+	defer cancel()
+
+	err := s.srv.Shutdown(ctx)
+	s.srv = nil
+
+	return err
+}
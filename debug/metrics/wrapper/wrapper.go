@@ -0,0 +1,194 @@
+// Package wrapper provides client and server wrappers recording metrics
+// of the requests and messages per service, endpoint and topic
+package wrapper
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/stack-labs/stack/client"
+	"github.com/stack-labs/stack/debug/metrics"
+	"github.com/stack-labs/stack/registry"
+	"github.com/stack-labs/stack/server"
+	"github.com/stack-labs/stack/util/errors"
+)
+
+const (
+	serverRequests = "stack_server_requests_total"
+	serverLatency  = "stack_server_request_duration_seconds"
+	serverSize     = "stack_server_request_size_bytes"
+	serverInFlight = "stack_server_requests_in_flight"
+
+	clientRequests = "stack_client_requests_total"
+	clientLatency  = "stack_client_request_duration_seconds"
+	clientSize     = "stack_client_request_size_bytes"
+
+	brokerPublished      = "stack_broker_published_total"
+	brokerPublishLatency = "stack_broker_publish_duration_seconds"
+	brokerConsumed       = "stack_broker_consumed_total"
+	brokerConsumeLatency = "stack_broker_consume_duration_seconds"
+	brokerSize           = "stack_broker_message_size_bytes"
+)
+
+// code returns the status code of an error, 200 if nil and 500 if it has none
+func code(err error) string {
+	if err == nil {
+		return "200"
+	}
+
+	e, ok := err.(*errors.Error)
+	if !ok {
+		e = errors.Parse(err.Error())
+	}
+
+	if e.Code == 0 {
+		return "500"
+	}
+
+	return strconv.Itoa(int(e.Code))
+}
+
+// size returns the size of a raw or proto payload
+func size(v interface{}) (int, bool) {
+	switch p := v.(type) {
+	case []byte:
+		return len(p), true
+	case *[]byte:
+		return len(*p), true
+	case proto.Message:
+		return proto.Size(p), true
+	case interface{ Size() int }:
+		return p.Size(), true
+	}
+	return 0, false
+}
+
+func getMetrics(m metrics.Metrics) metrics.Metrics {
+	if m == nil {
+		return metrics.DefaultMetrics
+	}
+	return m
+}
+
+// NewHandlerWrapper records the requests, latency, request size and requests
+// in flight per service and endpoint. Uses metrics.DefaultMetrics if m is nil.
+func NewHandlerWrapper(m metrics.Metrics) server.HandlerWrapper {
+	m = getMetrics(m)
+	m.Describe(serverRequests, "Total requests served by service, endpoint and status code.")
+	m.Describe(serverLatency, "Latency of the requests served in seconds.")
+	m.Describe(serverSize, "Size of the requests served in bytes.")
+	m.Describe(serverInFlight, "Requests currently being served.")
+
+	return func(h server.HandlerFunc) server.HandlerFunc {
+		return func(ctx context.Context, req server.Request, rsp interface{}) error {
+			service, endpoint := req.Service(), req.Endpoint()
+
+			inFlight := m.Gauge(serverInFlight, "service", service, "endpoint", endpoint)
+			inFlight.Inc()
+			defer inFlight.Dec()
+
+			if n, ok := size(req.Body()); ok {
+				m.Histogram(serverSize, "service", service, "endpoint", endpoint).Observe(float64(n))
+			}
+
+			start := time.Now()
+			err := h(ctx, req, rsp)
+
+			m.Histogram(serverLatency, "service", service, "endpoint", endpoint).Observe(time.Since(start).Seconds())
+			m.Counter(serverRequests, "service", service, "endpoint", endpoint, "code", code(err)).Inc()
+
+			return err
+		}
+	}
+}
+
+// NewSubscriberWrapper records the messages consumed, their latency and size
+// per topic. Uses metrics.DefaultMetrics if m is nil.
+func NewSubscriberWrapper(m metrics.Metrics) server.SubscriberWrapper {
+	m = getMetrics(m)
+	m.Describe(brokerConsumed, "Total messages consumed by topic and status code.")
+	m.Describe(brokerConsumeLatency, "Latency of processing the messages in seconds.")
+	m.Describe(brokerSize, "Size of the messages in bytes.")
+
+	return func(fn server.SubscriberFunc) server.SubscriberFunc {
+		return func(ctx context.Context, msg server.Message) error {
+			topic := msg.Topic()
+
+			m.Histogram(brokerSize, "topic", topic, "direction", "in").Observe(float64(len(msg.Body())))
+
+			start := time.Now()
+			err := fn(ctx, msg)
+
+			m.Histogram(brokerConsumeLatency, "topic", topic).Observe(time.Since(start).Seconds())
+			m.Counter(brokerConsumed, "topic", topic, "code", code(err)).Inc()
+
+			return err
+		}
+	}
+}
+
+// NewCallWrapper records every attempt made by the client, its latency and
+// request size per service and endpoint. Uses metrics.DefaultMetrics if m is nil.
+func NewCallWrapper(m metrics.Metrics) client.CallWrapper {
+	m = getMetrics(m)
+	m.Describe(clientRequests, "Total requests made by service, endpoint and status code.")
+	m.Describe(clientLatency, "Latency of the requests made in seconds.")
+	m.Describe(clientSize, "Size of the requests made in bytes.")
+
+	return func(cf client.CallFunc) client.CallFunc {
+		return func(ctx context.Context, node *registry.Node, req client.Request, rsp interface{}, opts client.CallOptions) error {
+			service, endpoint := req.Service(), req.Endpoint()
+
+			if n, ok := size(req.Body()); ok {
+				m.Histogram(clientSize, "service", service, "endpoint", endpoint).Observe(float64(n))
+			}
+
+			start := time.Now()
+			err := cf(ctx, node, req, rsp, opts)
+
+			m.Histogram(clientLatency, "service", service, "endpoint", endpoint).Observe(time.Since(start).Seconds())
+			m.Counter(clientRequests, "service", service, "endpoint", endpoint, "code", code(err)).Inc()
+
+			return err
+		}
+	}
+}
+
+type publishClient struct {
+	client.Client
+	m metrics.Metrics
+}
+
+func (c *publishClient) Publish(ctx context.Context, msg client.Message, opts ...client.PublishOption) error {
+	topic := msg.Topic()
+
+	if n, ok := size(msg.Payload()); ok {
+		c.m.Histogram(brokerSize, "topic", topic, "direction", "out").Observe(float64(n))
+	}
+
+	start := time.Now()
+	err := c.Client.Publish(ctx, msg, opts...)
+
+	c.m.Histogram(brokerPublishLatency, "topic", topic).Observe(time.Since(start).Seconds())
+	c.m.Counter(brokerPublished, "topic", topic, "code", code(err)).Inc()
+
+	return err
+}
+
+// NewClientWrapper records the messages published, their latency and size
+// per topic. Uses metrics.DefaultMetrics if m is nil.
+func NewClientWrapper(m metrics.Metrics) client.Wrapper {
+	m = getMetrics(m)
+	m.Describe(brokerPublished, "Total messages published by topic and status code.")
+	m.Describe(brokerPublishLatency, "Latency of publishing the messages in seconds.")
+	m.Describe(brokerSize, "Size of the messages in bytes.")
+
+	return func(c client.Client) client.Client {
+		return &publishClient{
+			Client: c,
+			m:      m,
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package wrapper
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stack-labs/stack/debug/metrics"
+	"github.com/stack-labs/stack/server"
+	"github.com/stack-labs/stack/util/errors"
+)
+
+type testRequest struct {
+	server.Request
+}
+
+func (testRequest) Service() string   { return "foo" }
+func (testRequest) Endpoint() string  { return "Foo.Bar" }
+func (testRequest) Body() interface{} { return []byte("hello") }
+
+func TestHandlerWrapper(t *testing.T) {
+	m := metrics.NewMetrics()
+
+	h := NewHandlerWrapper(m)(func(ctx context.Context, req server.Request, rsp interface{}) error {
+		return errors.NotFound("foo", "not found")
+	})
+	h(context.TODO(), testRequest{}, nil)
+
+	buf := bytes.NewBuffer(nil)
+	if err := m.Write(buf); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, line := range []string{
+		`stack_server_requests_total{service="foo",endpoint="Foo.Bar",code="404"} 1`,
+		`stack_server_request_duration_seconds_count{service="foo",endpoint="Foo.Bar"} 1`,
+		`stack_server_request_size_bytes_sum{service="foo",endpoint="Foo.Bar"} 5`,
+		`stack_server_requests_in_flight{service="foo",endpoint="Foo.Bar"} 0`,
+	} {
+		if !strings.Contains(buf.String(), line+"\n") {
+			t.Fatalf("Expected %s in:\n%s", line, buf.String())
+		}
+	}
+}
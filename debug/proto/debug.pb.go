@@ -25,16 +25,25 @@ type SpanType int32
 const (
 	SpanType_INBOUND  SpanType = 0
 	SpanType_OUTBOUND SpanType = 1
+	SpanType_PUBLISH  SpanType = 2
+	SpanType_CONSUME  SpanType = 3
+	SpanType_INTERNAL SpanType = 4
 )
 
 var SpanType_name = map[int32]string{
 	0: "INBOUND",
 	1: "OUTBOUND",
+	2: "PUBLISH",
+	3: "CONSUME",
+	4: "INTERNAL",
 }
 
 var SpanType_value = map[string]int32{
 	"INBOUND":  0,
 	"OUTBOUND": 1,
+	"PUBLISH":  2,
+	"CONSUME":  3,
+	"INTERNAL": 4,
 }
 
 func (x SpanType) String() string {
@@ -181,10 +190,12 @@ type StatsResponse struct {
 	// total number of requests
 	Requests uint64 `protobuf:"varint,7,opt,name=requests,proto3" json:"requests,omitempty"`
 	// total number of errors
-	Errors               uint64   `protobuf:"varint,8,opt,name=errors,proto3" json:"errors,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	Errors uint64 `protobuf:"varint,8,opt,name=errors,proto3" json:"errors,omitempty"`
+	// recent state transitions e.g circuit breakers
+	Transitions          []*Transition `protobuf:"bytes,9,rep,name=transitions,proto3" json:"transitions,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
+	XXX_unrecognized     []byte        `json:"-"`
+	XXX_sizecache        int32         `json:"-"`
 }
 
 func (m *StatsResponse) Reset()         { *m = StatsResponse{} }
@@ -268,6 +279,90 @@ func (m *StatsResponse) GetErrors() uint64 {
 	return 0
 }
 
+func (m *StatsResponse) GetTransitions() []*Transition {
+	if m != nil {
+		return m.Transitions
+	}
+	return nil
+}
+
+// Transition is a state change of a runtime component
+type Transition struct {
+	// unix timestamp of the change
+	Timestamp int64 `protobuf:"varint,1,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	// component that changed e.g breaker
+	Component string `protobuf:"bytes,2,opt,name=component,proto3" json:"component,omitempty"`
+	// name of the instance
+	Name string `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	// previous state
+	From string `protobuf:"bytes,4,opt,name=from,proto3" json:"from,omitempty"`
+	// current state
+	To                   string   `protobuf:"bytes,5,opt,name=to,proto3" json:"to,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Transition) Reset()         { *m = Transition{} }
+func (m *Transition) String() string { return proto.CompactTextString(m) }
+func (*Transition) ProtoMessage()    {}
+func (*Transition) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8d9d361be58531fb, []int{4}
+}
+
+func (m *Transition) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Transition.Unmarshal(m, b)
+}
+func (m *Transition) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Transition.Marshal(b, m, deterministic)
+}
+func (m *Transition) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Transition.Merge(m, src)
+}
+func (m *Transition) XXX_Size() int {
+	return xxx_messageInfo_Transition.Size(m)
+}
+func (m *Transition) XXX_DiscardUnknown() {
+	xxx_messageInfo_Transition.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Transition proto.InternalMessageInfo
+
+func (m *Transition) GetTimestamp() int64 {
+	if m != nil {
+		return m.Timestamp
+	}
+	return 0
+}
+
+func (m *Transition) GetComponent() string {
+	if m != nil {
+		return m.Component
+	}
+	return ""
+}
+
+func (m *Transition) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *Transition) GetFrom() string {
+	if m != nil {
+		return m.From
+	}
+	return ""
+}
+
+func (m *Transition) GetTo() string {
+	if m != nil {
+		return m.To
+	}
+	return ""
+}
+
 // LogRequest requests service logs
 type LogRequest struct {
 	// service to request logs for
@@ -289,7 +384,7 @@ func (m *LogRequest) Reset()         { *m = LogRequest{} }
 func (m *LogRequest) String() string { return proto.CompactTextString(m) }
 func (*LogRequest) ProtoMessage()    {}
 func (*LogRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_8d9d361be58531fb, []int{4}
+	return fileDescriptor_8d9d361be58531fb, []int{5}
 }
 
 func (m *LogRequest) XXX_Unmarshal(b []byte) error {
@@ -355,7 +450,7 @@ func (m *Record) Reset()         { *m = Record{} }
 func (m *Record) String() string { return proto.CompactTextString(m) }
 func (*Record) ProtoMessage()    {}
 func (*Record) Descriptor() ([]byte, []int) {
-	return fileDescriptor_8d9d361be58531fb, []int{5}
+	return fileDescriptor_8d9d361be58531fb, []int{6}
 }
 
 func (m *Record) XXX_Unmarshal(b []byte) error {
@@ -409,7 +504,7 @@ func (m *TraceRequest) Reset()         { *m = TraceRequest{} }
 func (m *TraceRequest) String() string { return proto.CompactTextString(m) }
 func (*TraceRequest) ProtoMessage()    {}
 func (*TraceRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_8d9d361be58531fb, []int{6}
+	return fileDescriptor_8d9d361be58531fb, []int{7}
 }
 
 func (m *TraceRequest) XXX_Unmarshal(b []byte) error {
@@ -448,7 +543,7 @@ func (m *TraceResponse) Reset()         { *m = TraceResponse{} }
 func (m *TraceResponse) String() string { return proto.CompactTextString(m) }
 func (*TraceResponse) ProtoMessage()    {}
 func (*TraceResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_8d9d361be58531fb, []int{7}
+	return fileDescriptor_8d9d361be58531fb, []int{8}
 }
 
 func (m *TraceResponse) XXX_Unmarshal(b []byte) error {
@@ -501,7 +596,7 @@ func (m *Span) Reset()         { *m = Span{} }
 func (m *Span) String() string { return proto.CompactTextString(m) }
 func (*Span) ProtoMessage()    {}
 func (*Span) Descriptor() ([]byte, []int) {
-	return fileDescriptor_8d9d361be58531fb, []int{8}
+	return fileDescriptor_8d9d361be58531fb, []int{9}
 }
 
 func (m *Span) XXX_Unmarshal(b []byte) error {
@@ -578,12 +673,264 @@ func (m *Span) GetType() SpanType {
 	return SpanType_INBOUND
 }
 
+// CronRequest requests the scheduled jobs and their history
+type CronRequest struct {
+	// optional job id
+	Job string `protobuf:"bytes,1,opt,name=job,proto3" json:"job,omitempty"`
+	// count of executions to return per job
+	History              int64    `protobuf:"varint,2,opt,name=history,proto3" json:"history,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CronRequest) Reset()         { *m = CronRequest{} }
+func (m *CronRequest) String() string { return proto.CompactTextString(m) }
+func (*CronRequest) ProtoMessage()    {}
+func (*CronRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8d9d361be58531fb, []int{10}
+}
+
+func (m *CronRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CronRequest.Unmarshal(m, b)
+}
+func (m *CronRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CronRequest.Marshal(b, m, deterministic)
+}
+func (m *CronRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CronRequest.Merge(m, src)
+}
+func (m *CronRequest) XXX_Size() int {
+	return xxx_messageInfo_CronRequest.Size(m)
+}
+func (m *CronRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CronRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CronRequest proto.InternalMessageInfo
+
+func (m *CronRequest) GetJob() string {
+	if m != nil {
+		return m.Job
+	}
+	return ""
+}
+
+func (m *CronRequest) GetHistory() int64 {
+	if m != nil {
+		return m.History
+	}
+	return 0
+}
+
+type CronResponse struct {
+	Jobs                 []*Job   `protobuf:"bytes,1,rep,name=jobs,proto3" json:"jobs,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CronResponse) Reset()         { *m = CronResponse{} }
+func (m *CronResponse) String() string { return proto.CompactTextString(m) }
+func (*CronResponse) ProtoMessage()    {}
+func (*CronResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8d9d361be58531fb, []int{11}
+}
+
+func (m *CronResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CronResponse.Unmarshal(m, b)
+}
+func (m *CronResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CronResponse.Marshal(b, m, deterministic)
+}
+func (m *CronResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CronResponse.Merge(m, src)
+}
+func (m *CronResponse) XXX_Size() int {
+	return xxx_messageInfo_CronResponse.Size(m)
+}
+func (m *CronResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_CronResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CronResponse proto.InternalMessageInfo
+
+func (m *CronResponse) GetJobs() []*Job {
+	if m != nil {
+		return m.Jobs
+	}
+	return nil
+}
+
+// Job is a command scheduled on a cron
+type Job struct {
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// name of the command
+	Name string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	// cron expression or interval
+	Schedule string `protobuf:"bytes,3,opt,name=schedule,proto3" json:"schedule,omitempty"`
+	// whether this node runs the job
+	Leader bool `protobuf:"varint,4,opt,name=leader,proto3" json:"leader,omitempty"`
+	// time of the next run in nanoseconds, 0 if not known
+	Next int64 `protobuf:"varint,5,opt,name=next,proto3" json:"next,omitempty"`
+	// recent executions, newest first
+	History              []*Execution `protobuf:"bytes,6,rep,name=history,proto3" json:"history,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
+func (m *Job) Reset()         { *m = Job{} }
+func (m *Job) String() string { return proto.CompactTextString(m) }
+func (*Job) ProtoMessage()    {}
+func (*Job) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8d9d361be58531fb, []int{12}
+}
+
+func (m *Job) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Job.Unmarshal(m, b)
+}
+func (m *Job) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Job.Marshal(b, m, deterministic)
+}
+func (m *Job) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Job.Merge(m, src)
+}
+func (m *Job) XXX_Size() int {
+	return xxx_messageInfo_Job.Size(m)
+}
+func (m *Job) XXX_DiscardUnknown() {
+	xxx_messageInfo_Job.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Job proto.InternalMessageInfo
+
+func (m *Job) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *Job) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *Job) GetSchedule() string {
+	if m != nil {
+		return m.Schedule
+	}
+	return ""
+}
+
+func (m *Job) GetLeader() bool {
+	if m != nil {
+		return m.Leader
+	}
+	return false
+}
+
+func (m *Job) GetNext() int64 {
+	if m != nil {
+		return m.Next
+	}
+	return 0
+}
+
+func (m *Job) GetHistory() []*Execution {
+	if m != nil {
+		return m.History
+	}
+	return nil
+}
+
+// Execution is a recorded run of a job
+type Execution struct {
+	// node that ran the job
+	Node string `protobuf:"bytes,1,opt,name=node,proto3" json:"node,omitempty"`
+	// time the run was due in nanoseconds
+	Scheduled int64 `protobuf:"varint,2,opt,name=scheduled,proto3" json:"scheduled,omitempty"`
+	// time of start in nanoseconds
+	Started int64 `protobuf:"varint,3,opt,name=started,proto3" json:"started,omitempty"`
+	// time of end in nanoseconds
+	Ended int64 `protobuf:"varint,4,opt,name=ended,proto3" json:"ended,omitempty"`
+	// error returned by the job
+	Error                string   `protobuf:"bytes,5,opt,name=error,proto3" json:"error,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Execution) Reset()         { *m = Execution{} }
+func (m *Execution) String() string { return proto.CompactTextString(m) }
+func (*Execution) ProtoMessage()    {}
+func (*Execution) Descriptor() ([]byte, []int) {
+	return fileDescriptor_8d9d361be58531fb, []int{13}
+}
+
+func (m *Execution) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Execution.Unmarshal(m, b)
+}
+func (m *Execution) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Execution.Marshal(b, m, deterministic)
+}
+func (m *Execution) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Execution.Merge(m, src)
+}
+func (m *Execution) XXX_Size() int {
+	return xxx_messageInfo_Execution.Size(m)
+}
+func (m *Execution) XXX_DiscardUnknown() {
+	xxx_messageInfo_Execution.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Execution proto.InternalMessageInfo
+
+func (m *Execution) GetNode() string {
+	if m != nil {
+		return m.Node
+	}
+	return ""
+}
+
+func (m *Execution) GetScheduled() int64 {
+	if m != nil {
+		return m.Scheduled
+	}
+	return 0
+}
+
+func (m *Execution) GetStarted() int64 {
+	if m != nil {
+		return m.Started
+	}
+	return 0
+}
+
+func (m *Execution) GetEnded() int64 {
+	if m != nil {
+		return m.Ended
+	}
+	return 0
+}
+
+func (m *Execution) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
 func init() {
 	proto.RegisterEnum("SpanType", SpanType_name, SpanType_value)
 	proto.RegisterType((*HealthRequest)(nil), "HealthRequest")
 	proto.RegisterType((*HealthResponse)(nil), "HealthResponse")
 	proto.RegisterType((*StatsRequest)(nil), "StatsRequest")
 	proto.RegisterType((*StatsResponse)(nil), "StatsResponse")
+	proto.RegisterType((*Transition)(nil), "Transition")
 	proto.RegisterType((*LogRequest)(nil), "LogRequest")
 	proto.RegisterType((*Record)(nil), "Record")
 	proto.RegisterMapType((map[string]string)(nil), "Record.MetadataEntry")
@@ -591,47 +938,68 @@ func init() {
 	proto.RegisterType((*TraceResponse)(nil), "TraceResponse")
 	proto.RegisterType((*Span)(nil), "Span")
 	proto.RegisterMapType((map[string]string)(nil), "Span.MetadataEntry")
+	proto.RegisterType((*CronRequest)(nil), "CronRequest")
+	proto.RegisterType((*CronResponse)(nil), "CronResponse")
+	proto.RegisterType((*Job)(nil), "Job")
+	proto.RegisterType((*Execution)(nil), "Execution")
 }
 
 func init() { proto.RegisterFile("debug.proto", fileDescriptor_8d9d361be58531fb) }
 
 var fileDescriptor_8d9d361be58531fb = []byte{
-	// 586 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0x54, 0xdb, 0x6e, 0xd3, 0x40,
-	0x10, 0x8d, 0xed, 0x38, 0xb1, 0x27, 0x8d, 0xa9, 0x96, 0x8b, 0x2c, 0x73, 0xab, 0x2c, 0x21, 0x85,
-	0x8b, 0x0c, 0x94, 0x17, 0x04, 0x6f, 0xa8, 0x48, 0x20, 0x95, 0x56, 0xda, 0xb6, 0x1f, 0xb0, 0xb5,
-	0x47, 0xae, 0xa1, 0xbe, 0xb0, 0xbb, 0xae, 0x94, 0x6f, 0xe1, 0x0b, 0x78, 0xe3, 0x67, 0xf8, 0x1f,
-	0xb4, 0x17, 0xa7, 0xb1, 0x10, 0xea, 0x03, 0x6f, 0x3e, 0x67, 0x67, 0x4f, 0x66, 0x4e, 0xce, 0x0e,
-	0x2c, 0x0a, 0x3c, 0xef, 0xcb, 0xac, 0xe3, 0xad, 0x6c, 0xd3, 0xa7, 0xb0, 0xfc, 0x84, 0xec, 0x52,
-	0x5e, 0x50, 0xfc, 0xde, 0xa3, 0x90, 0x24, 0x86, 0xb9, 0x40, 0x7e, 0x55, 0xe5, 0x18, 0x3b, 0x7b,
-	0xce, 0x2a, 0xa4, 0x03, 0x4c, 0x57, 0x10, 0x0d, 0xa5, 0xa2, 0x6b, 0x1b, 0x81, 0xe4, 0x1e, 0xcc,
-	0x84, 0x64, 0xb2, 0x17, 0xb6, 0xd4, 0xa2, 0x74, 0x05, 0x3b, 0x27, 0x92, 0x49, 0x71, 0xb3, 0xe6,
-	0x6f, 0x07, 0x96, 0xb6, 0xd4, 0x6a, 0x3e, 0x80, 0x50, 0x56, 0x35, 0x0a, 0xc9, 0xea, 0x4e, 0x57,
-	0x4f, 0xe9, 0x35, 0xa1, 0x95, 0x24, 0xe3, 0x12, 0x8b, 0xd8, 0xd5, 0x67, 0x03, 0x54, 0xbd, 0xf4,
-	0x9d, 0x2a, 0x8c, 0x3d, 0x7d, 0x60, 0x91, 0xe2, 0x6b, 0xac, 0x5b, 0xbe, 0x8e, 0xa7, 0x86, 0x37,
-	0x48, 0x29, 0xc9, 0x0b, 0x8e, 0xac, 0x10, 0xb1, 0x6f, 0x94, 0x2c, 0x24, 0x11, 0xb8, 0x65, 0x1e,
-	0xcf, 0x34, 0xe9, 0x96, 0x39, 0x49, 0x20, 0xe0, 0x66, 0x10, 0x11, 0xcf, 0x35, 0xbb, 0xc1, 0x4a,
-	0x1d, 0x39, 0x6f, 0xb9, 0x88, 0x03, 0xa3, 0x6e, 0x50, 0xfa, 0x15, 0xe0, 0xb0, 0x2d, 0x6f, 0x9c,
-	0xdf, 0x38, 0xc8, 0x91, 0xd5, 0x7a, 0x9c, 0x80, 0x5a, 0x44, 0xee, 0x80, 0x9f, 0xb7, 0x7d, 0x23,
-	0xf5, 0x30, 0x1e, 0x35, 0x40, 0xb1, 0xa2, 0x6a, 0x72, 0xd4, 0xa3, 0x78, 0xd4, 0x80, 0xf4, 0x97,
-	0x03, 0x33, 0x8a, 0x79, 0xcb, 0x8b, 0xbf, 0xcd, 0xf3, 0xb6, 0xcd, 0x7b, 0x0d, 0x41, 0x8d, 0x92,
-	0x15, 0x4c, 0xb2, 0xd8, 0xdd, 0xf3, 0x56, 0x8b, 0xfd, 0xbb, 0x99, 0xb9, 0x98, 0x7d, 0xb1, 0xfc,
-	0xc7, 0x46, 0xf2, 0x35, 0xdd, 0x94, 0xa9, 0xce, 0x6b, 0x14, 0x82, 0x95, 0xc6, 0xd6, 0x90, 0x0e,
-	0x30, 0x79, 0x0f, 0xcb, 0xd1, 0x25, 0xb2, 0x0b, 0xde, 0x37, 0x5c, 0xdb, 0x01, 0xd5, 0xa7, 0x6a,
-	0xf7, 0x8a, 0x5d, 0xf6, 0xa8, 0x67, 0x0b, 0xa9, 0x01, 0xef, 0xdc, 0xb7, 0x4e, 0xfa, 0x08, 0x76,
-	0x4e, 0x39, 0xcb, 0x71, 0x30, 0x28, 0x02, 0xb7, 0x2a, 0xec, 0x55, 0xb7, 0x2a, 0xd2, 0x17, 0xb0,
-	0xb4, 0xe7, 0x36, 0x15, 0xf7, 0xc1, 0x17, 0x1d, 0x6b, 0x54, 0xd0, 0x54, 0xdf, 0x7e, 0x76, 0xd2,
-	0xb1, 0x86, 0x1a, 0x2e, 0xfd, 0xe1, 0xc2, 0x54, 0x61, 0xf5, 0x83, 0x52, 0x5d, 0xb3, 0x4a, 0x06,
-	0x58, 0x71, 0x77, 0x10, 0x57, 0x9e, 0x77, 0x8c, 0xa3, 0x35, 0x37, 0xa4, 0x16, 0x11, 0x02, 0xd3,
-	0x86, 0xd5, 0xc6, 0xdc, 0x90, 0xea, 0xef, 0xed, 0xbc, 0xf9, 0xe3, 0xbc, 0x25, 0x10, 0x14, 0x3d,
-	0x67, 0xb2, 0x6a, 0x1b, 0x9b, 0x95, 0x0d, 0x26, 0x2f, 0xb7, 0x8c, 0x9e, 0xeb, 0x86, 0x6f, 0xeb,
-	0x86, 0xff, 0x69, 0xf3, 0x43, 0x98, 0xca, 0x75, 0x87, 0x3a, 0x44, 0xd1, 0x7e, 0xa8, 0x8b, 0x4f,
-	0xd7, 0x1d, 0x52, 0x4d, 0xff, 0x97, 0xd7, 0xcf, 0x9e, 0x40, 0x30, 0xc8, 0x91, 0x05, 0xcc, 0x3f,
-	0x1f, 0x7d, 0x38, 0x3e, 0x3b, 0x3a, 0xd8, 0x9d, 0x90, 0x1d, 0x08, 0x8e, 0xcf, 0x4e, 0x0d, 0x72,
-	0xf6, 0x7f, 0x3a, 0xe0, 0x1f, 0xa8, 0xc5, 0x40, 0x1e, 0x83, 0x77, 0xd8, 0x96, 0x64, 0x91, 0x5d,
-	0x27, 0x38, 0x99, 0xdb, 0xa0, 0xa4, 0x93, 0x57, 0x0e, 0x79, 0x0e, 0x33, 0xb3, 0x08, 0x48, 0x94,
-	0x8d, 0x96, 0x47, 0x72, 0x2b, 0x1b, 0x6f, 0x88, 0x74, 0x42, 0x56, 0xe0, 0xeb, 0x07, 0x4e, 0x96,
-	0xd9, 0xf6, 0x4e, 0x48, 0xa2, 0x6c, 0xf4, 0xee, 0x4d, 0xa5, 0xfe, 0xd3, 0xc9, 0x32, 0xdb, 0x0e,
-	0x47, 0x12, 0x65, 0xa3, 0x2c, 0xa4, 0x93, 0xf3, 0x99, 0xde, 0x5d, 0x6f, 0xfe, 0x04, 0x00, 0x00,
-	0xff, 0xff, 0x08, 0x25, 0x21, 0x57, 0xca, 0x04, 0x00, 0x00,
+	// 856 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0x55, 0xdb, 0x8e, 0x23, 0x35,
+	0x10, 0x4d, 0x5f, 0x72, 0xe9, 0xca, 0x24, 0x44, 0xe6, 0xa2, 0x56, 0xb3, 0xc0, 0xaa, 0x05, 0x52,
+	0xb8, 0x35, 0x30, 0xbc, 0x70, 0x79, 0x62, 0x77, 0x47, 0xda, 0x59, 0xcd, 0x64, 0x90, 0x27, 0xf3,
+	0x01, 0x4e, 0xb7, 0xc9, 0x64, 0x98, 0x6e, 0x37, 0xb6, 0x7b, 0xb5, 0x79, 0x43, 0x82, 0x7f, 0xe0,
+	0x81, 0x9f, 0xe0, 0x53, 0xf8, 0x24, 0xe4, 0xb2, 0xbb, 0xd3, 0x01, 0xa1, 0x79, 0xd8, 0x37, 0x9f,
+	0xe3, 0x72, 0xb9, 0x7c, 0xaa, 0xca, 0x05, 0xd3, 0x82, 0x6f, 0x9a, 0x6d, 0x56, 0x4b, 0xa1, 0x45,
+	0xfa, 0x31, 0xcc, 0x9e, 0x73, 0x76, 0xaf, 0x6f, 0x29, 0xff, 0xa5, 0xe1, 0x4a, 0x93, 0x18, 0xc6,
+	0x8a, 0xcb, 0x97, 0xbb, 0x9c, 0xc7, 0xde, 0x63, 0x6f, 0x19, 0xd1, 0x16, 0xa6, 0x4b, 0x98, 0xb7,
+	0xa6, 0xaa, 0x16, 0x95, 0xe2, 0xe4, 0x1d, 0x18, 0x29, 0xcd, 0x74, 0xa3, 0x9c, 0xa9, 0x43, 0xe9,
+	0x12, 0x4e, 0xae, 0x35, 0xd3, 0xea, 0x61, 0x9f, 0xbf, 0xfb, 0x30, 0x73, 0xa6, 0xce, 0xe7, 0x23,
+	0x88, 0xf4, 0xae, 0xe4, 0x4a, 0xb3, 0xb2, 0x46, 0xeb, 0x90, 0x1e, 0x08, 0xf4, 0xa4, 0x99, 0xd4,
+	0xbc, 0x88, 0x7d, 0xdc, 0x6b, 0xa1, 0x89, 0xa5, 0xa9, 0x8d, 0x61, 0x1c, 0xe0, 0x86, 0x43, 0x86,
+	0x2f, 0x79, 0x29, 0xe4, 0x3e, 0x0e, 0x2d, 0x6f, 0x91, 0xf1, 0xa4, 0x6f, 0x25, 0x67, 0x85, 0x8a,
+	0x87, 0xd6, 0x93, 0x83, 0x64, 0x0e, 0xfe, 0x36, 0x8f, 0x47, 0x48, 0xfa, 0xdb, 0x9c, 0x24, 0x30,
+	0x91, 0xf6, 0x21, 0x2a, 0x1e, 0x23, 0xdb, 0x61, 0xe3, 0x9d, 0x4b, 0x29, 0xa4, 0x8a, 0x27, 0xd6,
+	0xbb, 0x45, 0xe4, 0x73, 0x98, 0x6a, 0xc9, 0x2a, 0xb5, 0xd3, 0x3b, 0x51, 0xa9, 0x38, 0x7a, 0x1c,
+	0x2c, 0xa7, 0xa7, 0xd3, 0x6c, 0xdd, 0x71, 0xb4, 0xbf, 0x9f, 0xfe, 0xea, 0x01, 0x1c, 0xf6, 0xfe,
+	0xab, 0x41, 0xd0, 0xd7, 0xe0, 0x11, 0x44, 0xb9, 0x28, 0x6b, 0x51, 0xf1, 0x4a, 0xa3, 0x0a, 0x11,
+	0x3d, 0x10, 0x84, 0x40, 0x58, 0x31, 0xa7, 0x42, 0x44, 0x71, 0x6d, 0xb8, 0x9f, 0xa4, 0x28, 0x51,
+	0x81, 0x88, 0xe2, 0xda, 0xbc, 0x52, 0x0b, 0x7c, 0x7a, 0x44, 0x7d, 0x2d, 0xd2, 0x3b, 0x80, 0x0b,
+	0xb1, 0x7d, 0x30, 0x63, 0x36, 0xe7, 0x92, 0xb3, 0x12, 0xaf, 0x9e, 0x50, 0x87, 0xc8, 0x5b, 0x30,
+	0xcc, 0x45, 0x53, 0x69, 0xbc, 0x38, 0xa0, 0x16, 0x18, 0x56, 0xed, 0xaa, 0x9c, 0xe3, 0xd5, 0x01,
+	0xb5, 0x20, 0xfd, 0xcb, 0x83, 0x11, 0xe5, 0xb9, 0x90, 0xc5, 0x03, 0x4f, 0xfd, 0x0a, 0x26, 0x25,
+	0xd7, 0xac, 0x60, 0x9a, 0xc5, 0x3e, 0x6a, 0xf8, 0x76, 0x66, 0x0f, 0x66, 0x97, 0x8e, 0x3f, 0xab,
+	0xb4, 0xdc, 0xd3, 0xce, 0xcc, 0x44, 0x5e, 0x72, 0xa5, 0xd8, 0xb6, 0x95, 0xa0, 0x85, 0xc9, 0xf7,
+	0x30, 0x3b, 0x3a, 0x44, 0x16, 0x10, 0xfc, 0xcc, 0xf7, 0xee, 0x81, 0x66, 0x69, 0xc2, 0x7d, 0xc9,
+	0xee, 0x1b, 0xee, 0x64, 0xb5, 0xe0, 0x3b, 0xff, 0x1b, 0x2f, 0x7d, 0x1f, 0x4e, 0xd6, 0x92, 0xe5,
+	0xbc, 0x15, 0x68, 0x0e, 0xfe, 0xae, 0x70, 0x47, 0xfd, 0x5d, 0x91, 0x7e, 0x06, 0x33, 0xb7, 0xef,
+	0xea, 0xf8, 0x5d, 0x18, 0xaa, 0x9a, 0x55, 0xa6, 0x35, 0x4c, 0xdc, 0xc3, 0xec, 0xba, 0x66, 0x15,
+	0xb5, 0x5c, 0xfa, 0xa7, 0x0f, 0xa1, 0xc1, 0xe6, 0x42, 0x6d, 0x8e, 0x39, 0x4f, 0x16, 0x38, 0xe7,
+	0x7e, 0xeb, 0xdc, 0x68, 0x5e, 0x33, 0xc9, 0x9d, 0xb8, 0x11, 0x75, 0xa8, 0xcb, 0x75, 0xd8, 0xcb,
+	0x75, 0xaf, 0x43, 0x86, 0xc7, 0x1d, 0x92, 0xc0, 0xa4, 0x68, 0x24, 0x33, 0x15, 0xe6, 0xaa, 0xbb,
+	0xc3, 0xe4, 0x8b, 0x9e, 0xd0, 0x63, 0x0c, 0xf8, 0x4d, 0x0c, 0xf8, 0x7f, 0x65, 0x7e, 0x0f, 0x42,
+	0xbd, 0xaf, 0x39, 0x96, 0xfd, 0xfc, 0x34, 0x42, 0xe3, 0xf5, 0xbe, 0xe6, 0x14, 0xe9, 0xd7, 0xd3,
+	0xfa, 0x5b, 0x98, 0x3e, 0x95, 0xa2, 0x6a, 0xa5, 0x5e, 0x40, 0x70, 0x27, 0x36, 0xed, 0xd1, 0x3b,
+	0xb1, 0x31, 0x6f, 0xbc, 0xdd, 0x29, 0x6d, 0x9a, 0xda, 0xc7, 0x92, 0x69, 0xa1, 0xf9, 0x79, 0xec,
+	0x51, 0x97, 0x85, 0x18, 0xc2, 0x3b, 0xb1, 0x69, 0x93, 0x10, 0x66, 0x2f, 0xc4, 0x86, 0x22, 0x93,
+	0xfe, 0xe1, 0x41, 0xf0, 0x42, 0x6c, 0xfe, 0x9d, 0xc8, 0x4e, 0x53, 0xbf, 0xa7, 0x69, 0x02, 0x13,
+	0x95, 0xdf, 0xf2, 0xa2, 0xb9, 0x6f, 0x8b, 0xaa, 0xc3, 0x26, 0x37, 0xf7, 0x9c, 0x15, 0x5c, 0x62,
+	0x16, 0x26, 0xd4, 0x21, 0xf4, 0xc3, 0x5f, 0x69, 0x4c, 0x42, 0x40, 0x71, 0x4d, 0x3e, 0x3c, 0xc4,
+	0x3d, 0xc2, 0x80, 0x20, 0x3b, 0x7b, 0xc5, 0xf3, 0x06, 0x3f, 0x84, 0xee, 0x0d, 0xbf, 0x79, 0x10,
+	0x75, 0x34, 0xfa, 0x11, 0x45, 0x5b, 0x20, 0xb8, 0x36, 0x4d, 0xd3, 0xde, 0x5f, 0x38, 0x05, 0x0e,
+	0x44, 0xbf, 0x02, 0x6c, 0x2f, 0xb6, 0xd0, 0x48, 0xce, 0xab, 0x82, 0x17, 0x6d, 0x37, 0x22, 0x40,
+	0xd6, 0xfc, 0x5a, 0xee, 0x33, 0xb0, 0xe0, 0x93, 0x4b, 0x98, 0xb4, 0x39, 0x25, 0x53, 0x18, 0x9f,
+	0xaf, 0x9e, 0x5c, 0xdd, 0xac, 0x9e, 0x2d, 0x06, 0xe4, 0x04, 0x26, 0x57, 0x37, 0x6b, 0x8b, 0x3c,
+	0xb3, 0xf5, 0xe3, 0xcd, 0x93, 0x8b, 0xf3, 0xeb, 0xe7, 0x0b, 0xdf, 0x80, 0xa7, 0x57, 0xab, 0xeb,
+	0x9b, 0xcb, 0xb3, 0x45, 0x60, 0xec, 0xce, 0x57, 0xeb, 0x33, 0xba, 0xfa, 0xe1, 0x62, 0x11, 0x9e,
+	0xfe, 0xed, 0xc1, 0xf0, 0x99, 0x99, 0x3b, 0xe4, 0x03, 0x08, 0x2e, 0xc4, 0x96, 0x4c, 0xb3, 0xc3,
+	0x77, 0x93, 0x8c, 0x5d, 0x57, 0xa7, 0x83, 0x2f, 0x3d, 0xf2, 0x29, 0x8c, 0xec, 0x9c, 0x21, 0xf3,
+	0xec, 0x68, 0x36, 0x25, 0x6f, 0x64, 0xc7, 0x03, 0x28, 0x1d, 0x90, 0x25, 0x0c, 0x71, 0x7e, 0x90,
+	0x59, 0xd6, 0x1f, 0x39, 0xc9, 0x3c, 0x3b, 0x1a, 0x2b, 0xd6, 0x12, 0x3b, 0x94, 0xcc, 0xb2, 0x7e,
+	0x27, 0x27, 0xf3, 0xec, 0xa8, 0x71, 0xd3, 0x01, 0xf9, 0x08, 0x42, 0x53, 0x44, 0xe4, 0x24, 0xeb,
+	0x95, 0x61, 0x32, 0xcb, 0xfa, 0x95, 0x95, 0x0e, 0x36, 0x23, 0x9c, 0xa0, 0x5f, 0xff, 0x33, 0x00,
+	0x51, 0xd4, 0xcf, 0xb0, 0x50, 0x07, 0x00, 0x00,
 }
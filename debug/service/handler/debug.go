@@ -6,8 +6,10 @@ import (
 	"runtime"
 	"time"
 
+	dh "github.com/stack-labs/stack/debug/handler"
 	"github.com/stack-labs/stack/debug/log"
 	proto "github.com/stack-labs/stack/debug/proto"
+	"github.com/stack-labs/stack/debug/stats"
 	"github.com/stack-labs/stack/server"
 )
 
@@ -44,9 +46,24 @@ func (d *Debug) Stats(ctx context.Context, req *proto.StatsRequest, rsp *proto.S
 	rsp.Memory = mstat.Alloc
 	rsp.Gc = mstat.PauseTotalNs
 	rsp.Threads = uint64(runtime.NumGoroutine())
+
+	for _, t := range stats.ReadTransitions(0) {
+		rsp.Transitions = append(rsp.Transitions, &proto.Transition{
+			Timestamp: t.Timestamp,
+			Component: t.Component,
+			Name:      t.Name,
+			From:      t.From,
+			To:        t.To,
+		})
+	}
+
 	return nil
 }
 
+func (d *Debug) Cron(ctx context.Context, req *proto.CronRequest, rsp *proto.CronResponse) error {
+	return dh.Crons(req, rsp)
+}
+
 func (d *Debug) Log(ctx context.Context, stream server.Stream) error {
 	req := new(proto.LogRequest)
 	if err := stream.Recv(req); err != nil {
@@ -0,0 +1,48 @@
+package stats
+
+import (
+	"github.com/stack-labs/stack/debug/buffer"
+)
+
+// Transition is a state change of a runtime component e.g a circuit breaker
+type Transition struct {
+	// Timestamp of the change
+	Timestamp int64
+	// Component that changed e.g breaker
+	Component string
+	// Name of the instance e.g the breaker key
+	Name string
+	// Previous state
+	From string
+	// Current state
+	To string
+}
+
+var (
+	// DefaultTransitions keeps the most recent state transitions
+	DefaultTransitions = buffer.New(256)
+)
+
+// RecordTransition writes a transition to the default buffer
+func RecordTransition(t *Transition) {
+	DefaultTransitions.Put(t)
+}
+
+// ReadTransitions returns the last n transitions, or all of them for n <= 0
+func ReadTransitions(n int) []*Transition {
+	if n <= 0 {
+		n = -1
+	}
+
+	var transitions []*Transition
+
+	for _, e := range DefaultTransitions.Get(n) {
+		t, ok := e.Value.(*Transition)
+		if !ok {
+			continue
+		}
+		transitions = append(transitions, t)
+	}
+
+	return transitions
+}
@@ -4,7 +4,6 @@ import (
 	"context"
 	"time"
 
-	"github.com/google/uuid"
 	"github.com/stack-labs/stack/debug/trace"
 	"github.com/stack-labs/stack/util/ring"
 )
@@ -41,38 +40,53 @@ func (t *Tracer) Read(opts ...trace.ReadOption) ([]*trace.Span, error) {
 func (t *Tracer) Start(ctx context.Context, name string) (context.Context, *trace.Span) {
 	span := &trace.Span{
 		Name:     name,
-		Trace:    uuid.New().String(),
-		Id:       uuid.New().String(),
+		Trace:    trace.NewTraceID(),
+		Id:       trace.NewSpanID(),
 		Started:  time.Now(),
 		Metadata: make(map[string]string),
 	}
 
-	// return span if no context
 	if ctx == nil {
-		return trace.ToContext(context.Background(), span.Trace, span.Id), span
+		ctx = context.Background()
 	}
+
+	params := trace.SamplingParameters{Name: name}
+
 	traceID, parentSpanID, ok := trace.FromContext(ctx)
-	// If the trace can not be found in the header,
-	// that means this is where the trace is created.
-	if !ok {
-		return trace.ToContext(ctx, span.Trace, span.Id), span
+	// If the trace can be found in the header the span continues it,
+	// otherwise this is where the trace is created.
+	if ok {
+		span.Trace = traceID
+		span.Parent = parentSpanID
+
+		// spans propagated without a sampled flag are recorded
+		sampled, found := trace.SampledFromContext(ctx)
+		params.HasParent = true
+		params.ParentSampled = sampled || !found
 	}
 
-	// set trace id
-	span.Trace = traceID
-	// set parent
-	span.Parent = parentSpanID
+	params.TraceID = span.Trace
+	span.Sampled = t.opts.Sampler == nil || t.opts.Sampler(params)
 
-	// return the span
-	return trace.ToContext(ctx, span.Trace, span.Id), span
+	return trace.NewContext(ctx, span), span
 }
 
 func (t *Tracer) Finish(s *trace.Span) error {
 	// set finished time
 	s.Duration = time.Since(s.Started)
+
+	// the span isn't recorded
+	if !s.Sampled {
+		return nil
+	}
+
 	// save the span
 	t.buffer.Put(s)
 
+	if t.opts.Exporter != nil {
+		return t.opts.Exporter.Export([]*trace.Span{s})
+	}
+
 	return nil
 }
 
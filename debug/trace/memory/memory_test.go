@@ -0,0 +1,47 @@
+package memory
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stack-labs/stack/debug/trace"
+)
+
+func TestSampling(t *testing.T) {
+	tr := NewTracer(trace.WithSampler(trace.ParentBased(trace.NeverSample())))
+
+	ctx, root := tr.Start(context.Background(), "root")
+	if root.Sampled {
+		t.Fatal("Expected the root span not to be sampled")
+	}
+
+	_, child := tr.Start(ctx, "child")
+	if child.Sampled || child.Trace != root.Trace || child.Parent != root.Id {
+		t.Fatalf("Expected a child of the root span not sampled, got %+v", child)
+	}
+
+	tr.Finish(child)
+	tr.Finish(root)
+
+	spans, err := tr.Read()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(spans) != 0 {
+		t.Fatalf("Expected no spans recorded, got %d", len(spans))
+	}
+
+	// a sampled parent from another service is followed
+	sampled := trace.NewContext(context.Background(), &trace.Span{
+		Trace:   trace.NewTraceID(),
+		Id:      trace.NewSpanID(),
+		Sampled: true,
+	})
+
+	_, s := tr.Start(sampled, "remote")
+	tr.Finish(s)
+
+	if spans, _ = tr.Read(); len(spans) != 1 {
+		t.Fatalf("Expected the span to be recorded, got %d", len(spans))
+	}
+}
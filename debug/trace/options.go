@@ -3,10 +3,28 @@ package trace
 type Options struct {
 	// Size is the size of ring buffer
 	Size int
+	// Exporter the finished spans are sent to
+	Exporter Exporter
+	// Sampler decides which spans are recorded, all by default
+	Sampler Sampler
 }
 
 type Option func(o *Options)
 
+// WithSampler sets the sampler deciding which spans are recorded
+func WithSampler(s Sampler) Option {
+	return func(o *Options) {
+		o.Sampler = s
+	}
+}
+
+// WithExporter sets the exporter the finished spans are sent to
+func WithExporter(e Exporter) Option {
+	return func(o *Options) {
+		o.Exporter = e
+	}
+}
+
 type ReadOptions struct {
 	// Trace id
 	Trace string
@@ -0,0 +1,95 @@
+package otlp
+
+import (
+	"net/http"
+	"time"
+)
+
+var (
+	// DefaultEndpoint of the collector's OTLP/HTTP traces receiver
+	DefaultEndpoint = "http://localhost:4318/v1/traces"
+	// DefaultBatchSize is the max number of spans sent in a request
+	DefaultBatchSize = 512
+	// DefaultQueueSize is the max number of spans waiting to be sent, more are dropped
+	DefaultQueueSize = 2048
+	// DefaultFlushInterval is the interval at which the queued spans are sent
+	DefaultFlushInterval = time.Second * 5
+	// DefaultTimeout of a request to the collector
+	DefaultTimeout = time.Second * 10
+)
+
+type Options struct {
+	// Endpoint is the url the spans are posted to
+	Endpoint string
+	// ServiceName is set as the service.name resource attribute
+	ServiceName string
+	// Headers added to every request e.g for authentication
+	Headers       map[string]string
+	BatchSize     int
+	QueueSize     int
+	FlushInterval time.Duration
+	Timeout       time.Duration
+	// Client used to send the requests
+	Client *http.Client
+}
+
+type Option func(o *Options)
+
+// Endpoint sets the url the spans are posted to
+func Endpoint(url string) Option {
+	return func(o *Options) {
+		o.Endpoint = url
+	}
+}
+
+// ServiceName sets the service.name resource attribute
+func ServiceName(name string) Option {
+	return func(o *Options) {
+		o.ServiceName = name
+	}
+}
+
+// Header adds a header to every request
+func Header(key, val string) Option {
+	return func(o *Options) {
+		if o.Headers == nil {
+			o.Headers = make(map[string]string)
+		}
+		o.Headers[key] = val
+	}
+}
+
+// BatchSize sets the max number of spans sent in a request
+func BatchSize(n int) Option {
+	return func(o *Options) {
+		o.BatchSize = n
+	}
+}
+
+// QueueSize sets the max number of spans waiting to be sent
+func QueueSize(n int) Option {
+	return func(o *Options) {
+		o.QueueSize = n
+	}
+}
+
+// FlushInterval sets the interval at which the queued spans are sent
+func FlushInterval(d time.Duration) Option {
+	return func(o *Options) {
+		o.FlushInterval = d
+	}
+}
+
+// Timeout sets the timeout of a request to the collector
+func Timeout(d time.Duration) Option {
+	return func(o *Options) {
+		o.Timeout = d
+	}
+}
+
+// WithClient sets the http client used to send the requests
+func WithClient(c *http.Client) Option {
+	return func(o *Options) {
+		o.Client = c
+	}
+}
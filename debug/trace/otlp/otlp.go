@@ -0,0 +1,383 @@
+// Package otlp is a trace exporter sending spans to an OpenTelemetry collector with OTLP/HTTP JSON
+package otlp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/stack-labs/stack/debug/trace"
+	"github.com/stack-labs/stack/util/log"
+)
+
+type exporter struct {
+	opts Options
+
+	queue chan *trace.Span
+	flush chan chan error
+	exit  chan bool
+	once  sync.Once
+	wg    sync.WaitGroup
+}
+
+// OTLP/HTTP JSON payload, see opentelemetry-proto trace/v1/trace.proto
+type exportRequest struct {
+	ResourceSpans []resourceSpans `json:"resourceSpans"`
+}
+
+type resourceSpans struct {
+	Resource   resource     `json:"resource"`
+	ScopeSpans []scopeSpans `json:"scopeSpans"`
+}
+
+type resource struct {
+	Attributes []keyValue `json:"attributes"`
+}
+
+type scopeSpans struct {
+	Scope scope  `json:"scope"`
+	Spans []span `json:"spans"`
+}
+
+type scope struct {
+	Name string `json:"name"`
+}
+
+type span struct {
+	TraceID           string     `json:"traceId"`
+	SpanID            string     `json:"spanId"`
+	ParentSpanID      string     `json:"parentSpanId,omitempty"`
+	Name              string     `json:"name"`
+	Kind              int        `json:"kind"`
+	StartTimeUnixNano string     `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string     `json:"endTimeUnixNano"`
+	Attributes        []keyValue `json:"attributes,omitempty"`
+	Events            []event    `json:"events,omitempty"`
+	Status            status     `json:"status"`
+}
+
+type event struct {
+	TimeUnixNano string     `json:"timeUnixNano"`
+	Name         string     `json:"name"`
+	Attributes   []keyValue `json:"attributes,omitempty"`
+}
+
+type status struct {
+	Code    int    `json:"code,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+type keyValue struct {
+	Key   string   `json:"key"`
+	Value anyValue `json:"value"`
+}
+
+type anyValue struct {
+	StringValue *string  `json:"stringValue,omitempty"`
+	BoolValue   *bool    `json:"boolValue,omitempty"`
+	IntValue    *string  `json:"intValue,omitempty"`
+	DoubleValue *float64 `json:"doubleValue,omitempty"`
+}
+
+const (
+	kindInternal = 1
+	kindServer   = 2
+	kindClient   = 3
+	kindProducer = 4
+	kindConsumer = 5
+
+	statusOK    = 1
+	statusError = 2
+)
+
+func stringValue(v string) anyValue {
+	return anyValue{StringValue: &v}
+}
+
+// value converts a typed attribute, int64 is a string in the JSON encoding
+func value(v interface{}) anyValue {
+	switch t := v.(type) {
+	case string:
+		return stringValue(t)
+	case bool:
+		return anyValue{BoolValue: &t}
+	case int64:
+		i := strconv.FormatInt(t, 10)
+		return anyValue{IntValue: &i}
+	case float64:
+		return anyValue{DoubleValue: &t}
+	}
+	return stringValue(fmt.Sprintf("%v", v))
+}
+
+func attributes(attrs map[string]interface{}) []keyValue {
+	if len(attrs) == 0 {
+		return nil
+	}
+
+	kvs := make([]keyValue, 0, len(attrs))
+	for k, v := range attrs {
+		kvs = append(kvs, keyValue{Key: k, Value: value(v)})
+	}
+
+	sort.Slice(kvs, func(i, j int) bool {
+		return kvs[i].Key < kvs[j].Key
+	})
+
+	return kvs
+}
+
+func (e *exporter) Export(spans []*trace.Span) error {
+	for _, s := range spans {
+		select {
+		case e.queue <- s:
+		default:
+			log.Warnf("trace exporter queue is full, dropping span %s", s.Name)
+		}
+	}
+	return nil
+}
+
+// Flush sends the queued spans and waits for the result
+func (e *exporter) Flush() error {
+	ch := make(chan error, 1)
+	select {
+	case e.flush <- ch:
+		return <-ch
+	case <-e.exit:
+		return nil
+	}
+}
+
+func (e *exporter) Stop() error {
+	err := e.Flush()
+	e.once.Do(func() {
+		close(e.exit)
+	})
+	e.wg.Wait()
+	return err
+}
+
+func (e *exporter) run() {
+	defer e.wg.Done()
+
+	ticker := time.NewTicker(e.opts.FlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]*trace.Span, 0, e.opts.BatchSize)
+
+	send := func() error {
+		var err error
+
+		// drain the queue in batches
+	drain:
+		for {
+			select {
+			case s := <-e.queue:
+				batch = append(batch, s)
+				if len(batch) < e.opts.BatchSize {
+					continue
+				}
+				if serr := e.send(batch); serr != nil {
+					err = serr
+				}
+				batch = batch[:0]
+			default:
+				break drain
+			}
+		}
+
+		if len(batch) > 0 {
+			if serr := e.send(batch); serr != nil {
+				err = serr
+			}
+			batch = batch[:0]
+		}
+		return err
+	}
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := send(); err != nil {
+				log.Errorf("trace exporter error: %v", err)
+			}
+		case ch := <-e.flush:
+			ch <- send()
+		case <-e.exit:
+			return
+		}
+	}
+}
+
+func (e *exporter) send(spans []*trace.Span) error {
+	b, err := json.Marshal(e.request(spans))
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), e.opts.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequest("POST", e.opts.Endpoint, bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range e.opts.Headers {
+		req.Header.Set(k, v)
+	}
+
+	rsp, err := e.opts.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer rsp.Body.Close()
+
+	if rsp.StatusCode < 200 || rsp.StatusCode > 299 {
+		body, _ := ioutil.ReadAll(io.LimitReader(rsp.Body, 1024))
+		return fmt.Errorf("collector returned %s: %s", rsp.Status, string(body))
+	}
+
+	// drain the body to reuse the connection
+	io.Copy(ioutil.Discard, rsp.Body)
+
+	return nil
+}
+
+func (e *exporter) request(spans []*trace.Span) *exportRequest {
+	out := make([]span, 0, len(spans))
+
+	for _, s := range spans {
+		start := s.Started.UnixNano()
+
+		sp := span{
+			TraceID:           normalize(s.Trace, 32),
+			SpanID:            normalize(s.Id, 16),
+			ParentSpanID:      normalize(s.Parent, 16),
+			Name:              s.Name,
+			Kind:              kind(s.Type),
+			StartTimeUnixNano: strconv.FormatInt(start, 10),
+			EndTimeUnixNano:   strconv.FormatInt(start+s.Duration.Nanoseconds(), 10),
+		}
+
+		// the metadata are string attributes, typed attributes take precedence
+		attrs := make(map[string]interface{}, len(s.Metadata)+len(s.Attributes))
+		for k, v := range s.Metadata {
+			attrs[k] = v
+		}
+		for k, v := range s.Attributes {
+			attrs[k] = v
+		}
+		sp.Attributes = attributes(attrs)
+
+		for _, ev := range s.Events {
+			sp.Events = append(sp.Events, event{
+				TimeUnixNano: strconv.FormatInt(ev.Timestamp.UnixNano(), 10),
+				Name:         ev.Name,
+				Attributes:   attributes(ev.Attributes),
+			})
+		}
+
+		switch s.Status.Code {
+		case trace.StatusOK:
+			sp.Status = status{Code: statusOK}
+		case trace.StatusError:
+			sp.Status = status{Code: statusError, Message: s.Status.Message}
+		default:
+			// spans which only recorded the error in the metadata
+			if msg, ok := s.Metadata["error"]; ok {
+				sp.Status = status{Code: statusError, Message: msg}
+			}
+		}
+
+		out = append(out, sp)
+	}
+
+	return &exportRequest{
+		ResourceSpans: []resourceSpans{
+			{
+				Resource: resource{
+					Attributes: []keyValue{
+						{Key: "service.name", Value: stringValue(e.opts.ServiceName)},
+					},
+				},
+				ScopeSpans: []scopeSpans{
+					{
+						Scope: scope{Name: "github.com/stack-labs/stack/debug/trace"},
+						Spans: out,
+					},
+				},
+			},
+		},
+	}
+}
+
+func kind(t trace.SpanType) int {
+	switch t {
+	case trace.SpanTypeRequestInbound:
+		return kindServer
+	case trace.SpanTypeRequestOutbound:
+		return kindClient
+	case trace.SpanTypePublish:
+		return kindProducer
+	case trace.SpanTypeConsume:
+		return kindConsumer
+	}
+	return kindInternal
+}
+
+// normalize converts legacy uuid ids to the hex ids of n characters
+func normalize(id string, n int) string {
+	id = strings.ToLower(strings.Replace(id, "-", "", -1))
+	if len(id) > n {
+		return id[:n]
+	}
+	return id
+}
+
+// NewExporter returns an exporter posting batches of spans to the collector
+// in the background. Stop must be called to send the spans left on exit.
+func NewExporter(opts ...Option) trace.Exporter {
+	options := Options{
+		Endpoint:      DefaultEndpoint,
+		BatchSize:     DefaultBatchSize,
+		QueueSize:     DefaultQueueSize,
+		FlushInterval: DefaultFlushInterval,
+		Timeout:       DefaultTimeout,
+		Client:        http.DefaultClient,
+	}
+
+	for _, o := range opts {
+		o(&options)
+	}
+
+	if options.BatchSize < 1 {
+		options.BatchSize = DefaultBatchSize
+	}
+	if options.FlushInterval <= 0 {
+		options.FlushInterval = DefaultFlushInterval
+	}
+
+	e := &exporter{
+		opts:  options,
+		queue: make(chan *trace.Span, options.QueueSize),
+		flush: make(chan chan error),
+		exit:  make(chan bool),
+	}
+
+	e.wg.Add(1)
+	go e.run()
+
+	return e
+}
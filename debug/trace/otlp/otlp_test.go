@@ -0,0 +1,101 @@
+package otlp
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stack-labs/stack/debug/trace"
+	"github.com/stack-labs/stack/debug/trace/memory"
+)
+
+func TestExporter(t *testing.T) {
+	var mtx sync.Mutex
+	var requests []*exportRequest
+
+	// stand in for the collector
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/traces" || r.Header.Get("Content-Type") != "application/json" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if r.Header.Get("Authorization") != "token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		req := new(exportRequest)
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		mtx.Lock()
+		requests = append(requests, req)
+		mtx.Unlock()
+	}))
+	defer collector.Close()
+
+	e := NewExporter(
+		Endpoint(collector.URL+"/v1/traces"),
+		ServiceName("foo"),
+		Header("Authorization", "token"),
+		BatchSize(1),
+	)
+	tr := memory.NewTracer(trace.WithExporter(e))
+
+	ctx, parent := tr.Start(context.Background(), "foo.Parent")
+	parent.Type = trace.SpanTypeRequestInbound
+
+	_, child := tr.Start(ctx, "bar.Child")
+	child.Type = trace.SpanTypeRequestOutbound
+	child.Metadata["error"] = "failed"
+
+	tr.Finish(child)
+	tr.Finish(parent)
+
+	if err := e.Stop(); err != nil {
+		t.Fatal(err)
+	}
+
+	mtx.Lock()
+	defer mtx.Unlock()
+
+	if len(requests) != 2 {
+		t.Fatalf("Expected a request per span, got %d", len(requests))
+	}
+
+	rs := requests[0].ResourceSpans[0]
+	if v := rs.Resource.Attributes[0].Value.StringValue; v == nil || *v != "foo" {
+		t.Fatalf("Expected service name foo, got %v", v)
+	}
+
+	sp := rs.ScopeSpans[0].Spans[0]
+	if sp.Name != "bar.Child" || sp.Kind != kindClient || sp.Status.Code != statusError {
+		t.Fatalf("Unexpected span %+v", sp)
+	}
+	if sp.TraceID != parent.Trace || sp.ParentSpanID != parent.Id || len(sp.SpanID) != 16 {
+		t.Fatalf("Expected the child of %s/%s, got %+v", parent.Trace, parent.Id, sp)
+	}
+
+	if sp := requests[1].ResourceSpans[0].ScopeSpans[0].Spans[0]; sp.Kind != kindServer || len(sp.ParentSpanID) > 0 {
+		t.Fatalf("Unexpected root span %+v", sp)
+	}
+}
+
+func TestExporterError(t *testing.T) {
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer collector.Close()
+
+	e := NewExporter(Endpoint(collector.URL))
+	e.Export([]*trace.Span{{Name: "foo", Trace: trace.NewTraceID(), Id: trace.NewSpanID()}})
+
+	if err := e.Stop(); err == nil {
+		t.Fatal("Expected the collector error")
+	}
+}
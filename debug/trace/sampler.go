@@ -0,0 +1,84 @@
+package trace
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"math"
+
+	"github.com/stack-labs/stack/util/ratelimit"
+)
+
+// SamplingParameters are the inputs of a sampling decision
+type SamplingParameters struct {
+	// TraceID of the new span
+	TraceID string
+	// Name of the new span
+	Name string
+	// HasParent is true if the span continues a trace from the context
+	HasParent bool
+	// ParentSampled is the sampled flag propagated by the parent
+	ParentSampled bool
+}
+
+// Sampler decides whether a new span is recorded
+type Sampler func(p SamplingParameters) bool
+
+// AlwaysSample records every span
+func AlwaysSample() Sampler {
+	return func(p SamplingParameters) bool {
+		return true
+	}
+}
+
+// NeverSample records no span
+func NeverSample() Sampler {
+	return func(p SamplingParameters) bool {
+		return false
+	}
+}
+
+// ProbabilitySampler records a fraction of the traces. The decision is
+// derived from the trace id so every service makes the same one.
+func ProbabilitySampler(fraction float64) Sampler {
+	if fraction >= 1 {
+		return AlwaysSample()
+	}
+	if fraction <= 0 {
+		return NeverSample()
+	}
+
+	bound := uint64(fraction * math.MaxUint64)
+
+	return func(p SamplingParameters) bool {
+		b, err := hex.DecodeString(normalizeID(p.TraceID, 32))
+		if err != nil || len(b) < 16 {
+			return false
+		}
+		// the random part of the id is the low 8 bytes
+		return binary.BigEndian.Uint64(b[8:]) < bound
+	}
+}
+
+// RateLimitSampler records up to perSecond traces a second
+func RateLimitSampler(perSecond float64) Sampler {
+	if perSecond <= 0 {
+		return NeverSample()
+	}
+
+	bucket := ratelimit.NewBucket(perSecond, int(math.Ceil(perSecond)))
+
+	return func(p SamplingParameters) bool {
+		return bucket.Allow()
+	}
+}
+
+// ParentBased follows the decision of the parent of a span and uses
+// the root sampler for the spans which start a trace
+func ParentBased(root Sampler) Sampler {
+	return func(p SamplingParameters) bool {
+		if p.HasParent {
+			return p.ParentSampled
+		}
+		return root(p)
+	}
+}
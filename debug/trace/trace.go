@@ -3,6 +3,12 @@ package trace
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/stack-labs/stack/pkg/metadata"
@@ -18,6 +24,15 @@ type Tracer interface {
 	Read(...ReadOption) ([]*Span, error)
 }
 
+// Exporter sends the finished spans to a collector. Export is called
+// by the tracer on finish so it should not block for long.
+type Exporter interface {
+	// Export the spans
+	Export(spans []*Span) error
+	// Stop flushes the spans not exported yet
+	Stop() error
+}
+
 // SpanType describe the nature of the trace span
 type SpanType int
 
@@ -26,8 +41,55 @@ const (
 	SpanTypeRequestInbound SpanType = iota
 	// SpanTypeRequestOutbound is a span created when making a service call
 	SpanTypeRequestOutbound
+	// SpanTypePublish is a span created when publishing a message
+	SpanTypePublish
+	// SpanTypeConsume is a span created when processing a message
+	SpanTypeConsume
+	// SpanTypeInternal is a span of work within the service
+	SpanTypeInternal
 )
 
+func (t SpanType) String() string {
+	switch t {
+	case SpanTypeRequestInbound:
+		return "inbound"
+	case SpanTypeRequestOutbound:
+		return "outbound"
+	case SpanTypePublish:
+		return "publish"
+	case SpanTypeConsume:
+		return "consume"
+	case SpanTypeInternal:
+		return "internal"
+	}
+	return "unknown"
+}
+
+// StatusCode of a span
+type StatusCode int
+
+const (
+	// StatusUnset is the status of a span which didn't set one
+	StatusUnset StatusCode = iota
+	// StatusOK marks the span as successful
+	StatusOK
+	// StatusError marks the span as failed
+	StatusError
+)
+
+// Status of a span
+type Status struct {
+	Code    StatusCode
+	Message string
+}
+
+// Event is a timestamped annotation of a span
+type Event struct {
+	Name       string
+	Timestamp  time.Time
+	Attributes map[string]interface{}
+}
+
 // Span is used to record an entry
 type Span struct {
 	// Id of the trace
@@ -46,17 +108,167 @@ type Span struct {
 	Metadata map[string]string
 	// Type
 	Type SpanType
+	// Attributes of the span, values are a string, bool, int64 or float64
+	Attributes map[string]interface{}
+	// Events recorded during the span
+	Events []*Event
+	// Status of the span
+	Status Status
+	// Sampled spans are recorded and exported
+	Sampled bool
+
+	mtx sync.Mutex
+}
+
+// SetAttribute sets a typed attribute of the span. Ints are stored as
+// int64, floats as float64 and any other type as its string.
+func (s *Span) SetAttribute(key string, value interface{}) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	if s.Attributes == nil {
+		s.Attributes = make(map[string]interface{})
+	}
+	s.Attributes[key] = attributeValue(value)
+}
+
+// AddEvent records an event at the current time
+func (s *Span) AddEvent(name string, attrs map[string]interface{}) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	e := &Event{
+		Name:      name,
+		Timestamp: time.Now(),
+	}
+
+	if len(attrs) > 0 {
+		e.Attributes = make(map[string]interface{}, len(attrs))
+		for k, v := range attrs {
+			e.Attributes[k] = attributeValue(v)
+		}
+	}
+
+	s.Events = append(s.Events, e)
+}
+
+// SetStatus sets the status of the span
+func (s *Span) SetStatus(code StatusCode, message string) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	s.Status = Status{Code: code, Message: message}
+}
+
+// SetError marks the span as failed with the error. The error is also
+// kept in the metadata for the readers of the debug handler.
+func (s *Span) SetError(err error) {
+	if err == nil {
+		return
+	}
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	s.Status = Status{Code: StatusError, Message: err.Error()}
+	if s.Metadata == nil {
+		s.Metadata = make(map[string]string)
+	}
+	s.Metadata["error"] = err.Error()
+}
+
+func attributeValue(v interface{}) interface{} {
+	switch t := v.(type) {
+	case string, bool, int64, float64:
+		return t
+	case int:
+		return int64(t)
+	case int8:
+		return int64(t)
+	case int16:
+		return int64(t)
+	case int32:
+		return int64(t)
+	case uint:
+		return int64(t)
+	case uint8:
+		return int64(t)
+	case uint16:
+		return int64(t)
+	case uint32:
+		return int64(t)
+	case uint64:
+		return int64(t)
+	case float32:
+		return float64(t)
+	case fmt.Stringer:
+		return t.String()
+	}
+	return fmt.Sprintf("%v", v)
 }
 
 const (
 	traceIDKey = "Micro-Trace-Id"
 	spanIDKey  = "Micro-Span-Id"
+
+	// W3C trace context headers
+	traceParentKey = "traceparent"
+	traceStateKey  = "tracestate"
 )
 
-// FromContext returns a span from context
+// SampledFromContext returns the sampled flag of the W3C traceparent header
+func SampledFromContext(ctx context.Context) (sampled bool, isFound bool) {
+	md, ok := metadata.FromContext(ctx)
+	if !ok {
+		return false, false
+	}
+
+	tp, ok := getHeader(md, traceParentKey)
+	if !ok {
+		return false, false
+	}
+	if _, _, ok := parseTraceParent(tp); !ok {
+		return false, false
+	}
+
+	flags, err := hex.DecodeString(strings.Split(strings.TrimSpace(tp), "-")[3])
+	if err != nil {
+		return false, false
+	}
+
+	return flags[0]&0x01 == 0x01, true
+}
+
+// NewContext saves the trace, id and sampled flag of the span in the context
+func NewContext(ctx context.Context, s *Span) context.Context {
+	ctx = ToContext(ctx, s.Trace, s.Id)
+	if s.Sampled {
+		return ctx
+	}
+
+	// mark as not sampled
+	md, _ := metadata.FromContext(ctx)
+	md[traceParentKey] = md[traceParentKey][:len(md[traceParentKey])-2] + "00"
+
+	return ctx
+}
+
+// FromContext returns a span from context. The W3C traceparent header is
+// used if valid, otherwise the legacy trace and span id headers.
 func FromContext(ctx context.Context) (traceID string, parentSpanID string, isFound bool) {
-	traceID, traceOk := metadata.Get(ctx, traceIDKey)
-	microID, microOk := metadata.Get(ctx, "Micro-Id")
+	md, ok := metadata.FromContext(ctx)
+	if !ok {
+		return
+	}
+
+	if tp, ok := getHeader(md, traceParentKey); ok {
+		if traceID, parentSpanID, ok = parseTraceParent(tp); ok {
+			return traceID, parentSpanID, true
+		}
+	}
+
+	traceID, traceOk := getHeader(md, traceIDKey)
+	microID, microOk := getHeader(md, "Micro-Id")
 	if !traceOk && !microOk {
 		isFound = false
 		return
@@ -64,16 +276,143 @@ func FromContext(ctx context.Context) (traceID string, parentSpanID string, isFo
 	if !traceOk {
 		traceID = microID
 	}
-	parentSpanID, ok := metadata.Get(ctx, spanIDKey)
-	return traceID, parentSpanID, ok
+	parentSpanID, ok = getHeader(md, spanIDKey)
+	return normalizeID(traceID, 32), normalizeID(parentSpanID, 16), ok
 }
 
-// ToContext saves the trace and span ids in the context
+// ToContext saves the trace and span ids in the context as the W3C
+// traceparent header and the legacy trace and span id headers. The
+// tracestate header is kept as is.
 func ToContext(ctx context.Context, traceID, parentSpanID string) context.Context {
-	return metadata.MergeContext(ctx, map[string]string{
-		traceIDKey: traceID,
-		spanIDKey:  parentSpanID,
-	}, true)
+	md, _ := metadata.FromContext(ctx)
+	md = metadata.Copy(md)
+
+	state, hasState := getHeader(md, traceStateKey)
+
+	// remove the headers set with another case e.g by http or grpc
+	for k := range md {
+		switch strings.ToLower(k) {
+		case traceParentKey, traceStateKey, strings.ToLower(traceIDKey), strings.ToLower(spanIDKey):
+			delete(md, k)
+		}
+	}
+
+	md[traceIDKey] = traceID
+	md[spanIDKey] = parentSpanID
+	md[traceParentKey] = formatTraceParent(traceID, parentSpanID)
+	if hasState {
+		md[traceStateKey] = state
+	}
+
+	return metadata.NewContext(ctx, md)
+}
+
+// StateFromContext returns the W3C tracestate header of the context
+func StateFromContext(ctx context.Context) (string, bool) {
+	md, ok := metadata.FromContext(ctx)
+	if !ok {
+		return "", false
+	}
+	return getHeader(md, traceStateKey)
+}
+
+// NewTraceID returns a random W3C trace id of 32 hex characters
+func NewTraceID() string {
+	return randomID(16)
+}
+
+// NewSpanID returns a random W3C span id of 16 hex characters
+func NewSpanID() string {
+	return randomID(8)
+}
+
+func randomID(n int) string {
+	b := make([]byte, n)
+	for {
+		if _, err := rand.Read(b); err != nil {
+			panic(err)
+		}
+		// all zero ids are invalid
+		for _, c := range b {
+			if c != 0 {
+				return hex.EncodeToString(b)
+			}
+		}
+	}
+}
+
+// getHeader returns a header regardless of its case, grpc lower cases
+// the headers and http canonicalizes them
+func getHeader(md metadata.Metadata, key string) (string, bool) {
+	if v, ok := md[key]; ok {
+		return v, true
+	}
+	for k, v := range md {
+		if strings.EqualFold(k, key) {
+			return v, true
+		}
+	}
+	return "", false
+}
+
+// parseTraceParent parses a traceparent header e.g
+// 00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01
+func parseTraceParent(tp string) (traceID, spanID string, ok bool) {
+	parts := strings.Split(strings.TrimSpace(tp), "-")
+	if len(parts) < 4 {
+		return "", "", false
+	}
+
+	version, traceID, spanID, flags := parts[0], parts[1], parts[2], parts[3]
+
+	// version ff is invalid and version 00 has exactly 4 parts
+	if !isHex(version, 2) || version == "ff" || (version == "00" && len(parts) != 4) {
+		return "", "", false
+	}
+	if !isHex(traceID, 32) || traceID == strings.Repeat("0", 32) {
+		return "", "", false
+	}
+	if !isHex(spanID, 16) || spanID == strings.Repeat("0", 16) {
+		return "", "", false
+	}
+	if !isHex(flags, 2) {
+		return "", "", false
+	}
+
+	return traceID, spanID, true
+}
+
+// formatTraceParent returns the traceparent header of sampled ids
+func formatTraceParent(traceID, spanID string) string {
+	return "00-" + normalizeID(traceID, 32) + "-" + normalizeID(spanID, 16) + "-01"
+}
+
+// normalizeID converts an id such as a uuid to n lower case hex characters
+func normalizeID(id string, n int) string {
+	if len(id) == 0 {
+		return id
+	}
+
+	id = strings.ToLower(strings.Replace(id, "-", "", -1))
+	if isHex(id, len(id)) && len(id) >= n {
+		return id[:n]
+	}
+
+	// hash ids which aren't hex
+	h := sha256.Sum256([]byte(id))
+	return hex.EncodeToString(h[:])[:n]
+}
+
+func isHex(s string, n int) bool {
+	if len(s) != n || n == 0 {
+		return false
+	}
+	for _, c := range s {
+		if !(c >= '0' && c <= '9' || c >= 'a' && c <= 'f') {
+			return false
+		}
+	}
+	return true
 }
 
 var (
@@ -0,0 +1,169 @@
+package trace
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stack-labs/stack/pkg/metadata"
+)
+
+func TestTraceParent(t *testing.T) {
+	testData := []struct {
+		header string
+		valid  bool
+	}{
+		{"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", true},
+		{"01-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra", true},
+		{"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra", false},
+		{"ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", false},
+		{"00-00000000000000000000000000000000-00f067aa0ba902b7-01", false},
+		{"00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01", false},
+		{"00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01", false},
+		{"00-4bf92f3577b34da6-00f067aa0ba902b7-01", false},
+	}
+
+	for _, d := range testData {
+		traceID, spanID, ok := parseTraceParent(d.header)
+		if ok != d.valid {
+			t.Fatalf("Expected %s valid %v, got %v", d.header, d.valid, ok)
+		}
+		if ok && (traceID != "4bf92f3577b34da6a3ce929d0e0e4736" || spanID != "00f067aa0ba902b7") {
+			t.Fatalf("Unexpected ids of %s: %s %s", d.header, traceID, spanID)
+		}
+	}
+}
+
+func TestContext(t *testing.T) {
+	// grpc lower cases the headers
+	ctx := metadata.NewContext(context.Background(), metadata.Metadata{
+		"traceparent": "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		"tracestate":  "congo=t61rcWkgMzE",
+	})
+
+	traceID, spanID, ok := FromContext(ctx)
+	if !ok || traceID != "4bf92f3577b34da6a3ce929d0e0e4736" || spanID != "00f067aa0ba902b7" {
+		t.Fatalf("Unexpected trace %s span %s found %v", traceID, spanID, ok)
+	}
+
+	ctx = ToContext(ctx, traceID, "b7ad6b7169203331")
+
+	md, _ := metadata.FromContext(ctx)
+	if v := md["traceparent"]; v != "00-4bf92f3577b34da6a3ce929d0e0e4736-b7ad6b7169203331-01" {
+		t.Fatalf("Unexpected traceparent %s", v)
+	}
+	if v, _ := StateFromContext(ctx); v != "congo=t61rcWkgMzE" {
+		t.Fatalf("Expected the tracestate to be kept, got %s", v)
+	}
+	if v := md[spanIDKey]; v != "b7ad6b7169203331" {
+		t.Fatalf("Expected the legacy span header to be set, got %s", v)
+	}
+
+	// http canonicalizes the headers
+	ctx = metadata.NewContext(context.Background(), metadata.Metadata{
+		"Traceparent": "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+	})
+	ctx = ToContext(ctx, "4bf92f3577b34da6a3ce929d0e0e4736", "b7ad6b7169203331")
+	md, _ = metadata.FromContext(ctx)
+	if _, ok := md["Traceparent"]; ok {
+		t.Fatal("Expected the header with another case to be replaced")
+	}
+}
+
+func TestLegacyContext(t *testing.T) {
+	ctx := metadata.NewContext(context.Background(), metadata.Metadata{
+		traceIDKey: "9c1d4b4e-6e2a-4d1b-9a53-5a0f7c3e1f22",
+		spanIDKey:  "0b9a1c2d-3e4f-5a6b-7c8d-9e0f1a2b3c4d",
+	})
+
+	traceID, spanID, ok := FromContext(ctx)
+	if !ok {
+		t.Fatal("Expected the legacy headers to be read")
+	}
+	if traceID != "9c1d4b4e6e2a4d1b9a535a0f7c3e1f22" || spanID != "0b9a1c2d3e4f5a6b" {
+		t.Fatalf("Unexpected trace %s span %s", traceID, spanID)
+	}
+}
+
+func TestSpan(t *testing.T) {
+	s := &Span{}
+
+	s.SetAttribute("count", 3)
+	s.SetAttribute("ratio", float32(0.5))
+	s.SetAttribute("ok", true)
+	s.AddEvent("retry", map[string]interface{}{"attempt": uint8(2)})
+	s.SetError(errors.New("failed"))
+
+	if v, ok := s.Attributes["count"].(int64); !ok || v != 3 {
+		t.Fatalf("Expected int64 attribute, got %T", s.Attributes["count"])
+	}
+	if _, ok := s.Attributes["ratio"].(float64); !ok {
+		t.Fatalf("Expected float64 attribute, got %T", s.Attributes["ratio"])
+	}
+	if len(s.Events) != 1 || s.Events[0].Attributes["attempt"] != int64(2) {
+		t.Fatalf("Unexpected events %+v", s.Events)
+	}
+	if s.Status.Code != StatusError || s.Status.Message != "failed" || s.Metadata["error"] != "failed" {
+		t.Fatalf("Unexpected status %+v", s.Status)
+	}
+}
+
+func TestSamplers(t *testing.T) {
+	n := 0
+	sampler := ProbabilitySampler(0.25)
+	for i := 0; i < 10000; i++ {
+		if sampler(SamplingParameters{TraceID: NewTraceID()}) {
+			n++
+		}
+	}
+	if n < 2000 || n > 3000 {
+		t.Fatalf("Expected about 2500 sampled traces, got %d", n)
+	}
+
+	// every service makes the same decision
+	traceID := NewTraceID()
+	if sampler(SamplingParameters{TraceID: traceID}) != ProbabilitySampler(0.25)(SamplingParameters{TraceID: traceID}) {
+		t.Fatal("Expected the decision to depend on the trace id")
+	}
+
+	n = 0
+	sampler = RateLimitSampler(5)
+	for i := 0; i < 100; i++ {
+		if sampler(SamplingParameters{}) {
+			n++
+		}
+	}
+	if n != 5 {
+		t.Fatalf("Expected 5 sampled traces, got %d", n)
+	}
+
+	sampler = ParentBased(NeverSample())
+	if !sampler(SamplingParameters{HasParent: true, ParentSampled: true}) {
+		t.Fatal("Expected the sampled parent to be followed")
+	}
+	if sampler(SamplingParameters{}) {
+		t.Fatal("Expected the root sampler to be used without a parent")
+	}
+}
+
+func TestSampledContext(t *testing.T) {
+	ctx := NewContext(context.Background(), &Span{
+		Trace: "4bf92f3577b34da6a3ce929d0e0e4736",
+		Id:    "00f067aa0ba902b7",
+	})
+
+	sampled, ok := SampledFromContext(ctx)
+	if !ok || sampled {
+		t.Fatalf("Expected a span not sampled, got %v found %v", sampled, ok)
+	}
+
+	ctx = NewContext(ctx, &Span{
+		Trace:   "4bf92f3577b34da6a3ce929d0e0e4736",
+		Id:      "b7ad6b7169203331",
+		Sampled: true,
+	})
+
+	if sampled, _ = SampledFromContext(ctx); !sampled {
+		t.Fatal("Expected a sampled span")
+	}
+}
@@ -3,5 +3,8 @@ module github.com/stack-labs/stack/plugin/broker/nats
 go 1.15
 
 require (
+	github.com/nats-io/nats.go v1.10.0
 	github.com/stack-labs/stack v1.0.1-rc2
 )
+
+replace github.com/stack-labs/stack => ../../..
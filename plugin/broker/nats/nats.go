@@ -87,6 +87,13 @@ func setAddrs(addrs []string) []string {
 	return cAddrs
 }
 
+// Connected reports whether the broker is connected to nats
+func (n *natsBroker) Connected() bool {
+	n.RLock()
+	defer n.RUnlock()
+	return n.conn != nil && n.conn.IsConnected()
+}
+
 func (n *natsBroker) Connect() error {
 	n.Lock()
 	defer n.Unlock()
@@ -163,6 +170,8 @@ func (n *natsBroker) Subscribe(topic string, handler broker.Handler, opts ...bro
 		o(&opt)
 	}
 
+	handler = broker.NewRetryHandler(n, topic, handler, opt)
+
 	fn := func(msg *nats.Msg) {
 		var m broker.Message
 		if err := n.opts.Codec.Unmarshal(msg.Data, &m); err != nil {
@@ -0,0 +1,15 @@
+module github.com/stack-labs/stack/plugin/compress/zstd
+
+go 1.22
+
+replace github.com/stack-labs/stack => ../../..
+
+require (
+	github.com/klauspost/compress v1.18.0
+	github.com/stack-labs/stack v1.0.1-rc2
+)
+
+require (
+	github.com/golang/snappy v1.0.0 // indirect
+	google.golang.org/grpc v1.29.1 // indirect
+)
@@ -0,0 +1,83 @@
+// Package zstd provides the zstd compressor of message bodies, import it
+// to compress with the zstd content encoding
+package zstd
+
+import (
+	"io"
+	"sync"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/stack-labs/stack/codec/compress"
+)
+
+// Name is the content encoding of zstd
+const Name = "zstd"
+
+type zstdCompressor struct {
+	encoders sync.Pool
+	decoders sync.Pool
+}
+
+type zstdWriter struct {
+	*zstd.Encoder
+	pool *sync.Pool
+}
+
+type zstdReader struct {
+	*zstd.Decoder
+	pool *sync.Pool
+}
+
+func (w *zstdWriter) Close() error {
+	defer w.pool.Put(w)
+	return w.Encoder.Close()
+}
+
+// Read returns the decoder to the pool once the body is read
+func (r *zstdReader) Read(p []byte) (int, error) {
+	n, err := r.Decoder.Read(p)
+	if err == io.EOF {
+		r.Decoder.Reset(nil)
+		r.pool.Put(r)
+	}
+	return n, err
+}
+
+func (z *zstdCompressor) Compress(w io.Writer) (io.WriteCloser, error) {
+	if e, ok := z.encoders.Get().(*zstdWriter); ok {
+		e.Reset(w)
+		return e, nil
+	}
+
+	e, err := zstd.NewWriter(w, zstd.WithEncoderConcurrency(1))
+	if err != nil {
+		return nil, err
+	}
+	return &zstdWriter{Encoder: e, pool: &z.encoders}, nil
+}
+
+func (z *zstdCompressor) Decompress(r io.Reader) (io.Reader, error) {
+	if d, ok := z.decoders.Get().(*zstdReader); ok {
+		if err := d.Reset(r); err != nil {
+			return nil, err
+		}
+		return d, nil
+	}
+
+	d, err := zstd.NewReader(r,
+		zstd.WithDecoderConcurrency(1),
+		zstd.WithDecoderMaxMemory(uint64(compress.DefaultMaxSize)),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &zstdReader{Decoder: d, pool: &z.decoders}, nil
+}
+
+func (z *zstdCompressor) Name() string {
+	return Name
+}
+
+func init() {
+	compress.Register(&zstdCompressor{})
+}
@@ -0,0 +1,44 @@
+package zstd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stack-labs/stack/codec/compress"
+)
+
+func TestZstd(t *testing.T) {
+	c, ok := compress.Get(Name)
+	if !ok {
+		t.Fatal("Expected the zstd compressor to be registered")
+	}
+
+	body := bytes.Repeat([]byte("stack "), 1024)
+
+	// twice to reuse the pooled encoders and decoders
+	for i := 0; i < 2; i++ {
+		b, err := compress.Encode(c, body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(b) >= len(body) {
+			t.Fatal("Expected zstd to compress the body")
+		}
+
+		d, err := compress.Body(Name, b, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(d, body) {
+			t.Fatal("Unexpected zstd body")
+		}
+	}
+
+	b, err := compress.Encode(c, body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := compress.Body(Name, b, int64(len(body)-1)); err != compress.ErrTooLarge {
+		t.Fatalf("Expected the body to be too large got %v", err)
+	}
+}
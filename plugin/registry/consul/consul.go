@@ -150,20 +150,38 @@ func configure(c *consulRegistry, opts ...registry.Option) {
 	c.Client()
 }
 
+// domain returns the domain of a request, falling back to the registry default
+func (c *consulRegistry) domain(d string) string {
+	if len(d) > 0 {
+		return d
+	}
+	if len(c.opts.Domain) > 0 {
+		return c.opts.Domain
+	}
+	return registry.DefaultDomain
+}
+
 func (c *consulRegistry) Init(opts ...registry.Option) error {
 	configure(c, opts...)
 	return nil
 }
 
-func (c *consulRegistry) Deregister(s *registry.Service) error {
+func (c *consulRegistry) Deregister(s *registry.Service, opts ...registry.DeregisterOption) error {
 	if len(s.Nodes) == 0 {
 		return errors.New("Require at least one node")
 	}
 
+	var options registry.DeregisterOptions
+	for _, o := range opts {
+		o(&options)
+	}
+
+	key := c.domain(options.Domain) + s.Name
+
 	// delete our hash and time check of the service
 	c.Lock()
-	delete(c.register, s.Name)
-	delete(c.lastChecked, s.Name)
+	delete(c.register, key)
+	delete(c.lastChecked, key)
 	c.Unlock()
 
 	node := s.Nodes[0]
@@ -199,10 +217,13 @@ func (c *consulRegistry) Register(s *registry.Service, opts ...registry.Register
 	// use first node
 	node := s.Nodes[0]
 
+	domain := c.domain(options.Domain)
+	key := domain + s.Name
+
 	// get existing hash and last checked time
 	c.Lock()
-	v, ok := c.register[s.Name]
-	lastChecked := c.lastChecked[s.Name]
+	v, ok := c.register[key]
+	lastChecked := c.lastChecked[key]
 	c.Unlock()
 
 	// if it's already registered and matches then just pass the check
@@ -233,6 +254,7 @@ func (c *consulRegistry) Register(s *registry.Service, opts ...registry.Register
 	tags := encodeMetadata(node.Metadata)
 	tags = append(tags, encodeEndpoints(s.Endpoints)...)
 	tags = append(tags, encodeVersion(s.Version)...)
+	tags = append(tags, encodeDomain(domain))
 
 	var check *consul.AgentServiceCheck
 
@@ -284,8 +306,8 @@ func (c *consulRegistry) Register(s *registry.Service, opts ...registry.Register
 
 	// save our hash and time check of the service
 	c.Lock()
-	c.register[s.Name] = h
-	c.lastChecked[s.Name] = time.Now()
+	c.register[key] = h
+	c.lastChecked[key] = time.Now()
 	c.Unlock()
 
 	// if the TTL is 0 we don't mess with the checks
@@ -297,15 +319,23 @@ func (c *consulRegistry) Register(s *registry.Service, opts ...registry.Register
 	return c.Client().Agent().PassTTL("service:"+node.Id, "")
 }
 
-func (c *consulRegistry) GetService(name string) ([]*registry.Service, error) {
+func (c *consulRegistry) GetService(name string, opts ...registry.GetOption) ([]*registry.Service, error) {
+	var options registry.GetOptions
+	for _, o := range opts {
+		o(&options)
+	}
+
 	var rsp []*consul.ServiceEntry
 	var err error
 
+	// only get the services of the domain
+	tag := encodeDomain(c.domain(options.Domain))
+
 	// if we're connect enabled only get connect services
 	if c.connect {
-		rsp, _, err = c.Client().Health().Connect(name, "", false, c.queryOptions)
+		rsp, _, err = c.Client().Health().Connect(name, tag, false, c.queryOptions)
 	} else {
-		rsp, _, err = c.Client().Health().Service(name, "", false, c.queryOptions)
+		rsp, _, err = c.Client().Health().Service(name, tag, false, c.queryOptions)
 	}
 	if err != nil {
 		return nil, err
@@ -372,15 +402,25 @@ func (c *consulRegistry) GetService(name string) ([]*registry.Service, error) {
 	return services, nil
 }
 
-func (c *consulRegistry) ListServices() ([]*registry.Service, error) {
+func (c *consulRegistry) ListServices(opts ...registry.ListOption) ([]*registry.Service, error) {
+	var options registry.ListOptions
+	for _, o := range opts {
+		o(&options)
+	}
+
 	rsp, _, err := c.Client().Catalog().Services(c.queryOptions)
 	if err != nil {
 		return nil, err
 	}
 
+	tag := encodeDomain(c.domain(options.Domain))
+
 	var services []*registry.Service
 
-	for service := range rsp {
+	for service, tags := range rsp {
+		if !hasTag(tags, tag) {
+			continue
+		}
 		services = append(services, &registry.Service{Name: service})
 	}
 
@@ -441,3 +481,12 @@ func NewRegistry(opts ...registry.Option) registry.Registry {
 	configure(cr, opts...)
 	return cr
 }
+
+func hasTag(tags []string, tag string) bool {
+	for _, t := range tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
@@ -169,3 +169,9 @@ func decodeVersion(tags []string) (string, bool) {
 	}
 	return "", false
 }
+
+// encodeDomain returns the tag of a domain, it is kept in plain text
+// so the services of a domain can be filtered by tag
+func encodeDomain(d string) string {
+	return "d-" + d
+}
@@ -4,6 +4,8 @@ go 1.15
 
 require (
 	github.com/hashicorp/consul/api v1.3.0
-	github.com/stack-labs/stack v1.0.1-rc2
 	github.com/mitchellh/hashstructure v1.0.0
+	github.com/stack-labs/stack v1.0.1-rc2
 )
+
+replace github.com/stack-labs/stack => ../../..
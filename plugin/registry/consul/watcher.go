@@ -13,8 +13,10 @@ import (
 )
 
 type consulWatcher struct {
-	r        *consulRegistry
-	wo       registry.WatchOptions
+	r  *consulRegistry
+	wo registry.WatchOptions
+	// tag of the watched domain
+	tag      string
 	wp       *watch.Plan
 	watchers map[string]*watch.Plan
 
@@ -34,6 +36,7 @@ func newConsulWatcher(cr *consulRegistry, opts ...registry.WatchOption) (registr
 	cw := &consulWatcher{
 		r:        cr,
 		wo:       wo,
+		tag:      encodeDomain(cr.domain(wo.Domain)),
 		exit:     make(chan bool),
 		next:     make(chan *registry.Result, 10),
 		watchers: make(map[string]*watch.Plan),
@@ -193,6 +196,13 @@ func (cw *consulWatcher) handle(idx uint64, data interface{}) {
 		return
 	}
 
+	// only keep the services of the domain
+	for service, tags := range services {
+		if !hasTag(tags, cw.tag) {
+			delete(services, service)
+		}
+	}
+
 	// add new watchers
 	for service, _ := range services {
 		// Filter on watch options
@@ -207,6 +217,7 @@ func (cw *consulWatcher) handle(idx uint64, data interface{}) {
 		wp, err := watch.Parse(map[string]interface{}{
 			"type":    "service",
 			"service": service,
+			"tag":     cw.tag,
 		})
 		if err == nil {
 			wp.Handler = cw.serviceHandler
@@ -122,14 +122,30 @@ func decode(ds []byte) *registry.Service {
 	return s
 }
 
-func nodePath(s, id string) string {
+// nodes are stored at prefix/domain/service/node
+func nodePath(domain, s, id string) string {
 	service := strings.Replace(s, "/", "-", -1)
 	node := strings.Replace(id, "/", "-", -1)
-	return path.Join(prefix, service, node)
+	return path.Join(prefix, domainPath(domain), service, node)
 }
 
-func servicePath(s string) string {
-	return path.Join(prefix, strings.Replace(s, "/", "-", -1))
+func servicePath(domain, s string) string {
+	return path.Join(prefix, domainPath(domain), strings.Replace(s, "/", "-", -1))
+}
+
+func domainPath(domain string) string {
+	return strings.Replace(domain, "/", "-", -1)
+}
+
+// domain returns the domain of a request, falling back to the registry default
+func (e *etcdRegistry) domain(d string) string {
+	if len(d) > 0 {
+		return d
+	}
+	if len(e.options.Domain) > 0 {
+		return e.options.Domain
+	}
+	return registry.DefaultDomain
 }
 
 func (e *etcdRegistry) Init(opts ...registry.Option) error {
@@ -145,9 +161,18 @@ func (e *etcdRegistry) registerNode(s *registry.Service, node *registry.Node, op
 		return errors.New("Require at least one node")
 	}
 
+	var options registry.RegisterOptions
+	for _, o := range opts {
+		o(&options)
+	}
+
+	domain := e.domain(options.Domain)
+	// key of the node in the hash and lease caches
+	key := domain + s.Name + node.Id
+
 	// check existing lease cache
 	e.RLock()
-	leaseID, ok := e.leases[s.Name+node.Id]
+	leaseID, ok := e.leases[key]
 	e.RUnlock()
 
 	if !ok {
@@ -156,7 +181,7 @@ func (e *etcdRegistry) registerNode(s *registry.Service, node *registry.Node, op
 		defer cancel()
 
 		// look for the existing key
-		rsp, err := e.client.Get(ctx, nodePath(s.Name, node.Id), clientv3.WithSerializable())
+		rsp, err := e.client.Get(ctx, nodePath(domain, s.Name, node.Id), clientv3.WithSerializable())
 		if err != nil {
 			return err
 		}
@@ -180,8 +205,8 @@ func (e *etcdRegistry) registerNode(s *registry.Service, node *registry.Node, op
 
 				// save the info
 				e.Lock()
-				e.leases[s.Name+node.Id] = leaseID
-				e.register[s.Name+node.Id] = h
+				e.leases[key] = leaseID
+				e.register[key] = h
 				e.Unlock()
 
 				break
@@ -213,7 +238,7 @@ func (e *etcdRegistry) registerNode(s *registry.Service, node *registry.Node, op
 
 	// get existing hash for the service node
 	e.Lock()
-	v, ok := e.register[s.Name+node.Id]
+	v, ok := e.register[key]
 	e.Unlock()
 
 	// the service is unchanged, skip registering
@@ -230,11 +255,6 @@ func (e *etcdRegistry) registerNode(s *registry.Service, node *registry.Node, op
 		Nodes:     []*registry.Node{node},
 	}
 
-	var options registry.RegisterOptions
-	for _, o := range opts {
-		o(&options)
-	}
-
 	ctx, cancel := context.WithTimeout(context.Background(), e.options.Timeout)
 	defer cancel()
 
@@ -247,12 +267,12 @@ func (e *etcdRegistry) registerNode(s *registry.Service, node *registry.Node, op
 		}
 	}
 
-	log.Tracef("Registering %s id %s with lease %v and ttl %v", service.Name, node.Id, lgr, options.TTL)
+	log.Tracef("Registering %s id %s in domain %s with lease %v and ttl %v", service.Name, node.Id, domain, lgr, options.TTL)
 	// create an entry for the node
 	if lgr != nil {
-		_, err = e.client.Put(ctx, nodePath(service.Name, node.Id), encode(service), clientv3.WithLease(lgr.ID))
+		_, err = e.client.Put(ctx, nodePath(domain, service.Name, node.Id), encode(service), clientv3.WithLease(lgr.ID))
 	} else {
-		_, err = e.client.Put(ctx, nodePath(service.Name, node.Id), encode(service))
+		_, err = e.client.Put(ctx, nodePath(domain, service.Name, node.Id), encode(service))
 	}
 	if err != nil {
 		return err
@@ -260,34 +280,41 @@ func (e *etcdRegistry) registerNode(s *registry.Service, node *registry.Node, op
 
 	e.Lock()
 	// save our hash of the service
-	e.register[s.Name+node.Id] = h
+	e.register[key] = h
 	// save our leaseID of the service
 	if lgr != nil {
-		e.leases[s.Name+node.Id] = lgr.ID
+		e.leases[key] = lgr.ID
 	}
 	e.Unlock()
 
 	return nil
 }
 
-func (e *etcdRegistry) Deregister(s *registry.Service) error {
+func (e *etcdRegistry) Deregister(s *registry.Service, opts ...registry.DeregisterOption) error {
 	if len(s.Nodes) == 0 {
 		return errors.New("Require at least one node")
 	}
 
+	var options registry.DeregisterOptions
+	for _, o := range opts {
+		o(&options)
+	}
+
+	domain := e.domain(options.Domain)
+
 	for _, node := range s.Nodes {
 		e.Lock()
 		// delete our hash of the service
-		delete(e.register, s.Name+node.Id)
+		delete(e.register, domain+s.Name+node.Id)
 		// delete our lease of the service
-		delete(e.leases, s.Name+node.Id)
+		delete(e.leases, domain+s.Name+node.Id)
 		e.Unlock()
 
 		ctx, cancel := context.WithTimeout(context.Background(), e.options.Timeout)
 		defer cancel()
 
-		log.Tracef("Deregistering %s id %s", s.Name, node.Id)
-		_, err := e.client.Delete(ctx, nodePath(s.Name, node.Id))
+		log.Tracef("Deregistering %s id %s in domain %s", s.Name, node.Id, domain)
+		_, err := e.client.Delete(ctx, nodePath(domain, s.Name, node.Id))
 		if err != nil {
 			return err
 		}
@@ -314,11 +341,16 @@ func (e *etcdRegistry) Register(s *registry.Service, opts ...registry.RegisterOp
 	return gerr
 }
 
-func (e *etcdRegistry) GetService(name string) ([]*registry.Service, error) {
+func (e *etcdRegistry) GetService(name string, opts ...registry.GetOption) ([]*registry.Service, error) {
+	var options registry.GetOptions
+	for _, o := range opts {
+		o(&options)
+	}
+
 	ctx, cancel := context.WithTimeout(context.Background(), e.options.Timeout)
 	defer cancel()
 
-	rsp, err := e.client.Get(ctx, servicePath(name)+"/", clientv3.WithPrefix(), clientv3.WithSerializable())
+	rsp, err := e.client.Get(ctx, servicePath(e.domain(options.Domain), name)+"/", clientv3.WithPrefix(), clientv3.WithSerializable())
 	if err != nil {
 		return nil, err
 	}
@@ -354,13 +386,19 @@ func (e *etcdRegistry) GetService(name string) ([]*registry.Service, error) {
 	return services, nil
 }
 
-func (e *etcdRegistry) ListServices() ([]*registry.Service, error) {
+func (e *etcdRegistry) ListServices(opts ...registry.ListOption) ([]*registry.Service, error) {
+	var options registry.ListOptions
+	for _, o := range opts {
+		o(&options)
+	}
+
 	versions := make(map[string]*registry.Service)
 
 	ctx, cancel := context.WithTimeout(context.Background(), e.options.Timeout)
 	defer cancel()
 
-	rsp, err := e.client.Get(ctx, prefix, clientv3.WithPrefix(), clientv3.WithSerializable())
+	domainPrefix := path.Join(prefix, domainPath(e.domain(options.Domain))) + "/"
+	rsp, err := e.client.Get(ctx, domainPrefix, clientv3.WithPrefix(), clientv3.WithSerializable())
 	if err != nil {
 		return nil, err
 	}
@@ -4,6 +4,7 @@ go 1.14
 
 replace (
 	github.com/coreos/bbolt => go.etcd.io/bbolt v1.3.4
+	github.com/stack-labs/stack => ../../..
 	google.golang.org/grpc => google.golang.org/grpc v1.26.0
 )
 
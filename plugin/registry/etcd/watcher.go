@@ -3,6 +3,7 @@ package etcd
 import (
 	"context"
 	"errors"
+	"path"
 	"time"
 
 	"github.com/coreos/etcd/clientv3"
@@ -30,9 +31,11 @@ func newEtcdWatcher(r *etcdRegistry, timeout time.Duration, opts ...registry.Wat
 		cancel()
 	}()
 
-	watchPath := prefix
+	domain := r.domain(wo.Domain)
+
+	watchPath := path.Join(prefix, domainPath(domain)) + "/"
 	if len(wo.Service) > 0 {
-		watchPath = servicePath(wo.Service) + "/"
+		watchPath = servicePath(domain, wo.Service) + "/"
 	}
 
 	return &etcdWatcher{
@@ -10,3 +10,5 @@ require (
 	github.com/smartystreets/goconvey v1.6.4
 	github.com/stack-labs/stack v1.0.1-rc2
 )
+
+replace github.com/stack-labs/stack => ../../..
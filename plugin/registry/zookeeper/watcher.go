@@ -9,7 +9,9 @@ import (
 )
 
 type zookeeperWatcher struct {
-	wo       registry.WatchOptions
+	wo registry.WatchOptions
+	// root path of the watched domain
+	root     string
 	client   *zk.Conn
 	stop     chan bool
 	results  chan result
@@ -35,6 +37,7 @@ func newZookeeperWatcher(r *zookeeperRegistry, opts ...registry.WatchOption) (re
 
 	zw := &zookeeperWatcher{
 		wo:       wo,
+		root:     prefixWithDomain(r.domain(wo.Domain)),
 		client:   r.client,
 		stop:     make(chan bool),
 		results:  make(chan result),
@@ -96,8 +99,8 @@ func (zw *zookeeperWatcher) watchDir(key string) {
 
 				newNode := path.Join(e.Path, i)
 
-				if key == prefix {
-					// a new service was created under prefix
+				if key == zw.root {
+					// a new service was created under the domain
 					go zw.watchDir(newNode)
 
 					nodes, _, _ := zw.client.Children(newNode)
@@ -183,7 +186,7 @@ func (zw *zookeeperWatcher) watch() {
 		if len(zw.wo.Service) > 0 {
 			return []string{zw.wo.Service}
 		}
-		allServices, _, err := zw.client.Children(prefix)
+		allServices, _, err := zw.client.Children(zw.root)
 		if err != nil {
 			zw.writeResult(&result{nil, err})
 		}
@@ -192,7 +195,7 @@ func (zw *zookeeperWatcher) watch() {
 
 	//watch every service
 	for _, service := range services() {
-		sPath := childPath(prefix, service)
+		sPath := childPath(zw.root, service)
 		go zw.watchDir(sPath)
 		children, _, err := zw.client.Children(sPath)
 		if err != nil {
@@ -17,8 +17,7 @@ import (
 )
 
 var (
-	prefix        = "/stack-labs/registry"
-	defaultDomain = "stack"
+	prefix = "/stack-labs/registry"
 )
 
 type zookeeperRegistry struct {
@@ -96,6 +95,17 @@ func configure(z *zookeeperRegistry, opts ...registry.Option) error {
 	return nil
 }
 
+// domain returns the domain of a request, falling back to the registry default
+func (z *zookeeperRegistry) domain(d string) string {
+	if len(d) > 0 {
+		return d
+	}
+	if len(z.options.Domain) > 0 {
+		return z.options.Domain
+	}
+	return registry.DefaultDomain
+}
+
 func (z *zookeeperRegistry) Init(opts ...registry.Option) error {
 	return configure(z, opts...)
 }
@@ -104,9 +114,7 @@ func (z *zookeeperRegistry) Options() registry.Options {
 	return z.options
 }
 
-func (z *zookeeperRegistry) Deregister(s *registry.Service) error {
-	// todo
-	var opts []registry.DeregisterOption
+func (z *zookeeperRegistry) Deregister(s *registry.Service, opts ...registry.DeregisterOption) error {
 	if len(s.Nodes) == 0 {
 		return errors.New("Require at least one currentNode")
 	}
@@ -116,9 +124,7 @@ func (z *zookeeperRegistry) Deregister(s *registry.Service) error {
 	for _, o := range opts {
 		o(&options)
 	}
-	if len(options.Domain) == 0 {
-		options.Domain = defaultDomain
-	}
+	options.Domain = z.domain(options.Domain)
 
 	for _, node := range s.Nodes {
 		z.Lock()
@@ -166,16 +172,12 @@ func (z *zookeeperRegistry) Register(s *registry.Service, opts ...registry.Regis
 	return gerr
 }
 
-func (z *zookeeperRegistry) GetService(name string) ([]*registry.Service, error) {
-	// todo
-	var opts []registry.GetOption
+func (z *zookeeperRegistry) GetService(name string, opts ...registry.GetOption) ([]*registry.Service, error) {
 	var options registry.GetOptions
 	for _, o := range opts {
 		o(&options)
 	}
-	if len(options.Domain) == 0 {
-		options.Domain = defaultDomain
-	}
+	options.Domain = z.domain(options.Domain)
 
 	path := servicePath(options.Domain, name)
 	l, _, err := z.client.Children(path)
@@ -233,16 +235,12 @@ func (z *zookeeperRegistry) GetService(name string) ([]*registry.Service, error)
 	return services, nil
 }
 
-func (z *zookeeperRegistry) ListServices() ([]*registry.Service, error) {
-	// todo
-	var opts []registry.ListOption
+func (z *zookeeperRegistry) ListServices(opts ...registry.ListOption) ([]*registry.Service, error) {
 	var options registry.ListOptions
 	for _, o := range opts {
 		o(&options)
 	}
-	if len(options.Domain) == 0 {
-		options.Domain = defaultDomain
-	}
+	options.Domain = z.domain(options.Domain)
 
 	var p = prefixWithDomain(options.Domain)
 	srv, _, err := z.client.Children(p)
@@ -427,9 +425,7 @@ func (z *zookeeperRegistry) prepareService(s *registry.Service, node *registry.N
 	for _, o := range opts {
 		o(&options)
 	}
-	if len(options.Domain) == 0 {
-		options.Domain = defaultDomain
-	}
+	options.Domain = z.domain(options.Domain)
 
 	if s.Metadata == nil {
 		s.Metadata = map[string]string{}
@@ -476,7 +472,7 @@ func (z *zookeeperRegistry) prepareService(s *registry.Service, node *registry.N
 // checkSupportTTL zk doest support TTL currentNode by default. we need to check it.
 func (z *zookeeperRegistry) checkSupportTTL() bool {
 	tempID := uuid.New().String()
-	key := nodePath(defaultDomain, tempID, tempID)
+	key := nodePath(registry.DefaultDomain, tempID, tempID)
 	_, err := z.client.CreateTTL(key, []byte{}, zk.FlagTTL, nil, time.Second)
 	if err != nil && strings.Contains(err.Error(), "-6") {
 		log.Infof("[check ttl] this zk doesn't support TTL")
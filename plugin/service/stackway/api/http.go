@@ -125,7 +125,9 @@ func (s *httpServer) Start() error {
 
 	// Init plugins
 	for _, p := range plugin.Plugins() {
-		_ = p.Init(cfg)
+		if err := p.Init(cfg); err != nil {
+			return fmt.Errorf("init plugin %s: %v", p, err)
+		}
 	}
 
 	// Init API
@@ -288,7 +290,7 @@ func (s *httpServer) Start() error {
 	}
 
 	// reverse wrap handler
-	plugins := append(plugin.Plugins(), plugin.Plugins()...)
+	plugins := plugin.Plugins()
 	for i := len(plugins); i > 0; i-- {
 		h = plugins[i-1].Handler()(h)
 	}
@@ -9,3 +9,5 @@ require (
 	github.com/stack-labs/stack v1.0.1-rc2
 	github.com/stretchr/testify v1.4.0
 )
+
+replace github.com/stack-labs/stack => ../../..
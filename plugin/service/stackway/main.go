@@ -5,6 +5,8 @@ import (
 	"github.com/stack-labs/stack/util/log"
 
 	"github.com/stack-labs/stack/plugin/service/stackway/api"
+	"github.com/stack-labs/stack/plugin/service/stackway/plugin"
+	"github.com/stack-labs/stack/plugin/service/stackway/plugin/ratelimit"
 )
 
 func main() {
@@ -14,6 +16,9 @@ func main() {
 	apiServer := api.NewServer(svc)
 	svc.Init(apiServer.Options()...)
 
+	// client rate limiting, configured by stack.stackway.ratelimit
+	plugin.Register(ratelimit.NewPlugin(svc.Options().Auth))
+
 	// run service
 	if err := svc.Run(); err != nil {
 		log.Fatal(err)
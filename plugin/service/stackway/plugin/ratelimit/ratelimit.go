@@ -0,0 +1,208 @@
+// Package ratelimit is a stackway plugin limiting the requests of a client
+package ratelimit
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/stack-labs/stack/auth"
+	"github.com/stack-labs/stack/config"
+	"github.com/stack-labs/stack/plugin/service/stackway/plugin"
+	"github.com/stack-labs/stack/util/errors"
+	"github.com/stack-labs/stack/util/log"
+	rl "github.com/stack-labs/stack/util/ratelimit"
+)
+
+var (
+	// DefaultPruneInterval is the interval at which idle clients are removed
+	DefaultPruneInterval = time.Minute
+)
+
+// rateLimit is the config under stack.stackway.ratelimit
+type rateLimit struct {
+	// Key is ip or account, account falls back to the ip without a valid token
+	Key         string  `json:"key"`
+	Rate        float64 `json:"rate"`
+	Burst       int     `json:"burst"`
+	MaxInFlight int     `json:"max_in_flight"`
+	// TrustedProxies are the addresses or CIDRs of the proxies whose
+	// X-Forwarded-For header is trusted, it is ignored without them
+	TrustedProxies []string `json:"trusted_proxies"`
+}
+
+type rateLimiter struct {
+	auth auth.Auth
+
+	once     sync.Once
+	key      string
+	proxies  []*net.IPNet
+	limiters *rl.Limiters
+}
+
+func (r *rateLimiter) init(cfg config.Config) error {
+	conf := rateLimit{Key: "ip"}
+	if cfg != nil {
+		if c := cfg.Get("stack", "stackway", "ratelimit"); c != nil {
+			if err := c.Scan(&conf); err != nil {
+				return err
+			}
+		}
+	}
+
+	proxies, err := parseProxies(conf.TrustedProxies)
+	if err != nil {
+		return err
+	}
+
+	r.key = conf.Key
+	r.proxies = proxies
+	r.limiters = rl.NewLimiters(rl.Limit{
+		Rate:        conf.Rate,
+		Burst:       conf.Burst,
+		MaxInFlight: conf.MaxInFlight,
+	}, nil)
+
+	if conf.Rate <= 0 && conf.MaxInFlight <= 0 {
+		return nil
+	}
+
+	log.Infof("Rate limiting clients by %s", r.key)
+
+	r.once.Do(func() {
+		go r.prune()
+	})
+
+	return nil
+}
+
+func (r *rateLimiter) prune() {
+	t := time.NewTicker(DefaultPruneInterval)
+	defer t.Stop()
+
+	for range t.C {
+		if l := r.limiters; l != nil {
+			l.Prune(DefaultPruneInterval)
+		}
+	}
+}
+
+// clientKey returns the account id of the request or the client ip
+func (r *rateLimiter) clientKey(req *http.Request) string {
+	if r.key == "account" && r.auth != nil {
+		token := strings.TrimPrefix(req.Header.Get("Authorization"), auth.BearerScheme)
+		if len(token) > 0 {
+			if acc, err := r.auth.Inspect(token); err == nil && acc != nil {
+				return "account:" + acc.ID
+			}
+		}
+	}
+
+	return "ip:" + clientIP(req, r.proxies)
+}
+
+func (r *rateLimiter) handler(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if r.limiters == nil {
+			h.ServeHTTP(w, req)
+			return
+		}
+
+		release, ok := r.limiters.Acquire(r.clientKey(req))
+		if !ok {
+			e := errors.TooManyRequests("stack.stackway", "too many requests")
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusTooManyRequests)
+			w.Write([]byte(e.Error()))
+			return
+		}
+		defer release()
+
+		h.ServeHTTP(w, req)
+	})
+}
+
+// parseProxies parses the addresses and CIDRs of the trusted proxies
+func parseProxies(addrs []string) ([]*net.IPNet, error) {
+	var proxies []*net.IPNet
+
+	for _, addr := range addrs {
+		if !strings.Contains(addr, "/") {
+			ip := net.ParseIP(addr)
+			if ip == nil {
+				return nil, fmt.Errorf("invalid trusted proxy %s", addr)
+			}
+			bits := 8 * net.IPv6len
+			if ip.To4() != nil {
+				ip, bits = ip.To4(), 8*net.IPv4len
+			}
+			proxies = append(proxies, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+
+		_, n, err := net.ParseCIDR(addr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy %s: %v", addr, err)
+		}
+		proxies = append(proxies, n)
+	}
+
+	return proxies, nil
+}
+
+func trusted(ip string, proxies []*net.IPNet) bool {
+	addr := net.ParseIP(ip)
+	if addr == nil {
+		return false
+	}
+	for _, n := range proxies {
+		if n.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// clientIP returns the remote address, or when it is a trusted proxy the
+// last address of X-Forwarded-For which is not a trusted proxy
+func clientIP(req *http.Request, proxies []*net.IPNet) string {
+	host, _, err := net.SplitHostPort(req.RemoteAddr)
+	if err != nil {
+		host = req.RemoteAddr
+	}
+
+	if !trusted(host, proxies) {
+		return host
+	}
+
+	// the proxies append the address of their client
+	fwd := strings.Split(strings.Join(req.Header.Values("X-Forwarded-For"), ","), ",")
+	for i := len(fwd) - 1; i >= 0; i-- {
+		ip := strings.TrimSpace(fwd[i])
+		if len(ip) == 0 {
+			continue
+		}
+		host = ip
+		if !trusted(ip, proxies) {
+			break
+		}
+	}
+
+	return host
+}
+
+// NewPlugin returns a plugin limiting the rate and concurrent requests of
+// every client, keyed by ip or by the auth account id. The limits are read
+// from stack.stackway.ratelimit and the plugin does nothing without them.
+func NewPlugin(a auth.Auth) plugin.Plugin {
+	r := &rateLimiter{auth: a}
+
+	return plugin.NewPlugin(
+		plugin.WithName("ratelimit"),
+		plugin.WithHandler(r.handler),
+		plugin.WithInit(r.init),
+	)
+}
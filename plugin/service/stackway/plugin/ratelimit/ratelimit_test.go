@@ -0,0 +1,42 @@
+package ratelimit
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClientIP(t *testing.T) {
+	proxies, err := parseProxies([]string{"10.0.0.0/8", "192.168.1.1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testData := []struct {
+		remote string
+		fwd    string
+		ip     string
+	}{
+		// a client can't spoof its address
+		{"1.2.3.4:1000", "5.6.7.8", "1.2.3.4"},
+		{"10.0.0.1:1000", "5.6.7.8", "5.6.7.8"},
+		// the spoofed address in front of the forwarded one is ignored
+		{"10.0.0.1:1000", "9.9.9.9, 5.6.7.8, 192.168.1.1", "5.6.7.8"},
+		{"192.168.1.1:1000", "", "192.168.1.1"},
+		{"192.168.1.1:1000", "10.0.0.2", "10.0.0.2"},
+	}
+
+	for _, d := range testData {
+		req := httptest.NewRequest("GET", "/", nil)
+		req.RemoteAddr = d.remote
+		if len(d.fwd) > 0 {
+			req.Header.Set("X-Forwarded-For", d.fwd)
+		}
+		if ip := clientIP(req, proxies); ip != d.ip {
+			t.Fatalf("Expected %s for %s via %s got %s", d.ip, d.fwd, d.remote, ip)
+		}
+	}
+
+	if _, err := parseProxies([]string{"invalid"}); err == nil {
+		t.Fatal("Expected an invalid proxy")
+	}
+}
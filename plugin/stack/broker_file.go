@@ -0,0 +1,20 @@
+package stack
+
+import (
+	"github.com/stack-labs/stack/broker"
+	"github.com/stack-labs/stack/broker/file"
+)
+
+type fileBrokerPlugin struct{}
+
+func (f *fileBrokerPlugin) Name() string {
+	return "file"
+}
+
+func (f *fileBrokerPlugin) Options() []broker.Option {
+	return nil
+}
+
+func (f *fileBrokerPlugin) New(opts ...broker.Option) broker.Broker {
+	return file.NewBroker(opts...)
+}
@@ -37,7 +37,10 @@ func init() {
 	plugin.SelectorPlugins["static"] = &staticSelectorPlugin{}
 	plugin.BrokerPlugins["memory"] = &memoryBrokerPlugin{}
 	plugin.BrokerPlugins["http"] = &httpBrokerPlugin{}
+	plugin.BrokerPlugins["file"] = &fileBrokerPlugin{}
 	plugin.BrokerPlugins["service"] = &serviceBrokerPlugin{}
+	plugin.TransportPlugins["tcp"] = &tcpTransportPlugin{}
+	plugin.TransportPlugins["unix"] = &unixTransportPlugin{}
 	plugin.AuthTokenProviderPlugins["jwt"] = &jwtTokenProviderPlugin{}
 	plugin.AuthTokenProviderPlugins["basic"] = &basicTokenProviderPlugin{}
 	plugin.AuthPlugins["jwt"] = &jwtAuthPlugin{}
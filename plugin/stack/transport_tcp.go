@@ -0,0 +1,20 @@
+package stack
+
+import (
+	"github.com/stack-labs/stack/transport"
+	"github.com/stack-labs/stack/transport/tcp"
+)
+
+type tcpTransportPlugin struct{}
+
+func (t *tcpTransportPlugin) Name() string {
+	return "tcp"
+}
+
+func (t *tcpTransportPlugin) Options() []transport.Option {
+	return nil
+}
+
+func (t *tcpTransportPlugin) New(opts ...transport.Option) transport.Transport {
+	return tcp.NewTransport(opts...)
+}
@@ -0,0 +1,20 @@
+package stack
+
+import (
+	"github.com/stack-labs/stack/transport"
+	"github.com/stack-labs/stack/transport/unix"
+)
+
+type unixTransportPlugin struct{}
+
+func (u *unixTransportPlugin) Name() string {
+	return "unix"
+}
+
+func (u *unixTransportPlugin) Options() []transport.Option {
+	return nil
+}
+
+func (u *unixTransportPlugin) New(opts ...transport.Option) transport.Transport {
+	return unix.NewTransport(opts...)
+}
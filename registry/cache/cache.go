@@ -31,17 +31,17 @@ type cache struct {
 	registry.Registry
 	opts Options
 
-	// registry cache
+	// registry cache, keyed by domain and service
 	sync.RWMutex
 	cache   map[string][]*registry.Service
 	ttls    map[string]time.Time
-	watched map[string]bool
+	watched map[string]map[string]bool
 
 	// used to stop the cache
 	exit chan bool
 
-	// indicate whether its running
-	running bool
+	// indicate whether the watcher of a domain is running
+	running map[string]bool
 	// status of the registry
 	// used to hold onto the cache
 	// in failure state
@@ -52,6 +52,11 @@ var (
 	DefaultTTL = time.Minute
 )
 
+// key of a service in a domain, a blank domain is the registry default
+func key(domain, service string) string {
+	return domain + "/" + service
+}
+
 func backoff(attempts int) time.Duration {
 	if attempts == 0 {
 		return time.Duration(0)
@@ -101,24 +106,24 @@ func (c *cache) quit() bool {
 	}
 }
 
-func (c *cache) del(service string) {
+func (c *cache) del(domain, service string) {
 	// don't blow away cache in error state
 	if err := c.status; err != nil {
 		return
 	}
 	// otherwise delete entries
-	delete(c.cache, service)
-	delete(c.ttls, service)
+	delete(c.cache, key(domain, service))
+	delete(c.ttls, key(domain, service))
 }
 
-func (c *cache) get(service string) ([]*registry.Service, error) {
+func (c *cache) get(domain, service string) ([]*registry.Service, error) {
 	// read lock
 	c.RLock()
 
 	// check the cache first
-	services := c.cache[service]
+	services := c.cache[key(domain, service)]
 	// get cache ttl
-	ttl := c.ttls[service]
+	ttl := c.ttls[key(domain, service)]
 	// make a copy
 	cp := regutil.Copy(services)
 
@@ -132,11 +137,7 @@ func (c *cache) get(service string) ([]*registry.Service, error) {
 	// get does the actual request for a service and cache it
 	get := func(service string, cached []*registry.Service) ([]*registry.Service, error) {
 		// ask the registry
-		ss, _ := c.Registry.ListServices()
-		for _, s := range ss {
-			log.Info(s.Name)
-		}
-		services, err := c.Registry.GetService(service)
+		services, err := c.Registry.GetService(service, registry.GetDomain(domain))
 		if err != nil {
 			// check the cache
 			if len(cached) > 0 {
@@ -157,14 +158,14 @@ func (c *cache) get(service string) ([]*registry.Service, error) {
 
 		// cache results
 		c.Lock()
-		c.set(service, regutil.Copy(services))
+		c.set(domain, service, regutil.Copy(services))
 		c.Unlock()
 
 		return services, nil
 	}
 
 	// watch service if not watched
-	_, ok := c.watched[service]
+	_, ok := c.watched[domain][service]
 
 	// unlock the read lock
 	c.RUnlock()
@@ -174,11 +175,15 @@ func (c *cache) get(service string) ([]*registry.Service, error) {
 		c.Lock()
 
 		// set to watched
-		c.watched[service] = true
+		if _, ok := c.watched[domain]; !ok {
+			c.watched[domain] = make(map[string]bool)
+		}
+		c.watched[domain][service] = true
 
 		// only kick it off if not running
-		if !c.running {
-			go c.run()
+		if !c.running[domain] {
+			c.running[domain] = true
+			go c.run(domain)
 		}
 
 		c.Unlock()
@@ -188,12 +193,12 @@ func (c *cache) get(service string) ([]*registry.Service, error) {
 	return get(service, cp)
 }
 
-func (c *cache) set(service string, services []*registry.Service) {
-	c.cache[service] = services
-	c.ttls[service] = time.Now().Add(c.opts.TTL)
+func (c *cache) set(domain, service string, services []*registry.Service) {
+	c.cache[key(domain, service)] = services
+	c.ttls[key(domain, service)] = time.Now().Add(c.opts.TTL)
 }
 
-func (c *cache) update(res *registry.Result) {
+func (c *cache) update(domain string, res *registry.Result) {
 	if res == nil || res.Service == nil {
 		return
 	}
@@ -202,11 +207,11 @@ func (c *cache) update(res *registry.Result) {
 	defer c.Unlock()
 
 	// only save watched services
-	if _, ok := c.watched[res.Service.Name]; !ok {
+	if _, ok := c.watched[domain][res.Service.Name]; !ok {
 		return
 	}
 
-	services, ok := c.cache[res.Service.Name]
+	services, ok := c.cache[key(domain, res.Service.Name)]
 	if !ok {
 		// we're not going to cache anything
 		// unless there was already a lookup
@@ -216,7 +221,7 @@ func (c *cache) update(res *registry.Result) {
 	if len(res.Service.Nodes) == 0 {
 		switch res.Action {
 		case "delete":
-			c.del(res.Service.Name)
+			c.del(domain, res.Service.Name)
 		}
 		return
 	}
@@ -234,7 +239,7 @@ func (c *cache) update(res *registry.Result) {
 	switch res.Action {
 	case "create", "update":
 		if service == nil {
-			c.set(res.Service.Name, append(services, res.Service))
+			c.set(domain, res.Service.Name, append(services, res.Service))
 			return
 		}
 
@@ -253,7 +258,7 @@ func (c *cache) update(res *registry.Result) {
 		}
 
 		services[index] = res.Service
-		c.set(res.Service.Name, services)
+		c.set(domain, res.Service.Name, services)
 	case "delete":
 		if service == nil {
 			return
@@ -279,7 +284,7 @@ func (c *cache) update(res *registry.Result) {
 		if len(nodes) > 0 {
 			service.Nodes = nodes
 			services[index] = service
-			c.set(service.Name, services)
+			c.set(domain, service.Name, services)
 			return
 		}
 
@@ -288,7 +293,7 @@ func (c *cache) update(res *registry.Result) {
 		// only have one thing to delete
 		// nuke the thing
 		if len(services) == 1 {
-			c.del(service.Name)
+			c.del(domain, service.Name)
 			return
 		}
 
@@ -302,22 +307,18 @@ func (c *cache) update(res *registry.Result) {
 		}
 
 		// save
-		c.set(service.Name, srvs)
+		c.set(domain, service.Name, srvs)
 	}
 }
 
-// run starts the cache watcher loop
+// run starts the cache watcher loop of a domain
 // it creates a new watcher if there's a problem
-func (c *cache) run() {
-	c.Lock()
-	c.running = true
-	c.Unlock()
-
+func (c *cache) run(domain string) {
 	// reset watcher on exit
 	defer func() {
 		c.Lock()
-		c.watched = make(map[string]bool)
-		c.running = false
+		delete(c.watched, domain)
+		delete(c.running, domain)
 		c.Unlock()
 	}()
 
@@ -334,7 +335,7 @@ func (c *cache) run() {
 		time.Sleep(time.Duration(j) * time.Millisecond)
 
 		// create new watcher
-		w, err := c.Registry.Watch()
+		w, err := c.Registry.Watch(registry.WatchDomain(domain))
 		if err != nil {
 			if c.quit() {
 				return
@@ -358,7 +359,7 @@ func (c *cache) run() {
 		a = 0
 
 		// watch for events
-		if err := c.watch(w); err != nil {
+		if err := c.watch(domain, w); err != nil {
 			if c.quit() {
 				return
 			}
@@ -384,7 +385,7 @@ func (c *cache) run() {
 
 // watch loops the next event and calls update
 // it returns if there's an error
-func (c *cache) watch(w registry.Watcher) error {
+func (c *cache) watch(domain string, w registry.Watcher) error {
 	// used to stop the watch
 	stop := make(chan bool)
 
@@ -415,13 +416,18 @@ func (c *cache) watch(w registry.Watcher) error {
 			c.setStatus(nil)
 		}
 
-		c.update(res)
+		c.update(domain, res)
 	}
 }
 
-func (c *cache) GetService(service string) ([]*registry.Service, error) {
+func (c *cache) GetService(service string, opts ...registry.GetOption) ([]*registry.Service, error) {
+	var options registry.GetOptions
+	for _, o := range opts {
+		o(&options)
+	}
+
 	// get the service
-	services, err := c.get(service)
+	services, err := c.get(options.Domain, service)
 	if err != nil {
 		return nil, err
 	}
@@ -465,7 +471,8 @@ func New(r registry.Registry, opts ...Option) Cache {
 	return &cache{
 		Registry: r,
 		opts:     options,
-		watched:  make(map[string]bool),
+		watched:  make(map[string]map[string]bool),
+		running:  make(map[string]bool),
 		cache:    make(map[string][]*registry.Service),
 		ttls:     make(map[string]time.Time),
 		exit:     make(chan bool),
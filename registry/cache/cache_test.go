@@ -0,0 +1,56 @@
+package cache
+
+import (
+	"testing"
+
+	"github.com/stack-labs/stack/registry"
+	"github.com/stack-labs/stack/registry/memory"
+)
+
+func TestCacheDomain(t *testing.T) {
+	r := memory.NewRegistry()
+
+	foo := &registry.Service{
+		Name:    "foo",
+		Version: "1.0.0",
+		Nodes:   []*registry.Node{{Id: "foo-1", Address: "localhost:9999"}},
+	}
+	bar := &registry.Service{
+		Name:    "foo",
+		Version: "1.0.0",
+		Nodes:   []*registry.Node{{Id: "foo-2", Address: "localhost:8888"}},
+	}
+
+	if err := r.Register(foo); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Register(bar, registry.RegisterDomain("bar")); err != nil {
+		t.Fatal(err)
+	}
+
+	c := New(r)
+	defer c.Stop()
+
+	for _, test := range []struct {
+		domain string
+		node   string
+	}{
+		{"", "foo-1"},
+		{"bar", "foo-2"},
+		// cached
+		{"", "foo-1"},
+		{"bar", "foo-2"},
+	} {
+		services, err := c.GetService("foo", registry.GetDomain(test.domain))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(services) != 1 || len(services[0].Nodes) != 1 || services[0].Nodes[0].Id != test.node {
+			t.Fatalf("Expected node %s in domain %q, got %+v", test.node, test.domain, services)
+		}
+	}
+
+	if _, err := c.GetService("foo", registry.GetDomain("baz")); err != registry.ErrNotFound {
+		t.Fatalf("Expected %v in domain baz, got %v", registry.ErrNotFound, err)
+	}
+}
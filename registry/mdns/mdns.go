@@ -15,11 +15,6 @@ import (
 	"github.com/stack-labs/stack/registry"
 )
 
-var (
-	// use a .stack domain rather than .local
-	mdnsDomain = "stack"
-)
-
 type mdnsTxt struct {
 	Service   string
 	Version   string
@@ -34,11 +29,10 @@ type mdnsEntry struct {
 
 type mdnsRegistry struct {
 	opts registry.Options
-	// the mdns domain
-	domain string
 
 	sync.Mutex
-	services map[string][]*mdnsEntry
+	// entries of the services by domain and name
+	services map[string]map[string][]*mdnsEntry
 
 	mtx sync.RWMutex
 
@@ -59,18 +53,9 @@ func newRegistry(opts ...registry.Option) registry.Registry {
 		o(&options)
 	}
 
-	// set the domain
-	domain := mdnsDomain
-
-	d, ok := options.Context.Value("mdns.domain").(string)
-	if ok {
-		domain = d
-	}
-
 	return &mdnsRegistry{
 		opts:     options,
-		domain:   domain,
-		services: make(map[string][]*mdnsEntry),
+		services: make(map[string]map[string][]*mdnsEntry),
 		watchers: make(map[string]*mdnsWatcher),
 	}
 }
@@ -86,17 +71,39 @@ func (m *mdnsRegistry) Options() registry.Options {
 	return m.opts
 }
 
+// domain returns the mdns domain of a request, falling back to the registry
+// default, services are advertised as <node>.<service>.<domain>
+func (m *mdnsRegistry) domain(d string) string {
+	if len(d) > 0 {
+		return d
+	}
+	if len(m.opts.Domain) > 0 {
+		return m.opts.Domain
+	}
+	return registry.DefaultDomain
+}
+
 func (m *mdnsRegistry) Register(service *registry.Service, opts ...registry.RegisterOption) error {
 	m.Lock()
 	defer m.Unlock()
 
-	entries, ok := m.services[service.Name]
+	var options registry.RegisterOptions
+	for _, o := range opts {
+		o(&options)
+	}
+
+	domain := m.domain(options.Domain)
+	if _, ok := m.services[domain]; !ok {
+		m.services[domain] = make(map[string][]*mdnsEntry)
+	}
+
+	entries, ok := m.services[domain][service.Name]
 	// first entry, create wildcard used for list queries
 	if !ok {
 		s, err := mdns.NewMDNSService(
 			service.Name,
 			"_services",
-			m.domain+".",
+			domain+".",
 			"",
 			9999,
 			[]net.IP{net.ParseIP("0.0.0.0")},
@@ -161,7 +168,7 @@ func (m *mdnsRegistry) Register(service *registry.Service, opts ...registry.Regi
 		s, err := mdns.NewMDNSService(
 			node.Id,
 			service.Name,
-			m.domain+".",
+			domain+".",
 			"",
 			port,
 			[]net.IP{net.ParseIP(host)},
@@ -184,19 +191,29 @@ func (m *mdnsRegistry) Register(service *registry.Service, opts ...registry.Regi
 	}
 
 	// save
-	m.services[service.Name] = entries
+	m.services[domain][service.Name] = entries
 
 	return gerr
 }
 
-func (m *mdnsRegistry) Deregister(service *registry.Service) error {
+func (m *mdnsRegistry) Deregister(service *registry.Service, opts ...registry.DeregisterOption) error {
 	m.Lock()
 	defer m.Unlock()
 
+	var options registry.DeregisterOptions
+	for _, o := range opts {
+		o(&options)
+	}
+
+	services, ok := m.services[m.domain(options.Domain)]
+	if !ok {
+		return nil
+	}
+
 	var newEntries []*mdnsEntry
 
 	// loop existing entries, check if any match, shutdown those that do
-	for _, entry := range m.services[service.Name] {
+	for _, entry := range services[service.Name] {
 		var remove bool
 
 		for _, node := range service.Nodes {
@@ -216,15 +233,21 @@ func (m *mdnsRegistry) Deregister(service *registry.Service) error {
 	// last entry is the wildcard for list queries. Remove it.
 	if len(newEntries) == 1 && newEntries[0].id == "*" {
 		newEntries[0].node.Shutdown()
-		delete(m.services, service.Name)
+		delete(services, service.Name)
 	} else {
-		m.services[service.Name] = newEntries
+		services[service.Name] = newEntries
 	}
 
 	return nil
 }
 
-func (m *mdnsRegistry) GetService(service string) ([]*registry.Service, error) {
+func (m *mdnsRegistry) GetService(service string, opts ...registry.GetOption) ([]*registry.Service, error) {
+	var options registry.GetOptions
+	for _, o := range opts {
+		o(&options)
+	}
+
+	domain := m.domain(options.Domain)
 	serviceMap := make(map[string]*registry.Service)
 	entries := make(chan *mdns.ServiceEntry, 10)
 	done := make(chan bool)
@@ -237,7 +260,7 @@ func (m *mdnsRegistry) GetService(service string) ([]*registry.Service, error) {
 	// set entries channel
 	p.Entries = entries
 	// set the domain
-	p.Domain = m.domain
+	p.Domain = domain
 
 	go func() {
 		for {
@@ -247,7 +270,7 @@ func (m *mdnsRegistry) GetService(service string) ([]*registry.Service, error) {
 				if p.Service == "_services" {
 					continue
 				}
-				if p.Domain != m.domain {
+				if p.Domain != domain {
 					continue
 				}
 				if e.TTL == 0 {
@@ -304,7 +327,12 @@ func (m *mdnsRegistry) GetService(service string) ([]*registry.Service, error) {
 	return services, nil
 }
 
-func (m *mdnsRegistry) ListServices() ([]*registry.Service, error) {
+func (m *mdnsRegistry) ListServices(opts ...registry.ListOption) ([]*registry.Service, error) {
+	var options registry.ListOptions
+	for _, o := range opts {
+		o(&options)
+	}
+
 	serviceMap := make(map[string]bool)
 	entries := make(chan *mdns.ServiceEntry, 10)
 	done := make(chan bool)
@@ -317,7 +345,7 @@ func (m *mdnsRegistry) ListServices() ([]*registry.Service, error) {
 	// set entries channel
 	p.Entries = entries
 	// set domain
-	p.Domain = m.domain
+	p.Domain = m.domain(options.Domain)
 
 	var services []*registry.Service
 
@@ -365,7 +393,7 @@ func (m *mdnsRegistry) Watch(opts ...registry.WatchOption) (registry.Watcher, er
 		wo:       wo,
 		ch:       make(chan *mdns.ServiceEntry, 32),
 		exit:     make(chan struct{}),
-		domain:   m.domain,
+		domain:   m.domain(wo.Domain),
 		registry: m,
 	}
 
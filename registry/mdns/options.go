@@ -2,17 +2,12 @@
 package mdns
 
 import (
-	"context"
-
 	"github.com/stack-labs/stack/registry"
 )
 
-// Domain sets the mdnsDomain
+// Domain sets the mdns domain, it is the same as registry.Domain
+//
+// Deprecated: use registry.Domain
 func Domain(d string) registry.Option {
-	return func(o *registry.Options) {
-		if o.Context == nil {
-			o.Context = context.Background()
-		}
-		o.Context = context.WithValue(o.Context, "mdns.domain", d)
-	}
+	return registry.Domain(d)
 }
@@ -34,10 +34,14 @@ type Registry struct {
 	options registry.Options
 
 	sync.RWMutex
-	records  map[string]map[string]*record
+	// records of the services by domain, name and version
+	records  map[string]services
 	watchers map[string]*Watcher
 }
 
+// services records by name and version
+type services map[string]map[string]*record
+
 func NewRegistry(opts ...registry.Option) registry.Registry {
 	options := registry.Options{
 		Context: context.Background(),
@@ -47,17 +51,16 @@ func NewRegistry(opts ...registry.Option) registry.Registry {
 		o(&options)
 	}
 
-	records := getServiceRecords(options.Context)
-	if records == nil {
-		records = make(map[string]map[string]*record)
-	}
-
 	reg := &Registry{
 		options:  options,
-		records:  records,
+		records:  make(map[string]services),
 		watchers: make(map[string]*Watcher),
 	}
 
+	if records := getServiceRecords(options.Context); records != nil {
+		reg.records[reg.domain("")] = records
+	}
+
 	go reg.ttlPrune()
 
 	return reg
@@ -71,12 +74,14 @@ func (m *Registry) ttlPrune() {
 		select {
 		case <-prune.C:
 			m.Lock()
-			for name, records := range m.records {
-				for version, record := range records {
-					for id, n := range record.Nodes {
-						if n.TTL != 0 && time.Since(n.LastSeen) > n.TTL {
-							log.Debugf("Registry TTL expired for node %s of service %s", n.Id, name)
-							delete(m.records[name][version].Nodes, id)
+			for _, domain := range m.records {
+				for name, records := range domain {
+					for _, record := range records {
+						for id, n := range record.Nodes {
+							if n.TTL != 0 && time.Since(n.LastSeen) > n.TTL {
+								log.Debugf("Registry TTL expired for node %s of service %s", n.Id, name)
+								delete(record.Nodes, id)
+							}
 						}
 					}
 				}
@@ -86,10 +91,25 @@ func (m *Registry) ttlPrune() {
 	}
 }
 
-func (m *Registry) sendEvent(r *registry.Result) {
+// domain returns the domain of a request, falling back to the registry default
+func (m *Registry) domain(d string) string {
+	if len(d) > 0 {
+		return d
+	}
+	if len(m.options.Domain) > 0 {
+		return m.options.Domain
+	}
+	return registry.DefaultDomain
+}
+
+// sendEvent sends the result to the watchers of the domain
+func (m *Registry) sendEvent(domain string, r *registry.Result) {
 	m.RLock()
 	watchers := make([]*Watcher, 0, len(m.watchers))
 	for _, w := range m.watchers {
+		if w.wo.Domain != domain {
+			continue
+		}
 		watchers = append(watchers, w)
 	}
 	m.RUnlock()
@@ -118,17 +138,22 @@ func (m *Registry) Init(opts ...registry.Option) error {
 	m.Lock()
 	defer m.Unlock()
 
+	domain := m.domain("")
+	if _, ok := m.records[domain]; !ok {
+		m.records[domain] = make(services)
+	}
+
 	records := getServiceRecords(m.options.Context)
 	for name, record := range records {
 		// add a whole new service including all of its versions
-		if _, ok := m.records[name]; !ok {
-			m.records[name] = record
+		if _, ok := m.records[domain][name]; !ok {
+			m.records[domain][name] = record
 			continue
 		}
 		// add the versions of the service we dont track yet
 		for version, r := range record {
-			if _, ok := m.records[name][version]; !ok {
-				m.records[name][version] = r
+			if _, ok := m.records[domain][name][version]; !ok {
+				m.records[domain][name][version] = r
 				continue
 			}
 		}
@@ -151,26 +176,33 @@ func (m *Registry) Register(s *registry.Service, opts ...registry.RegisterOption
 	}
 
 	r := serviceToRecord(s, options.TTL)
+	domain := m.domain(options.Domain)
 
-	if _, ok := m.records[s.Name]; !ok {
-		m.records[s.Name] = make(map[string]*record)
+	if _, ok := m.records[domain]; !ok {
+		m.records[domain] = make(services)
 	}
 
-	if _, ok := m.records[s.Name][s.Version]; !ok {
-		m.records[s.Name][s.Version] = r
-		log.Debugf("Registry added new service: %s, version: %s", s.Name, s.Version)
-		go m.sendEvent(&registry.Result{Action: "update", Service: s})
+	records := m.records[domain]
+
+	if _, ok := records[s.Name]; !ok {
+		records[s.Name] = make(map[string]*record)
+	}
+
+	if _, ok := records[s.Name][s.Version]; !ok {
+		records[s.Name][s.Version] = r
+		log.Debugf("Registry added new service: %s, version: %s, domain: %s", s.Name, s.Version, domain)
+		go m.sendEvent(domain, &registry.Result{Action: "update", Service: s})
 		return nil
 	}
 
 	addedNodes := false
 	for _, n := range s.Nodes {
-		if _, ok := m.records[s.Name][s.Version].Nodes[n.Id]; !ok {
+		if _, ok := records[s.Name][s.Version].Nodes[n.Id]; !ok {
 			addedNodes = true
 			metadata := make(map[string]string)
 			for k, v := range n.Metadata {
 				metadata[k] = v
-				m.records[s.Name][s.Version].Nodes[n.Id] = &node{
+				records[s.Name][s.Version].Nodes[n.Id] = &node{
 					Node: &registry.Node{
 						Id:       n.Id,
 						Address:  n.Address,
@@ -184,58 +216,71 @@ func (m *Registry) Register(s *registry.Service, opts ...registry.RegisterOption
 	}
 
 	if addedNodes {
-		log.Debugf("Registry added new node to service: %s, version: %s", s.Name, s.Version)
-		go m.sendEvent(&registry.Result{Action: "update", Service: s})
+		log.Debugf("Registry added new node to service: %s, version: %s, domain: %s", s.Name, s.Version, domain)
+		go m.sendEvent(domain, &registry.Result{Action: "update", Service: s})
 		return nil
 	}
 
 	// refresh TTL and timestamp
 	for _, n := range s.Nodes {
 		log.Debugf("Updated registration for service: %s, version: %s", s.Name, s.Version)
-		m.records[s.Name][s.Version].Nodes[n.Id].TTL = options.TTL
-		m.records[s.Name][s.Version].Nodes[n.Id].LastSeen = time.Now()
+		records[s.Name][s.Version].Nodes[n.Id].TTL = options.TTL
+		records[s.Name][s.Version].Nodes[n.Id].LastSeen = time.Now()
 	}
 
 	return nil
 }
 
-func (m *Registry) Deregister(s *registry.Service) error {
+func (m *Registry) Deregister(s *registry.Service, opts ...registry.DeregisterOption) error {
 	m.Lock()
 	defer m.Unlock()
 
-	if _, ok := m.records[s.Name]; ok {
-		if _, ok := m.records[s.Name][s.Version]; ok {
+	var options registry.DeregisterOptions
+	for _, o := range opts {
+		o(&options)
+	}
+
+	domain := m.domain(options.Domain)
+	records := m.records[domain]
+
+	if _, ok := records[s.Name]; ok {
+		if _, ok := records[s.Name][s.Version]; ok {
 			for _, n := range s.Nodes {
-				if _, ok := m.records[s.Name][s.Version].Nodes[n.Id]; ok {
-					log.Debugf("Registry removed node from service: %s, version: %s", s.Name, s.Version)
-					delete(m.records[s.Name][s.Version].Nodes, n.Id)
+				if _, ok := records[s.Name][s.Version].Nodes[n.Id]; ok {
+					log.Debugf("Registry removed node from service: %s, version: %s, domain: %s", s.Name, s.Version, domain)
+					delete(records[s.Name][s.Version].Nodes, n.Id)
 				}
 			}
-			if len(m.records[s.Name][s.Version].Nodes) == 0 {
-				delete(m.records[s.Name], s.Version)
-				log.Debugf("Registry removed service: %s, version: %s", s.Name, s.Version)
+			if len(records[s.Name][s.Version].Nodes) == 0 {
+				delete(records[s.Name], s.Version)
+				log.Debugf("Registry removed service: %s, version: %s, domain: %s", s.Name, s.Version, domain)
 			}
 		}
-		if len(m.records[s.Name]) == 0 {
-			delete(m.records, s.Name)
-			log.Debugf("Registry removed service: %s", s.Name)
+		if len(records[s.Name]) == 0 {
+			delete(records, s.Name)
+			log.Debugf("Registry removed service: %s, domain: %s", s.Name, domain)
 		}
-		go m.sendEvent(&registry.Result{Action: "delete", Service: s})
+		go m.sendEvent(domain, &registry.Result{Action: "delete", Service: s})
 	}
 
 	return nil
 }
 
-func (m *Registry) GetService(name string) ([]*registry.Service, error) {
+func (m *Registry) GetService(name string, opts ...registry.GetOption) ([]*registry.Service, error) {
 	m.RLock()
 	defer m.RUnlock()
 
-	records, ok := m.records[name]
+	var options registry.GetOptions
+	for _, o := range opts {
+		o(&options)
+	}
+
+	records, ok := m.records[m.domain(options.Domain)][name]
 	if !ok {
 		return nil, registry.ErrNotFound
 	}
 
-	services := make([]*registry.Service, len(m.records[name]))
+	services := make([]*registry.Service, len(records))
 	i := 0
 	for _, record := range records {
 		services[i] = recordToService(record)
@@ -245,12 +290,17 @@ func (m *Registry) GetService(name string) ([]*registry.Service, error) {
 	return services, nil
 }
 
-func (m *Registry) ListServices() ([]*registry.Service, error) {
+func (m *Registry) ListServices(opts ...registry.ListOption) ([]*registry.Service, error) {
 	m.RLock()
 	defer m.RUnlock()
 
+	var options registry.ListOptions
+	for _, o := range opts {
+		o(&options)
+	}
+
 	var services []*registry.Service
-	for _, records := range m.records {
+	for _, records := range m.records[m.domain(options.Domain)] {
 		for _, record := range records {
 			services = append(services, recordToService(record))
 		}
@@ -264,6 +314,7 @@ func (m *Registry) Watch(opts ...registry.WatchOption) (registry.Watcher, error)
 	for _, o := range opts {
 		o(&wo)
 	}
+	wo.Domain = m.domain(wo.Domain)
 
 	w := &Watcher{
 		exit: make(chan bool),
@@ -241,3 +241,65 @@ func TestMemoryRegistryTTLConcurrent(t *testing.T) {
 		}
 	}
 }
+
+func TestMemoryRegistryDomain(t *testing.T) {
+	m := NewRegistry(registry.Domain("foo"))
+
+	service := testData["foo"][0]
+
+	if err := m.Register(service); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Register(service, registry.RegisterDomain("bar")); err != nil {
+		t.Fatal(err)
+	}
+
+	// the registry default domain
+	if services, err := m.GetService(service.Name); err != nil || len(services) != 1 {
+		t.Fatalf("Expected the service in the default domain, got %v: %v", services, err)
+	}
+	if services, err := m.GetService(service.Name, registry.GetDomain("bar")); err != nil || len(services) != 1 {
+		t.Fatalf("Expected the service in domain bar, got %v: %v", services, err)
+	}
+	if _, err := m.GetService(service.Name, registry.GetDomain(registry.DefaultDomain)); err != registry.ErrNotFound {
+		t.Fatalf("Expected %v from another domain, got %v", registry.ErrNotFound, err)
+	}
+	if services, _ := m.ListServices(registry.ListDomain("baz")); len(services) != 0 {
+		t.Fatalf("Expected no services in domain baz, got %d", len(services))
+	}
+
+	w, err := m.Watch(registry.WatchDomain("bar"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Stop()
+
+	// only the events of the watched domain are sent
+	if err := m.Register(testData["bar"][0]); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Deregister(service); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Deregister(service, registry.DeregisterDomain("bar")); err != nil {
+		t.Fatal(err)
+	}
+
+	// skip the events of the earlier registration
+	res, err := w.Next()
+	for err == nil && res.Action == "update" && res.Service.Name == service.Name {
+		res, err = w.Next()
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Action != "delete" || res.Service.Name != service.Name {
+		t.Fatalf("Expected delete of %s, got %s of %s", service.Name, res.Action, res.Service.Name)
+	}
+
+	for _, d := range []string{"foo", "bar"} {
+		if _, err := m.GetService(service.Name, registry.GetDomain(d)); err != registry.ErrNotFound {
+			t.Fatalf("Expected %v in domain %s, got %v", registry.ErrNotFound, d, err)
+		}
+	}
+}
@@ -8,28 +8,28 @@ import (
 
 type servicesKey struct{}
 
-func getServiceRecords(ctx context.Context) map[string]map[string]*record {
+func getServiceRecords(ctx context.Context) services {
 	memServices, ok := ctx.Value(servicesKey{}).(map[string][]*registry.Service)
 	if !ok {
 		return nil
 	}
 
-	services := make(map[string]map[string]*record)
+	records := make(services)
 
 	for name, svc := range memServices {
-		if _, ok := services[name]; !ok {
-			services[name] = make(map[string]*record)
+		if _, ok := records[name]; !ok {
+			records[name] = make(map[string]*record)
 		}
 		// go through every version of the service
 		for _, s := range svc {
-			services[s.Name][s.Version] = serviceToRecord(s, 0)
+			records[s.Name][s.Version] = serviceToRecord(s, 0)
 		}
 	}
 
-	return services
+	return records
 }
 
-// Services is an option that preloads service data
+// Services is an option that preloads service data into the default domain
 func Services(s map[string][]*registry.Service) registry.Option {
 	return func(o *registry.Options) {
 		if o.Context == nil {
@@ -12,6 +12,9 @@ type Options struct {
 	Timeout   time.Duration
 	Secure    bool
 	TLSConfig *tls.Config
+	// Domain is the default domain of the requests
+	// which do not set one
+	Domain string
 	// Other options for implementations of the interface
 	// can be stored in a context
 	Context context.Context
@@ -22,13 +25,16 @@ type RegisterOptions struct {
 	// Other options for implementations of the interface
 	// can be stored in a context
 	Context context.Context
-	Domain  string
+	// Domain to register the service in
+	Domain string
 }
 
 type WatchOptions struct {
 	// Specify a service to watch
 	// If blank, the watch is for all services
 	Service string
+	// Domain to watch the services of
+	Domain string
 	// Other options for implementations of the interface
 	// can be stored in a context
 	Context context.Context
@@ -93,6 +99,13 @@ func TLSConfig(t *tls.Config) Option {
 	}
 }
 
+// Domain sets the default domain of the registry
+func Domain(d string) Option {
+	return func(o *Options) {
+		o.Domain = d
+	}
+}
+
 func RegisterTTL(t time.Duration) RegisterOption {
 	return func(o *RegisterOptions) {
 		o.TTL = t
@@ -105,3 +118,38 @@ func WatchService(name string) WatchOption {
 		o.Service = name
 	}
 }
+
+// Register the service in a domain
+func RegisterDomain(d string) RegisterOption {
+	return func(o *RegisterOptions) {
+		o.Domain = d
+	}
+}
+
+// Watch the services of a domain
+func WatchDomain(d string) WatchOption {
+	return func(o *WatchOptions) {
+		o.Domain = d
+	}
+}
+
+// Deregister the service from a domain
+func DeregisterDomain(d string) DeregisterOption {
+	return func(o *DeregisterOptions) {
+		o.Domain = d
+	}
+}
+
+// Get the service from a domain
+func GetDomain(d string) GetOption {
+	return func(o *GetOptions) {
+		o.Domain = d
+	}
+}
+
+// List the services of a domain
+func ListDomain(d string) ListOption {
+	return func(o *ListOptions) {
+		o.Domain = d
+	}
+}
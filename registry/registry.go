@@ -6,6 +6,10 @@ import (
 )
 
 var (
+	// DefaultDomain is the domain services are registered in and looked up
+	// from when none is set
+	DefaultDomain = "stack"
+
 	// Not found error when GetService is called
 	ErrNotFound = errors.New("service not found")
 	// Watcher stopped error when watcher is stopped
@@ -19,9 +23,9 @@ type Registry interface {
 	Init(...Option) error
 	Options() Options
 	Register(*Service, ...RegisterOption) error
-	Deregister(*Service) error
-	GetService(string) ([]*Service, error)
-	ListServices() ([]*Service, error)
+	Deregister(*Service, ...DeregisterOption) error
+	GetService(string, ...GetOption) ([]*Service, error)
+	ListServices(...ListOption) ([]*Service, error)
 	Watch(...WatchOption) (Watcher, error)
 	String() string
 }
@@ -16,7 +16,7 @@ type Registry struct {
 }
 
 func (r *Registry) GetService(ctx context.Context, req *pb.GetRequest, rsp *pb.GetResponse) error {
-	services, err := r.Registry.GetService(req.Service)
+	services, err := r.Registry.GetService(req.Service, registry.GetDomain(req.Options.GetDomain()))
 	if err != nil {
 		return errors.InternalServerError("stack.rpc.registry", err.Error())
 	}
@@ -30,7 +30,7 @@ func (r *Registry) Register(ctx context.Context, req *pb.Service, rsp *pb.EmptyR
 	var regOpts []registry.RegisterOption
 	if req.Options != nil {
 		ttl := time.Duration(req.Options.Ttl) * time.Second
-		regOpts = append(regOpts, registry.RegisterTTL(ttl))
+		regOpts = append(regOpts, registry.RegisterTTL(ttl), registry.RegisterDomain(req.Options.Domain))
 	}
 
 	err := r.Registry.Register(service.ToService(req), regOpts...)
@@ -42,7 +42,7 @@ func (r *Registry) Register(ctx context.Context, req *pb.Service, rsp *pb.EmptyR
 }
 
 func (r *Registry) Deregister(ctx context.Context, req *pb.Service, rsp *pb.EmptyResponse) error {
-	err := r.Registry.Deregister(service.ToService(req))
+	err := r.Registry.Deregister(service.ToService(req), registry.DeregisterDomain(req.Options.GetDomain()))
 	if err != nil {
 		return errors.InternalServerError("stack.rpc.registry", err.Error())
 	}
@@ -50,7 +50,7 @@ func (r *Registry) Deregister(ctx context.Context, req *pb.Service, rsp *pb.Empt
 }
 
 func (r *Registry) ListServices(ctx context.Context, req *pb.ListRequest, rsp *pb.ListResponse) error {
-	services, err := r.Registry.ListServices()
+	services, err := r.Registry.ListServices(registry.ListDomain(req.Options.GetDomain()))
 	if err != nil {
 		return errors.InternalServerError("stack.rpc.registry", err.Error())
 	}
@@ -61,7 +61,10 @@ func (r *Registry) ListServices(ctx context.Context, req *pb.ListRequest, rsp *p
 }
 
 func (r *Registry) Watch(ctx context.Context, req *pb.WatchRequest, rsp pb.Registry_WatchStream) error {
-	watcher, err := r.Registry.Watch(registry.WatchService(req.Service))
+	watcher, err := r.Registry.Watch(
+		registry.WatchService(req.Service),
+		registry.WatchDomain(req.Options.GetDomain()),
+	)
 	if err != nil {
 		return errors.InternalServerError("stack.rpc.registry", err.Error())
 	}
@@ -315,7 +315,9 @@ func (m *Value) GetValues() []*Value {
 
 // Options are registry options
 type Options struct {
-	Ttl                  int64    `protobuf:"varint,1,opt,name=ttl,proto3" json:"ttl,omitempty"`
+	Ttl int64 `protobuf:"varint,1,opt,name=ttl,proto3" json:"ttl,omitempty"`
+	// domain of the request, the registry default when blank
+	Domain               string   `protobuf:"bytes,2,opt,name=domain,proto3" json:"domain,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -353,6 +355,13 @@ func (m *Options) GetTtl() int64 {
 	return 0
 }
 
+func (m *Options) GetDomain() string {
+	if m != nil {
+		return m.Domain
+	}
+	return ""
+}
+
 // Result is returns by the watcher
 type Result struct {
 	Action               string   `protobuf:"bytes,1,opt,name=action,proto3" json:"action,omitempty"`
@@ -442,6 +451,7 @@ var xxx_messageInfo_EmptyResponse proto.InternalMessageInfo
 
 type GetRequest struct {
 	Service              string   `protobuf:"bytes,1,opt,name=service,proto3" json:"service,omitempty"`
+	Options              *Options `protobuf:"bytes,2,opt,name=options,proto3" json:"options,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -479,6 +489,13 @@ func (m *GetRequest) GetService() string {
 	return ""
 }
 
+func (m *GetRequest) GetOptions() *Options {
+	if m != nil {
+		return m.Options
+	}
+	return nil
+}
+
 type GetResponse struct {
 	Services             []*Service `protobuf:"bytes,1,rep,name=services,proto3" json:"services,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
@@ -519,6 +536,8 @@ func (m *GetResponse) GetServices() []*Service {
 }
 
 type ListRequest struct {
+	// TODO: filtering
+	Options              *Options `protobuf:"bytes,1,opt,name=options,proto3" json:"options,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -549,6 +568,13 @@ func (m *ListRequest) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_ListRequest proto.InternalMessageInfo
 
+func (m *ListRequest) GetOptions() *Options {
+	if m != nil {
+		return m.Options
+	}
+	return nil
+}
+
 type ListResponse struct {
 	Services             []*Service `protobuf:"bytes,1,rep,name=services,proto3" json:"services,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
@@ -591,6 +617,7 @@ func (m *ListResponse) GetServices() []*Service {
 type WatchRequest struct {
 	// service is optional
 	Service              string   `protobuf:"bytes,1,opt,name=service,proto3" json:"service,omitempty"`
+	Options              *Options `protobuf:"bytes,2,opt,name=options,proto3" json:"options,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -628,6 +655,13 @@ func (m *WatchRequest) GetService() string {
 	return ""
 }
 
+func (m *WatchRequest) GetOptions() *Options {
+	if m != nil {
+		return m.Options
+	}
+	return nil
+}
+
 // Event is registry event
 type Event struct {
 	// Event Id
@@ -719,47 +753,49 @@ func init() {
 func init() { proto.RegisterFile("registry.proto", fileDescriptor_41af05d40a615591) }
 
 var fileDescriptor_41af05d40a615591 = []byte{
-	// 666 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb4, 0x55, 0xcd, 0x6e, 0xd3, 0x4c,
-	0x14, 0x8d, 0xed, 0xfc, 0xde, 0xb4, 0xfd, 0xaa, 0xab, 0x4f, 0xc8, 0xa4, 0x05, 0x82, 0x17, 0x55,
-	0xe8, 0xc2, 0xd0, 0x54, 0x15, 0xa8, 0xec, 0xa0, 0x69, 0x85, 0x54, 0x5a, 0x69, 0xca, 0xcf, 0xda,
-	0x8d, 0xaf, 0xc0, 0x6a, 0x62, 0x9b, 0x99, 0x49, 0xa4, 0x3c, 0x05, 0x6b, 0x56, 0x3c, 0x0d, 0xaf,
-	0xc1, 0xb3, 0xa0, 0x19, 0x8f, 0x93, 0x54, 0x71, 0x0c, 0x52, 0x61, 0x77, 0xc7, 0x73, 0xee, 0xdf,
-	0x39, 0x67, 0x64, 0xd8, 0xe2, 0xf4, 0x29, 0x12, 0x92, 0xcf, 0xfc, 0x94, 0x27, 0x32, 0x41, 0x14,
-	0x32, 0x18, 0xde, 0xf8, 0x3c, 0x1d, 0xfa, 0xf9, 0x8d, 0xf7, 0xd3, 0x86, 0xc6, 0x15, 0xf1, 0x69,
-	0x34, 0x24, 0x44, 0xa8, 0xc6, 0xc1, 0x98, 0x5c, 0xab, 0x6b, 0xf5, 0x5a, 0x4c, 0xc7, 0xe8, 0x42,
-	0x63, 0x4a, 0x5c, 0x44, 0x49, 0xec, 0xda, 0xfa, 0x73, 0x7e, 0xc4, 0x01, 0x34, 0xc7, 0x24, 0x83,
-	0x30, 0x90, 0x81, 0xeb, 0x74, 0x9d, 0x5e, 0xbb, 0xff, 0xc4, 0x5f, 0x6d, 0xe0, 0x9b, 0xe2, 0xfe,
-	0x5b, 0x83, 0x1d, 0xc4, 0x92, 0xcf, 0xd8, 0x3c, 0x15, 0x8f, 0xa1, 0x45, 0x71, 0x98, 0x26, 0x51,
-	0x2c, 0x85, 0x5b, 0xd5, 0x75, 0x76, 0x8b, 0xea, 0x0c, 0x0c, 0x88, 0x2d, 0xe0, 0xe8, 0x43, 0x2d,
-	0x4e, 0x42, 0x12, 0x6e, 0x4d, 0xe7, 0xb9, 0x45, 0x79, 0x17, 0x49, 0x48, 0x2c, 0x83, 0xe1, 0x11,
-	0x34, 0x92, 0x54, 0x46, 0x49, 0x2c, 0xdc, 0x7a, 0xd7, 0xea, 0xb5, 0xfb, 0x3b, 0x45, 0x19, 0x97,
-	0x19, 0x84, 0xe5, 0xd8, 0xce, 0x4b, 0xd8, 0xbc, 0x35, 0x3d, 0x6e, 0x83, 0x73, 0x43, 0x33, 0xc3,
-	0x93, 0x0a, 0xf1, 0x7f, 0xa8, 0x4d, 0x83, 0xd1, 0x84, 0x0c, 0x49, 0xd9, 0xe1, 0xd8, 0x7e, 0x61,
-	0x79, 0x3f, 0x2c, 0xa8, 0xaa, 0x19, 0x70, 0x0b, 0xec, 0x28, 0x34, 0x39, 0x76, 0x14, 0x2a, 0x66,
-	0x83, 0x30, 0xe4, 0x24, 0x44, 0xce, 0xac, 0x39, 0x2a, 0x1d, 0xd2, 0x84, 0x4b, 0xd7, 0xe9, 0x5a,
-	0x3d, 0x87, 0xe9, 0x18, 0x5f, 0x2d, 0xb1, 0x9d, 0xb1, 0xb4, 0xb7, 0x6e, 0xdb, 0x75, 0x54, 0xdf,
-	0x6d, 0x8f, 0xaf, 0x36, 0x34, 0x73, 0x0d, 0x0a, 0x9d, 0x72, 0x08, 0x0d, 0x4e, 0x5f, 0x26, 0x24,
-	0xa4, 0x4e, 0x6e, 0xf7, 0xef, 0x17, 0x0d, 0xf8, 0x41, 0x15, 0x64, 0x39, 0x12, 0x8f, 0xa0, 0xc9,
-	0x49, 0xa4, 0x49, 0x2c, 0x48, 0xaf, 0x5b, 0x9a, 0x35, 0x87, 0xe2, 0xe9, 0x0a, 0x1b, 0xfb, 0x65,
-	0x9e, 0xf9, 0x37, 0x8c, 0x5c, 0x43, 0x4d, 0xcf, 0x55, 0xc8, 0x06, 0x42, 0x55, 0xce, 0xd2, 0x3c,
-	0x4b, 0xc7, 0x78, 0x00, 0x75, 0x9d, 0x2d, 0xcc, 0x7b, 0x29, 0x59, 0xd5, 0x00, 0xbd, 0x1d, 0x68,
-	0x18, 0x3b, 0xaa, 0xd1, 0xa4, 0x1c, 0xe9, 0x26, 0x0e, 0x53, 0xa1, 0x37, 0x81, 0x3a, 0x23, 0x31,
-	0x19, 0x49, 0xbc, 0x07, 0xf5, 0x60, 0xa8, 0x60, 0x66, 0x06, 0x73, 0x52, 0x86, 0x17, 0xd9, 0xfb,
-	0x33, 0x9a, 0xec, 0x94, 0x3c, 0x51, 0x96, 0x63, 0x71, 0x17, 0x5a, 0x32, 0x1a, 0x93, 0x90, 0xc1,
-	0x38, 0x35, 0x2e, 0x5c, 0x7c, 0xf0, 0xfe, 0x83, 0xcd, 0xc1, 0x38, 0x95, 0x33, 0x66, 0xd4, 0xf0,
-	0xf6, 0x00, 0xce, 0x48, 0x32, 0x23, 0xa9, 0xbb, 0xe8, 0x99, 0x0d, 0x93, 0x1f, 0xbd, 0x53, 0x68,
-	0x6b, 0x9c, 0x11, 0xf1, 0x39, 0x34, 0xcd, 0x8d, 0x70, 0x2d, 0x4d, 0x48, 0xe9, 0x74, 0x73, 0xb0,
-	0xb7, 0x09, 0xed, 0xf3, 0x48, 0xe4, 0x0d, 0xbd, 0x33, 0xd8, 0xc8, 0x8e, 0x77, 0xad, 0xdb, 0x83,
-	0x8d, 0x8f, 0x81, 0x1c, 0x7e, 0xfe, 0xfd, 0x26, 0xdf, 0x2d, 0xa8, 0x0d, 0xa6, 0x14, 0xcb, 0x95,
-	0x57, 0x7d, 0xb0, 0xa4, 0xfb, 0x56, 0xff, 0x41, 0xa1, 0x2b, 0x55, 0xe2, 0xbb, 0x59, 0x4a, 0xc6,
-	0x16, 0xa5, 0x6c, 0x2f, 0x4b, 0x58, 0xfd, 0x73, 0x09, 0xf7, 0x9f, 0x42, 0x6b, 0xde, 0x07, 0x01,
-	0xea, 0xaf, 0x39, 0x05, 0x92, 0xb6, 0x2b, 0x2a, 0x3e, 0xa1, 0x11, 0x49, 0xda, 0xb6, 0x54, 0xfc,
-	0x3e, 0x0d, 0xd5, 0x77, 0xbb, 0xff, 0xcd, 0x81, 0x26, 0x33, 0xe5, 0xf0, 0x52, 0x2b, 0x9a, 0xff,
-	0x17, 0x1e, 0x16, 0x75, 0x5c, 0x28, 0xde, 0x79, 0xb4, 0xf6, 0xde, 0x18, 0xa4, 0x82, 0xe7, 0x79,
-	0x71, 0xe2, 0x58, 0xb6, 0x40, 0xe7, 0x71, 0x21, 0x63, 0xb7, 0xec, 0x56, 0xc1, 0x0b, 0x80, 0x13,
-	0xe2, 0x7f, 0xaf, 0xde, 0x55, 0xe6, 0x20, 0x93, 0x23, 0xb0, 0x70, 0xa1, 0x25, 0xcb, 0x75, 0xba,
-	0xeb, 0x01, 0xf3, 0xa2, 0x6f, 0xa0, 0xa6, 0xdd, 0x84, 0x85, 0xe0, 0x65, 0xa3, 0x75, 0x3a, 0x45,
-	0x88, 0xec, 0x69, 0x7b, 0x95, 0x67, 0xd6, 0x75, 0x5d, 0xff, 0xbf, 0x0f, 0x7f, 0x05, 0x00, 0x00,
-	0xff, 0xff, 0xb0, 0x99, 0x8a, 0xfe, 0xd1, 0x07, 0x00, 0x00,
+	// 690 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb4, 0x56, 0x4d, 0x6f, 0xd3, 0x40,
+	0x10, 0x8d, 0xed, 0x7c, 0x4e, 0xda, 0x52, 0x8d, 0x10, 0x32, 0xa1, 0x40, 0xf0, 0x01, 0x85, 0x1e,
+	0x0c, 0x4d, 0x55, 0x81, 0xca, 0x0d, 0x9a, 0x56, 0x48, 0xa5, 0x95, 0x5c, 0x3e, 0x4e, 0x08, 0xb9,
+	0xf1, 0x08, 0xac, 0x26, 0xb6, 0xd9, 0xdd, 0x44, 0xca, 0xaf, 0xe0, 0xcc, 0x89, 0x5f, 0xc3, 0xdf,
+	0xe0, 0xb7, 0xa0, 0x5d, 0xaf, 0x1d, 0x57, 0x71, 0x42, 0x51, 0xe9, 0x6d, 0xd6, 0x7e, 0xef, 0xed,
+	0xec, 0xdb, 0x37, 0x4e, 0x60, 0x83, 0xd1, 0x97, 0x90, 0x0b, 0x36, 0x73, 0x13, 0x16, 0x8b, 0x18,
+	0x91, 0x0b, 0x7f, 0x78, 0xe1, 0xb2, 0x64, 0xe8, 0x66, 0x6f, 0x9c, 0xdf, 0x26, 0x34, 0xce, 0x88,
+	0x4d, 0xc3, 0x21, 0x21, 0x42, 0x35, 0xf2, 0xc7, 0x64, 0x1b, 0x5d, 0xa3, 0xd7, 0xf2, 0x54, 0x8d,
+	0x36, 0x34, 0xa6, 0xc4, 0x78, 0x18, 0x47, 0xb6, 0xa9, 0x1e, 0x67, 0x4b, 0x1c, 0x40, 0x73, 0x4c,
+	0xc2, 0x0f, 0x7c, 0xe1, 0xdb, 0x56, 0xd7, 0xea, 0xb5, 0xfb, 0x4f, 0xdc, 0xc5, 0x0d, 0x5c, 0x2d,
+	0xee, 0xbe, 0xd5, 0xd8, 0x41, 0x24, 0xd8, 0xcc, 0xcb, 0xa9, 0xb8, 0x0f, 0x2d, 0x8a, 0x82, 0x24,
+	0x0e, 0x23, 0xc1, 0xed, 0xaa, 0xd2, 0xd9, 0x2a, 0xd3, 0x19, 0x68, 0x90, 0x37, 0x87, 0xa3, 0x0b,
+	0xb5, 0x28, 0x0e, 0x88, 0xdb, 0x35, 0xc5, 0xb3, 0xcb, 0x78, 0x27, 0x71, 0x40, 0x5e, 0x0a, 0xc3,
+	0x3d, 0x68, 0xc4, 0x89, 0x08, 0xe3, 0x88, 0xdb, 0xf5, 0xae, 0xd1, 0x6b, 0xf7, 0xef, 0x95, 0x31,
+	0x4e, 0x53, 0x88, 0x97, 0x61, 0x3b, 0x2f, 0x61, 0xfd, 0x52, 0xf7, 0xb8, 0x09, 0xd6, 0x05, 0xcd,
+	0xb4, 0x4f, 0xb2, 0xc4, 0xdb, 0x50, 0x9b, 0xfa, 0xa3, 0x09, 0x69, 0x93, 0xd2, 0xc5, 0xbe, 0xf9,
+	0xc2, 0x70, 0x7e, 0x19, 0x50, 0x95, 0x3d, 0xe0, 0x06, 0x98, 0x61, 0xa0, 0x39, 0x66, 0x18, 0x48,
+	0x67, 0xfd, 0x20, 0x60, 0xc4, 0x79, 0xe6, 0xac, 0x5e, 0xca, 0x7b, 0x48, 0x62, 0x26, 0x6c, 0xab,
+	0x6b, 0xf4, 0x2c, 0x4f, 0xd5, 0xf8, 0xaa, 0xe0, 0x76, 0xea, 0xd2, 0xe3, 0x65, 0xa7, 0x5d, 0x66,
+	0xf5, 0xf5, 0xce, 0xf1, 0xdd, 0x84, 0x66, 0x76, 0x07, 0xa5, 0x49, 0xd9, 0x85, 0x06, 0xa3, 0x6f,
+	0x13, 0xe2, 0x42, 0x91, 0xdb, 0xfd, 0xbb, 0x65, 0x0d, 0x7e, 0x90, 0x82, 0x5e, 0x86, 0xc4, 0x3d,
+	0x68, 0x32, 0xe2, 0x49, 0x1c, 0x71, 0xb2, 0xad, 0xbf, 0xb1, 0x72, 0x28, 0x1e, 0x2e, 0xb8, 0xb1,
+	0xbd, 0x2a, 0x33, 0x37, 0xe3, 0xc8, 0x39, 0xd4, 0x54, 0x5f, 0xa5, 0x6e, 0x20, 0x54, 0xc5, 0x2c,
+	0xc9, 0x58, 0xaa, 0xc6, 0x1d, 0xa8, 0x2b, 0x36, 0xd7, 0xf3, 0xb2, 0xe2, 0xa8, 0x1a, 0xe8, 0xec,
+	0x42, 0x43, 0xc7, 0x51, 0xb6, 0x26, 0xc4, 0x48, 0x6d, 0x62, 0x79, 0xb2, 0xc4, 0x3b, 0x50, 0x0f,
+	0xe2, 0xb1, 0x1f, 0x66, 0xa3, 0xa9, 0x57, 0xce, 0x04, 0xea, 0x1e, 0xf1, 0xc9, 0x48, 0x48, 0x84,
+	0x3f, 0x94, 0x74, 0xdd, 0x9b, 0x5e, 0xc9, 0x41, 0xe0, 0xe9, 0x5c, 0xda, 0xe6, 0xf2, 0x41, 0xd0,
+	0xa3, 0xeb, 0x65, 0x58, 0xdc, 0x82, 0x96, 0x08, 0xc7, 0xc4, 0x85, 0x3f, 0x4e, 0x74, 0x3a, 0xe7,
+	0x0f, 0x9c, 0x5b, 0xb0, 0x3e, 0x18, 0x27, 0x62, 0xe6, 0xe9, 0x5b, 0x72, 0x3e, 0x01, 0x1c, 0x91,
+	0xf0, 0xf4, 0x55, 0xdb, 0xf3, 0x3d, 0xd3, 0x66, 0x72, 0xd9, 0xc2, 0x58, 0x9a, 0x57, 0x1f, 0x4b,
+	0xe7, 0x10, 0xda, 0x4a, 0x5e, 0x67, 0xe2, 0x39, 0x34, 0xb5, 0x20, 0xb7, 0x8d, 0xae, 0xb5, 0x4c,
+	0x26, 0x3b, 0x54, 0x0e, 0x76, 0x0e, 0xa0, 0x7d, 0x1c, 0xf2, 0xbc, 0xcf, 0x42, 0x37, 0xc6, 0x3f,
+	0x74, 0x73, 0x04, 0x6b, 0xa9, 0xca, 0x75, 0xdb, 0xf9, 0x0c, 0x6b, 0x1f, 0x7d, 0x31, 0xfc, 0x7a,
+	0x63, 0xbe, 0xfd, 0x34, 0xa0, 0x36, 0x98, 0x52, 0x24, 0x16, 0x3e, 0x49, 0x3b, 0x85, 0xd0, 0x6e,
+	0xf4, 0xef, 0x97, 0x8e, 0x94, 0x24, 0xbe, 0x9b, 0x25, 0xa4, 0x33, 0xbd, 0x32, 0x12, 0xc5, 0x9c,
+	0x55, 0xaf, 0x9e, 0xb3, 0xed, 0xa7, 0xd0, 0xca, 0xf7, 0x41, 0x80, 0xfa, 0x6b, 0x46, 0xbe, 0xa0,
+	0xcd, 0x8a, 0xac, 0x0f, 0x68, 0x44, 0x82, 0x36, 0x0d, 0x59, 0xbf, 0x4f, 0x02, 0xf9, 0xdc, 0xec,
+	0xff, 0xb0, 0xa0, 0xe9, 0x69, 0x39, 0x3c, 0x55, 0xb1, 0xcb, 0x7e, 0xd4, 0x1e, 0x94, 0xed, 0x38,
+	0x8f, 0x65, 0xe7, 0xe1, 0xd2, 0xf7, 0x3a, 0xc5, 0x15, 0x3c, 0xce, 0xc4, 0x89, 0xe1, 0xaa, 0x03,
+	0x74, 0x1e, 0x95, 0x3a, 0x76, 0x69, 0x26, 0x2a, 0x78, 0x02, 0x70, 0x40, 0xec, 0xff, 0xe9, 0x9d,
+	0xa5, 0xc1, 0xd3, 0x1c, 0x8e, 0xa5, 0x07, 0x2a, 0x04, 0xbc, 0xd3, 0x5d, 0x0e, 0xc8, 0x45, 0xdf,
+	0x40, 0x4d, 0x85, 0x10, 0x4b, 0xc1, 0xc5, 0x7c, 0x76, 0x3a, 0x65, 0x88, 0xf4, 0xfb, 0xe3, 0x54,
+	0x9e, 0x19, 0xe7, 0x75, 0xf5, 0xe7, 0x63, 0xf7, 0xcf, 0x00, 0x2c, 0x63, 0xc2, 0xee, 0x8e, 0x08,
+	0x00, 0x00,
 }
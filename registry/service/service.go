@@ -10,6 +10,8 @@ import (
 	"github.com/stack-labs/stack/registry"
 	"github.com/stack-labs/stack/registry/mdns"
 	pb "github.com/stack-labs/stack/registry/service/proto"
+	"github.com/stack-labs/stack/transport"
+	httpt "github.com/stack-labs/stack/transport/http"
 )
 
 type serviceRegistry struct {
@@ -38,6 +40,15 @@ func (s *serviceRegistry) callOpts() []client.CallOption {
 	return opts
 }
 
+// domain returns the domain of a request, a blank domain is resolved by the
+// registry service
+func (s *serviceRegistry) domain(d string) string {
+	if len(d) > 0 {
+		return d
+	}
+	return s.opts.Domain
+}
+
 func (s *serviceRegistry) Init(opts ...registry.Option) error {
 	for _, o := range opts {
 		o(&s.opts)
@@ -57,7 +68,10 @@ func (s *serviceRegistry) Register(srv *registry.Service, opts ...registry.Regis
 
 	// encode srv into protobuf and pack Register TTL into it
 	pbSrv := ToProto(srv)
-	pbSrv.Options.Ttl = int64(options.TTL.Seconds())
+	pbSrv.Options = &pb.Options{
+		Ttl:    int64(options.TTL.Seconds()),
+		Domain: s.domain(options.Domain),
+	}
 
 	// register the service
 	_, err := s.client.Register(context.TODO(), pbSrv, s.callOpts()...)
@@ -68,18 +82,32 @@ func (s *serviceRegistry) Register(srv *registry.Service, opts ...registry.Regis
 	return nil
 }
 
-func (s *serviceRegistry) Deregister(srv *registry.Service) error {
+func (s *serviceRegistry) Deregister(srv *registry.Service, opts ...registry.DeregisterOption) error {
+	var options registry.DeregisterOptions
+	for _, o := range opts {
+		o(&options)
+	}
+
+	pbSrv := ToProto(srv)
+	pbSrv.Options = &pb.Options{Domain: s.domain(options.Domain)}
+
 	// deregister the service
-	_, err := s.client.Deregister(context.TODO(), ToProto(srv), s.callOpts()...)
+	_, err := s.client.Deregister(context.TODO(), pbSrv, s.callOpts()...)
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
-func (s *serviceRegistry) GetService(name string) ([]*registry.Service, error) {
+func (s *serviceRegistry) GetService(name string, opts ...registry.GetOption) ([]*registry.Service, error) {
+	var options registry.GetOptions
+	for _, o := range opts {
+		o(&options)
+	}
+
 	rsp, err := s.client.GetService(context.TODO(), &pb.GetRequest{
 		Service: name,
+		Options: &pb.Options{Domain: s.domain(options.Domain)},
 	}, s.callOpts()...)
 
 	if err != nil {
@@ -93,8 +121,15 @@ func (s *serviceRegistry) GetService(name string) ([]*registry.Service, error) {
 	return services, nil
 }
 
-func (s *serviceRegistry) ListServices() ([]*registry.Service, error) {
-	rsp, err := s.client.ListServices(context.TODO(), &pb.ListRequest{}, s.callOpts()...)
+func (s *serviceRegistry) ListServices(opts ...registry.ListOption) ([]*registry.Service, error) {
+	var options registry.ListOptions
+	for _, o := range opts {
+		o(&options)
+	}
+
+	rsp, err := s.client.ListServices(context.TODO(), &pb.ListRequest{
+		Options: &pb.Options{Domain: s.domain(options.Domain)},
+	}, s.callOpts()...)
 	if err != nil {
 		return nil, err
 	}
@@ -115,6 +150,7 @@ func (s *serviceRegistry) Watch(opts ...registry.WatchOption) (registry.Watcher,
 
 	stream, err := s.client.Watch(context.TODO(), &pb.WatchRequest{
 		Service: options.Service,
+		Options: &pb.Options{Domain: s.domain(options.Domain)},
 	}, s.callOpts()...)
 
 	if err != nil {
@@ -145,10 +181,20 @@ func NewRegistry(opts ...registry.Option) registry.Registry {
 	// use mdns as a fall back in case its used
 	mReg := mdns.NewRegistry()
 
-	// create new client with mdns
-	cli := mucp.NewClient(
+	cliOpts := []client.Option{
 		client.Registry(mReg),
-	)
+	}
+
+	// secure the calls to the registry service
+	if options.Secure || options.TLSConfig != nil {
+		cliOpts = append(cliOpts, client.Transport(httpt.NewTransport(
+			transport.Secure(options.Secure),
+			transport.TLSConfig(options.TLSConfig),
+		)))
+	}
+
+	// create new client with mdns
+	cli := mucp.NewClient(cliOpts...)
 
 	// service name
 	name := "stack.registry.service"
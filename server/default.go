@@ -15,4 +15,5 @@ var (
 	DefaultRegisterCheck    = func(context.Context) error { return nil }
 	DefaultRegisterInterval = time.Second * 30
 	DefaultRegisterTTL      = time.Minute
+	DefaultShutdownTimeout  = time.Second * 30
 )
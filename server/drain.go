@@ -0,0 +1,31 @@
+package server
+
+import (
+	"time"
+)
+
+// WaitTimeout calls wait and blocks until it returns or the timeout expires,
+// a negative timeout blocks until it returns. It reports whether wait returned
+func WaitTimeout(wait func(), timeout time.Duration) bool {
+	done := make(chan bool)
+
+	go func() {
+		wait()
+		close(done)
+	}()
+
+	if timeout < 0 {
+		<-done
+		return true
+	}
+
+	t := time.NewTimer(timeout)
+	defer t.Stop()
+
+	select {
+	case <-done:
+		return true
+	case <-t.C:
+		return false
+	}
+}
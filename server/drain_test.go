@@ -0,0 +1,23 @@
+package server
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWaitTimeout(t *testing.T) {
+	if !WaitTimeout(func() {}, time.Second) {
+		t.Fatal("Expected wait to return in time")
+	}
+
+	if !WaitTimeout(func() { time.Sleep(10 * time.Millisecond) }, -1) {
+		t.Fatal("Expected a negative timeout to wait until wait returns")
+	}
+
+	block := make(chan bool)
+	defer close(block)
+
+	if WaitTimeout(func() { <-block }, 10*time.Millisecond) {
+		t.Fatal("Expected the timeout to expire")
+	}
+}
@@ -16,6 +16,8 @@ import (
 
 	"github.com/stack-labs/stack/broker"
 	"github.com/stack-labs/stack/codec"
+	// register the compressors with grpc
+	"github.com/stack-labs/stack/codec/compress"
 	meta "github.com/stack-labs/stack/pkg/metadata"
 	"github.com/stack-labs/stack/registry"
 	"github.com/stack-labs/stack/server"
@@ -24,6 +26,7 @@ import (
 	mgrpc "github.com/stack-labs/stack/util/grpc"
 	"github.com/stack-labs/stack/util/log"
 	mnet "github.com/stack-labs/stack/util/net"
+	mls "github.com/stack-labs/stack/util/tls"
 
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
@@ -31,6 +34,7 @@ import (
 	"google.golang.org/grpc/encoding"
 	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/peer"
+	rpb "google.golang.org/grpc/reflection/grpc_reflection_v1alpha"
 	"google.golang.org/grpc/status"
 )
 
@@ -127,6 +131,18 @@ func (g *grpcServer) configure(opts ...server.Option) {
 	}
 
 	g.srv = grpc.NewServer(gopts...)
+
+	if g.getReflection() {
+		rpb.RegisterServerReflectionServer(g.srv, &reflectionServer{g})
+	}
+}
+
+func (g *grpcServer) getReflection() bool {
+	if g.opts.Context == nil {
+		return false
+	}
+	b, _ := g.opts.Context.Value(reflectionKey{}).(bool)
+	return b
 }
 
 func (g *grpcServer) getMaxMsgSize() int {
@@ -171,10 +187,8 @@ func (g *grpcServer) getGrpcOptions() []grpc.ServerOption {
 }
 
 func (g *grpcServer) handler(srv interface{}, stream grpc.ServerStream) error {
-	if g.wg != nil {
-		g.wg.Add(1)
-		defer g.wg.Done()
-	}
+	g.wg.Add(1)
+	defer g.wg.Done()
 
 	fullMethod, ok := grpc.MethodFromServerStream(stream)
 	if !ok {
@@ -217,6 +231,13 @@ func (g *grpcServer) handler(srv interface{}, stream grpc.ServerStream) error {
 	if p, ok := peer.FromContext(stream.Context()); ok {
 		md["Remote"] = p.Addr.String()
 		ctx = peer.NewContext(ctx, p)
+
+		// set the identity of mutual tls clients
+		if info, ok := p.AuthInfo.(credentials.TLSInfo); ok {
+			if mp := mls.NewPeer(&info.State); mp != nil {
+				ctx = mls.NewContext(ctx, mp)
+			}
+		}
 	}
 
 	// set the timeout if we have it
@@ -567,6 +588,7 @@ func (g *grpcServer) Register() error {
 	node.Metadata["server"] = g.String()
 	node.Metadata["transport"] = g.String()
 	node.Metadata["protocol"] = "grpc"
+	node.Metadata[compress.MetadataKey] = compress.Advertise()
 
 	g.RLock()
 	// Maps are ordered randomly, sort the keys for consistency
@@ -642,6 +664,14 @@ func (g *grpcServer) Register() error {
 			opts = append(opts, broker.DisableAutoAck())
 		}
 
+		if r := sb.Options().Retry; r.MaxAttempts > 1 {
+			opts = append(opts, broker.Retry(r.MaxAttempts, r.Backoff, r.MaxBackoff))
+		}
+
+		if dlq := sb.Options().DeadLetter; len(dlq) > 0 {
+			opts = append(opts, broker.DeadLetter(dlq))
+		}
+
 		sub, err := config.Broker.Subscribe(sb.Topic(), handler, opts...)
 		if err != nil {
 			return err
@@ -793,13 +823,21 @@ func (g *grpcServer) Start() error {
 			log.Log("Server deregister error: ", err)
 		}
 
-		// wait for waitgroup
-		if g.wg != nil {
-			g.wg.Wait()
+		// keep serving until the clients stop selecting this node
+		if config.DrainPeriod > 0 {
+			log.Logf("Server [grpc] draining for %v", config.DrainPeriod)
+			time.Sleep(config.DrainPeriod)
 		}
 
-		// stop the grpc server
-		g.srv.GracefulStop()
+		// stop accepting connections, send a go away to the clients and
+		// wait for the handlers and subscribers to finish
+		if !server.WaitTimeout(func() {
+			g.srv.GracefulStop()
+			g.wg.Wait()
+		}, config.ShutdownTimeout) {
+			log.Logf("Server [grpc] shutdown timeout %v exceeded with requests in flight", config.ShutdownTimeout)
+			g.srv.Stop()
+		}
 
 		// close transport
 		ch <- nil
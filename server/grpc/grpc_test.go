@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"google.golang.org/grpc"
+	rpb "google.golang.org/grpc/reflection/grpc_reflection_v1alpha"
 	"github.com/stack-labs/stack/registry/memory"
 	"github.com/stack-labs/stack/server"
 
@@ -64,3 +65,79 @@ func TestGRPCServer(t *testing.T) {
 		}
 	}
 }
+
+func TestGRPCReflection(t *testing.T) {
+	s := NewServer(
+		server.Name("foo"),
+		server.Registry(memory.NewRegistry()),
+		Reflection(true),
+	)
+
+	pb.RegisterTestHandler(s, &testServer{})
+
+	if err := s.Start(); err != nil {
+		t.Fatalf("failed to start: %v", err)
+	}
+	defer s.Stop()
+
+	cc, err := grpc.Dial(s.Options().Address, grpc.WithInsecure())
+	if err != nil {
+		t.Fatalf("failed to dial server: %v", err)
+	}
+	defer cc.Close()
+
+	stream, err := rpb.NewServerReflectionClient(cc).ServerReflectionInfo(context.Background())
+	if err != nil {
+		t.Fatalf("failed to open the reflection stream: %v", err)
+	}
+
+	if err := stream.Send(&rpb.ServerReflectionRequest{
+		MessageRequest: &rpb.ServerReflectionRequest_ListServices{},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	rsp, err := stream.Recv()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var services []string
+	for _, s := range rsp.GetListServicesResponse().GetService() {
+		services = append(services, s.Name)
+	}
+	if len(services) != 2 || services[0] != "Test" || services[1] != "grpc.reflection.v1alpha.ServerReflection" {
+		t.Fatalf("unexpected services: %v", services)
+	}
+
+	if err := stream.Send(&rpb.ServerReflectionRequest{
+		MessageRequest: &rpb.ServerReflectionRequest_FileContainingSymbol{
+			FileContainingSymbol: "Test",
+		},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	rsp, err = stream.Recv()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	files := rsp.GetFileDescriptorResponse().GetFileDescriptorProto()
+	if len(files) != 1 {
+		t.Fatalf("expected the file of the Test service, got %d files", len(files))
+	}
+
+	if err := stream.Send(&rpb.ServerReflectionRequest{
+		MessageRequest: &rpb.ServerReflectionRequest_FileContainingSymbol{
+			FileContainingSymbol: "Missing",
+		},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	rsp, err = stream.Recv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rsp.GetErrorResponse() == nil {
+		t.Fatal("expected an error for a missing symbol")
+	}
+}
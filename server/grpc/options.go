@@ -20,6 +20,7 @@ type codecsKey struct{}
 type tlsAuth struct{}
 type maxMsgSizeKey struct{}
 type grpcOptions struct{}
+type reflectionKey struct{}
 
 // gRPC Codec to be used to encode/decode requests for a given content type
 func Codec(contentType string, c encoding.Codec) server.Option {
@@ -56,6 +57,17 @@ func Options(opts ...grpc.ServerOption) server.Option {
 	}
 }
 
+// Reflection serves the grpc server reflection service, it is disabled by default
+// since it lets any client list the services and their message types
+func Reflection(b bool) server.Option {
+	return func(o *server.Options) {
+		if o.Context == nil {
+			o.Context = context.Background()
+		}
+		o.Context = context.WithValue(o.Context, reflectionKey{}, b)
+	}
+}
+
 //
 // MaxMsgSize set the maximum message in bytes the server can receive and
 // send.  Default maximum message size is 4 MB.
@@ -71,8 +83,9 @@ func MaxMsgSize(s int) server.Option {
 
 func newOptions(opt ...server.Option) server.Options {
 	opts := server.Options{
-		Codecs:   make(map[string]codec.NewCodec),
-		Metadata: map[string]string{},
+		Codecs:          make(map[string]codec.NewCodec),
+		Metadata:        map[string]string{},
+		ShutdownTimeout: server.DefaultShutdownTimeout,
 	}
 
 	for _, o := range opt {
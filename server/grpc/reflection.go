@@ -0,0 +1,217 @@
+package grpc
+
+import (
+	"io"
+	"reflect"
+	"sort"
+
+	"github.com/golang/protobuf/proto"
+	"google.golang.org/grpc/codes"
+	rpb "google.golang.org/grpc/reflection/grpc_reflection_v1alpha"
+	"google.golang.org/grpc/status"
+	protov2 "google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+var (
+	typeOfMessage = reflect.TypeOf((*proto.Message)(nil)).Elem()
+)
+
+// reflectionServer implements the grpc server reflection service for
+// the handlers registered with the server
+type reflectionServer struct {
+	g *grpcServer
+}
+
+// services returns the full names of the services served
+func (r *reflectionServer) services() []string {
+	var names []string
+
+	r.g.rpc.mu.Lock()
+	for _, s := range r.g.rpc.serviceMap {
+		if sd := serviceDescriptor(s); sd != nil {
+			names = append(names, string(sd.FullName()))
+		}
+	}
+	r.g.rpc.mu.Unlock()
+
+	for name := range r.g.srv.GetServiceInfo() {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+	return names
+}
+
+// serviceDescriptor finds the proto service of a handler in the files of its
+// message types, falling back to any registered service of the same name
+func serviceDescriptor(s *service) protoreflect.ServiceDescriptor {
+	match := func(sd protoreflect.ServiceDescriptor) bool {
+		if string(sd.Name()) != s.name {
+			return false
+		}
+		for name := range s.method {
+			if sd.Methods().ByName(protoreflect.Name(name)) == nil {
+				return false
+			}
+		}
+		return true
+	}
+
+	for _, m := range s.method {
+		if m.stream || !m.ArgType.Implements(typeOfMessage) {
+			continue
+		}
+
+		msg := proto.MessageV2(reflect.New(m.ArgType.Elem()).Interface().(proto.Message))
+		services := msg.ProtoReflect().Descriptor().ParentFile().Services()
+		for i := 0; i < services.Len(); i++ {
+			if match(services.Get(i)) {
+				return services.Get(i)
+			}
+		}
+	}
+
+	var sd protoreflect.ServiceDescriptor
+
+	protoregistry.GlobalFiles.RangeFiles(func(fd protoreflect.FileDescriptor) bool {
+		services := fd.Services()
+		for i := 0; i < services.Len(); i++ {
+			if match(services.Get(i)) {
+				sd = services.Get(i)
+				return false
+			}
+		}
+		return true
+	})
+
+	return sd
+}
+
+// encodeFiles encodes a file and the dependencies not sent yet on the stream
+func encodeFiles(fd protoreflect.FileDescriptor, sent map[string]bool) ([][]byte, error) {
+	var files [][]byte
+
+	var encode func(fd protoreflect.FileDescriptor, requested bool) error
+	encode = func(fd protoreflect.FileDescriptor, requested bool) error {
+		if sent[fd.Path()] && !requested {
+			return nil
+		}
+		sent[fd.Path()] = true
+
+		b, err := protov2.Marshal(protodesc.ToFileDescriptorProto(fd))
+		if err != nil {
+			return err
+		}
+		files = append(files, b)
+
+		imports := fd.Imports()
+		for i := 0; i < imports.Len(); i++ {
+			if err := encode(imports.Get(i).FileDescriptor, false); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if err := encode(fd, true); err != nil {
+		return nil, err
+	}
+
+	return files, nil
+}
+
+func (r *reflectionServer) ServerReflectionInfo(stream rpb.ServerReflection_ServerReflectionInfoServer) error {
+	sent := make(map[string]bool)
+
+	for {
+		in, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		out := &rpb.ServerReflectionResponse{
+			ValidHost:       in.Host,
+			OriginalRequest: in,
+		}
+
+		var fd protoreflect.FileDescriptor
+
+		switch req := in.MessageRequest.(type) {
+		case *rpb.ServerReflectionRequest_FileByFilename:
+			fd, err = protoregistry.GlobalFiles.FindFileByPath(req.FileByFilename)
+		case *rpb.ServerReflectionRequest_FileContainingSymbol:
+			var d protoreflect.Descriptor
+			d, err = protoregistry.GlobalFiles.FindDescriptorByName(protoreflect.FullName(req.FileContainingSymbol))
+			if err == nil {
+				fd = d.ParentFile()
+			}
+		case *rpb.ServerReflectionRequest_FileContainingExtension:
+			var xt protoreflect.ExtensionType
+			ext := req.FileContainingExtension
+			xt, err = protoregistry.GlobalTypes.FindExtensionByNumber(protoreflect.FullName(ext.ContainingType), protoreflect.FieldNumber(ext.ExtensionNumber))
+			if err == nil {
+				fd = xt.TypeDescriptor().ParentFile()
+			}
+		case *rpb.ServerReflectionRequest_AllExtensionNumbersOfType:
+			if _, err = protoregistry.GlobalFiles.FindDescriptorByName(protoreflect.FullName(req.AllExtensionNumbersOfType)); err != nil {
+				break
+			}
+			var numbers []int32
+			protoregistry.GlobalTypes.RangeExtensionsByMessage(protoreflect.FullName(req.AllExtensionNumbersOfType), func(xt protoreflect.ExtensionType) bool {
+				numbers = append(numbers, int32(xt.TypeDescriptor().Number()))
+				return true
+			})
+			sort.Slice(numbers, func(i, j int) bool {
+				return numbers[i] < numbers[j]
+			})
+			out.MessageResponse = &rpb.ServerReflectionResponse_AllExtensionNumbersResponse{
+				AllExtensionNumbersResponse: &rpb.ExtensionNumberResponse{
+					BaseTypeName:    req.AllExtensionNumbersOfType,
+					ExtensionNumber: numbers,
+				},
+			}
+		case *rpb.ServerReflectionRequest_ListServices:
+			var services []*rpb.ServiceResponse
+			for _, name := range r.services() {
+				services = append(services, &rpb.ServiceResponse{Name: name})
+			}
+			out.MessageResponse = &rpb.ServerReflectionResponse_ListServicesResponse{
+				ListServicesResponse: &rpb.ListServiceResponse{
+					Service: services,
+				},
+			}
+		default:
+			return status.Errorf(codes.InvalidArgument, "invalid MessageRequest: %v", in.MessageRequest)
+		}
+
+		if err == nil && fd != nil {
+			var files [][]byte
+			if files, err = encodeFiles(fd, sent); err == nil {
+				out.MessageResponse = &rpb.ServerReflectionResponse_FileDescriptorResponse{
+					FileDescriptorResponse: &rpb.FileDescriptorResponse{
+						FileDescriptorProto: files,
+					},
+				}
+			}
+		}
+
+		if err != nil {
+			out.MessageResponse = &rpb.ServerReflectionResponse_ErrorResponse{
+				ErrorResponse: &rpb.ErrorResponse{
+					ErrorCode:    int32(codes.NotFound),
+					ErrorMessage: err.Error(),
+				},
+			}
+		}
+
+		if err := stream.Send(out); err != nil {
+			return err
+		}
+	}
+}
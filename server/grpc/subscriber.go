@@ -242,13 +242,9 @@ func (g *grpcServer) createSubHandler(sb *subscriber, opts server.Options) broke
 				fn = opts.SubWrappers[i-1](fn)
 			}
 
-			if g.wg != nil {
-				g.wg.Add(1)
-			}
+			g.wg.Add(1)
 			go func() {
-				if g.wg != nil {
-					defer g.wg.Done()
-				}
+				defer g.wg.Done()
 				results <- fn(ctx, &rpcMessage{
 					topic:       sb.topic,
 					contentType: ct,
@@ -48,13 +48,15 @@ func convertCode(err error) codes.Code {
 	return codes.Unknown
 }
 
+// wait returns the wait group passed with server.Wait, the server
+// tracks its in-flight requests on a new one otherwise
 func wait(ctx context.Context) *sync.WaitGroup {
 	if ctx == nil {
-		return nil
+		return new(sync.WaitGroup)
 	}
 	wg, ok := ctx.Value("wait").(*sync.WaitGroup)
 	if !ok {
-		return nil
+		return new(sync.WaitGroup)
 	}
 	return wg
 }
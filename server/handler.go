@@ -1,6 +1,11 @@
 package server
 
-import "context"
+import (
+	"context"
+	"time"
+
+	"github.com/stack-labs/stack/broker"
+)
 
 type HandlerOption func(*HandlerOptions)
 
@@ -17,7 +22,11 @@ type SubscriberOptions struct {
 	AutoAck  bool
 	Queue    string
 	Internal bool
-	Context  context.Context
+	// Retry is the redelivery policy for messages the handler fails
+	Retry broker.RetryPolicy
+	// DeadLetter is the topic failed messages are published to
+	DeadLetter string
+	Context    context.Context
 }
 
 // EndpointMetadata is a Handler option that allows metadata to be added to
@@ -78,3 +87,23 @@ func SubscriberContext(ctx context.Context) SubscriberOption {
 		o.Context = ctx
 	}
 }
+
+// SubscriberRetry redelivers messages the handler returns an error for, up to
+// maxAttempts deliveries in total with an exponential backoff capped at maxBackoff
+func SubscriberRetry(maxAttempts int, backoff, maxBackoff time.Duration) SubscriberOption {
+	return func(o *SubscriberOptions) {
+		o.Retry = broker.RetryPolicy{
+			MaxAttempts: maxAttempts,
+			Backoff:     backoff,
+			MaxBackoff:  maxBackoff,
+		}
+	}
+}
+
+// SubscriberDeadLetter publishes messages that still fail after
+// all retries to the topic along with the error and attempts
+func SubscriberDeadLetter(topic string) SubscriberOption {
+	return func(o *SubscriberOptions) {
+		o.DeadLetter = topic
+	}
+}
@@ -0,0 +1,198 @@
+package http
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/stack-labs/stack/codec/compress"
+)
+
+// incompressible are the content types compressed already
+var incompressible = []string{
+	"image/",
+	"video/",
+	"audio/",
+	"font/woff",
+	"application/gzip",
+	"application/x-gzip",
+	"application/zip",
+	"application/zstd",
+	"application/x-7z-compressed",
+	"application/x-rar-compressed",
+}
+
+// compressWriter buffers the response until it reaches the threshold, the
+// rest of the response is then compressed. Smaller responses and those of a
+// content type compressed already are sent as is
+type compressWriter struct {
+	http.ResponseWriter
+
+	c         compress.Compressor
+	threshold int
+
+	buf    bytes.Buffer
+	code   int
+	w      io.WriteCloser
+	direct bool
+}
+
+func (c *compressWriter) WriteHeader(code int) {
+	c.code = code
+}
+
+func (c *compressWriter) Write(b []byte) (int, error) {
+	if c.w != nil {
+		return c.w.Write(b)
+	}
+	if c.direct {
+		return c.ResponseWriter.Write(b)
+	}
+
+	c.buf.Write(b)
+	if c.buf.Len() < c.threshold {
+		return len(b), nil
+	}
+
+	// the content type is sniffed before the body is compressed
+	if len(c.Header().Get("Content-Type")) == 0 {
+		c.Header().Set("Content-Type", http.DetectContentType(c.buf.Bytes()))
+	}
+
+	// the response is compressed unless the handler set its own encoding
+	if len(c.Header().Get(compress.ContentEncoding)) > 0 || !compressible(c.Header().Get("Content-Type")) {
+		return len(b), c.flush()
+	}
+
+	w, err := c.c.Compress(c.ResponseWriter)
+	if err != nil {
+		return 0, err
+	}
+
+	c.Header().Set(compress.ContentEncoding, c.c.Name())
+	c.Header().Del("Content-Length")
+	c.Header().Add("Vary", compress.AcceptEncoding)
+	c.writeHeader()
+
+	c.w = w
+	if _, err := c.w.Write(c.buf.Bytes()); err != nil {
+		return 0, err
+	}
+	c.buf.Reset()
+
+	return len(b), nil
+}
+
+// Flush flushes the active compressor, or sends the buffered response
+// uncompressed when the response is not compressed yet
+func (c *compressWriter) Flush() {
+	if c.w == nil {
+		c.flush()
+	} else if f, ok := c.w.(flusher); ok {
+		f.Flush()
+	}
+	if f, ok := c.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Hijack hands the connection over to the handler e.g for websockets, the
+// response is no longer written by the compressWriter
+func (c *compressWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h, ok := c.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.New("http: the response writer does not support hijacking")
+	}
+	c.direct = true
+	return h.Hijack()
+}
+
+// flusher is a compressing writer which flushes its pending data
+type flusher interface {
+	Flush() error
+}
+
+func (c *compressWriter) writeHeader() {
+	if c.code > 0 {
+		c.ResponseWriter.WriteHeader(c.code)
+	}
+}
+
+func (c *compressWriter) flush() error {
+	if c.direct {
+		return nil
+	}
+	c.direct = true
+	c.writeHeader()
+	_, err := c.ResponseWriter.Write(c.buf.Bytes())
+	c.buf.Reset()
+	return err
+}
+
+func (c *compressWriter) Close() error {
+	if c.w != nil {
+		return c.w.Close()
+	}
+	return c.flush()
+}
+
+// compressible reports whether a body of the content type is worth compressing
+func compressible(contentType string) bool {
+	contentType = strings.ToLower(contentType)
+	if strings.HasPrefix(contentType, "image/svg") {
+		return true
+	}
+	for _, t := range incompressible {
+		if strings.HasPrefix(contentType, t) {
+			return false
+		}
+	}
+	return true
+}
+
+// compressHandler decompresses the request bodies of a Content-Encoding up to
+// max bytes and, when enabled, compresses the responses with a compressor of
+// the Accept-Encoding
+func compressHandler(handler http.Handler, enable bool, threshold int, max int64) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if enc := r.Header.Get(compress.ContentEncoding); len(enc) > 0 && enc != "identity" {
+			c, ok := compress.Get(enc)
+			if !ok {
+				http.Error(w, (&compress.UnsupportedError{Encoding: enc}).Error(), http.StatusUnsupportedMediaType)
+				return
+			}
+
+			body, err := c.Decompress(r.Body)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+
+			r.Body = ioutil.NopCloser(compress.Limit(body, max))
+			r.ContentLength = -1
+			r.Header.Del(compress.ContentEncoding)
+			r.Header.Del("Content-Length")
+		}
+
+		threshold := compress.Threshold(threshold)
+		c, ok := compress.Negotiate(r.Header.Get(compress.AcceptEncoding))
+		if !enable || threshold < 0 || !ok {
+			handler.ServeHTTP(w, r)
+			return
+		}
+
+		cw := &compressWriter{
+			ResponseWriter: w,
+			c:              c,
+			threshold:      threshold,
+		}
+		defer cw.Close()
+
+		handler.ServeHTTP(cw, r)
+	})
+}
@@ -0,0 +1,221 @@
+package http
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stack-labs/stack/codec/compress"
+)
+
+func TestCompressHandler(t *testing.T) {
+	handler := compressHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.WriteHeader(http.StatusAccepted)
+		w.Write(b)
+	}), true, 0, 0)
+
+	large := strings.Repeat("a", 2048)
+	gz, err := compress.Encode(mustGet(t, compress.Gzip), []byte(large))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testData := []struct {
+		body     []byte
+		encoding string
+		accept   string
+		expect   string
+	}{
+		{[]byte("small"), "", compress.Snappy, ""},
+		{[]byte(large), "", "", ""},
+		{[]byte(large), "", "br, " + compress.Snappy, compress.Snappy},
+		{gz, compress.Gzip, compress.Gzip, compress.Gzip},
+	}
+
+	for _, d := range testData {
+		r := httptest.NewRequest("POST", "/", bytes.NewReader(d.body))
+		r.Header.Set(compress.ContentEncoding, d.encoding)
+		r.Header.Set(compress.AcceptEncoding, d.accept)
+
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+
+		if w.Code != http.StatusAccepted {
+			t.Fatalf("Expected status %d got %d", http.StatusAccepted, w.Code)
+		}
+
+		enc := w.Header().Get(compress.ContentEncoding)
+		if enc != d.expect {
+			t.Fatalf("Expected encoding %q got %q", d.expect, enc)
+		}
+
+		b, err := compress.Body(enc, w.Body.Bytes(), 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		body, err := compress.Body(d.encoding, d.body, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(b, body) {
+			t.Fatal("Unexpected response body")
+		}
+	}
+
+	// unsupported encoding
+	r := httptest.NewRequest("POST", "/", strings.NewReader("body"))
+	r.Header.Set(compress.ContentEncoding, "br")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+	if w.Code != http.StatusUnsupportedMediaType {
+		t.Fatalf("Expected status %d got %d", http.StatusUnsupportedMediaType, w.Code)
+	}
+}
+
+func TestCompressHandlerFlush(t *testing.T) {
+	w := httptest.NewRecorder()
+	large := strings.Repeat("a", 2048)
+
+	handler := compressHandler(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		rw.Write([]byte(large))
+		rw.(http.Flusher).Flush()
+		// the compressed data is sent before the handler returns
+		d, err := mustGet(t, compress.Gzip).Decompress(bytes.NewReader(w.Body.Bytes()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		b := make([]byte, len(large))
+		if _, err := io.ReadFull(d, b); err != nil || !w.Flushed {
+			t.Fatalf("Expected the compressed response to be flushed got %v", err)
+		}
+	}), true, 0, 0)
+
+	r := httptest.NewRequest("GET", "/", nil)
+	r.Header.Set(compress.AcceptEncoding, compress.Gzip)
+	handler.ServeHTTP(w, r)
+
+	b, err := compress.Body(w.Header().Get(compress.ContentEncoding), w.Body.Bytes(), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != large {
+		t.Fatal("Unexpected response body")
+	}
+}
+
+func TestCompressHandlerMaxSize(t *testing.T) {
+	handler := compressHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, err := ioutil.ReadAll(r.Body); err != nil {
+			http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+		}
+	}), false, 0, 1024)
+
+	gz, err := compress.Encode(mustGet(t, compress.Gzip), bytes.Repeat([]byte("a"), 4096))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := httptest.NewRequest("POST", "/", bytes.NewReader(gz))
+	r.Header.Set(compress.ContentEncoding, compress.Gzip)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+	if w.Code != http.StatusRequestEntityTooLarge {
+		t.Fatalf("Expected status %d got %d", http.StatusRequestEntityTooLarge, w.Code)
+	}
+}
+
+func TestCompressHandlerContentType(t *testing.T) {
+	large := strings.Repeat("a", 2048)
+	png := append([]byte("\x89PNG\x0D\x0A\x1A\x0A"), large...)
+
+	testData := []struct {
+		enable      bool
+		contentType string
+		body        []byte
+		expect      string
+		sniffed     string
+	}{
+		// compression is opt in
+		{false, "", []byte(large), "", "text/plain; charset=utf-8"},
+		{true, "", []byte(large), compress.Gzip, "text/plain; charset=utf-8"},
+		{true, "image/svg+xml", []byte(large), compress.Gzip, "image/svg+xml"},
+		{true, "application/zip", []byte(large), "", "application/zip"},
+		{true, "", png, "", "image/png"},
+	}
+
+	for _, d := range testData {
+		handler := compressHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if len(d.contentType) > 0 {
+				w.Header().Set("Content-Type", d.contentType)
+			}
+			w.Write(d.body)
+		}), d.enable, 0, 0)
+
+		r := httptest.NewRequest("GET", "/", nil)
+		r.Header.Set(compress.AcceptEncoding, compress.Gzip)
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+
+		if enc := w.Header().Get(compress.ContentEncoding); enc != d.expect {
+			t.Fatalf("Expected encoding %q got %q", d.expect, enc)
+		}
+		if ct := w.Header().Get("Content-Type"); ct != d.sniffed {
+			t.Fatalf("Expected content type %q got %q", d.sniffed, ct)
+		}
+	}
+}
+
+func TestCompressHandlerHijack(t *testing.T) {
+	handler := compressHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		h, ok := w.(http.Hijacker)
+		if !ok {
+			t.Error("Expected the response writer to be a hijacker")
+			return
+		}
+		conn, rw, err := h.Hijack()
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		defer conn.Close()
+		rw.WriteString("HTTP/1.1 101 Switching Protocols\r\nConnection: Upgrade\r\nUpgrade: test\r\n\r\n")
+		rw.Flush()
+	}), true, 0, 0)
+
+	ts := httptest.NewServer(handler)
+	defer ts.Close()
+
+	r, err := http.NewRequest("GET", ts.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.Header.Set(compress.AcceptEncoding, compress.Gzip)
+	r.Header.Set("Connection", "Upgrade")
+	r.Header.Set("Upgrade", "test")
+
+	rsp, err := http.DefaultClient.Do(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rsp.Body.Close()
+	if rsp.StatusCode != http.StatusSwitchingProtocols {
+		t.Fatalf("Expected status %d got %d", http.StatusSwitchingProtocols, rsp.StatusCode)
+	}
+}
+
+func mustGet(t *testing.T, name string) compress.Compressor {
+	c, ok := compress.Get(name)
+	if !ok {
+		t.Fatalf("Expected the %s compressor", name)
+	}
+	return c
+}
@@ -6,6 +6,7 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/stack-labs/stack/codec/compress"
 	"github.com/stack-labs/stack/registry"
 	"github.com/stack-labs/stack/server"
 	"github.com/stack-labs/stack/util/addr"
@@ -44,6 +45,7 @@ func serviceDef(opts server.Options) *registry.Service {
 	node.Metadata["broker"] = opts.Broker.String()
 	node.Metadata["registry"] = opts.Registry.String()
 	node.Metadata["protocol"] = "http"
+	node.Metadata[compress.MetadataKey] = compress.Advertise()
 
 	return &registry.Service{
 		Name:    opts.Name,
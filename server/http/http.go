@@ -2,6 +2,7 @@
 package http
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"net"
@@ -38,6 +39,8 @@ type httpServer struct {
 	subscribers  map[*httpSubscriber][]broker.Subscriber
 	// used for first registration
 	registered bool
+	// in-flight handlers and subscribers
+	wg sync.WaitGroup
 }
 
 func (h *httpServer) newCodec(contentType string) (codec.NewCodec, error) {
@@ -186,6 +189,14 @@ func (h *httpServer) Register() error {
 			subOpts = append(subOpts, broker.DisableAutoAck())
 		}
 
+		if r := sb.Options().Retry; r.MaxAttempts > 1 {
+			subOpts = append(subOpts, broker.Retry(r.MaxAttempts, r.Backoff, r.MaxBackoff))
+		}
+
+		if dlq := sb.Options().DeadLetter; len(dlq) > 0 {
+			subOpts = append(subOpts, broker.DeadLetter(dlq))
+		}
+
 		sub, err := opts.Broker.Subscribe(sb.Topic(), handler, subOpts...)
 		if err != nil {
 			return err
@@ -246,6 +257,8 @@ func (h *httpServer) Start() error {
 	if !ok {
 		return errors.New("Server required http.Handler ")
 	}
+	handler = wrapHandler(opts.Name, handler, getWrappers(opts))
+	handler = compressHandler(handler, opts.Compress, opts.CompressThreshold, opts.MaxDecompressSize)
 
 	if err = opts.Broker.Connect(); err != nil {
 		return err
@@ -256,7 +269,15 @@ func (h *httpServer) Start() error {
 		return err
 	}
 
-	go http.Serve(ln, handler)
+	srv := &http.Server{
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			h.wg.Add(1)
+			defer h.wg.Done()
+			handler.ServeHTTP(w, r)
+		}),
+	}
+
+	go srv.Serve(ln)
 
 	go func() {
 		t := new(time.Ticker)
@@ -284,10 +305,30 @@ func (h *httpServer) Start() error {
 			}
 		}
 
-		ch <- ln.Close()
-
 		// deregister
-		h.Deregister()
+		if err := h.Deregister(); err != nil {
+			log.Error("Server deregister error: ", err)
+		}
+
+		// keep serving until the clients stop selecting this node
+		if opts.DrainPeriod > 0 {
+			log.Infof("Draining for %v", opts.DrainPeriod)
+			time.Sleep(opts.DrainPeriod)
+		}
+
+		// stop accepting connections, close the idle ones once their
+		// responses are written and wait for the handlers and subscribers
+		if !server.WaitTimeout(func() {
+			if err := srv.Shutdown(context.Background()); err != nil {
+				log.Error("Server shutdown error: ", err)
+			}
+			h.wg.Wait()
+		}, opts.ShutdownTimeout) {
+			log.Warnf("Shutdown timeout %v exceeded with requests in flight", opts.ShutdownTimeout)
+			srv.Close()
+		}
+
+		ch <- nil
 
 		opts.Broker.Disconnect()
 	}()
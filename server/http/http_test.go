@@ -1,14 +1,18 @@
 package http
 
 import (
+	"context"
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
 
 	hb "github.com/stack-labs/stack/broker/http"
 	"github.com/stack-labs/stack/registry/memory"
 	"github.com/stack-labs/stack/server"
+	"github.com/stack-labs/stack/util/errors"
 )
 
 func TestHTTPServer(t *testing.T) {
@@ -71,3 +75,158 @@ func TestHTTPServer(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+func TestHTTPServerDrain(t *testing.T) {
+	reg := memory.NewRegistry()
+
+	srv := NewServer(
+		server.Broker(hb.NewBroker()),
+		server.Registry(reg),
+		server.DrainPeriod(200*time.Millisecond),
+	)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`hello world`))
+	})
+	mux.HandleFunc("/slow", func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(400 * time.Millisecond)
+		w.Write([]byte(`slow`))
+	})
+
+	if err := srv.Handle(srv.NewHandler(mux)); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := srv.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	address := srv.Options().Address
+
+	get := func(path string) (string, error) {
+		rsp, err := http.Get(fmt.Sprintf("http://%s%s", address, path))
+		if err != nil {
+			return "", err
+		}
+		defer rsp.Body.Close()
+		b, err := ioutil.ReadAll(rsp.Body)
+		return string(b), err
+	}
+
+	slow := make(chan string, 1)
+	go func() {
+		b, err := get("/slow")
+		if err != nil {
+			t.Error(err)
+		}
+		slow <- b
+	}()
+
+	// let the slow request start
+	time.Sleep(50 * time.Millisecond)
+
+	stopped := make(chan error, 1)
+	go func() {
+		stopped <- srv.Stop()
+	}()
+
+	// the node is deregistered but still serving during the drain period
+	time.Sleep(100 * time.Millisecond)
+
+	if services, err := reg.GetService(server.DefaultName); err == nil && len(services) > 0 {
+		t.Fatalf("Expected the service to be deregistered, got %+v", services)
+	}
+
+	if b, err := get("/"); err != nil {
+		t.Fatalf("Expected requests to be served while draining, got %v", err)
+	} else if b != "hello world" {
+		t.Fatalf("Expected response %s, got %s", "hello world", b)
+	}
+
+	if err := <-stopped; err != nil {
+		t.Fatal(err)
+	}
+
+	// stop waits for the in-flight request
+	select {
+	case b := <-slow:
+		if b != "slow" {
+			t.Fatalf("Expected response %s, got %s", "slow", b)
+		}
+	default:
+		t.Fatal("Expected stop to wait for the in-flight request")
+	}
+
+	if _, err := get("/"); err == nil {
+		t.Fatal("Expected connections to be refused after stop")
+	}
+}
+
+func TestHTTPServerShutdownTimeout(t *testing.T) {
+	srv := NewServer(
+		server.Broker(hb.NewBroker()),
+		server.Registry(memory.NewRegistry()),
+		server.ShutdownTimeout(100*time.Millisecond),
+	)
+
+	block := make(chan bool)
+	defer close(block)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		<-block
+	})
+
+	if err := srv.Handle(srv.NewHandler(mux)); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := srv.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	go http.Get(fmt.Sprintf("http://%s/", srv.Options().Address))
+
+	// let the request start
+	time.Sleep(50 * time.Millisecond)
+
+	start := time.Now()
+	if err := srv.Stop(); err != nil {
+		t.Fatal(err)
+	}
+
+	if d := time.Since(start); d > time.Second {
+		t.Fatalf("Expected stop to give up after the shutdown timeout, took %v", d)
+	}
+}
+
+func TestHTTPServerWrapHandler(t *testing.T) {
+	var rpc, wrapped bool
+	opts := newOptions(
+		server.WrapHandler(func(fn server.HandlerFunc) server.HandlerFunc {
+			rpc = true
+			return fn
+		}),
+		WrapHandler(func(fn server.HandlerFunc) server.HandlerFunc {
+			return func(ctx context.Context, req server.Request, rsp interface{}) error {
+				wrapped = true
+				return errors.New("test.limit", "too many requests", http.StatusTooManyRequests)
+			}
+		}),
+	)
+
+	h := wrapHandler("test", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Fatal("Expected the wrapper to reject the request")
+	}), getWrappers(opts))
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+
+	if rpc {
+		t.Fatal("Expected the rpc handler wrapper not to be applied")
+	}
+	if !wrapped || w.Code != http.StatusTooManyRequests {
+		t.Fatalf("Expected the http wrapper to reject the request, got %d", w.Code)
+	}
+}
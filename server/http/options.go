@@ -9,9 +9,10 @@ import (
 
 func newOptions(opt ...server.Option) server.Options {
 	opts := server.Options{
-		Codecs:   make(map[string]codec.NewCodec),
-		Metadata: map[string]string{},
-		Context:  context.Background(),
+		Codecs:          make(map[string]codec.NewCodec),
+		Metadata:        map[string]string{},
+		ShutdownTimeout: server.DefaultShutdownTimeout,
+		Context:         context.Background(),
 	}
 
 	for _, o := range opt {
@@ -36,3 +37,25 @@ func newOptions(opt ...server.Option) server.Options {
 
 	return opts
 }
+
+type wrappersKey struct{}
+
+// WrapHandler adds wrappers the http handler is served through. The server handler
+// wrappers are written for rpc handlers so they are not applied to the http handler.
+func WrapHandler(w ...server.HandlerWrapper) server.Option {
+	return func(o *server.Options) {
+		if o.Context == nil {
+			o.Context = context.Background()
+		}
+		wrappers := append(getWrappers(*o), w...)
+		o.Context = context.WithValue(o.Context, wrappersKey{}, wrappers)
+	}
+}
+
+func getWrappers(o server.Options) []server.HandlerWrapper {
+	if o.Context == nil {
+		return nil
+	}
+	w, _ := o.Context.Value(wrappersKey{}).([]server.HandlerWrapper)
+	return w
+}
@@ -0,0 +1,107 @@
+package http
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/stack-labs/stack/codec"
+	"github.com/stack-labs/stack/server"
+	"github.com/stack-labs/stack/util/errors"
+)
+
+// httpRequest exposes a http request to the handler wrappers
+type httpRequest struct {
+	service string
+	req     *http.Request
+	header  map[string]string
+}
+
+func newRequest(service string, r *http.Request) *httpRequest {
+	header := make(map[string]string, len(r.Header))
+	for k, v := range r.Header {
+		if len(v) > 0 {
+			header[k] = v[0]
+		}
+	}
+
+	return &httpRequest{
+		service: service,
+		req:     r,
+		header:  header,
+	}
+}
+
+func (r *httpRequest) Service() string {
+	return r.service
+}
+
+func (r *httpRequest) Method() string {
+	return r.req.URL.Path
+}
+
+func (r *httpRequest) Endpoint() string {
+	return r.req.URL.Path
+}
+
+func (r *httpRequest) ContentType() string {
+	return r.req.Header.Get("Content-Type")
+}
+
+func (r *httpRequest) Header() map[string]string {
+	return r.header
+}
+
+func (r *httpRequest) Body() interface{} {
+	return r.req
+}
+
+func (r *httpRequest) Read() ([]byte, error) {
+	return ioutil.ReadAll(r.req.Body)
+}
+
+func (r *httpRequest) Codec() codec.Reader {
+	return nil
+}
+
+func (r *httpRequest) Stream() bool {
+	return false
+}
+
+// wrapHandler runs the http handler through the wrappers set with WrapHandler.
+// An error returned by a wrapper is written as the response.
+func wrapHandler(service string, handler http.Handler, wrappers []server.HandlerWrapper) http.Handler {
+	if len(wrappers) == 0 {
+		return handler
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fn := func(ctx context.Context, req server.Request, rsp interface{}) error {
+			handler.ServeHTTP(w, r.WithContext(ctx))
+			return nil
+		}
+
+		for i := len(wrappers); i > 0; i-- {
+			fn = wrappers[i-1](fn)
+		}
+
+		err := fn(r.Context(), newRequest(service, r), w)
+		if err == nil {
+			return
+		}
+
+		e, ok := err.(*errors.Error)
+		if !ok {
+			e = errors.Parse(err.Error())
+		}
+
+		code := int(e.Code)
+		if code == 0 {
+			code = http.StatusInternalServerError
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(code)
+		w.Write([]byte(e.Error()))
+	})
+}
@@ -187,6 +187,10 @@ func validateSubscriber(sub server.Subscriber) error {
 
 func (h *httpServer) createSubHandler(sb *httpSubscriber, opts server.Options) broker.Handler {
 	return func(p broker.Event) error {
+		// wait for the subscribers on stop
+		h.wg.Add(1)
+		defer h.wg.Done()
+
 		msg := p.Message()
 		ct := msg.Header["Content-Type"]
 		cf, err := h.newCodec(ct)
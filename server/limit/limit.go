@@ -0,0 +1,97 @@
+// Package limit provides rate and concurrency limiting server wrappers
+package limit
+
+import (
+	"context"
+	"time"
+
+	"github.com/stack-labs/stack/server"
+	"github.com/stack-labs/stack/util/errors"
+	"github.com/stack-labs/stack/util/ratelimit"
+)
+
+var (
+	// DefaultIdle is how long the limiter of an endpoint or topic is kept unused
+	DefaultIdle = time.Minute
+)
+
+type Options struct {
+	// Limit is the default limit of every endpoint
+	Limit ratelimit.Limit
+	// Endpoints overrides the limit of an endpoint e.g Greeter.Hello, or of a topic
+	Endpoints map[string]ratelimit.Limit
+}
+
+type Option func(o *Options)
+
+// Rate sets the default requests per second and burst of an endpoint
+func Rate(rate float64, burst int) Option {
+	return func(o *Options) {
+		o.Limit.Rate = rate
+		o.Limit.Burst = burst
+	}
+}
+
+// MaxInFlight sets the default number of concurrent requests of an endpoint
+func MaxInFlight(n int) Option {
+	return func(o *Options) {
+		o.Limit.MaxInFlight = n
+	}
+}
+
+// Endpoint sets the limit of a single endpoint or topic
+func Endpoint(name string, l ratelimit.Limit) Option {
+	return func(o *Options) {
+		if o.Endpoints == nil {
+			o.Endpoints = make(map[string]ratelimit.Limit)
+		}
+		o.Endpoints[name] = l
+	}
+}
+
+func newLimiters(opts ...Option) *ratelimit.Limiters {
+	var options Options
+	for _, o := range opts {
+		o(&options)
+	}
+	// the limiters of the endpoints requested are removed once idle
+	limiters := ratelimit.NewLimiters(options.Limit, options.Endpoints)
+	limiters.Idle = DefaultIdle
+	return limiters
+}
+
+// NewHandlerWrapper limits the requests per endpoint. Requests over
+// the limit are rejected with a 429 error.
+func NewHandlerWrapper(opts ...Option) server.HandlerWrapper {
+	limiters := newLimiters(opts...)
+
+	return func(h server.HandlerFunc) server.HandlerFunc {
+		return func(ctx context.Context, req server.Request, rsp interface{}) error {
+			release, ok := limiters.Acquire(req.Endpoint())
+			if !ok {
+				return errors.TooManyRequests(req.Service(), "too many requests to %s", req.Endpoint())
+			}
+			defer release()
+
+			return h(ctx, req, rsp)
+		}
+	}
+}
+
+// NewSubscriberWrapper limits the messages processed per topic. Messages
+// over the limit are rejected with a 429 error.
+func NewSubscriberWrapper(opts ...Option) server.SubscriberWrapper {
+	limiters := newLimiters(opts...)
+
+	return func(fn server.SubscriberFunc) server.SubscriberFunc {
+		return func(ctx context.Context, msg server.Message) error {
+			release, ok := limiters.Acquire(msg.Topic())
+			if !ok {
+				return errors.TooManyRequests("stack.server.limit", "too many messages on %s", msg.Topic())
+			}
+			defer release()
+
+			return fn(ctx, msg)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package limit
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stack-labs/stack/server"
+	"github.com/stack-labs/stack/util/errors"
+	"github.com/stack-labs/stack/util/ratelimit"
+)
+
+type testRequest struct {
+	server.Request
+	endpoint string
+	header   map[string]string
+}
+
+func (r testRequest) Service() string  { return "foo" }
+func (r testRequest) Endpoint() string { return r.endpoint }
+
+func (r testRequest) Header() map[string]string { return r.header }
+
+func TestHandlerWrapper(t *testing.T) {
+	block := make(chan struct{})
+	started := make(chan struct{})
+
+	h := NewHandlerWrapper(
+		MaxInFlight(1),
+		Endpoint("Foo.Unlimited", ratelimit.Limit{}),
+	)(func(ctx context.Context, req server.Request, rsp interface{}) error {
+		if req.Header()["Block"] == "true" {
+			close(started)
+			<-block
+		}
+		return nil
+	})
+
+	done := make(chan error)
+	go func() {
+		done <- h(context.TODO(), testRequest{
+			endpoint: "Foo.Bar",
+			header:   map[string]string{"Block": "true"},
+		}, nil)
+	}()
+	<-started
+
+	err := h(context.TODO(), testRequest{endpoint: "Foo.Bar"}, nil)
+	if e, ok := err.(*errors.Error); !ok || e.Code != 429 {
+		t.Fatalf("Expected 429 error, got %v", err)
+	}
+
+	if err := h(context.TODO(), testRequest{endpoint: "Foo.Unlimited"}, nil); err != nil {
+		t.Fatalf("Expected the endpoint override to allow the request, got %v", err)
+	}
+
+	close(block)
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+
+	if err := h(context.TODO(), testRequest{endpoint: "Foo.Bar"}, nil); err != nil {
+		t.Fatalf("Expected the request to be allowed once released, got %v", err)
+	}
+}
@@ -14,6 +14,7 @@ func newOptions(opt ...server.Option) server.Options {
 		Metadata:         map[string]string{},
 		RegisterInterval: server.DefaultRegisterInterval,
 		RegisterTTL:      server.DefaultRegisterTTL,
+		ShutdownTimeout:  server.DefaultShutdownTimeout,
 	}
 
 	for _, o := range opt {
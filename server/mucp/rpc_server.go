@@ -15,6 +15,7 @@ import (
 	"github.com/stack-labs/stack/broker"
 	"github.com/stack-labs/stack/codec"
 	raw "github.com/stack-labs/stack/codec/bytes"
+	"github.com/stack-labs/stack/codec/compress"
 	"github.com/stack-labs/stack/pkg/metadata"
 	"github.com/stack-labs/stack/registry"
 	"github.com/stack-labs/stack/server"
@@ -24,6 +25,7 @@ import (
 	"github.com/stack-labs/stack/util/log"
 	mnet "github.com/stack-labs/stack/util/net"
 	"github.com/stack-labs/stack/util/socket"
+	mls "github.com/stack-labs/stack/util/tls"
 )
 
 type rpcServer struct {
@@ -42,6 +44,9 @@ type rpcServer struct {
 	subscriber broker.Subscriber
 	// graceful exit
 	wg *sync.WaitGroup
+	// streams is cancelled to tell in-progress streams to go away
+	streams context.Context
+	goAway  context.CancelFunc
 }
 
 func NewServer(opts ...server.Option) server.Server {
@@ -63,6 +68,10 @@ func NewServer(opts ...server.Option) server.Server {
 // HandleEvent handles inbound messages to the service directly
 // TODO: handle requests from an event. We won't send a response.
 func (s *rpcServer) HandleEvent(e broker.Event) error {
+	// wait for the subscribers on stop
+	s.wg.Add(1)
+	defer s.wg.Done()
+
 	// formatting horrible cruft
 	msg := e.Message()
 
@@ -176,6 +185,25 @@ func (s *rpcServer) ServeConn(sock transport.Socket) {
 			return
 		}
 
+		// decompress the body
+		if enc := msg.Header[compress.ContentEncoding]; len(enc) > 0 {
+			b, err := compress.Body(enc, msg.Body, s.opts.MaxDecompressSize)
+			if err != nil {
+				if err := sock.Send(&transport.Message{
+					Header: map[string]string{
+						"Content-Type": "text/plain",
+					},
+					Body: []byte(err.Error()),
+				}); err != nil {
+					gerr = err
+					return
+				}
+				continue
+			}
+			msg.Body = b
+			delete(msg.Header, compress.ContentEncoding)
+		}
+
 		// check the message header for
 		// Stack-Service is a request
 		// Stack-Topic is a message
@@ -273,8 +301,21 @@ func (s *rpcServer) ServeConn(sock transport.Socket) {
 		hdr["Local"] = sock.Local()
 		hdr["Remote"] = sock.Remote()
 
+		// streams are cancelled when the server stops
+		base := context.Background()
+		if stream {
+			base = s.streamContext()
+		}
+
 		// create new context with the metadata
-		ctx := metadata.NewContext(context.Background(), hdr)
+		ctx := metadata.NewContext(base, hdr)
+
+		// set the identity of mutual tls clients
+		if ss, ok := sock.(transport.SecureSocket); ok {
+			if p := mls.NewPeer(ss.ConnectionState()); p != nil {
+				ctx = mls.NewContext(ctx, p)
+			}
+		}
 
 		// set the timeout from the header if we have it
 		if len(to) > 0 {
@@ -360,6 +401,13 @@ func (s *rpcServer) ServeConn(sock transport.Socket) {
 			r = rpcRouter{h: handler}
 		}
 
+		// compress the responses with an encoding the client accepts
+		var accept string
+		if s.opts.Compress {
+			accept = msg.Header[compress.AcceptEncoding]
+		}
+		threshold := s.opts.CompressThreshold
+
 		// process the outbound messages from the socket
 		go func(id string, psock *socket.Socket) {
 			// wait for processing to exit
@@ -385,6 +433,20 @@ func (s *rpcServer) ServeConn(sock transport.Socket) {
 					return
 				}
 
+				// a response which fails to compress is sent as is
+				if len(accept) > 0 && protocol != "grpc" {
+					b, enc, err := compress.Message(accept, threshold, m.Body)
+					if err != nil {
+						log.Debugf("rpc: unable to compress response: %v", err)
+					} else if len(enc) > 0 {
+						if m.Header == nil {
+							m.Header = make(map[string]string)
+						}
+						m.Body = b
+						m.Header[compress.ContentEncoding] = enc
+					}
+				}
+
 				// send the message back over the socket
 				if err := sock.Send(m); err != nil {
 					return
@@ -549,6 +611,7 @@ func (s *rpcServer) Register() error {
 	node.Metadata["server"] = s.String()
 	node.Metadata["registry"] = config.Registry.String()
 	node.Metadata["protocol"] = "mucp"
+	node.Metadata[compress.MetadataKey] = compress.Advertise()
 
 	s.RLock()
 
@@ -644,6 +707,14 @@ func (s *rpcServer) Register() error {
 			opts = append(opts, broker.DisableAutoAck())
 		}
 
+		if r := sb.Options().Retry; r.MaxAttempts > 1 {
+			opts = append(opts, broker.Retry(r.MaxAttempts, r.Backoff, r.MaxBackoff))
+		}
+
+		if dlq := sb.Options().DeadLetter; len(dlq) > 0 {
+			opts = append(opts, broker.DeadLetter(dlq))
+		}
+
 		sub, err := config.Broker.Subscribe(sb.Topic(), s.HandleEvent, opts...)
 		if err != nil {
 			return err
@@ -779,6 +850,10 @@ func (s *rpcServer) Start() error {
 
 	exit := make(chan bool)
 
+	s.Lock()
+	s.streams, s.goAway = context.WithCancel(context.Background())
+	s.Unlock()
+
 	go func() {
 		for {
 			// listen for connections
@@ -849,17 +924,26 @@ func (s *rpcServer) Start() error {
 			log.Logf("Server %s-%s deregister error: %s", config.Name, config.Id, err)
 		}
 
+		// keep serving until the clients stop selecting this node
+		if config.DrainPeriod > 0 {
+			log.Logf("Server %s-%s draining for %v", config.Name, config.Id, config.DrainPeriod)
+			time.Sleep(config.DrainPeriod)
+		}
+
+		// close transport listener
+		err := ts.Close()
+
+		// tell the in-progress streams to go away
 		s.Lock()
-		swg := s.wg
+		s.goAway()
 		s.Unlock()
 
-		// wait for requests to finish
-		if swg != nil {
-			swg.Wait()
+		// wait for requests and subscribers to finish
+		if !server.WaitTimeout(s.wg.Wait, config.ShutdownTimeout) {
+			log.Logf("Server %s-%s shutdown timeout %v exceeded with requests in flight", config.Name, config.Id, config.ShutdownTimeout)
 		}
 
-		// close transport listener
-		ch <- ts.Close()
+		ch <- err
 
 		// disconnect the broker
 		config.Broker.Disconnect()
@@ -878,6 +962,16 @@ func (s *rpcServer) Start() error {
 	return nil
 }
 
+// streamContext returns the context streams are served with
+func (s *rpcServer) streamContext() context.Context {
+	s.RLock()
+	defer s.RUnlock()
+	if s.streams == nil {
+		return context.Background()
+	}
+	return s.streams
+}
+
 func (s *rpcServer) Stop() error {
 	s.RLock()
 	if !s.started {
@@ -0,0 +1,177 @@
+package mucp
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stack-labs/stack/client"
+	cmucp "github.com/stack-labs/stack/client/mucp"
+	"github.com/stack-labs/stack/client/selector"
+	sr "github.com/stack-labs/stack/client/selector/registry"
+	"github.com/stack-labs/stack/codec/compress"
+	"github.com/stack-labs/stack/registry/memory"
+	"github.com/stack-labs/stack/server"
+	"github.com/stack-labs/stack/transport"
+	httpt "github.com/stack-labs/stack/transport/http"
+)
+
+type EchoRequest struct {
+	Body string
+}
+
+type EchoResponse struct {
+	Body string
+}
+
+type Echo struct{}
+
+func (t *Echo) Echo(ctx context.Context, req *EchoRequest, rsp *EchoResponse) error {
+	rsp.Body = req.Body
+	return nil
+}
+
+// recordTransport records the content encoding of the messages sent and
+// received by the server
+type recordTransport struct {
+	transport.Transport
+
+	sync.Mutex
+	recv []string
+	sent []string
+}
+
+type recordListener struct {
+	transport.Listener
+	t *recordTransport
+}
+
+type recordSocket struct {
+	transport.Socket
+	t *recordTransport
+}
+
+func (r *recordTransport) Listen(addr string, opts ...transport.ListenOption) (transport.Listener, error) {
+	l, err := r.Transport.Listen(addr, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &recordListener{Listener: l, t: r}, nil
+}
+
+func (r *recordListener) Accept(fn func(transport.Socket)) error {
+	return r.Listener.Accept(func(sock transport.Socket) {
+		fn(&recordSocket{Socket: sock, t: r.t})
+	})
+}
+
+func (r *recordSocket) Recv(m *transport.Message) error {
+	if err := r.Socket.Recv(m); err != nil {
+		return err
+	}
+	r.t.Lock()
+	r.t.recv = append(r.t.recv, m.Header[compress.ContentEncoding])
+	r.t.Unlock()
+	return nil
+}
+
+func (r *recordSocket) Send(m *transport.Message) error {
+	r.t.Lock()
+	r.t.sent = append(r.t.sent, m.Header[compress.ContentEncoding])
+	r.t.Unlock()
+	return r.Socket.Send(m)
+}
+
+func TestCompression(t *testing.T) {
+	reg := memory.NewRegistry()
+	tr := &recordTransport{Transport: httpt.NewTransport()}
+
+	srv := NewServer(
+		server.Name("test.compress"),
+		server.Address("127.0.0.1:0"),
+		server.Registry(reg),
+		server.Transport(tr),
+		server.Compress(true),
+	)
+	if err := srv.Handle(srv.NewHandler(&Echo{})); err != nil {
+		t.Fatal(err)
+	}
+	if err := srv.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer srv.Stop()
+
+	testData := []struct {
+		compressor string
+		body       string
+		encoding   string
+	}{
+		{"", strings.Repeat("a", 2048), ""},
+		{compress.Gzip, "small", ""},
+		{compress.Gzip, strings.Repeat("a", 2048), compress.Gzip},
+		{compress.Snappy, strings.Repeat("a", 2048), compress.Snappy},
+	}
+
+	for _, d := range testData {
+		tr.Lock()
+		tr.recv, tr.sent = nil, nil
+		tr.Unlock()
+
+		c := cmucp.NewClient(
+			client.ContentType("application/json"),
+			client.Compressor(d.compressor),
+			client.Selector(sr.NewSelector(selector.Registry(reg))),
+			client.Transport(httpt.NewTransport()),
+		)
+
+		req := c.NewRequest("test.compress", "Echo.Echo", &EchoRequest{Body: d.body})
+		rsp := new(EchoResponse)
+		if err := c.Call(context.Background(), req, rsp); err != nil {
+			t.Fatal(err)
+		}
+		if rsp.Body != d.body {
+			t.Fatalf("Expected the body %q got %q", d.body, rsp.Body)
+		}
+
+		tr.Lock()
+		if len(tr.recv) == 0 || tr.recv[0] != d.encoding {
+			t.Fatalf("Expected a request of encoding %q got %v", d.encoding, tr.recv)
+		}
+		if len(tr.sent) == 0 || tr.sent[0] != d.encoding {
+			t.Fatalf("Expected a response of encoding %q got %v", d.encoding, tr.sent)
+		}
+		tr.Unlock()
+	}
+
+	// the request is not compressed for an address which doesn't advertise the encoding
+	tr.Lock()
+	tr.recv, tr.sent = nil, nil
+	tr.Unlock()
+
+	c := cmucp.NewClient(
+		client.ContentType("application/json"),
+		client.Compressor(compress.Gzip),
+		client.Selector(sr.NewSelector(selector.Registry(reg))),
+		client.Transport(httpt.NewTransport()),
+	)
+
+	body := strings.Repeat("a", 2048)
+	req := c.NewRequest("test.compress", "Echo.Echo", &EchoRequest{Body: body})
+	rsp := new(EchoResponse)
+	if err := c.Call(context.Background(), req, rsp, client.WithAddress(srv.Options().Address)); err != nil {
+		t.Fatal(err)
+	}
+	if rsp.Body != body {
+		t.Fatalf("Expected the body %q got %q", body, rsp.Body)
+	}
+
+	tr.Lock()
+	defer tr.Unlock()
+	if len(tr.recv) == 0 || tr.recv[0] != "" {
+		t.Fatalf("Expected an uncompressed request got %v", tr.recv)
+	}
+	if len(tr.sent) == 0 || tr.sent[0] != compress.Gzip {
+		t.Fatalf("Expected a compressed response got %v", tr.sent)
+	}
+}
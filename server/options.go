@@ -34,6 +34,24 @@ type Options struct {
 	// The interval on which to register
 	RegisterInterval time.Duration
 
+	// DrainPeriod is how long the server keeps serving after
+	// deregistering so that client selector caches expire
+	DrainPeriod time.Duration
+	// ShutdownTimeout is how long to wait for in-flight handlers and
+	// subscribers once the server stopped accepting connections
+	ShutdownTimeout time.Duration
+
+	// Compress enables the compression of the responses with an encoding
+	// accepted by the client, the request bodies are always decompressed
+	Compress bool
+	// CompressThreshold is the smallest response body in bytes which is
+	// compressed, zero uses the default and a negative value disables the
+	// compression
+	CompressThreshold int
+	// MaxDecompressSize is the largest request body in bytes which is
+	// decompressed, zero uses the default
+	MaxDecompressSize int64
+
 	// The router for requests
 	Router Router
 
@@ -142,6 +160,42 @@ func RegisterInterval(t time.Duration) Option {
 	}
 }
 
+// DrainPeriod to keep serving requests after deregistering on stop
+func DrainPeriod(d time.Duration) Option {
+	return func(o *Options) {
+		o.DrainPeriod = d
+	}
+}
+
+// ShutdownTimeout to wait for in-flight requests on stop, a negative
+// timeout waits until they all finish
+func ShutdownTimeout(d time.Duration) Option {
+	return func(o *Options) {
+		o.ShutdownTimeout = d
+	}
+}
+
+// Compress enables the compression of the responses with an encoding accepted by the client
+func Compress(b bool) Option {
+	return func(o *Options) {
+		o.Compress = b
+	}
+}
+
+// CompressThreshold sets the smallest response body in bytes which is compressed
+func CompressThreshold(n int) Option {
+	return func(o *Options) {
+		o.CompressThreshold = n
+	}
+}
+
+// MaxDecompressSize sets the largest request body in bytes which is decompressed
+func MaxDecompressSize(n int64) Option {
+	return func(o *Options) {
+		o.MaxDecompressSize = n
+	}
+}
+
 // WithRouter sets the request router
 func WithRouter(r Router) Option {
 	return func(o *Options) {
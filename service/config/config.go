@@ -1,23 +1,40 @@
 package config
 
 import (
+	"crypto/tls"
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
 	au "github.com/stack-labs/stack/auth"
 	br "github.com/stack-labs/stack/broker"
 	cl "github.com/stack-labs/stack/client"
+	"github.com/stack-labs/stack/client/balancer"
+	grpcc "github.com/stack-labs/stack/client/grpc"
 	sel "github.com/stack-labs/stack/client/selector"
+	selReg "github.com/stack-labs/stack/client/selector/registry"
 	cfg "github.com/stack-labs/stack/config"
+	"github.com/stack-labs/stack/debug/health"
+	"github.com/stack-labs/stack/debug/metrics"
+	mw "github.com/stack-labs/stack/debug/metrics/wrapper"
 	lg "github.com/stack-labs/stack/logger"
 	"github.com/stack-labs/stack/plugin"
 	reg "github.com/stack-labs/stack/registry"
 	ser "github.com/stack-labs/stack/server"
+	grpcs "github.com/stack-labs/stack/server/grpc"
+	https "github.com/stack-labs/stack/server/http"
+	"github.com/stack-labs/stack/server/limit"
 	ss "github.com/stack-labs/stack/service"
 	sw "github.com/stack-labs/stack/service/web"
 	tra "github.com/stack-labs/stack/transport"
 	"github.com/stack-labs/stack/util/log"
+	"github.com/stack-labs/stack/util/ratelimit"
+	mls "github.com/stack-labs/stack/util/tls"
+	"github.com/stack-labs/stack/util/tls/ca"
+	cs "github.com/stack-labs/stack/util/tls/ca/service"
+	"github.com/stack-labs/stack/util/tls/ca/service/handler"
+	cpb "github.com/stack-labs/stack/util/tls/ca/service/proto"
 )
 
 var (
@@ -43,9 +60,75 @@ func (c *Config) Options() []cfg.Option {
 	return cfgOptions
 }
 
+// tlsConfig are the files of mutual TLS, it is disabled without a CA
+type tlsConfig struct {
+	CAFile   string `json:"caFile" sc:"ca-file"`
+	CertFile string `json:"certFile" sc:"cert-file"`
+	KeyFile  string `json:"keyFile" sc:"key-file"`
+	// AllowedClientSANs are the client identities allowed. When empty any
+	// client signed by the CA is allowed
+	AllowedClientSANs []string `json:"allowedClientSans" sc:"allowed-client-sans"`
+	// AllowedServerSANs are the server identities allowed. When empty a server
+	// must carry the name of the service dialled
+	AllowedServerSANs []string `json:"allowedServerSans" sc:"allowed-server-sans"`
+	// ReloadInterval to check the files for changes, e.g 1m
+	ReloadInterval string `json:"reloadInterval" sc:"reload-interval"`
+
+	creds *mls.Credentials
+}
+
+// load builds the credentials once, there are none when no CA is set
+func (t *tlsConfig) load() error {
+	if t.creds != nil || len(t.CAFile) == 0 {
+		return nil
+	}
+
+	opts := []mls.Option{
+		mls.CA(t.CAFile),
+		mls.KeyPair(t.CertFile, t.KeyFile),
+		mls.AllowedClientSANs(t.AllowedClientSANs...),
+		mls.AllowedServerSANs(t.AllowedServerSANs...),
+	}
+
+	if len(t.ReloadInterval) > 0 {
+		d, err := time.ParseDuration(t.ReloadInterval)
+		if err != nil {
+			return fmt.Errorf("failed to parse tls reload interval: %v. it shoud be with unit suffix such as 1s, 2m", t.ReloadInterval)
+		}
+		opts = append(opts, mls.ReloadInterval(d))
+	}
+
+	// never fall back to an insecure connection
+	creds, err := mls.NewCredentials(opts...)
+	if err != nil {
+		return fmt.Errorf("failed to load the tls credentials of %s: %v", t.CAFile, err)
+	}
+	t.creds = creds
+
+	return nil
+}
+
+// Config returns the mutual TLS config, nil when no CA is set
+func (t *tlsConfig) Config() *tls.Config {
+	if t.creds == nil {
+		return nil
+	}
+	return t.creds.Config()
+}
+
+// Stop watching the files of the credentials
+func (t *tlsConfig) Stop() error {
+	if t.creds != nil {
+		t.creds.Stop()
+		t.creds = nil
+	}
+	return nil
+}
+
 type Broker struct {
-	Address string `json:"address" sc:"address"`
-	Name    string `json:"name" sc:"name"`
+	Address string    `json:"address" sc:"address"`
+	Name    string    `json:"name" sc:"name"`
+	TLS     tlsConfig `json:"tls" sc:"tls"`
 }
 
 func (b *Broker) Options() []br.Option {
@@ -59,6 +142,10 @@ func (b *Broker) Options() []br.Option {
 		brOptions = append(brOptions, br.Addrs(strings.Split(b.Address, ",")...))
 	}
 
+	if c := b.TLS.Config(); c != nil {
+		brOptions = append(brOptions, br.TLSConfig(c))
+	}
+
 	// todo adapt options by name
 
 	return brOptions
@@ -79,6 +166,10 @@ type Client struct {
 	Protocol string        `json:"protocol" sc:"protocol"`
 	Pool     pool          `json:"pool" sc:"pool"`
 	Request  clientRequest `json:"request" sc:"request"`
+	// Compressor of the request bodies, gzip, snappy or a plugin e.g zstd
+	Compressor string `json:"compressor" sc:"compressor"`
+	// CompressThreshold is the smallest body compressed in bytes, -1 disables
+	CompressThreshold int `json:"compressThreshold" sc:"compress-threshold"`
 }
 
 func (c *Client) Options() []cl.Option {
@@ -115,12 +206,22 @@ func (c *Client) Options() []cl.Option {
 		cliOpts = append(cliOpts, cl.PoolTTL(poolTTL*time.Second))
 	}
 
+	if len(c.Compressor) > 0 {
+		cliOpts = append(cliOpts, cl.Compressor(c.Compressor))
+	}
+
+	if c.CompressThreshold != 0 {
+		cliOpts = append(cliOpts, cl.CompressThreshold(c.CompressThreshold))
+	}
+
 	return cliOpts
 }
 
 type Registry struct {
-	Address string `json:"address" sc:"address"`
-	Name    string `json:"name" sc:"name"`
+	Address string    `json:"address" sc:"address"`
+	Name    string    `json:"name" sc:"name"`
+	Domain  string    `json:"domain" sc:"domain"`
+	TLS     tlsConfig `json:"tls" sc:"tls"`
 }
 
 func (r *Registry) Options() []reg.Option {
@@ -134,6 +235,14 @@ func (r *Registry) Options() []reg.Option {
 		regOptions = append(regOptions, reg.Addrs(strings.Split(r.Address, ",")...))
 	}
 
+	if len(r.Domain) > 0 {
+		regOptions = append(regOptions, reg.Domain(r.Domain))
+	}
+
+	if c := r.TLS.Config(); c != nil {
+		regOptions = append(regOptions, reg.TLSConfig(c))
+	}
+
 	if plugin.RegistryPlugins[r.Name] != nil {
 		regOptions = append(regOptions, plugin.RegistryPlugins[r.Name].Options()...)
 	}
@@ -164,6 +273,19 @@ type Server struct {
 	Version     string         `json:"version" sc:"version"`
 	Registry    serverRegistry `json:"Registry" sc:"Registry"`
 	EnableDebug bool           `json:"enableDebug" sc:"enable-debug"`
+	Limit       serverLimit    `json:"limit" sc:"limit"`
+	// DrainPeriod to keep serving after deregistering on stop, e.g 5s
+	DrainPeriod string `json:"drainPeriod" sc:"drain-period"`
+	// ShutdownTimeout to wait for in-flight requests on stop, e.g 30s
+	ShutdownTimeout string `json:"shutdownTimeout" sc:"shutdown-timeout"`
+	// Compress the responses with an encoding accepted by the client
+	Compress bool `json:"compress" sc:"compress"`
+	// CompressThreshold is the smallest response compressed in bytes, -1 disables
+	CompressThreshold int `json:"compressThreshold" sc:"compress-threshold"`
+	// MaxDecompressSize is the largest request decompressed in bytes
+	MaxDecompressSize int64 `json:"maxDecompressSize" sc:"max-decompress-size"`
+	// Reflection serves the grpc server reflection service
+	Reflection bool `json:"reflection" sc:"reflection"`
 }
 
 type serverRegistry struct {
@@ -171,6 +293,63 @@ type serverRegistry struct {
 	Interval int `json:"interval" sc:"interval"`
 }
 
+type serverLimit struct {
+	Rate        float64 `json:"rate" sc:"rate"`
+	Burst       int     `json:"burst" sc:"burst"`
+	MaxInFlight int     `json:"maxInFlight" sc:"max-in-flight"`
+	// Endpoints overrides the limit of an endpoint or topic
+	// e.g Greeter.Hello=rate:10,burst:20,inflight:5
+	Endpoints []string `json:"endpoints" sc:"endpoints"`
+}
+
+func (s *serverLimit) Options() []limit.Option {
+	var limitOpts []limit.Option
+
+	if s.Rate > 0 {
+		limitOpts = append(limitOpts, limit.Rate(s.Rate, s.Burst))
+	}
+
+	if s.MaxInFlight > 0 {
+		limitOpts = append(limitOpts, limit.MaxInFlight(s.MaxInFlight))
+	}
+
+	for _, e := range s.Endpoints {
+		parts := strings.SplitN(e, "=", 2)
+		if len(parts) != 2 {
+			log.Warnf("failed to parse server limit endpoint: %v. it should be like Greeter.Hello=rate:10,burst:20,inflight:5", e)
+			continue
+		}
+
+		var l ratelimit.Limit
+		for _, kv := range strings.Split(parts[1], ",") {
+			pair := strings.SplitN(strings.TrimSpace(kv), ":", 2)
+			if len(pair) != 2 {
+				log.Warnf("failed to parse server limit of %s: %v", parts[0], kv)
+				continue
+			}
+
+			var err error
+			switch pair[0] {
+			case "rate":
+				l.Rate, err = strconv.ParseFloat(pair[1], 64)
+			case "burst":
+				l.Burst, err = strconv.Atoi(pair[1])
+			case "inflight":
+				l.MaxInFlight, err = strconv.Atoi(pair[1])
+			default:
+				log.Warnf("unknown server limit of %s: %v", parts[0], pair[0])
+			}
+			if err != nil {
+				log.Warnf("failed to parse server limit of %s: %v", parts[0], kv)
+			}
+		}
+
+		limitOpts = append(limitOpts, limit.Endpoint(strings.TrimSpace(parts[0]), l))
+	}
+
+	return limitOpts
+}
+
 func (s *Server) Options() []ser.Option {
 	var serverOpts []ser.Option
 
@@ -222,11 +401,113 @@ func (s *Server) Options() []ser.Option {
 		serverOpts = append(serverOpts, ser.RegisterInterval(val*time.Second))
 	}
 
+	if len(s.DrainPeriod) > 0 {
+		d, err := time.ParseDuration(s.DrainPeriod)
+		if err != nil {
+			log.Warnf("failed to parse server drain period: %v. it shoud be with unit suffix such as 1s, 2m", s.DrainPeriod)
+		} else {
+			serverOpts = append(serverOpts, ser.DrainPeriod(d))
+		}
+	}
+
+	if len(s.ShutdownTimeout) > 0 {
+		d, err := time.ParseDuration(s.ShutdownTimeout)
+		if err != nil {
+			log.Warnf("failed to parse server shutdown timeout: %v. it shoud be with unit suffix such as 1s, 2m", s.ShutdownTimeout)
+		} else {
+			serverOpts = append(serverOpts, ser.ShutdownTimeout(d))
+		}
+	}
+
+	if s.Compress {
+		serverOpts = append(serverOpts, ser.Compress(true))
+	}
+
+	if s.CompressThreshold != 0 {
+		serverOpts = append(serverOpts, ser.CompressThreshold(s.CompressThreshold))
+	}
+
+	if s.MaxDecompressSize > 0 {
+		serverOpts = append(serverOpts, ser.MaxDecompressSize(s.MaxDecompressSize))
+	}
+
+	if s.Reflection {
+		serverOpts = append(serverOpts, grpcs.Reflection(true))
+	}
+
+	if limitOpts := s.Limit.Options(); len(limitOpts) > 0 {
+		handlerWrapper := limit.NewHandlerWrapper(limitOpts...)
+		serverOpts = append(serverOpts,
+			ser.WrapHandler(handlerWrapper),
+			ser.WrapSubscriber(limit.NewSubscriberWrapper(limitOpts...)),
+			// the http server only runs the wrappers set for it
+			https.WrapHandler(handlerWrapper),
+		)
+	}
+
 	return serverOpts
 }
 
+type selectorOutlier struct {
+	MaxFailures  int     `json:"maxFailures" sc:"max-failures"`
+	FailureRate  float64 `json:"failureRate" sc:"failure-rate"`
+	MinRequests  int     `json:"minRequests" sc:"min-requests"`
+	Window       string  `json:"window" sc:"window"`
+	EjectTime    string  `json:"ejectTime" sc:"eject-time"`
+	MaxEjectTime string  `json:"maxEjectTime" sc:"max-eject-time"`
+}
+
+func (s *selectorOutlier) Options() []sel.Option {
+	var selOptions []sel.Option
+
+	if s.MaxFailures > 0 {
+		selOptions = append(selOptions, selReg.MaxFailures(s.MaxFailures))
+	}
+
+	if s.FailureRate > 0 {
+		minRequests := s.MinRequests
+		if minRequests <= 0 {
+			minRequests = selReg.DefaultMinRequests
+		}
+		selOptions = append(selOptions, selReg.FailureRate(s.FailureRate, minRequests))
+	}
+
+	if len(s.Window) > 0 {
+		d, err := time.ParseDuration(s.Window)
+		if err != nil {
+			log.Warnf("failed to parse selector outlier window: %v. it shoud be with unit suffix such as 1s, 2m", s.Window)
+		} else {
+			selOptions = append(selOptions, selReg.FailureWindow(d))
+		}
+	}
+
+	if len(s.EjectTime) > 0 {
+		base, err := time.ParseDuration(s.EjectTime)
+		if err != nil {
+			log.Warnf("failed to parse selector outlier eject time: %v. it shoud be with unit suffix such as 1s, 2m", s.EjectTime)
+			return selOptions
+		}
+
+		max := selReg.DefaultMaxEjectTime
+		if len(s.MaxEjectTime) > 0 {
+			if d, err := time.ParseDuration(s.MaxEjectTime); err != nil {
+				log.Warnf("failed to parse selector outlier max eject time: %v. it shoud be with unit suffix such as 1s, 2m", s.MaxEjectTime)
+			} else {
+				max = d
+			}
+		}
+		selOptions = append(selOptions, selReg.EjectTime(base, max))
+	}
+
+	return selOptions
+}
+
 type Selector struct {
 	Name string `json:"name" sc:"name"`
+	// Strategy is one of random, roundrobin, weighted, least-request or hash
+	Strategy string          `json:"strategy" sc:"strategy"`
+	HashKey  string          `json:"hashKey" sc:"hash-key"`
+	Outlier  selectorOutlier `json:"outlier" sc:"outlier"`
 }
 
 func (s *Selector) Options() []sel.Option {
@@ -236,6 +517,24 @@ func (s *Selector) Options() []sel.Option {
 		selOptions = append(selOptions, sel.Name(s.Name))
 	}
 
+	switch s.Strategy {
+	case "":
+	case "random":
+		selOptions = append(selOptions, sel.SetStrategy(sel.Random()))
+	case "roundrobin":
+		selOptions = append(selOptions, sel.SetStrategy(sel.RoundRobin()))
+	case "weighted":
+		selOptions = append(selOptions, sel.SetStrategy(sel.Weighted()))
+	case "least-request":
+		selOptions = append(selOptions, sel.SetStrategy(sel.LeastRequest(sel.DefaultLoad)))
+	case "hash":
+		// calls without a hash key are routed randomly
+	default:
+		log.Warnf("unknown selector strategy: %s", s.Strategy)
+	}
+
+	selOptions = append(selOptions, s.Outlier.Options()...)
+
 	if plugin.TransportPlugins[s.Name] != nil {
 		selOptions = append(selOptions, plugin.SelectorPlugins[s.Name].Options()...)
 	}
@@ -243,9 +542,22 @@ func (s *Selector) Options() []sel.Option {
 	return selOptions
 }
 
+// Wrappers returns the client wrappers the strategy needs to track the calls
+func (s *Selector) Wrappers() []cl.Wrapper {
+	switch s.Strategy {
+	case "least-request":
+		return []cl.Wrapper{balancer.NewLeastRequestWrapper()}
+	case "hash":
+		return []cl.Wrapper{balancer.NewHashWrapper(balancer.HashKey(s.HashKey))}
+	}
+
+	return nil
+}
+
 type Transport struct {
-	Name    string `json:"name" sc:"name"`
-	Address string `json:"address" sc:"address"`
+	Name    string    `json:"name" sc:"name"`
+	Address string    `json:"address" sc:"address"`
+	TLS     tlsConfig `json:"tls" sc:"tls"`
 }
 
 func (t *Transport) Options() []tra.Option {
@@ -259,6 +571,10 @@ func (t *Transport) Options() []tra.Option {
 		traOptions = append(traOptions, tra.Addrs(strings.Split(t.Address, ",")...))
 	}
 
+	if c := t.TLS.Config(); c != nil {
+		traOptions = append(traOptions, tra.TLSConfig(c))
+	}
+
 	if plugin.TransportPlugins[t.Name] != nil {
 		traOptions = append(traOptions, plugin.TransportPlugins[t.Name].Options()...)
 	}
@@ -426,6 +742,244 @@ func (s *Service) Options() serviceOpts {
 	return opts
 }
 
+type Metrics struct {
+	Enable  bool   `json:"enable" sc:"enable"`
+	Address string `json:"address" sc:"address"`
+	Path    string `json:"path" sc:"path"`
+}
+
+// Options wraps the client and server to record the metrics
+// and serves them on their own address once started
+func (m *Metrics) Options() serviceOpts {
+	var opts serviceOpts
+
+	if !m.Enable {
+		return opts
+	}
+
+	srv := metrics.NewServer(m.Address, m.Path, metrics.DefaultMetrics)
+
+	opts = append(opts,
+		func(o *ss.Options) {
+			o.ServerOptions = append(o.ServerOptions,
+				ser.WrapHandler(mw.NewHandlerWrapper(metrics.DefaultMetrics)),
+				ser.WrapSubscriber(mw.NewSubscriberWrapper(metrics.DefaultMetrics)),
+			)
+			o.ClientOptions = append(o.ClientOptions, cl.WrapCall(mw.NewCallWrapper(metrics.DefaultMetrics)))
+			o.ClientWrapper = append(o.ClientWrapper, mw.NewClientWrapper(metrics.DefaultMetrics))
+		},
+		ss.AfterStart(srv.Start),
+		ss.BeforeStop(srv.Stop),
+	)
+
+	return opts
+}
+
+type Health struct {
+	Enable      bool   `json:"enable" sc:"enable"`
+	Address     string `json:"address" sc:"address"`
+	GRPCAddress string `json:"grpcAddress" sc:"grpc-address"`
+}
+
+// Options serves the health of the service on its own addresses when enabled or
+// when the service registers checks. The service is ready once started and until
+// it begins to stop, as long as the registry, the broker and the checks pass.
+func (h *Health) Options() serviceOpts {
+	var opts serviceOpts
+
+	hl := health.NewHealth()
+
+	opts = append(opts, func(o *ss.Options) {
+		var srv *health.Server
+
+		o.BeforeStart = append(o.BeforeStart, func() error {
+			if !h.Enable && len(o.HealthChecks) == 0 {
+				return nil
+			}
+
+			hl.Register("registry", health.RegistryCheck(o.Registry))
+			hl.Register("broker", health.BrokerCheck(o.Broker))
+			for name, c := range o.HealthChecks {
+				hl.Register(name, c)
+			}
+
+			srv = health.NewServer(h.Address, h.GRPCAddress, o.Server.Options().Name, hl)
+			return srv.Start()
+		})
+		o.AfterStart = append(o.AfterStart, func() error {
+			hl.SetReady(true)
+			return nil
+		})
+		// not ready before anything else stops
+		o.BeforeStop = append([]func() error{func() error {
+			hl.SetReady(false)
+			return nil
+		}}, o.BeforeStop...)
+		o.AfterStop = append(o.AfterStop, func() error {
+			if srv == nil {
+				return nil
+			}
+			return srv.Stop()
+		})
+	})
+
+	return opts
+}
+
+type caServer struct {
+	Enable bool `json:"enable" sc:"enable"`
+	// CertFile and KeyFile of the authority, generated when missing. The
+	// authority lives in memory when not set
+	CertFile string `json:"certFile" sc:"cert-file"`
+	KeyFile  string `json:"keyFile" sc:"key-file"`
+	// TTL of the certificates issued and the longest a service may request, e.g 24h
+	TTL    string `json:"ttl" sc:"ttl"`
+	MaxTTL string `json:"maxTtl" sc:"max-ttl"`
+	// Tokens the services bootstrap their first certificate with, e.g stack.rpc.greeter=secret
+	Tokens []string `json:"tokens" sc:"tokens"`
+}
+
+type caClient struct {
+	Enable bool `json:"enable" sc:"enable"`
+	// Name of the ca service
+	Name  string `json:"name" sc:"name"`
+	Token string `json:"token" sc:"token"`
+	// Dir the certificate is written to
+	Dir string `json:"dir" sc:"dir"`
+	// TTL requested for the certificate, e.g 24h
+	TTL string `json:"ttl" sc:"ttl"`
+	// AllowedClientSANs are the client identities allowed. When empty any
+	// client signed by the CA is allowed
+	AllowedClientSANs []string `json:"allowedClientSans" sc:"allowed-client-sans"`
+	// AllowedServerSANs are the server identities allowed. When empty a server
+	// must carry the name of the service dialled
+	AllowedServerSANs []string `json:"allowedServerSans" sc:"allowed-server-sans"`
+}
+
+type CA struct {
+	Server caServer `json:"server" sc:"server"`
+	Client caClient `json:"client" sc:"client"`
+}
+
+// Options serves the certificate authority from the service, and issues the
+// certificate of the service with the ca service. The certificate is renewed
+// before it expires and secures the transport, the grpc server and client
+func (c *CA) Options() serviceOpts {
+	var opts serviceOpts
+
+	if c.Server.Enable {
+		opts = append(opts, func(o *ss.Options) {
+			o.BeforeStart = append(o.BeforeStart, func() error {
+				return c.Server.register(o.Server)
+			})
+		})
+	}
+
+	if c.Client.Enable {
+		opts = append(opts, func(o *ss.Options) {
+			var r *cs.Renewer
+
+			o.BeforeStart = append(o.BeforeStart, func() error {
+				var err error
+				r, err = c.Client.renewer(o)
+				if err != nil {
+					return err
+				}
+				if err := r.Start(); err != nil {
+					return fmt.Errorf("failed to issue the certificate of %s: %v", o.Server.Options().Name, err)
+				}
+
+				config := r.Config()
+				if err := o.Transport.Init(tra.TLSConfig(config)); err != nil {
+					return err
+				}
+				if err := o.Server.Init(grpcs.AuthTLS(config)); err != nil {
+					return err
+				}
+				return o.Client.Init(grpcc.AuthTLS(config))
+			})
+			o.AfterStop = append(o.AfterStop, func() error {
+				if r != nil {
+					r.Stop()
+				}
+				return nil
+			})
+		})
+	}
+
+	return opts
+}
+
+// register serves the authority with the ca handler of the server
+func (c *caServer) register(s ser.Server) error {
+	var opts []ca.Option
+
+	if len(c.CertFile) > 0 {
+		opts = append(opts, ca.KeyPair(c.CertFile, c.KeyFile))
+	}
+
+	for _, d := range []struct {
+		val string
+		opt func(time.Duration) ca.Option
+	}{{c.TTL, ca.TTL}, {c.MaxTTL, ca.MaxTTL}} {
+		if len(d.val) == 0 {
+			continue
+		}
+		t, err := time.ParseDuration(d.val)
+		if err != nil {
+			return fmt.Errorf("failed to parse ca ttl: %v. it shoud be with unit suffix such as 1s, 2m", d.val)
+		}
+		opts = append(opts, d.opt(t))
+	}
+
+	a, err := ca.NewAuthority(opts...)
+	if err != nil {
+		return err
+	}
+
+	tokens := make(map[string]string)
+	for _, d := range c.Tokens {
+		parts := strings.SplitN(d, "=", 2)
+		if len(parts) != 2 {
+			return fmt.Errorf("invalid ca token %s, it should be name=token", parts[0])
+		}
+		tokens[parts[0]] = parts[1]
+	}
+
+	return cpb.RegisterCAHandler(s, &handler.CA{Authority: a, Tokens: tokens})
+}
+
+// renewer of the certificate of the service, the ca service is called with
+// the client of the service. The first certificate is issued before the
+// transport is secured, it is renewed over mutual TLS
+func (c *caClient) renewer(o *ss.Options) (*cs.Renewer, error) {
+	opts := []cs.Option{
+		cs.Service(o.Server.Options().Name),
+		cs.Client(o.Client),
+		cs.Token(c.Token),
+		cs.AllowedClientSANs(c.AllowedClientSANs...),
+		cs.AllowedServerSANs(c.AllowedServerSANs...),
+	}
+
+	if len(c.Name) > 0 {
+		opts = append(opts, cs.Name(c.Name))
+	}
+
+	if len(c.Dir) > 0 {
+		opts = append(opts, cs.Dir(c.Dir))
+	}
+
+	if len(c.TTL) > 0 {
+		d, err := time.ParseDuration(c.TTL)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse ca ttl: %v. it shoud be with unit suffix such as 1s, 2m", c.TTL)
+		}
+		opts = append(opts, cs.TTL(d))
+	}
+
+	return cs.NewRenewer(opts...)
+}
+
 type StackConfig struct {
 	Stack struct {
 		Includes  string    `json:"includes" sc:"includes"`
@@ -440,6 +994,9 @@ type StackConfig struct {
 		Transport Transport `json:"transport" sc:"transport"`
 		Logger    Logger    `json:"logger" sc:"logger"`
 		Auth      Auth      `json:"auth" sc:"auth"`
+		Metrics   Metrics   `json:"metrics" sc:"metrics"`
+		Health    Health    `json:"health" sc:"health"`
+		CA        CA        `json:"ca" sc:"ca"`
 		Service   Service   `json:"service" sc:"service"`
 	} `json:"stack" sc:"stack"`
 }
@@ -104,16 +104,41 @@ func LoadConfig(sOpts *service.Options) (err error) {
 func SetOptions(sOpts *service.Options) (err error) {
 	conf := stackConfig.Stack
 
+	// the tls credentials are loaded once and watched until the service stops
+	tlsConfigs := []*tlsConfig{&conf.Broker.TLS, &conf.Registry.TLS, &conf.Transport.TLS}
+	for i, t := range tlsConfigs {
+		if err = t.load(); err != nil {
+			for _, t := range tlsConfigs[:i] {
+				t.Stop()
+			}
+			return
+		}
+		sOpts.AfterStop = append(sOpts.AfterStop, t.Stop)
+	}
+
 	// serviceOptions
 	for _, option := range conf.Service.Options() {
 		option(sOpts)
 	}
 
+	for _, option := range conf.Metrics.Options() {
+		option(sOpts)
+	}
+
+	for _, option := range conf.Health.Options() {
+		option(sOpts)
+	}
+
+	for _, option := range conf.CA.Options() {
+		option(sOpts)
+	}
+
 	sOpts.ServerOptions = append(sOpts.ServerOptions, conf.Server.Options()...)
 	sOpts.ClientOptions = append(sOpts.ClientOptions, conf.Client.Options()...)
 	sOpts.ConfigOptions = append(sOpts.ConfigOptions, conf.Config.Options()...)
 	sOpts.TransportOptions = append(sOpts.TransportOptions, conf.Transport.Options()...)
 	sOpts.SelectorOptions = append(sOpts.SelectorOptions, conf.Selector.Options()...)
+	sOpts.ClientWrapper = append(sOpts.ClientWrapper, conf.Selector.Wrappers()...)
 	sOpts.RegistryOptions = append(sOpts.RegistryOptions, conf.Registry.Options()...)
 	sOpts.BrokerOptions = append(sOpts.BrokerOptions, conf.Broker.Options()...)
 	sOpts.LoggerOptions = append(sOpts.LoggerOptions, conf.Logger.Options()...)
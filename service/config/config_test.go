@@ -1,18 +1,27 @@
 package config
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
 	"os"
 	"path/filepath"
 	"testing"
 
+	bmemory "github.com/stack-labs/stack/broker/memory"
 	"github.com/stack-labs/stack/cmd"
 	cfg "github.com/stack-labs/stack/config"
 	"github.com/stack-labs/stack/pkg/config/source"
 	cliSource "github.com/stack-labs/stack/pkg/config/source/cli"
 	"github.com/stack-labs/stack/pkg/config/source/file"
 	"github.com/stack-labs/stack/pkg/config/source/memory"
+	rmemory "github.com/stack-labs/stack/registry/memory"
+	"github.com/stack-labs/stack/server/mock"
+	ss "github.com/stack-labs/stack/service"
+	"github.com/stack-labs/stack/util/tls/ca"
 )
 
 var (
@@ -324,6 +333,86 @@ testA:
 	}
 }
 
+func TestTLSConfig(t *testing.T) {
+	dir, err := ioutil.TempDir("", "tls")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	a, err := ca.NewAuthority()
+	if err != nil {
+		t.Fatal(err)
+	}
+	csr, key, err := ca.NewRequest("stack.rpc.test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, _, err := a.Sign("stack.rpc.test", csr, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tc := tlsConfig{
+		CAFile:   filepath.Join(dir, "ca.crt"),
+		CertFile: filepath.Join(dir, "test.crt"),
+		KeyFile:  filepath.Join(dir, "test.key"),
+	}
+
+	// the files are missing
+	if err := tc.load(); err == nil {
+		t.Fatal("Expected an error loading the missing files")
+	}
+	if tc.Config() != nil {
+		t.Fatal("Expected no config without credentials")
+	}
+
+	for file, data := range map[string][]byte{tc.CAFile: a.Bundle(), tc.CertFile: cert, tc.KeyFile: key} {
+		if err := ca.WriteFile(file, data); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := tc.load(); err != nil {
+		t.Fatal(err)
+	}
+	creds := tc.creds
+	if err := tc.load(); err != nil || tc.creds != creds {
+		t.Fatal("Expected the credentials to be loaded once")
+	}
+	if tc.Config() == nil {
+		t.Fatal("Expected a config")
+	}
+
+	tc.Stop()
+	if tc.Config() != nil {
+		t.Fatal("Expected no config once stopped")
+	}
+
+	tc.ReloadInterval = "1"
+	if err := tc.load(); err == nil {
+		t.Fatal("Expected an invalid reload interval")
+	}
+}
+
+func TestCAServer(t *testing.T) {
+	testData := []struct {
+		server caServer
+		err    bool
+	}{
+		{caServer{Tokens: []string{"stack.rpc.greeter=secret"}, TTL: "1h"}, false},
+		{caServer{Tokens: []string{"stack.rpc.greeter"}}, true},
+		{caServer{MaxTTL: "1"}, true},
+	}
+
+	for _, d := range testData {
+		err := d.server.register(mock.NewServer())
+		if (err != nil) != d.err {
+			t.Fatalf("Expected the error %v got %v", d.err, err)
+		}
+	}
+}
+
 func touchFile(t *testing.T, fileName string, data []byte) (f *os.File, fullPath string, err error) {
 	fileName = t.Name() + fileName
 	filePath := filepath.Join(os.TempDir(), fileName)
@@ -339,3 +428,58 @@ func touchFile(t *testing.T, fileName string, data []byte) (f *os.File, fullPath
 
 	return file, filePath, nil
 }
+
+func TestHealthChecks(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	address := ln.Addr().String()
+	ln.Close()
+
+	start := func(h Health, opts ...ss.Option) *ss.Options {
+		o := &ss.Options{
+			Server:   mock.NewServer(),
+			Registry: rmemory.NewRegistry(),
+			Broker:   bmemory.NewBroker(),
+		}
+		for _, opt := range opts {
+			opt(o)
+		}
+		for _, opt := range h.Options() {
+			opt(o)
+		}
+		for _, fn := range o.BeforeStart {
+			if err := fn(); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return o
+	}
+	stop := func(o *ss.Options) {
+		for _, fn := range o.AfterStop {
+			if err := fn(); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	// disabled without checks
+	o := start(Health{Address: address})
+	if _, err := http.Get("http://" + address + "/healthz"); err == nil {
+		t.Fatal("expected health to be disabled")
+	}
+	stop(o)
+
+	// enabled by a check
+	o = start(Health{Address: address}, ss.HealthCheck("db", func(ctx context.Context) error {
+		return nil
+	}))
+	defer stop(o)
+
+	rsp, err := http.Get("http://" + address + "/healthz")
+	if err != nil {
+		t.Fatalf("expected health to be enabled by the check: %v", err)
+	}
+	rsp.Body.Close()
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/stack-labs/stack/client/selector"
 	"github.com/stack-labs/stack/cmd"
 	"github.com/stack-labs/stack/config"
+	"github.com/stack-labs/stack/debug/health"
 	"github.com/stack-labs/stack/debug/profile"
 	"github.com/stack-labs/stack/logger"
 	"github.com/stack-labs/stack/registry"
@@ -59,6 +60,8 @@ type Options struct {
 	CallWrapper       []client.CallWrapper
 	HandlerWrapper    []server.HandlerWrapper
 	SubscriberWrapper []server.SubscriberWrapper
+	// HealthChecks are the readiness checks of the service by name
+	HealthChecks map[string]health.Check
 	// Other options for implementations of the interface
 	// can be stored in a context
 	Context context.Context
@@ -344,6 +347,16 @@ func WrapSubscriber(w ...server.SubscriberWrapper) Option {
 	}
 }
 
+// HealthCheck registers a check the readiness of the service depends on
+func HealthCheck(name string, c health.Check) Option {
+	return func(o *Options) {
+		if o.HealthChecks == nil {
+			o.HealthChecks = make(map[string]health.Check)
+		}
+		o.HealthChecks[name] = c
+	}
+}
+
 func BeforeInit(fn func(sOpts *Options) error) Option {
 	return func(o *Options) {
 		o.BeforeInit = append(o.BeforeInit, fn)
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/stack-labs/stack/auth"
 	br "github.com/stack-labs/stack/broker"
 	cl "github.com/stack-labs/stack/client"
 	sel "github.com/stack-labs/stack/client/selector"
@@ -140,7 +141,10 @@ func (s *stackService) initComponents() error {
 	s.opts.SelectorOptions = append(s.opts.SelectorOptions, sel.Registry(s.opts.Registry))
 	s.opts.BrokerOptions = append(s.opts.BrokerOptions, br.Registry(s.opts.Registry))
 
-	// set wrappers
+	// set wrappers, the account of the caller is put in the context first
+	s.opts.ServerOptions = append(s.opts.ServerOptions, ser.WrapHandler(wrapper.AuthHandler(func() auth.Auth {
+		return s.opts.Auth
+	})))
 	for _, wrapper := range s.opts.HandlerWrapper {
 		s.opts.ServerOptions = append(s.opts.ServerOptions, ser.WrapHandler(wrapper))
 	}
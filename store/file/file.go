@@ -0,0 +1,839 @@
+// Package file is a store persisted to an append only data file per namespace.
+// Every Write or Delete is appended as a single checksummed frame so a crash
+// never leaves part of a write behind, and stale records are dropped by
+// compacting the data file in the background.
+package file
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/stack-labs/stack/store"
+	"github.com/stack-labs/stack/store/internal/watch"
+	"github.com/stack-labs/stack/util/file"
+	"github.com/stack-labs/stack/util/log"
+	"github.com/stack-labs/stack/util/options"
+)
+
+var (
+	// DefaultDir is the directory data files are kept in
+	DefaultDir = filepath.Join(os.TempDir(), "stack", "store")
+	// DefaultNamespace is the namespace used when none is set
+	DefaultNamespace = "stack"
+	// DefaultCompactInterval is how often the data file is checked for compaction
+	DefaultCompactInterval = 10 * time.Minute
+	// DefaultCompactRatio is the share of stale bytes that triggers compaction
+	DefaultCompactRatio = 0.5
+
+	// ErrClosed is returned when using a closed store
+	ErrClosed = errors.New("store is closed")
+)
+
+const (
+	opPut    byte = 1
+	opDelete byte = 2
+	// opRevision keeps the revision of the store across compaction
+	opRevision byte = 3
+
+	// a frame is the payload length and crc32 followed by the payload
+	frameHeaderSize = 8
+	// an op is its kind, expiry and version followed by the key and value
+	opHeaderSize = 17
+)
+
+type fileStore struct {
+	options.Options
+
+	path   string
+	prefix string
+	sync   bool
+	ratio  float64
+
+	sync.RWMutex
+	// lock is held for the lifetime of the store so no other process
+	// writes the data file
+	lock   *os.File
+	file   *os.File
+	size   int64
+	stale  int64
+	index  map[string]*entry
+	closed bool
+	exit   chan bool
+	// revision is the version of the last change
+	revision uint64
+	watchers watch.Watchers
+	// err is set when the data file could not be opened
+	err error
+}
+
+// entry locates the latest value of a key in the data file
+type entry struct {
+	// pos and length of the value in the data file
+	pos    int64
+	length int
+	// size of the whole encoded entry
+	size int64
+	// expiry is zero for records which don't expire
+	expiry time.Time
+	// version is the revision the record was written at
+	version uint64
+}
+
+// op is a single put or delete within a frame
+type op struct {
+	kind    byte
+	key     string
+	value   []byte
+	expiry  time.Time
+	version uint64
+}
+
+func (e *entry) expired() bool {
+	return !e.expiry.IsZero() && time.Now().After(e.expiry)
+}
+
+// encode appends the frame holding the ops to b and returns the
+// entries of the ops relative to the start of the frame
+func encode(ops []*op) ([]byte, []*entry) {
+	payload := make([]byte, 0, 64*len(ops))
+	entries := make([]*entry, len(ops))
+	tmp := make([]byte, binary.MaxVarintLen64)
+
+	for i, o := range ops {
+		start := len(payload)
+
+		var expiry int64
+		if !o.expiry.IsZero() {
+			expiry = o.expiry.UnixNano()
+		}
+
+		payload = append(payload, o.kind)
+		binary.BigEndian.PutUint64(tmp, uint64(expiry))
+		payload = append(payload, tmp[:8]...)
+		binary.BigEndian.PutUint64(tmp, o.version)
+		payload = append(payload, tmp[:8]...)
+		payload = append(payload, tmp[:binary.PutUvarint(tmp, uint64(len(o.key)))]...)
+		payload = append(payload, o.key...)
+		payload = append(payload, tmp[:binary.PutUvarint(tmp, uint64(len(o.value)))]...)
+
+		entries[i] = &entry{
+			pos:     int64(frameHeaderSize + len(payload)),
+			length:  len(o.value),
+			expiry:  o.expiry,
+			version: o.version,
+		}
+
+		payload = append(payload, o.value...)
+		entries[i].size = int64(len(payload) - start)
+	}
+
+	frame := make([]byte, frameHeaderSize, frameHeaderSize+len(payload))
+	binary.BigEndian.PutUint32(frame[:4], uint32(len(payload)))
+	binary.BigEndian.PutUint32(frame[4:], crc32.ChecksumIEEE(payload))
+
+	return append(frame, payload...), entries
+}
+
+// decode parses the ops of a frame payload
+func decode(payload []byte) ([]*op, []*entry, error) {
+	var ops []*op
+	var entries []*entry
+
+	for p := 0; p < len(payload); {
+		start := p
+		if len(payload)-p < opHeaderSize {
+			return nil, nil, io.ErrUnexpectedEOF
+		}
+
+		o := &op{kind: payload[p]}
+		if expiry := int64(binary.BigEndian.Uint64(payload[p+1 : p+9])); expiry > 0 {
+			o.expiry = time.Unix(0, expiry)
+		}
+		o.version = binary.BigEndian.Uint64(payload[p+9 : p+opHeaderSize])
+		p += opHeaderSize
+
+		klen, n := binary.Uvarint(payload[p:])
+		if n <= 0 || uint64(len(payload)-p-n) < klen {
+			return nil, nil, io.ErrUnexpectedEOF
+		}
+		p += n
+		o.key = string(payload[p : p+int(klen)])
+		p += int(klen)
+
+		vlen, n := binary.Uvarint(payload[p:])
+		if n <= 0 || uint64(len(payload)-p-n) < vlen {
+			return nil, nil, io.ErrUnexpectedEOF
+		}
+		p += n
+
+		e := &entry{
+			pos:     int64(frameHeaderSize + p),
+			length:  int(vlen),
+			expiry:  o.expiry,
+			version: o.version,
+		}
+		p += int(vlen)
+		e.size = int64(p - start)
+
+		ops = append(ops, o)
+		entries = append(entries, e)
+	}
+
+	return ops, entries, nil
+}
+
+// load opens the data file at path and indexes it. A frame torn by a crash
+// at the tail is truncated, a corrupt frame followed by others is returned
+// as an error so that no valid record after it is lost.
+func (f *fileStore) load(path string) error {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return err
+	}
+
+	fi, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+	end := fi.Size()
+
+	f.file = file
+	f.size = 0
+	f.stale = 0
+	f.index = make(map[string]*entry)
+
+	header := make([]byte, frameHeaderSize)
+	for end-f.size >= frameHeaderSize {
+		if _, err := file.ReadAt(header, f.size); err != nil {
+			file.Close()
+			return err
+		}
+
+		// a frame running past the end of the file is torn
+		length := int64(binary.BigEndian.Uint32(header[:4]))
+		next := f.size + frameHeaderSize + length
+		if next > end {
+			break
+		}
+
+		payload := make([]byte, length)
+		if _, err := file.ReadAt(payload, f.size+frameHeaderSize); err != nil {
+			file.Close()
+			return err
+		}
+
+		ops, entries, err := decode(payload)
+		if err != nil || crc32.ChecksumIEEE(payload) != binary.BigEndian.Uint32(header[4:]) {
+			if next == end {
+				break
+			}
+			file.Close()
+			return fmt.Errorf("corrupt frame at position %d of %s", f.size, path)
+		}
+
+		f.apply(f.size, ops, entries)
+		f.size = next
+	}
+
+	if f.size < end {
+		log.Warnf("Truncating a torn write of %d bytes at the end of %s", end-f.size, path)
+		if err := file.Truncate(f.size); err != nil {
+			file.Close()
+			return err
+		}
+	}
+	if _, err := file.Seek(f.size, io.SeekStart); err != nil {
+		file.Close()
+		return err
+	}
+
+	return nil
+}
+
+// open locks and loads the data file
+func (f *fileStore) open() error {
+	lock, err := os.OpenFile(f.path+".lock", os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return err
+	}
+	if err := file.TryLock(lock); err != nil {
+		lock.Close()
+		return fmt.Errorf("%s: %v", f.path, err)
+	}
+	if err := f.load(f.path); err != nil {
+		lock.Close()
+		return err
+	}
+	f.lock = lock
+	return nil
+}
+
+// release closes the data file and its lock
+func (f *fileStore) release() error {
+	var err error
+	if f.file != nil {
+		err = f.file.Close()
+		f.file = nil
+	}
+	if f.lock != nil {
+		if cerr := f.lock.Close(); cerr != nil {
+			err = cerr
+		}
+		f.lock = nil
+	}
+	return err
+}
+
+// configure sets the options of the store and returns the path of the data
+// file of the namespace
+func (f *fileStore) configure() string {
+	dir := DefaultDir
+	if v, ok := f.Options.Values().Get("store.file.dir"); ok {
+		dir = v.(string)
+	}
+	namespace := DefaultNamespace
+	if v, ok := f.Options.Values().Get("store.namespace"); ok && len(v.(string)) > 0 {
+		namespace = v.(string)
+	}
+
+	f.prefix = ""
+	if v, ok := f.Options.Values().Get("store.prefix"); ok {
+		f.prefix = v.(string)
+	}
+	f.sync = true
+	if v, ok := f.Options.Values().Get("store.file.sync"); ok {
+		f.sync = v.(bool)
+	}
+	f.ratio = DefaultCompactRatio
+	if v, ok := f.Options.Values().Get("store.file.compact_ratio"); ok {
+		f.ratio = v.(float64)
+	}
+
+	return filepath.Join(dir, url.PathEscape(namespace)+".db")
+}
+
+// reopen switches to the data file at path, the error is kept and returned
+// by the operations if it can't be opened
+func (f *fileStore) reopen(path string) error {
+	if err := f.release(); err != nil {
+		log.Errorf("Error closing store %s: %v", f.path, err)
+	}
+
+	f.path = path
+	f.revision = 0
+	f.err = nil
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		f.err = err
+	} else if err := f.open(); err != nil {
+		f.err = err
+	}
+	if f.err != nil {
+		log.Errorf("Error opening store %s: %v", f.path, f.err)
+	}
+	return f.err
+}
+
+// apply updates the index with the ops of the frame at pos
+func (f *fileStore) apply(pos int64, ops []*op, entries []*entry) {
+	for i, o := range ops {
+		e := entries[i]
+		e.pos += pos
+
+		if o.version > f.revision {
+			f.revision = o.version
+		}
+		if o.kind == opRevision {
+			f.stale += e.size
+			continue
+		}
+
+		if old, ok := f.index[o.key]; ok {
+			f.stale += old.size
+		}
+
+		if o.kind == opDelete {
+			f.stale += e.size
+			delete(f.index, o.key)
+			continue
+		}
+		f.index[o.key] = e
+	}
+}
+
+// append writes the ops as a single frame
+func (f *fileStore) append(ops []*op) error {
+	if err := f.check(); err != nil {
+		return err
+	}
+
+	for i, o := range ops {
+		o.version = f.revision + uint64(i) + 1
+	}
+
+	frame, entries := encode(ops)
+	if _, err := f.file.Write(frame); err != nil {
+		f.rollback()
+		return err
+	}
+	if f.sync {
+		if err := f.file.Sync(); err != nil {
+			f.rollback()
+			return err
+		}
+	}
+
+	f.apply(f.size, ops, entries)
+	f.size += int64(len(frame))
+
+	return nil
+}
+
+// rollback drops a frame that was not fully written or synced so the next
+// one starts on a boundary
+func (f *fileStore) rollback() {
+	if err := f.file.Truncate(f.size); err != nil {
+		log.Errorf("Error truncating store %s: %v", f.path, err)
+	}
+	if _, err := f.file.Seek(f.size, io.SeekStart); err != nil {
+		log.Errorf("Error seeking store %s: %v", f.path, err)
+	}
+}
+
+// events returns the changes of the appended ops
+func (f *fileStore) events(ops []*op) []*store.Event {
+	events := make([]*store.Event, 0, len(ops))
+	for _, o := range ops {
+		r := &store.Record{
+			Key:     strings.TrimPrefix(o.key, f.prefix),
+			Version: o.version,
+		}
+		if o.kind == opDelete {
+			events = append(events, &store.Event{Type: store.Delete, Record: r})
+			continue
+		}
+		r.Value = o.value
+		if !o.expiry.IsZero() {
+			r.Expiry = time.Until(o.expiry)
+		}
+		events = append(events, &store.Event{Type: store.Put, Record: r})
+	}
+	return events
+}
+
+// check returns why the store can't be used, if anything
+func (f *fileStore) check() error {
+	if f.err != nil {
+		return f.err
+	}
+	if f.closed {
+		return ErrClosed
+	}
+	return nil
+}
+
+func (f *fileStore) record(key string, e *entry, keysOnly bool) (*store.Record, error) {
+	r := &store.Record{Key: strings.TrimPrefix(key, f.prefix), Version: e.version}
+	if !e.expiry.IsZero() {
+		r.Expiry = time.Until(e.expiry)
+	}
+	if keysOnly {
+		return r, nil
+	}
+
+	r.Value = make([]byte, e.length)
+	if _, err := f.file.ReadAt(r.Value, e.pos); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// match returns the unexpired records with matching keys ordered by key
+func (f *fileStore) match(fn func(key string) bool, offset, limit uint, keysOnly bool) ([]*store.Record, error) {
+	//nolint:prealloc
+	var keys []string
+	for k, e := range f.index {
+		if !strings.HasPrefix(k, f.prefix) || e.expired() {
+			continue
+		}
+		if fn(strings.TrimPrefix(k, f.prefix)) {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	if uint(len(keys)) <= offset {
+		return nil, nil
+	}
+	keys = keys[offset:]
+	if limit > 0 && uint(len(keys)) > limit {
+		keys = keys[:limit]
+	}
+
+	records := make([]*store.Record, 0, len(keys))
+	for _, k := range keys {
+		r, err := f.record(k, f.index[k], keysOnly)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, r)
+	}
+
+	return records, nil
+}
+
+func (f *fileStore) List(opts ...store.ListOption) ([]*store.Record, error) {
+	var options store.ListOptions
+	for _, o := range opts {
+		o(&options)
+	}
+
+	f.RLock()
+	defer f.RUnlock()
+
+	if err := f.check(); err != nil {
+		return nil, err
+	}
+
+	return f.match(func(key string) bool {
+		return strings.HasPrefix(key, options.Prefix) && strings.HasSuffix(key, options.Suffix)
+	}, options.Offset, options.Limit, options.KeysOnly)
+}
+
+func (f *fileStore) Read(key string, opts ...store.ReadOption) ([]*store.Record, error) {
+	var options store.ReadOptions
+	for _, o := range opts {
+		o(&options)
+	}
+
+	f.RLock()
+	defer f.RUnlock()
+
+	if err := f.check(); err != nil {
+		return nil, err
+	}
+
+	if !options.Prefix && !options.Suffix {
+		e, ok := f.index[f.prefix+key]
+		if !ok || e.expired() {
+			return nil, store.ErrNotFound
+		}
+		r, err := f.record(f.prefix+key, e, false)
+		if err != nil {
+			return nil, err
+		}
+		return []*store.Record{r}, nil
+	}
+
+	return f.match(func(k string) bool {
+		if options.Prefix && !strings.HasPrefix(k, key) {
+			return false
+		}
+		if options.Suffix && !strings.HasSuffix(k, key) {
+			return false
+		}
+		return true
+	}, options.Offset, options.Limit, false)
+}
+
+func (f *fileStore) Write(records ...*store.Record) error {
+	if len(records) == 0 {
+		return nil
+	}
+
+	f.Lock()
+	return f.write(records)
+}
+
+// CompareAndSwap writes the records if the stored version of every record
+// equals its Version, zero requiring that the key does not exist
+func (f *fileStore) CompareAndSwap(records ...*store.Record) error {
+	f.Lock()
+
+	if err := f.check(); err != nil {
+		f.Unlock()
+		return err
+	}
+
+	for _, r := range records {
+		var current uint64
+		if e, ok := f.index[f.prefix+r.Key]; ok && !e.expired() {
+			current = e.version
+		}
+		if current != r.Version {
+			f.Unlock()
+			return &store.ConflictError{
+				Key:      r.Key,
+				Expected: r.Version,
+				Current:  current,
+			}
+		}
+	}
+
+	if len(records) == 0 {
+		f.Unlock()
+		return nil
+	}
+
+	return f.write(records)
+}
+
+// write appends the records and sets their versions, it is called with the
+// lock held and releases it before notifying the watchers
+func (f *fileStore) write(records []*store.Record) error {
+	ops := make([]*op, 0, len(records))
+	for _, r := range records {
+		o := &op{kind: opPut, key: f.prefix + r.Key, value: r.Value}
+		if r.Expiry > 0 {
+			o.expiry = time.Now().Add(r.Expiry)
+		}
+		ops = append(ops, o)
+	}
+
+	if err := f.append(ops); err != nil {
+		f.Unlock()
+		return err
+	}
+	for i, r := range records {
+		r.Version = ops[i].version
+	}
+
+	send := f.watchers.Notify(f.events(ops)...)
+	f.Unlock()
+	send()
+
+	return nil
+}
+
+func (f *fileStore) Delete(keys ...string) error {
+	f.Lock()
+
+	//nolint:prealloc
+	var ops []*op
+	for _, key := range keys {
+		if _, ok := f.index[f.prefix+key]; ok {
+			ops = append(ops, &op{kind: opDelete, key: f.prefix + key})
+		}
+	}
+	if len(ops) == 0 {
+		f.Unlock()
+		return nil
+	}
+
+	if err := f.append(ops); err != nil {
+		f.Unlock()
+		return err
+	}
+
+	send := f.watchers.Notify(f.events(ops)...)
+	f.Unlock()
+	send()
+
+	return nil
+}
+
+// Watch the changes to records with keys that have the prefix, a watcher
+// falling behind is stopped with store.ErrWatcherLagging
+func (f *fileStore) Watch(prefix string) (store.Watcher, error) {
+	f.RLock()
+	defer f.RUnlock()
+
+	if err := f.check(); err != nil {
+		return nil, err
+	}
+	return f.watchers.Watch(prefix), nil
+}
+
+// Compact rewrites the data file with only the live records
+func (f *fileStore) Compact() error {
+	f.Lock()
+	defer f.Unlock()
+
+	if err := f.check(); err != nil {
+		return err
+	}
+
+	tmp := f.path + ".compact"
+	file, err := os.OpenFile(tmp, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp)
+
+	keys := make([]string, 0, len(f.index))
+	for k := range f.index {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		e := f.index[k]
+		if e.expired() {
+			continue
+		}
+
+		value := make([]byte, e.length)
+		if _, err := f.file.ReadAt(value, e.pos); err != nil {
+			file.Close()
+			return err
+		}
+
+		frame, _ := encode([]*op{{kind: opPut, key: k, value: value, expiry: e.expiry, version: e.version}})
+		if _, err := file.Write(frame); err != nil {
+			file.Close()
+			return err
+		}
+	}
+
+	// versions are never reused, even those of deleted records
+	frame, _ := encode([]*op{{kind: opRevision, version: f.revision}})
+	if _, err := file.Write(frame); err != nil {
+		file.Close()
+		return err
+	}
+
+	if err := file.Sync(); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+
+	// the current data file is kept until the compacted one is loaded
+	old, size, stale, index, revision := f.file, f.size, f.stale, f.index, f.revision
+	restore := func() {
+		f.file, f.size, f.stale, f.index, f.revision = old, size, stale, index, revision
+	}
+
+	if err := f.load(tmp); err != nil {
+		restore()
+		return err
+	}
+
+	// the rename swaps the data file atomically, the loaded file handle
+	// stays valid across it
+	if err := os.Rename(tmp, f.path); err != nil {
+		f.file.Close()
+		restore()
+		return err
+	}
+	if dir, err := os.Open(filepath.Dir(f.path)); err == nil {
+		dir.Sync()
+		dir.Close()
+	}
+
+	old.Close()
+	return nil
+}
+
+// needsCompaction reports whether stale and expired records make up enough of the file
+func (f *fileStore) needsCompaction() bool {
+	f.RLock()
+	defer f.RUnlock()
+
+	if f.check() != nil || f.size == 0 {
+		return false
+	}
+
+	stale := f.stale
+	for _, e := range f.index {
+		if e.expired() {
+			stale += e.size
+		}
+	}
+
+	return float64(stale)/float64(f.size) >= f.ratio
+}
+
+func (f *fileStore) run(interval time.Duration) {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-f.exit:
+			return
+		case <-t.C:
+			if !f.needsCompaction() {
+				continue
+			}
+			if err := f.Compact(); err != nil {
+				log.Errorf("Error compacting store %s: %v", f.path, err)
+			}
+		}
+	}
+}
+
+// Close stops compaction and closes the data file
+func (f *fileStore) Close() error {
+	f.Lock()
+	defer f.Unlock()
+
+	if f.closed {
+		return nil
+	}
+	f.closed = true
+	close(f.exit)
+	f.watchers.Stop()
+
+	return f.release()
+}
+
+// Init sets the options and opens the data file of the namespace, should it
+// have changed
+func (f *fileStore) Init(opts ...options.Option) error {
+	if err := f.Options.Init(opts...); err != nil {
+		return err
+	}
+
+	f.Lock()
+	defer f.Unlock()
+
+	if f.closed {
+		return ErrClosed
+	}
+
+	if path := f.configure(); path != f.path || f.err != nil {
+		return f.reopen(path)
+	}
+	return nil
+}
+
+func (f *fileStore) String() string {
+	return "file"
+}
+
+// NewStore returns a store.Store persisted to a data file named after the
+// namespace in the directory set with Dir. The data file is locked so only
+// one store opens it at a time. The returned store implements store.Versioned
+// and io.Closer to stop compaction and release the file.
+func NewStore(opts ...options.Option) store.Store {
+	f := &fileStore{
+		Options: options.NewOptions(opts...),
+		exit:    make(chan bool),
+	}
+
+	interval := DefaultCompactInterval
+	if v, ok := f.Options.Values().Get("store.file.compact_interval"); ok {
+		interval = v.(time.Duration)
+	}
+
+	// operations return the error if the data file can't be opened
+	f.reopen(f.configure())
+
+	go f.run(interval)
+
+	return f
+}
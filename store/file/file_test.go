@@ -0,0 +1,289 @@
+package file
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stack-labs/stack/store"
+)
+
+func TestFileStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "store")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	s := NewStore(Dir(dir), store.Namespace("test"))
+
+	if err := s.Write(
+		&store.Record{Key: "a/1", Value: []byte("1")},
+		&store.Record{Key: "a/2", Value: []byte("2")},
+		&store.Record{Key: "b/1", Value: []byte("3")},
+		&store.Record{Key: "tmp", Value: []byte("4"), Expiry: 50 * time.Millisecond},
+	); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Write(&store.Record{Key: "a/2", Value: []byte("22")}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Delete("b/1"); err != nil {
+		t.Fatal(err)
+	}
+
+	recs, err := s.Read("a/", store.ReadPrefix())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(recs) != 2 || string(recs[1].Value) != "22" {
+		t.Fatalf("Unexpected prefix read %v", recs)
+	}
+	if _, err := s.Read("b/1"); err != store.ErrNotFound {
+		t.Fatalf("Expected deleted key to be not found got %v", err)
+	}
+	if recs, err := s.Read("tmp"); err != nil || recs[0].Expiry <= 0 {
+		t.Fatalf("Expected expiring record got %v %v", recs, err)
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	if _, err := s.Read("tmp"); err != store.ErrNotFound {
+		t.Fatalf("Expected expired key to be not found got %v", err)
+	}
+
+	// namespaces are kept in separate data files
+	other := NewStore(Dir(dir), store.Namespace("other"))
+	if recs, _ := other.List(); len(recs) != 0 {
+		t.Fatalf("Expected an empty namespace got %v", recs)
+	}
+	other.(io.Closer).Close()
+
+	s.(io.Closer).Close()
+	if _, err := s.Read("a/1"); err != ErrClosed {
+		t.Fatalf("Expected closed error got %v", err)
+	}
+
+	// a torn frame at the tail is dropped on reopen
+	path := filepath.Join(dir, "test.db")
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Write([]byte{0, 0, 0, 40, 1, 2, 3})
+	f.Close()
+
+	s = NewStore(Dir(dir), store.Namespace("test"))
+	defer s.(io.Closer).Close()
+
+	recs, err = s.List(store.ListKeysOnly())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(recs) != 2 || recs[0].Key != "a/1" || recs[1].Key != "a/2" || recs[0].Value != nil {
+		t.Fatalf("Unexpected records after reopen %v", recs)
+	}
+
+	before, _ := os.Stat(path)
+	if err := s.(*fileStore).Compact(); err != nil {
+		t.Fatal(err)
+	}
+	after, _ := os.Stat(path)
+	if after.Size() >= before.Size() {
+		t.Fatalf("Expected compaction to shrink the data file from %d got %d", before.Size(), after.Size())
+	}
+
+	if recs, err := s.Read("a/2"); err != nil || string(recs[0].Value) != "22" {
+		t.Fatalf("Unexpected record after compaction %v %v", recs, err)
+	}
+	if err := s.Write(&store.Record{Key: "c", Value: []byte("5")}); err != nil {
+		t.Fatal(err)
+	}
+	if recs, _ := s.List(); len(recs) != 3 {
+		t.Fatalf("Expected 3 records got %d", len(recs))
+	}
+}
+
+func TestFileStorePrefix(t *testing.T) {
+	dir, err := ioutil.TempDir("", "store")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	a := NewStore(Dir(dir), store.Prefix("a/"))
+	defer a.(io.Closer).Close()
+
+	if err := a.Write(&store.Record{Key: "1", Value: []byte("1")}); err != nil {
+		t.Fatal(err)
+	}
+
+	recs, err := a.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(recs) != 1 || recs[0].Key != "1" {
+		t.Fatalf("Expected keys without the store prefix got %v", recs)
+	}
+}
+
+func TestFileStoreVersioned(t *testing.T) {
+	dir, err := ioutil.TempDir("", "store")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	s := NewStore(Dir(dir)).(store.Versioned)
+
+	// a second store can't open the locked data file
+	locked := NewStore(Dir(dir))
+	if _, err := locked.List(); err == nil {
+		t.Fatal("Expected the data file to be locked")
+	}
+	locked.(io.Closer).Close()
+
+	w, err := s.Watch("a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Stop()
+
+	r := &store.Record{Key: "a", Value: []byte("1")}
+	if err := s.CompareAndSwap(r); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.CompareAndSwap(&store.Record{Key: "a", Value: []byte("2")}); !store.IsConflict(err) {
+		t.Fatalf("Expected a conflict got %v", err)
+	}
+	version := r.Version
+	if err := s.CompareAndSwap(r); err != nil {
+		t.Fatal(err)
+	}
+	if r.Version <= version {
+		t.Fatalf("Expected a new version after %d got %d", version, r.Version)
+	}
+	if err := s.Delete("a"); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, typ := range []store.EventType{store.Put, store.Put, store.Delete} {
+		e, err := w.Next()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if e.Type != typ || e.Record.Key != "a" {
+			t.Fatalf("Unexpected event %v %+v", e.Type, e.Record)
+		}
+	}
+
+	if err := s.Write(&store.Record{Key: "b", Value: []byte("1")}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Delete("b"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.(*fileStore).Compact(); err != nil {
+		t.Fatal(err)
+	}
+	revision := s.(*fileStore).revision
+	s.(io.Closer).Close()
+
+	// versions survive reopening a compacted data file
+	s = NewStore(Dir(dir)).(store.Versioned)
+	defer s.(io.Closer).Close()
+
+	if got := s.(*fileStore).revision; got != revision {
+		t.Fatalf("Expected revision %d got %d", revision, got)
+	}
+	r = &store.Record{Key: "c", Value: []byte("1")}
+	if err := s.CompareAndSwap(r); err != nil {
+		t.Fatal(err)
+	}
+	if r.Version != revision+1 {
+		t.Fatalf("Expected version %d got %d", revision+1, r.Version)
+	}
+	if recs, err := s.Read("c"); err != nil || recs[0].Version != r.Version {
+		t.Fatalf("Unexpected record %v %v", recs, err)
+	}
+}
+
+func TestFileStoreCorrupt(t *testing.T) {
+	dir, err := ioutil.TempDir("", "store")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	s := NewStore(Dir(dir))
+	for _, k := range []string{"a", "b", "c"} {
+		if err := s.Write(&store.Record{Key: k, Value: []byte(k)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	s.(io.Closer).Close()
+
+	// flip a byte of the first frame
+	path := filepath.Join(dir, DefaultNamespace+".db")
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b[frameHeaderSize+opHeaderSize] ^= 0xff
+	if err := ioutil.WriteFile(path, b, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	s = NewStore(Dir(dir))
+	defer s.(io.Closer).Close()
+
+	if _, err := s.List(); err == nil {
+		t.Fatal("Expected the corrupt data file to fail")
+	}
+	if fi, err := os.Stat(path); err != nil || fi.Size() != int64(len(b)) {
+		t.Fatalf("Expected the data file to be kept got %v %v", fi, err)
+	}
+}
+
+func TestFileStoreInit(t *testing.T) {
+	dir, err := ioutil.TempDir("", "store")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	s := NewStore(Dir(dir), store.Namespace("a"))
+	defer s.(io.Closer).Close()
+
+	if err := s.Write(&store.Record{Key: "1", Value: []byte("1")}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.(*fileStore).Init(store.Namespace("b"), store.Prefix("p/")); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Write(&store.Record{Key: "2", Value: []byte("2")}); err != nil {
+		t.Fatal(err)
+	}
+
+	recs, err := s.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(recs) != 1 || recs[0].Key != "2" {
+		t.Fatalf("Expected the records of the new namespace got %v", recs)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "b.db")); err != nil {
+		t.Fatalf("Expected the data file of the new namespace got %v", err)
+	}
+
+	// the data file of the old namespace is released
+	a := NewStore(Dir(dir), store.Namespace("a"))
+	defer a.(io.Closer).Close()
+
+	if recs, err := a.Read("1"); err != nil || len(recs) != 1 {
+		t.Fatalf("Expected the record of the old namespace got %v %v", recs, err)
+	}
+}
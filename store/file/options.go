@@ -0,0 +1,27 @@
+package file
+
+import (
+	"time"
+
+	"github.com/stack-labs/stack/util/options"
+)
+
+// Dir sets the directory the data files are kept in
+func Dir(d string) options.Option {
+	return options.WithValue("store.file.dir", d)
+}
+
+// SyncWrites fsyncs the data file after every write, enabled by default
+func SyncWrites(b bool) options.Option {
+	return options.WithValue("store.file.sync", b)
+}
+
+// CompactInterval sets how often the data file is checked for compaction
+func CompactInterval(d time.Duration) options.Option {
+	return options.WithValue("store.file.compact_interval", d)
+}
+
+// CompactRatio sets the share of stale bytes above which the data file is compacted
+func CompactRatio(r float64) options.Option {
+	return options.WithValue("store.file.compact_ratio", r)
+}
@@ -0,0 +1,137 @@
+// Package watch fans the changes of a store out to its watchers. Changes are
+// queued without blocking the store, a watcher whose buffer fills up is
+// stopped with store.ErrWatcherLagging so that it never silently misses one.
+package watch
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/stack-labs/stack/store"
+)
+
+// BufferSize is the number of changes queued for a watcher
+var BufferSize = 128
+
+// Watchers of a store
+type Watchers struct {
+	sync.Mutex
+	watchers map[*watcher]bool
+
+	// order is held from Notify until the changes are sent
+	order sync.Mutex
+}
+
+type watcher struct {
+	w      *Watchers
+	prefix string
+	res    chan *store.Event
+
+	once sync.Once
+	exit chan bool
+	err  error
+}
+
+// Watch returns a watcher of the changes to keys with the prefix
+func (w *Watchers) Watch(prefix string) store.Watcher {
+	wt := &watcher{
+		w:      w,
+		prefix: prefix,
+		res:    make(chan *store.Event, BufferSize),
+		exit:   make(chan bool),
+	}
+
+	w.Lock()
+	if w.watchers == nil {
+		w.watchers = make(map[*watcher]bool)
+	}
+	w.watchers[wt] = true
+	w.Unlock()
+
+	return wt
+}
+
+// Notify is called with the store lock held, it returns the func sending the
+// events which is called once the lock is released. Events are delivered in
+// the order of the Notify calls.
+func (w *Watchers) Notify(events ...*store.Event) func() {
+	if len(events) == 0 {
+		return func() {}
+	}
+
+	w.order.Lock()
+
+	return func() {
+		defer w.order.Unlock()
+
+		w.Lock()
+		defer w.Unlock()
+
+		for wt := range w.watchers {
+			for _, e := range events {
+				if !strings.HasPrefix(e.Record.Key, wt.prefix) {
+					continue
+				}
+				select {
+				case wt.res <- e:
+				default:
+					wt.stop(store.ErrWatcherLagging)
+					delete(w.watchers, wt)
+				}
+				if wt.stopped() {
+					break
+				}
+			}
+		}
+	}
+}
+
+// Stop stops all the watchers
+func (w *Watchers) Stop() {
+	w.Lock()
+	defer w.Unlock()
+
+	for wt := range w.watchers {
+		wt.stop(store.ErrWatcherStopped)
+		delete(w.watchers, wt)
+	}
+}
+
+func (wt *watcher) stop(err error) {
+	wt.once.Do(func() {
+		wt.err = err
+		close(wt.exit)
+	})
+}
+
+func (wt *watcher) stopped() bool {
+	select {
+	case <-wt.exit:
+		return true
+	default:
+		return false
+	}
+}
+
+// Next returns the queued changes before the error the watcher stopped with
+func (wt *watcher) Next() (*store.Event, error) {
+	select {
+	case e := <-wt.res:
+		return e, nil
+	case <-wt.exit:
+		select {
+		case e := <-wt.res:
+			return e, nil
+		default:
+			return nil, wt.err
+		}
+	}
+}
+
+func (wt *watcher) Stop() {
+	wt.stop(store.ErrWatcherStopped)
+
+	wt.w.Lock()
+	delete(wt.w.watchers, wt)
+	wt.w.Unlock()
+}
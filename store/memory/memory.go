@@ -2,10 +2,13 @@
 package memory
 
 import (
+	"sort"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/stack-labs/stack/store"
+	"github.com/stack-labs/stack/store/internal/watch"
 	"github.com/stack-labs/stack/util/options"
 )
 
@@ -13,108 +16,270 @@ type memoryStore struct {
 	options.Options
 
 	sync.RWMutex
+	// prefix scopes the keys to the namespace and prefix options
+	prefix string
 	values map[string]*memoryRecord
+	// revision is incremented on every change
+	revision uint64
+	watchers watch.Watchers
 }
 
+// memoryRecord holds a copy of the record written, records are copied on the
+// way in and out so callers never share the stored version
 type memoryRecord struct {
 	r *store.Record
 	c time.Time
 }
 
-func (m *memoryStore) List() ([]*store.Record, error) {
+func copyRecord(r *store.Record) *store.Record {
+	c := *r
+	if r.Value != nil {
+		c.Value = make([]byte, len(r.Value))
+		copy(c.Value, r.Value)
+	}
+	return &c
+}
+
+func (m *memoryStore) List(opts ...store.ListOption) ([]*store.Record, error) {
+	var options store.ListOptions
+	for _, o := range opts {
+		o(&options)
+	}
+
 	m.RLock()
 	defer m.RUnlock()
 
-	//nolint:prealloc
-	var values []*store.Record
-
-	for _, v := range m.values {
-		// get expiry
-		d := v.r.Expiry
-		t := time.Since(v.c)
+	records := m.match(func(key string) bool {
+		return strings.HasPrefix(key, options.Prefix) && strings.HasSuffix(key, options.Suffix)
+	}, options.Offset, options.Limit)
 
-		if d > time.Duration(0) {
-			// expired
-			if t > d {
-				continue
-			}
-			// update expiry
-			v.r.Expiry -= t
-			v.c = time.Now()
+	if options.KeysOnly {
+		for i, r := range records {
+			records[i] = &store.Record{Key: r.Key}
 		}
-
-		values = append(values, v.r)
 	}
 
-	return values, nil
+	return records, nil
 }
 
-func (m *memoryStore) Read(keys ...string) ([]*store.Record, error) {
+func (m *memoryStore) Read(key string, opts ...store.ReadOption) ([]*store.Record, error) {
+	var options store.ReadOptions
+	for _, o := range opts {
+		o(&options)
+	}
+
 	m.RLock()
 	defer m.RUnlock()
 
+	if !options.Prefix && !options.Suffix {
+		r, ok := m.get(key)
+		if !ok {
+			return nil, store.ErrNotFound
+		}
+		return []*store.Record{r}, nil
+	}
+
+	return m.match(func(k string) bool {
+		if options.Prefix && !strings.HasPrefix(k, key) {
+			return false
+		}
+		if options.Suffix && !strings.HasSuffix(k, key) {
+			return false
+		}
+		return true
+	}, options.Offset, options.Limit), nil
+}
+
+// match returns the unexpired records with matching keys ordered by key
+func (m *memoryStore) match(fn func(key string) bool, offset, limit uint) []*store.Record {
 	//nolint:prealloc
-	var records []*store.Record
+	var keys []string
+	for k := range m.values {
+		if !strings.HasPrefix(k, m.prefix) {
+			continue
+		}
+		if k = strings.TrimPrefix(k, m.prefix); fn(k) {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
 
-	for _, key := range keys {
-		v, ok := m.values[key]
+	//nolint:prealloc
+	var records []*store.Record
+	for _, k := range keys {
+		r, ok := m.get(k)
 		if !ok {
-			return nil, store.ErrNotFound
+			continue
+		}
+		if offset > 0 {
+			offset--
+			continue
 		}
+		records = append(records, r)
+		if limit > 0 && uint(len(records)) == limit {
+			break
+		}
+	}
 
-		// get expiry
-		d := v.r.Expiry
-		t := time.Since(v.c)
+	return records
+}
+
+// get returns a copy of the record with key unless it has expired
+func (m *memoryStore) get(key string) (*store.Record, bool) {
+	v, ok := m.values[m.prefix+key]
+	if !ok {
+		return nil, false
+	}
 
+	// get expiry
+	d := v.r.Expiry
+	t := time.Since(v.c)
+
+	if d > time.Duration(0) {
 		// expired
-		if d > time.Duration(0) {
-			if t > d {
-				return nil, store.ErrNotFound
-			}
-			// update expiry
-			v.r.Expiry -= t
-			v.c = time.Now()
+		if t > d {
+			return nil, false
 		}
-
-		records = append(records, v.r)
+		// update expiry
+		v.r.Expiry -= t
+		v.c = time.Now()
 	}
 
-	return records, nil
+	return copyRecord(v.r), true
 }
 
 func (m *memoryStore) Write(records ...*store.Record) error {
 	m.Lock()
-	defer m.Unlock()
 
+	events := make([]*store.Event, 0, len(records))
 	for _, r := range records {
-		// set the record
-		m.values[r.Key] = &memoryRecord{
-			r: r,
-			c: time.Now(),
+		events = append(events, m.put(r))
+	}
+
+	send := m.watchers.Notify(events...)
+	m.Unlock()
+	send()
+
+	return nil
+}
+
+func (m *memoryStore) CompareAndSwap(records ...*store.Record) error {
+	m.Lock()
+
+	for _, r := range records {
+		var current uint64
+		if v, ok := m.get(r.Key); ok {
+			current = v.Version
+		}
+		if current != r.Version {
+			m.Unlock()
+			return &store.ConflictError{
+				Key:      r.Key,
+				Expected: r.Version,
+				Current:  current,
+			}
 		}
 	}
 
+	events := make([]*store.Event, 0, len(records))
+	for _, r := range records {
+		events = append(events, m.put(r))
+	}
+
+	send := m.watchers.Notify(events...)
+	m.Unlock()
+	send()
+
 	return nil
 }
 
+// put stores a copy of the record at the next revision and sets the version
+// of the record written
+func (m *memoryStore) put(r *store.Record) *store.Event {
+	m.revision++
+	r.Version = m.revision
+
+	// set the record
+	m.values[m.prefix+r.Key] = &memoryRecord{
+		r: copyRecord(r),
+		c: time.Now(),
+	}
+
+	return &store.Event{
+		Type:   store.Put,
+		Record: copyRecord(r),
+	}
+}
+
 func (m *memoryStore) Delete(keys ...string) error {
 	m.Lock()
-	defer m.Unlock()
 
+	//nolint:prealloc
+	var events []*store.Event
 	for _, key := range keys {
+		if _, ok := m.values[m.prefix+key]; !ok {
+			continue
+		}
+
 		// delete the value
-		delete(m.values, key)
+		delete(m.values, m.prefix+key)
+		m.revision++
+
+		events = append(events, &store.Event{
+			Type: store.Delete,
+			Record: &store.Record{
+				Key:     key,
+				Version: m.revision,
+			},
+		})
 	}
 
+	send := m.watchers.Notify(events...)
+	m.Unlock()
+	send()
+
 	return nil
 }
 
-// NewStore returns a new store.Store
+// Watch the changes to records with keys that have the prefix, a watcher
+// falling behind is stopped with store.ErrWatcherLagging
+func (m *memoryStore) Watch(prefix string) (store.Watcher, error) {
+	return m.watchers.Watch(prefix), nil
+}
+
+// Init the options, the keys of a new namespace or prefix are isolated
+// from those written before
+func (m *memoryStore) Init(opts ...options.Option) error {
+	if err := m.Options.Init(opts...); err != nil {
+		return err
+	}
+
+	m.Lock()
+	m.prefix = scope(m.Options)
+	m.Unlock()
+	return nil
+}
+
+// scope returns the key prefix of the namespace and prefix options
+func scope(o options.Options) string {
+	var prefix string
+	if v, ok := o.Values().Get("store.namespace"); ok && len(v.(string)) > 0 {
+		prefix = v.(string) + "/"
+	}
+	if v, ok := o.Values().Get("store.prefix"); ok {
+		prefix += v.(string)
+	}
+	return prefix
+}
+
+// NewStore returns a new store.Store, keys are scoped to the namespace
+// and prefix set with store.Namespace and store.Prefix
 func NewStore(opts ...options.Option) store.Store {
 	options := options.NewOptions(opts...)
 
 	return &memoryStore{
 		Options: options,
+		prefix:  scope(options),
 		values:  make(map[string]*memoryRecord),
 	}
 }
@@ -1,6 +1,7 @@
 package memory
 
 import (
+	"strings"
 	"testing"
 	"time"
 
@@ -35,3 +36,231 @@ func TestReadRecordExpire(t *testing.T) {
 		t.Fatal("expire elapsed, but key still accessable")
 	}
 }
+
+func TestQueryOptions(t *testing.T) {
+	s := NewStore()
+
+	for _, k := range []string{"a/1", "a/2", "a/3", "b/1", "b/2.json"} {
+		s.Write(&store.Record{Key: k, Value: []byte(k)})
+	}
+
+	keys := func(recs []*store.Record) string {
+		var ks []string
+		for _, r := range recs {
+			ks = append(ks, r.Key)
+		}
+		return strings.Join(ks, ",")
+	}
+
+	testData := []struct {
+		name string
+		opts []store.ListOption
+		want string
+	}{
+		{"all", nil, "a/1,a/2,a/3,b/1,b/2.json"},
+		{"prefix", []store.ListOption{store.ListPrefix("a/")}, "a/1,a/2,a/3"},
+		{"suffix", []store.ListOption{store.ListSuffix(".json")}, "b/2.json"},
+		{"limit", []store.ListOption{store.ListPrefix("a/"), store.ListLimit(2)}, "a/1,a/2"},
+		{"offset", []store.ListOption{store.ListPrefix("a/"), store.ListLimit(2), store.ListOffset(2)}, "a/3"},
+	}
+
+	for _, d := range testData {
+		recs, err := s.List(d.opts...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := keys(recs); got != d.want {
+			t.Fatalf("%s: expected %s got %s", d.name, d.want, got)
+		}
+	}
+
+	recs, _ := s.List(store.ListPrefix("b/"), store.ListKeysOnly())
+	if len(recs) != 2 || recs[0].Value != nil {
+		t.Fatalf("expected keys only records got %v", recs)
+	}
+
+	recs, err := s.Read("b/", store.ReadPrefix())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := keys(recs); got != "b/1,b/2.json" {
+		t.Fatalf("expected prefix read of b/ got %s", got)
+	}
+
+	recs, _ = s.Read("/1", store.ReadSuffix(), store.ReadOffset(1))
+	if got := keys(recs); got != "b/1" {
+		t.Fatalf("expected suffix read with offset got %s", got)
+	}
+
+	if _, err := s.Read("c"); err != store.ErrNotFound {
+		t.Fatalf("expected not found got %v", err)
+	}
+}
+
+func TestCompareAndSwap(t *testing.T) {
+	s := NewStore().(store.Versioned)
+
+	r := &store.Record{Key: "lock", Value: []byte("a")}
+	if err := s.CompareAndSwap(r); err != nil {
+		t.Fatalf("expected create of absent key got %v", err)
+	}
+	if r.Version == 0 {
+		t.Fatal("expected the record version to be set")
+	}
+
+	// a second create conflicts
+	err := s.CompareAndSwap(&store.Record{Key: "lock", Value: []byte("b")})
+	if c, ok := err.(*store.ConflictError); !ok || c.Current != r.Version || c.Expected != 0 {
+		t.Fatalf("expected a conflict got %v", err)
+	}
+
+	// updates with the read version succeed once
+	recs, _ := s.Read("lock")
+	update := &store.Record{Key: "lock", Value: []byte("c"), Version: recs[0].Version}
+	if err := s.CompareAndSwap(update); err != nil {
+		t.Fatal(err)
+	}
+	if update.Version <= r.Version {
+		t.Fatalf("expected the version to increase from %d got %d", r.Version, update.Version)
+	}
+	stale := &store.Record{Key: "lock", Value: []byte("d"), Version: r.Version}
+	if err := s.CompareAndSwap(stale); !store.IsConflict(err) {
+		t.Fatalf("expected a stale version to conflict got %v", err)
+	}
+
+	// nothing is written when any record conflicts
+	if err := s.CompareAndSwap(
+		&store.Record{Key: "other", Value: []byte("x")},
+		&store.Record{Key: "lock", Value: []byte("e")},
+	); !store.IsConflict(err) {
+		t.Fatalf("expected a conflict got %v", err)
+	}
+	if _, err := s.Read("other"); err != store.ErrNotFound {
+		t.Fatal("expected the conflicting swap to write nothing")
+	}
+}
+
+func TestWatch(t *testing.T) {
+	s := NewStore().(store.Versioned)
+
+	w, err := s.Watch("app/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Stop()
+
+	s.Write(&store.Record{Key: "other", Value: []byte("1")})
+	s.Write(&store.Record{Key: "app/a", Value: []byte("2")})
+	s.Delete("app/a")
+
+	e, err := w.Next()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e.Type != store.Put || e.Record.Key != "app/a" || string(e.Record.Value) != "2" {
+		t.Fatalf("unexpected event %s %+v", e.Type, e.Record)
+	}
+
+	e, err = w.Next()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e.Type != store.Delete || e.Record.Key != "app/a" {
+		t.Fatalf("unexpected event %s %+v", e.Type, e.Record)
+	}
+
+	w.Stop()
+	if _, err := w.Next(); err != store.ErrWatcherStopped {
+		t.Fatalf("expected stopped watcher got %v", err)
+	}
+}
+
+func TestCompareAndSwapSharedRecord(t *testing.T) {
+	s := NewStore().(store.Versioned)
+
+	if err := s.Write(&store.Record{Key: "k", Value: []byte("a")}); err != nil {
+		t.Fatal(err)
+	}
+
+	// two holders of the same record read at the same version
+	recs, _ := s.Read("k")
+	r := recs[0]
+	other, _ := s.Read("k")
+	if other[0] == r {
+		t.Fatal("expected reads to return copies")
+	}
+
+	r.Value = []byte("b")
+	if err := s.CompareAndSwap(r); err != nil {
+		t.Fatal(err)
+	}
+	other[0].Value = []byte("c")
+	if err := s.CompareAndSwap(other[0]); !store.IsConflict(err) {
+		t.Fatalf("expected the second swap to conflict got %v", err)
+	}
+
+	// the written record is not shared with the store
+	r.Value[0] = 'x'
+	if recs, _ := s.Read("k"); string(recs[0].Value) != "b" {
+		t.Fatalf("expected the stored value to be kept got %s", recs[0].Value)
+	}
+}
+
+func TestWatchLagging(t *testing.T) {
+	s := NewStore().(store.Versioned)
+
+	w, err := s.Watch("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Stop()
+
+	// writes never wait on a watcher which isn't reading
+	for i := 0; i < 200; i++ {
+		s.Write(&store.Record{Key: "k", Value: []byte("v")})
+	}
+
+	for {
+		_, err := w.Next()
+		if err == store.ErrWatcherLagging {
+			break
+		}
+		if err != nil {
+			t.Fatalf("expected a lagging watcher got %v", err)
+		}
+	}
+}
+
+func TestNamespace(t *testing.T) {
+	s := NewStore(store.Namespace("a"), store.Prefix("p/"))
+	if err := s.Write(&store.Record{Key: "foo", Value: []byte("a")}); err != nil {
+		t.Fatal(err)
+	}
+
+	recs, err := s.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(recs) != 1 || recs[0].Key != "foo" {
+		t.Fatalf("expected the key without the namespace and prefix, got %v", recs)
+	}
+
+	// a new namespace does not see the keys of the last one
+	if err := s.(*memoryStore).Init(store.Namespace("b")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Read("foo"); err != store.ErrNotFound {
+		t.Fatalf("expected foo not to be found in namespace b, got %v", err)
+	}
+	if recs, _ := s.List(); len(recs) != 0 {
+		t.Fatalf("expected namespace b to be empty, got %v", recs)
+	}
+
+	if err := s.(*memoryStore).Init(store.Namespace("a")); err != nil {
+		t.Fatal(err)
+	}
+	recs, err = s.Read("foo")
+	if err != nil || string(recs[0].Value) != "a" {
+		t.Fatalf("expected foo in namespace a, got %v %v", recs, err)
+	}
+}
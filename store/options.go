@@ -19,3 +19,98 @@ func Prefix(p string) options.Option {
 func Namespace(n string) options.Option {
 	return options.WithValue("store.namespace", n)
 }
+
+// ReadOptions configures an individual Read operation
+type ReadOptions struct {
+	// Prefix returns all records that are prefixed with key
+	Prefix bool
+	// Suffix returns all records that have the suffix key
+	Suffix bool
+	// Limit limits the number of returned records
+	Limit uint
+	// Offset when combined with Limit supports pagination
+	Offset uint
+}
+
+// ReadOption sets values in ReadOptions
+type ReadOption func(r *ReadOptions)
+
+// ReadPrefix returns all records that are prefixed with key
+func ReadPrefix() ReadOption {
+	return func(r *ReadOptions) {
+		r.Prefix = true
+	}
+}
+
+// ReadSuffix returns all records that have the suffix key
+func ReadSuffix() ReadOption {
+	return func(r *ReadOptions) {
+		r.Suffix = true
+	}
+}
+
+// ReadLimit limits the number of records returned
+func ReadLimit(l uint) ReadOption {
+	return func(r *ReadOptions) {
+		r.Limit = l
+	}
+}
+
+// ReadOffset starts returning records from the offset, records are ordered by key
+func ReadOffset(o uint) ReadOption {
+	return func(r *ReadOptions) {
+		r.Offset = o
+	}
+}
+
+// ListOptions configures an individual List operation
+type ListOptions struct {
+	// Prefix returns only records with the prefix
+	Prefix string
+	// Suffix returns only records with the suffix
+	Suffix string
+	// Limit limits the number of returned records
+	Limit uint
+	// Offset when combined with Limit supports pagination
+	Offset uint
+	// KeysOnly leaves the values of the returned records empty
+	KeysOnly bool
+}
+
+// ListOption sets values in ListOptions
+type ListOption func(l *ListOptions)
+
+// ListPrefix returns only records with the prefix
+func ListPrefix(p string) ListOption {
+	return func(l *ListOptions) {
+		l.Prefix = p
+	}
+}
+
+// ListSuffix returns only records with the suffix
+func ListSuffix(s string) ListOption {
+	return func(l *ListOptions) {
+		l.Suffix = s
+	}
+}
+
+// ListLimit limits the number of records returned
+func ListLimit(n uint) ListOption {
+	return func(l *ListOptions) {
+		l.Limit = n
+	}
+}
+
+// ListOffset starts returning records from the offset, records are ordered by key
+func ListOffset(o uint) ListOption {
+	return func(l *ListOptions) {
+		l.Offset = o
+	}
+}
+
+// ListKeysOnly returns records with only their keys set
+func ListKeysOnly() ListOption {
+	return func(l *ListOptions) {
+		l.KeysOnly = true
+	}
+}
@@ -3,6 +3,7 @@ package handler
 import (
 	"context"
 	"io"
+	"net/http"
 	"time"
 
 	"github.com/stack-labs/stack/store"
@@ -10,21 +11,40 @@ import (
 	"github.com/stack-labs/stack/util/errors"
 )
 
+// DefaultBatchSize is the number of records sent per List response
+var DefaultBatchSize = 100
+
 type Store struct {
 	Store store.Store
 }
 
 func (s *Store) Read(ctx context.Context, req *pb.ReadRequest, rsp *pb.ReadResponse) error {
-	vals, err := s.Store.Read(req.Keys...)
-	if err != nil {
-		return errors.InternalServerError("stack.rpc.store", err.Error())
+	var opts []store.ReadOption
+	if o := req.Options; o != nil {
+		if o.Prefix {
+			opts = append(opts, store.ReadPrefix())
+		}
+		if o.Suffix {
+			opts = append(opts, store.ReadSuffix())
+		}
+		opts = append(opts, store.ReadLimit(uint(o.Limit)), store.ReadOffset(uint(o.Offset)))
 	}
-	for _, val := range vals {
-		rsp.Records = append(rsp.Records, &pb.Record{
-			Key:    val.Key,
-			Value:  val.Value,
-			Expiry: int64(val.Expiry.Seconds()),
-		})
+
+	for _, key := range req.Keys {
+		vals, err := s.Store.Read(key, opts...)
+		if err == store.ErrNotFound {
+			return errors.NotFound("stack.rpc.store", err.Error())
+		} else if err != nil {
+			return errors.InternalServerError("stack.rpc.store", err.Error())
+		}
+		for _, val := range vals {
+			rsp.Records = append(rsp.Records, &pb.Record{
+				Key:     val.Key,
+				Value:   val.Value,
+				Expiry:  int64(val.Expiry.Seconds()),
+				Version: val.Version,
+			})
+		}
 	}
 	return nil
 }
@@ -44,6 +64,9 @@ func (s *Store) Write(ctx context.Context, req *pb.WriteRequest, rsp *pb.WriteRe
 	if err != nil {
 		return errors.InternalServerError("stack.rpc.store", err.Error())
 	}
+	for _, record := range records {
+		rsp.Versions = append(rsp.Versions, record.Version)
+	}
 	return nil
 }
 
@@ -59,31 +82,143 @@ func (s *Store) List(ctx context.Context, req *pb.ListRequest, stream pb.Store_L
 	var vals []*store.Record
 	var err error
 
+	// a key lists the record of the key, the options prefix lists the records it prefixes
 	if len(req.Key) > 0 {
 		vals, err = s.Store.Read(req.Key)
 	} else {
-		vals, err = s.Store.List()
+		var opts []store.ListOption
+		if o := req.Options; o != nil {
+			opts = append(opts,
+				store.ListPrefix(o.Prefix),
+				store.ListSuffix(o.Suffix),
+				store.ListLimit(uint(o.Limit)),
+				store.ListOffset(uint(o.Offset)),
+			)
+			if o.KeysOnly {
+				opts = append(opts, store.ListKeysOnly())
+			}
+		}
+		vals, err = s.Store.List(opts...)
 	}
 	if err != nil {
 		return errors.InternalServerError("stack.rpc.store", err.Error())
 	}
-	rsp := new(pb.ListResponse)
 
-	// TODO: batch sync
-	for _, val := range vals {
-		rsp.Records = append(rsp.Records, &pb.Record{
-			Key:    val.Key,
-			Value:  val.Value,
-			Expiry: int64(val.Expiry.Seconds()),
+	batch := int(req.BatchSize)
+	if batch <= 0 {
+		batch = DefaultBatchSize
+	}
+
+	// stream the records in batches rather than a single response
+	for i := 0; i < len(vals) || i == 0; i += batch {
+		end := i + batch
+		if end > len(vals) {
+			end = len(vals)
+		}
+
+		rsp := new(pb.ListResponse)
+		for _, val := range vals[i:end] {
+			rsp.Records = append(rsp.Records, &pb.Record{
+				Key:     val.Key,
+				Value:   val.Value,
+				Expiry:  int64(val.Expiry.Seconds()),
+				Version: val.Version,
+			})
+		}
+
+		err = stream.Send(rsp)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return errors.InternalServerError("stack.rpc.store", err.Error())
+		}
+	}
+	return nil
+}
+
+func (s *Store) CompareAndSwap(ctx context.Context, req *pb.CompareAndSwapRequest, rsp *pb.CompareAndSwapResponse) error {
+	v, ok := s.Store.(store.Versioned)
+	if !ok {
+		return errors.New("stack.rpc.store", "store does not support versioned writes", http.StatusNotImplemented)
+	}
+
+	records := make([]*store.Record, 0, len(req.Records))
+	for _, record := range req.Records {
+		records = append(records, &store.Record{
+			Key:     record.Key,
+			Value:   record.Value,
+			Expiry:  time.Duration(record.Expiry) * time.Second,
+			Version: record.Version,
 		})
 	}
 
-	err = stream.Send(rsp)
-	if err == io.EOF {
+	err := v.CompareAndSwap(records...)
+	if c, ok := err.(*store.ConflictError); ok {
+		rsp.Conflict = &pb.Conflict{
+			Key:      c.Key,
+			Expected: c.Expected,
+			Current:  c.Current,
+		}
 		return nil
 	}
 	if err != nil {
 		return errors.InternalServerError("stack.rpc.store", err.Error())
 	}
+
+	for _, record := range records {
+		rsp.Versions = append(rsp.Versions, record.Version)
+	}
 	return nil
 }
+
+func (s *Store) Watch(ctx context.Context, req *pb.WatchRequest, stream pb.Store_WatchStream) error {
+	v, ok := s.Store.(store.Versioned)
+	if !ok {
+		return errors.New("stack.rpc.store", "store does not support watches", http.StatusNotImplemented)
+	}
+
+	w, err := v.Watch(req.Prefix)
+	if err != nil {
+		return errors.InternalServerError("stack.rpc.store", err.Error())
+	}
+	defer w.Stop()
+
+	// stop the watcher when the client goes away
+	exit := make(chan bool)
+	defer close(exit)
+
+	go func() {
+		select {
+		case <-stream.Context().Done():
+			w.Stop()
+		case <-exit:
+		}
+	}()
+
+	for {
+		e, err := w.Next()
+		if err == store.ErrWatcherStopped {
+			return nil
+		}
+		if err != nil {
+			return errors.InternalServerError("stack.rpc.store", err.Error())
+		}
+
+		err = stream.Send(&pb.WatchResponse{
+			Type: e.Type.String(),
+			Record: &pb.Record{
+				Key:     e.Record.Key,
+				Value:   e.Record.Value,
+				Expiry:  int64(e.Record.Expiry.Seconds()),
+				Version: e.Record.Version,
+			},
+		})
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return errors.InternalServerError("stack.rpc.store", err.Error())
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package handler
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stack-labs/stack/store"
+	"github.com/stack-labs/stack/store/memory"
+	pb "github.com/stack-labs/stack/store/service/proto"
+)
+
+type listStream struct {
+	pb.Store_ListStream
+	records []*pb.Record
+}
+
+func (l *listStream) Send(rsp *pb.ListResponse) error {
+	l.records = append(l.records, rsp.Records...)
+	return nil
+}
+
+func TestList(t *testing.T) {
+	s := memory.NewStore()
+	for _, key := range []string{"a/1", "a/2", "a/b/1", "b/1"} {
+		if err := s.Write(&store.Record{Key: key, Value: []byte(key)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	testData := []struct {
+		req  *pb.ListRequest
+		keys []string
+	}{
+		{&pb.ListRequest{}, []string{"a/1", "a/2", "a/b/1", "b/1"}},
+		{&pb.ListRequest{Options: &pb.ListOptions{Prefix: "a/"}}, []string{"a/1", "a/2", "a/b/1"}},
+		{&pb.ListRequest{Options: &pb.ListOptions{Prefix: "a/b/"}}, []string{"a/b/1"}},
+		{&pb.ListRequest{Options: &pb.ListOptions{Prefix: "a/", Limit: 1, Offset: 1, KeysOnly: true}}, []string{"a/2"}},
+		// the key lists the record of the key only
+		{&pb.ListRequest{Key: "a/1", Options: &pb.ListOptions{Prefix: "b/"}}, []string{"a/1"}},
+	}
+
+	h := &Store{Store: s}
+	for _, d := range testData {
+		stream := new(listStream)
+		if err := h.List(context.TODO(), d.req, stream); err != nil {
+			t.Fatal(err)
+		}
+
+		var keys []string
+		for _, r := range stream.records {
+			keys = append(keys, r.Key)
+		}
+		if len(keys) != len(d.keys) {
+			t.Fatalf("expected keys %v for %v, got %v", d.keys, d.req, keys)
+		}
+		for i := range keys {
+			if keys[i] != d.keys[i] {
+				t.Fatalf("expected keys %v for %v, got %v", d.keys, d.req, keys)
+			}
+		}
+	}
+}
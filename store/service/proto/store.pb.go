@@ -26,7 +26,9 @@ type Record struct {
 	// value in the record
 	Value []byte `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
 	// timestamp in unix seconds
-	Expiry               int64    `protobuf:"varint,3,opt,name=expiry,proto3" json:"expiry,omitempty"`
+	Expiry int64 `protobuf:"varint,3,opt,name=expiry,proto3" json:"expiry,omitempty"`
+	// revision of the store the record was last written at
+	Version              uint64   `protobuf:"varint,4,opt,name=version,proto3" json:"version,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -78,18 +80,93 @@ func (m *Record) GetExpiry() int64 {
 	return 0
 }
 
-type ReadRequest struct {
-	Keys                 []string `protobuf:"bytes,1,rep,name=keys,proto3" json:"keys,omitempty"`
+func (m *Record) GetVersion() uint64 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
+}
+
+type ReadOptions struct {
+	// return all records prefixed with the key
+	Prefix bool `protobuf:"varint,1,opt,name=prefix,proto3" json:"prefix,omitempty"`
+	// return all records with the key as suffix
+	Suffix bool `protobuf:"varint,2,opt,name=suffix,proto3" json:"suffix,omitempty"`
+	// limit the number of records returned
+	Limit uint64 `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`
+	// offset of the first record returned
+	Offset               uint64   `protobuf:"varint,4,opt,name=offset,proto3" json:"offset,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
 }
 
+func (m *ReadOptions) Reset()         { *m = ReadOptions{} }
+func (m *ReadOptions) String() string { return proto.CompactTextString(m) }
+func (*ReadOptions) ProtoMessage()    {}
+func (*ReadOptions) Descriptor() ([]byte, []int) {
+	return fileDescriptor_98bbca36ef968dfc, []int{1}
+}
+
+func (m *ReadOptions) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReadOptions.Unmarshal(m, b)
+}
+func (m *ReadOptions) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReadOptions.Marshal(b, m, deterministic)
+}
+func (m *ReadOptions) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReadOptions.Merge(m, src)
+}
+func (m *ReadOptions) XXX_Size() int {
+	return xxx_messageInfo_ReadOptions.Size(m)
+}
+func (m *ReadOptions) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReadOptions.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReadOptions proto.InternalMessageInfo
+
+func (m *ReadOptions) GetPrefix() bool {
+	if m != nil {
+		return m.Prefix
+	}
+	return false
+}
+
+func (m *ReadOptions) GetSuffix() bool {
+	if m != nil {
+		return m.Suffix
+	}
+	return false
+}
+
+func (m *ReadOptions) GetLimit() uint64 {
+	if m != nil {
+		return m.Limit
+	}
+	return 0
+}
+
+func (m *ReadOptions) GetOffset() uint64 {
+	if m != nil {
+		return m.Offset
+	}
+	return 0
+}
+
+type ReadRequest struct {
+	Keys                 []string     `protobuf:"bytes,1,rep,name=keys,proto3" json:"keys,omitempty"`
+	Options              *ReadOptions `protobuf:"bytes,2,opt,name=options,proto3" json:"options,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
 func (m *ReadRequest) Reset()         { *m = ReadRequest{} }
 func (m *ReadRequest) String() string { return proto.CompactTextString(m) }
 func (*ReadRequest) ProtoMessage()    {}
 func (*ReadRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_98bbca36ef968dfc, []int{1}
+	return fileDescriptor_98bbca36ef968dfc, []int{2}
 }
 
 func (m *ReadRequest) XXX_Unmarshal(b []byte) error {
@@ -117,6 +194,13 @@ func (m *ReadRequest) GetKeys() []string {
 	return nil
 }
 
+func (m *ReadRequest) GetOptions() *ReadOptions {
+	if m != nil {
+		return m.Options
+	}
+	return nil
+}
+
 type ReadResponse struct {
 	Records              []*Record `protobuf:"bytes,1,rep,name=records,proto3" json:"records,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
@@ -128,7 +212,7 @@ func (m *ReadResponse) Reset()         { *m = ReadResponse{} }
 func (m *ReadResponse) String() string { return proto.CompactTextString(m) }
 func (*ReadResponse) ProtoMessage()    {}
 func (*ReadResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_98bbca36ef968dfc, []int{2}
+	return fileDescriptor_98bbca36ef968dfc, []int{3}
 }
 
 func (m *ReadResponse) XXX_Unmarshal(b []byte) error {
@@ -167,7 +251,7 @@ func (m *WriteRequest) Reset()         { *m = WriteRequest{} }
 func (m *WriteRequest) String() string { return proto.CompactTextString(m) }
 func (*WriteRequest) ProtoMessage()    {}
 func (*WriteRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_98bbca36ef968dfc, []int{3}
+	return fileDescriptor_98bbca36ef968dfc, []int{4}
 }
 
 func (m *WriteRequest) XXX_Unmarshal(b []byte) error {
@@ -196,6 +280,8 @@ func (m *WriteRequest) GetRecords() []*Record {
 }
 
 type WriteResponse struct {
+	// versions the records were written at
+	Versions             []uint64 `protobuf:"varint,1,rep,packed,name=versions,proto3" json:"versions,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -205,7 +291,7 @@ func (m *WriteResponse) Reset()         { *m = WriteResponse{} }
 func (m *WriteResponse) String() string { return proto.CompactTextString(m) }
 func (*WriteResponse) ProtoMessage()    {}
 func (*WriteResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_98bbca36ef968dfc, []int{4}
+	return fileDescriptor_98bbca36ef968dfc, []int{5}
 }
 
 func (m *WriteResponse) XXX_Unmarshal(b []byte) error {
@@ -226,6 +312,13 @@ func (m *WriteResponse) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_WriteResponse proto.InternalMessageInfo
 
+func (m *WriteResponse) GetVersions() []uint64 {
+	if m != nil {
+		return m.Versions
+	}
+	return nil
+}
+
 type DeleteRequest struct {
 	Keys                 []string `protobuf:"bytes,1,rep,name=keys,proto3" json:"keys,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
@@ -237,7 +330,7 @@ func (m *DeleteRequest) Reset()         { *m = DeleteRequest{} }
 func (m *DeleteRequest) String() string { return proto.CompactTextString(m) }
 func (*DeleteRequest) ProtoMessage()    {}
 func (*DeleteRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_98bbca36ef968dfc, []int{5}
+	return fileDescriptor_98bbca36ef968dfc, []int{6}
 }
 
 func (m *DeleteRequest) XXX_Unmarshal(b []byte) error {
@@ -275,7 +368,7 @@ func (m *DeleteResponse) Reset()         { *m = DeleteResponse{} }
 func (m *DeleteResponse) String() string { return proto.CompactTextString(m) }
 func (*DeleteResponse) ProtoMessage()    {}
 func (*DeleteResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_98bbca36ef968dfc, []int{6}
+	return fileDescriptor_98bbca36ef968dfc, []int{7}
 }
 
 func (m *DeleteResponse) XXX_Unmarshal(b []byte) error {
@@ -296,9 +389,89 @@ func (m *DeleteResponse) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_DeleteResponse proto.InternalMessageInfo
 
+type ListOptions struct {
+	// only records with the prefix
+	Prefix string `protobuf:"bytes,1,opt,name=prefix,proto3" json:"prefix,omitempty"`
+	// only records with the suffix
+	Suffix string `protobuf:"bytes,2,opt,name=suffix,proto3" json:"suffix,omitempty"`
+	// limit the number of records returned
+	Limit uint64 `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`
+	// offset of the first record returned
+	Offset uint64 `protobuf:"varint,4,opt,name=offset,proto3" json:"offset,omitempty"`
+	// leave the values of the records empty
+	KeysOnly             bool     `protobuf:"varint,5,opt,name=keys_only,json=keysOnly,proto3" json:"keys_only,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListOptions) Reset()         { *m = ListOptions{} }
+func (m *ListOptions) String() string { return proto.CompactTextString(m) }
+func (*ListOptions) ProtoMessage()    {}
+func (*ListOptions) Descriptor() ([]byte, []int) {
+	return fileDescriptor_98bbca36ef968dfc, []int{8}
+}
+
+func (m *ListOptions) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListOptions.Unmarshal(m, b)
+}
+func (m *ListOptions) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListOptions.Marshal(b, m, deterministic)
+}
+func (m *ListOptions) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListOptions.Merge(m, src)
+}
+func (m *ListOptions) XXX_Size() int {
+	return xxx_messageInfo_ListOptions.Size(m)
+}
+func (m *ListOptions) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListOptions.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListOptions proto.InternalMessageInfo
+
+func (m *ListOptions) GetPrefix() string {
+	if m != nil {
+		return m.Prefix
+	}
+	return ""
+}
+
+func (m *ListOptions) GetSuffix() string {
+	if m != nil {
+		return m.Suffix
+	}
+	return ""
+}
+
+func (m *ListOptions) GetLimit() uint64 {
+	if m != nil {
+		return m.Limit
+	}
+	return 0
+}
+
+func (m *ListOptions) GetOffset() uint64 {
+	if m != nil {
+		return m.Offset
+	}
+	return 0
+}
+
+func (m *ListOptions) GetKeysOnly() bool {
+	if m != nil {
+		return m.KeysOnly
+	}
+	return false
+}
+
 type ListRequest struct {
-	// optional key
-	Key                  string   `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	// optional key of the record listed, the options are ignored when set.
+	// records prefixed with a key are listed with the options prefix
+	Key     string       `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Options *ListOptions `protobuf:"bytes,2,opt,name=options,proto3" json:"options,omitempty"`
+	// records per streamed response, zero uses the server default
+	BatchSize            uint64   `protobuf:"varint,3,opt,name=batch_size,json=batchSize,proto3" json:"batch_size,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -308,7 +481,7 @@ func (m *ListRequest) Reset()         { *m = ListRequest{} }
 func (m *ListRequest) String() string { return proto.CompactTextString(m) }
 func (*ListRequest) ProtoMessage()    {}
 func (*ListRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_98bbca36ef968dfc, []int{7}
+	return fileDescriptor_98bbca36ef968dfc, []int{9}
 }
 
 func (m *ListRequest) XXX_Unmarshal(b []byte) error {
@@ -336,6 +509,20 @@ func (m *ListRequest) GetKey() string {
 	return ""
 }
 
+func (m *ListRequest) GetOptions() *ListOptions {
+	if m != nil {
+		return m.Options
+	}
+	return nil
+}
+
+func (m *ListRequest) GetBatchSize() uint64 {
+	if m != nil {
+		return m.BatchSize
+	}
+	return 0
+}
+
 type ListResponse struct {
 	Records              []*Record `protobuf:"bytes,1,rep,name=records,proto3" json:"records,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
@@ -347,7 +534,7 @@ func (m *ListResponse) Reset()         { *m = ListResponse{} }
 func (m *ListResponse) String() string { return proto.CompactTextString(m) }
 func (*ListResponse) ProtoMessage()    {}
 func (*ListResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_98bbca36ef968dfc, []int{8}
+	return fileDescriptor_98bbca36ef968dfc, []int{10}
 }
 
 func (m *ListResponse) XXX_Unmarshal(b []byte) error {
@@ -375,40 +562,298 @@ func (m *ListResponse) GetRecords() []*Record {
 	return nil
 }
 
+type CompareAndSwapRequest struct {
+	// records with the version they are expected to have, zero if absent
+	Records              []*Record `protobuf:"bytes,1,rep,name=records,proto3" json:"records,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
+}
+
+func (m *CompareAndSwapRequest) Reset()         { *m = CompareAndSwapRequest{} }
+func (m *CompareAndSwapRequest) String() string { return proto.CompactTextString(m) }
+func (*CompareAndSwapRequest) ProtoMessage()    {}
+func (*CompareAndSwapRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_98bbca36ef968dfc, []int{11}
+}
+
+func (m *CompareAndSwapRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CompareAndSwapRequest.Unmarshal(m, b)
+}
+func (m *CompareAndSwapRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CompareAndSwapRequest.Marshal(b, m, deterministic)
+}
+func (m *CompareAndSwapRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CompareAndSwapRequest.Merge(m, src)
+}
+func (m *CompareAndSwapRequest) XXX_Size() int {
+	return xxx_messageInfo_CompareAndSwapRequest.Size(m)
+}
+func (m *CompareAndSwapRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_CompareAndSwapRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CompareAndSwapRequest proto.InternalMessageInfo
+
+func (m *CompareAndSwapRequest) GetRecords() []*Record {
+	if m != nil {
+		return m.Records
+	}
+	return nil
+}
+
+type Conflict struct {
+	Key                  string   `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Expected             uint64   `protobuf:"varint,2,opt,name=expected,proto3" json:"expected,omitempty"`
+	Current              uint64   `protobuf:"varint,3,opt,name=current,proto3" json:"current,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Conflict) Reset()         { *m = Conflict{} }
+func (m *Conflict) String() string { return proto.CompactTextString(m) }
+func (*Conflict) ProtoMessage()    {}
+func (*Conflict) Descriptor() ([]byte, []int) {
+	return fileDescriptor_98bbca36ef968dfc, []int{12}
+}
+
+func (m *Conflict) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Conflict.Unmarshal(m, b)
+}
+func (m *Conflict) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Conflict.Marshal(b, m, deterministic)
+}
+func (m *Conflict) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Conflict.Merge(m, src)
+}
+func (m *Conflict) XXX_Size() int {
+	return xxx_messageInfo_Conflict.Size(m)
+}
+func (m *Conflict) XXX_DiscardUnknown() {
+	xxx_messageInfo_Conflict.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Conflict proto.InternalMessageInfo
+
+func (m *Conflict) GetKey() string {
+	if m != nil {
+		return m.Key
+	}
+	return ""
+}
+
+func (m *Conflict) GetExpected() uint64 {
+	if m != nil {
+		return m.Expected
+	}
+	return 0
+}
+
+func (m *Conflict) GetCurrent() uint64 {
+	if m != nil {
+		return m.Current
+	}
+	return 0
+}
+
+type CompareAndSwapResponse struct {
+	// versions the records were written at
+	Versions []uint64 `protobuf:"varint,1,rep,packed,name=versions,proto3" json:"versions,omitempty"`
+	// set instead of versions when a version did not match
+	Conflict             *Conflict `protobuf:"bytes,2,opt,name=conflict,proto3" json:"conflict,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
+}
+
+func (m *CompareAndSwapResponse) Reset()         { *m = CompareAndSwapResponse{} }
+func (m *CompareAndSwapResponse) String() string { return proto.CompactTextString(m) }
+func (*CompareAndSwapResponse) ProtoMessage()    {}
+func (*CompareAndSwapResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_98bbca36ef968dfc, []int{13}
+}
+
+func (m *CompareAndSwapResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CompareAndSwapResponse.Unmarshal(m, b)
+}
+func (m *CompareAndSwapResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CompareAndSwapResponse.Marshal(b, m, deterministic)
+}
+func (m *CompareAndSwapResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CompareAndSwapResponse.Merge(m, src)
+}
+func (m *CompareAndSwapResponse) XXX_Size() int {
+	return xxx_messageInfo_CompareAndSwapResponse.Size(m)
+}
+func (m *CompareAndSwapResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_CompareAndSwapResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CompareAndSwapResponse proto.InternalMessageInfo
+
+func (m *CompareAndSwapResponse) GetVersions() []uint64 {
+	if m != nil {
+		return m.Versions
+	}
+	return nil
+}
+
+func (m *CompareAndSwapResponse) GetConflict() *Conflict {
+	if m != nil {
+		return m.Conflict
+	}
+	return nil
+}
+
+type WatchRequest struct {
+	// watch records with the prefix
+	Prefix               string   `protobuf:"bytes,1,opt,name=prefix,proto3" json:"prefix,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *WatchRequest) Reset()         { *m = WatchRequest{} }
+func (m *WatchRequest) String() string { return proto.CompactTextString(m) }
+func (*WatchRequest) ProtoMessage()    {}
+func (*WatchRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_98bbca36ef968dfc, []int{14}
+}
+
+func (m *WatchRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_WatchRequest.Unmarshal(m, b)
+}
+func (m *WatchRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_WatchRequest.Marshal(b, m, deterministic)
+}
+func (m *WatchRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WatchRequest.Merge(m, src)
+}
+func (m *WatchRequest) XXX_Size() int {
+	return xxx_messageInfo_WatchRequest.Size(m)
+}
+func (m *WatchRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_WatchRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_WatchRequest proto.InternalMessageInfo
+
+func (m *WatchRequest) GetPrefix() string {
+	if m != nil {
+		return m.Prefix
+	}
+	return ""
+}
+
+type WatchResponse struct {
+	// put or delete
+	Type                 string   `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	Record               *Record  `protobuf:"bytes,2,opt,name=record,proto3" json:"record,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *WatchResponse) Reset()         { *m = WatchResponse{} }
+func (m *WatchResponse) String() string { return proto.CompactTextString(m) }
+func (*WatchResponse) ProtoMessage()    {}
+func (*WatchResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_98bbca36ef968dfc, []int{15}
+}
+
+func (m *WatchResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_WatchResponse.Unmarshal(m, b)
+}
+func (m *WatchResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_WatchResponse.Marshal(b, m, deterministic)
+}
+func (m *WatchResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_WatchResponse.Merge(m, src)
+}
+func (m *WatchResponse) XXX_Size() int {
+	return xxx_messageInfo_WatchResponse.Size(m)
+}
+func (m *WatchResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_WatchResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_WatchResponse proto.InternalMessageInfo
+
+func (m *WatchResponse) GetType() string {
+	if m != nil {
+		return m.Type
+	}
+	return ""
+}
+
+func (m *WatchResponse) GetRecord() *Record {
+	if m != nil {
+		return m.Record
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*Record)(nil), "stack.rpc.store.Record")
+	proto.RegisterType((*ReadOptions)(nil), "stack.rpc.store.ReadOptions")
 	proto.RegisterType((*ReadRequest)(nil), "stack.rpc.store.ReadRequest")
 	proto.RegisterType((*ReadResponse)(nil), "stack.rpc.store.ReadResponse")
 	proto.RegisterType((*WriteRequest)(nil), "stack.rpc.store.WriteRequest")
 	proto.RegisterType((*WriteResponse)(nil), "stack.rpc.store.WriteResponse")
 	proto.RegisterType((*DeleteRequest)(nil), "stack.rpc.store.DeleteRequest")
 	proto.RegisterType((*DeleteResponse)(nil), "stack.rpc.store.DeleteResponse")
+	proto.RegisterType((*ListOptions)(nil), "stack.rpc.store.ListOptions")
 	proto.RegisterType((*ListRequest)(nil), "stack.rpc.store.ListRequest")
 	proto.RegisterType((*ListResponse)(nil), "stack.rpc.store.ListResponse")
+	proto.RegisterType((*CompareAndSwapRequest)(nil), "stack.rpc.store.CompareAndSwapRequest")
+	proto.RegisterType((*Conflict)(nil), "stack.rpc.store.Conflict")
+	proto.RegisterType((*CompareAndSwapResponse)(nil), "stack.rpc.store.CompareAndSwapResponse")
+	proto.RegisterType((*WatchRequest)(nil), "stack.rpc.store.WatchRequest")
+	proto.RegisterType((*WatchResponse)(nil), "stack.rpc.store.WatchResponse")
 }
 
 func init() { proto.RegisterFile("store.proto", fileDescriptor_98bbca36ef968dfc) }
 
 var fileDescriptor_98bbca36ef968dfc = []byte{
-	// 317 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x92, 0xc1, 0x4e, 0x83, 0x40,
-	0x10, 0x86, 0xbb, 0xa5, 0xc5, 0x74, 0x68, 0x6d, 0x33, 0x31, 0x4a, 0x1a, 0xb5, 0x88, 0x17, 0x4e,
-	0x44, 0xeb, 0x13, 0x98, 0x68, 0x6c, 0xa2, 0xa7, 0xf5, 0xe0, 0xb9, 0xd2, 0x39, 0x10, 0x1a, 0xc1,
-	0xdd, 0xad, 0x91, 0x97, 0xf2, 0x19, 0xcd, 0xb2, 0xa0, 0x28, 0x90, 0x18, 0x6f, 0xbb, 0xcc, 0x3f,
-	0xdf, 0xfc, 0xcc, 0xbf, 0xe0, 0x48, 0x95, 0x0a, 0x0a, 0x33, 0x91, 0xaa, 0x14, 0xa7, 0x52, 0xad,
-	0xa3, 0x24, 0x14, 0x59, 0x14, 0x16, 0x9f, 0xfd, 0x15, 0xd8, 0x9c, 0xa2, 0x54, 0x6c, 0x70, 0x06,
-	0x56, 0x42, 0xb9, 0xcb, 0x3c, 0x16, 0x8c, 0xb8, 0x3e, 0xe2, 0x01, 0x0c, 0xdf, 0xd6, 0xdb, 0x1d,
-	0xb9, 0x7d, 0x8f, 0x05, 0x63, 0x6e, 0x2e, 0x78, 0x08, 0x36, 0xbd, 0x67, 0xb1, 0xc8, 0x5d, 0xcb,
-	0x63, 0x81, 0xc5, 0xcb, 0x9b, 0x7f, 0x06, 0x0e, 0xa7, 0xf5, 0x86, 0xd3, 0xeb, 0x8e, 0xa4, 0x42,
-	0x84, 0x41, 0x42, 0xb9, 0x74, 0x99, 0x67, 0x05, 0x23, 0x5e, 0x9c, 0xfd, 0x6b, 0x18, 0x1b, 0x89,
-	0xcc, 0xd2, 0x17, 0x49, 0x78, 0x09, 0x7b, 0xa2, 0x18, 0x6e, 0x64, 0xce, 0xf2, 0x28, 0xfc, 0xe5,
-	0x2f, 0x34, 0xe6, 0x78, 0xa5, 0xd3, 0x88, 0x27, 0x11, 0x2b, 0xaa, 0xc6, 0xd4, 0x10, 0xfd, 0x3f,
-	0x22, 0xa6, 0x30, 0x29, 0x11, 0xc6, 0x86, 0x7f, 0x0e, 0x93, 0x1b, 0xda, 0xd2, 0x37, 0xb4, 0xcd,
-	0xfb, 0x0c, 0xf6, 0x2b, 0x51, 0xd9, 0xb6, 0x00, 0xe7, 0x21, 0x96, 0xaa, 0x6a, 0x6a, 0xec, 0x4f,
-	0x7b, 0x35, 0x82, 0x7f, 0xff, 0xee, 0xf2, 0xa3, 0x0f, 0xc3, 0x47, 0x5d, 0xc1, 0x3b, 0x18, 0x68,
-	0x18, 0x1e, 0x37, 0x7a, 0x6a, 0x26, 0xe6, 0x27, 0x1d, 0xd5, 0xd2, 0x72, 0xef, 0x82, 0xe1, 0x2d,
-	0x0c, 0x74, 0x08, 0x2d, 0xa0, 0x5a, 0x7c, 0x2d, 0xa0, 0x7a, 0x72, 0x7e, 0x0f, 0x57, 0x30, 0x2c,
-	0xb6, 0x88, 0x4d, 0x65, 0x3d, 0xa0, 0xf9, 0x69, 0x57, 0xf9, 0x8b, 0x74, 0x0f, 0xb6, 0xd9, 0x2c,
-	0x36, 0xb5, 0x3f, 0x72, 0x99, 0x2f, 0x3a, 0xeb, 0x15, 0xec, 0xd9, 0x2e, 0xde, 0xf9, 0xd5, 0x67,
-	0x00, 0x00, 0x00, 0xff, 0xff, 0x99, 0xc4, 0x25, 0xd1, 0xf6, 0x02, 0x00, 0x00,
+	// 622 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x55, 0x6d, 0x4f, 0xd4, 0x4c,
+	0x14, 0xa5, 0x6c, 0x77, 0xd9, 0xbd, 0x0b, 0x3c, 0x64, 0xf2, 0x88, 0xb5, 0x0a, 0x6e, 0xc6, 0x04,
+	0x37, 0x31, 0x59, 0x15, 0xa3, 0xdf, 0x09, 0x1a, 0x0d, 0x9a, 0x90, 0x0c, 0x26, 0xc4, 0x4f, 0x64,
+	0xe9, 0xde, 0x8d, 0xcd, 0x96, 0x4e, 0x9d, 0x99, 0x45, 0xca, 0x2f, 0xf0, 0x5f, 0xfa, 0x57, 0xcc,
+	0xbc, 0xb4, 0x16, 0xb6, 0x95, 0x0d, 0xdf, 0xe6, 0xbe, 0xe4, 0xdc, 0xd3, 0x73, 0xee, 0x4c, 0xa1,
+	0x2f, 0x15, 0x17, 0x38, 0xca, 0x04, 0x57, 0x9c, 0xfc, 0x27, 0xd5, 0x38, 0x9a, 0x8d, 0x44, 0x16,
+	0x8d, 0x4c, 0x9a, 0x9e, 0x43, 0x87, 0x61, 0xc4, 0xc5, 0x84, 0x6c, 0x41, 0x6b, 0x86, 0x79, 0xe0,
+	0x0d, 0xbc, 0x61, 0x8f, 0xe9, 0x23, 0xf9, 0x1f, 0xda, 0x97, 0xe3, 0x64, 0x8e, 0xc1, 0xea, 0xc0,
+	0x1b, 0xae, 0x33, 0x1b, 0x90, 0x6d, 0xe8, 0xe0, 0x55, 0x16, 0x8b, 0x3c, 0x68, 0x0d, 0xbc, 0x61,
+	0x8b, 0xb9, 0x88, 0x04, 0xb0, 0x76, 0x89, 0x42, 0xc6, 0x3c, 0x0d, 0xfc, 0x81, 0x37, 0xf4, 0x59,
+	0x11, 0xd2, 0x19, 0xf4, 0x19, 0x8e, 0x27, 0xc7, 0x99, 0x8a, 0x79, 0x2a, 0x35, 0x40, 0x26, 0x70,
+	0x1a, 0x5f, 0x99, 0x59, 0x5d, 0xe6, 0x22, 0x9d, 0x97, 0xf3, 0xa9, 0xce, 0xaf, 0xda, 0xbc, 0x8d,
+	0x34, 0x8d, 0x24, 0xbe, 0x88, 0x95, 0x99, 0xe7, 0x33, 0x1b, 0xe8, 0x6e, 0x3e, 0x9d, 0x4a, 0x54,
+	0x6e, 0x9a, 0x8b, 0xe8, 0x37, 0x3b, 0x8c, 0xe1, 0x8f, 0x39, 0x4a, 0x45, 0x08, 0xf8, 0x33, 0xcc,
+	0x65, 0xe0, 0x0d, 0x5a, 0xc3, 0x1e, 0x33, 0x67, 0xf2, 0x0e, 0xd6, 0xb8, 0xe5, 0x62, 0x26, 0xf5,
+	0xf7, 0x9f, 0x8c, 0x6e, 0xc9, 0x32, 0xaa, 0xf0, 0x65, 0x45, 0x33, 0x3d, 0x80, 0x75, 0x0b, 0x2d,
+	0x33, 0x9e, 0x4a, 0x24, 0xaf, 0x61, 0x4d, 0x18, 0xed, 0x2c, 0x7c, 0x7f, 0xff, 0x61, 0x0d, 0x8e,
+	0xae, 0xb3, 0xa2, 0x4f, 0x43, 0x9c, 0x8a, 0x58, 0x61, 0x41, 0xaf, 0x02, 0xb1, 0xba, 0x24, 0xc4,
+	0x0b, 0xd8, 0x70, 0x10, 0x8e, 0x46, 0x08, 0x5d, 0xa7, 0xb4, 0xe5, 0xe1, 0xb3, 0x32, 0xa6, 0xcf,
+	0x60, 0xe3, 0x3d, 0x26, 0xa8, 0xf0, 0x1f, 0x7a, 0xd0, 0x2d, 0xd8, 0x2c, 0x9a, 0x2c, 0x24, 0xfd,
+	0xe5, 0x41, 0xff, 0x4b, 0x2c, 0x55, 0xbd, 0x65, 0xbd, 0x06, 0xcb, 0x7a, 0xf7, 0xb3, 0x8c, 0x3c,
+	0x86, 0x9e, 0xe6, 0x71, 0xc6, 0xd3, 0x24, 0x0f, 0xda, 0xc6, 0xfb, 0xae, 0x4e, 0x1c, 0xa7, 0x49,
+	0x4e, 0x2f, 0x2d, 0x93, 0x82, 0xff, 0xe2, 0x96, 0x2e, 0xe1, 0x66, 0xe5, 0x53, 0x4a, 0x37, 0xc9,
+	0x0e, 0xc0, 0xf9, 0x58, 0x45, 0xdf, 0xcf, 0x64, 0x7c, 0x8d, 0x8e, 0x68, 0xcf, 0x64, 0x4e, 0xe2,
+	0x6b, 0xd4, 0x4e, 0xd9, 0xb9, 0xf7, 0x37, 0xfb, 0x08, 0x1e, 0x1c, 0xf2, 0x8b, 0x6c, 0x2c, 0xf0,
+	0x20, 0x9d, 0x9c, 0xfc, 0x1c, 0x67, 0x35, 0xae, 0x2f, 0x8b, 0xc5, 0xa0, 0x7b, 0xc8, 0xd3, 0x69,
+	0x12, 0x47, 0x75, 0x1a, 0x84, 0xd0, 0xc5, 0xab, 0x0c, 0x23, 0x85, 0x13, 0x23, 0x82, 0xcf, 0xca,
+	0x58, 0xdf, 0xcb, 0x68, 0x2e, 0x04, 0xa6, 0x85, 0x1b, 0x45, 0x48, 0x67, 0xb0, 0x7d, 0x9b, 0xdf,
+	0xdd, 0x2b, 0x45, 0xde, 0x42, 0x37, 0x72, 0x4c, 0x9c, 0xe0, 0x8f, 0x16, 0xd8, 0x17, 0x54, 0x59,
+	0xd9, 0x4a, 0xf7, 0x60, 0xfd, 0x54, 0x8b, 0x5b, 0x68, 0xd0, 0xb0, 0x52, 0xf4, 0x2b, 0x6c, 0xb8,
+	0x3e, 0xc7, 0x85, 0x80, 0xaf, 0xf2, 0x0c, 0x5d, 0x9b, 0x39, 0x93, 0x97, 0xd0, 0xb1, 0xc2, 0x38,
+	0x06, 0x8d, 0xfa, 0xb9, 0xb6, 0xfd, 0xdf, 0x2d, 0x68, 0x9f, 0xe8, 0x02, 0xf9, 0x08, 0xbe, 0xf6,
+	0x95, 0xd4, 0x6f, 0x89, 0x63, 0x17, 0xee, 0x34, 0x54, 0xdd, 0xfd, 0x58, 0x79, 0xe5, 0x91, 0x0f,
+	0xe0, 0xeb, 0xd7, 0x80, 0xd4, 0x3f, 0x1e, 0xcd, 0x40, 0xd5, 0x27, 0x84, 0xae, 0x90, 0x4f, 0xd0,
+	0x36, 0xd7, 0x99, 0x2c, 0x76, 0x56, 0x5f, 0x8a, 0x70, 0xb7, 0xa9, 0x5c, 0x22, 0x7d, 0x86, 0x8e,
+	0xbd, 0xc6, 0x64, 0xb1, 0xf7, 0xc6, 0x23, 0x10, 0x3e, 0x6d, 0xac, 0x97, 0x60, 0x11, 0x6c, 0xde,
+	0xdc, 0x0d, 0xb2, 0x57, 0xe3, 0x72, 0xcd, 0x72, 0x87, 0xcf, 0xef, 0xec, 0x2b, 0x87, 0x1c, 0x41,
+	0xdb, 0x78, 0x5d, 0xf7, 0xed, 0x95, 0x5d, 0x09, 0x77, 0x9b, 0xca, 0x7f, 0xed, 0x38, 0xef, 0x98,
+	0x1f, 0xdc, 0x9b, 0x3f, 0x03, 0x00, 0x36, 0x5e, 0x64, 0x99, 0xef, 0x06, 0x00, 0x00,
 }
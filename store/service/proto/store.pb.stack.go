@@ -48,6 +48,8 @@ type StoreService interface {
 	Read(ctx context.Context, in *ReadRequest, opts ...client.CallOption) (*ReadResponse, error)
 	Write(ctx context.Context, in *WriteRequest, opts ...client.CallOption) (*WriteResponse, error)
 	Delete(ctx context.Context, in *DeleteRequest, opts ...client.CallOption) (*DeleteResponse, error)
+	CompareAndSwap(ctx context.Context, in *CompareAndSwapRequest, opts ...client.CallOption) (*CompareAndSwapResponse, error)
+	Watch(ctx context.Context, in *WatchRequest, opts ...client.CallOption) (Store_WatchService, error)
 }
 
 type storeService struct {
@@ -141,6 +143,65 @@ func (c *storeService) Delete(ctx context.Context, in *DeleteRequest, opts ...cl
 	return out, nil
 }
 
+func (c *storeService) CompareAndSwap(ctx context.Context, in *CompareAndSwapRequest, opts ...client.CallOption) (*CompareAndSwapResponse, error) {
+	req := c.c.NewRequest(c.name, "Store.CompareAndSwap", in)
+	out := new(CompareAndSwapResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storeService) Watch(ctx context.Context, in *WatchRequest, opts ...client.CallOption) (Store_WatchService, error) {
+	req := c.c.NewRequest(c.name, "Store.Watch", &WatchRequest{})
+	stream, err := c.c.Stream(ctx, req, opts...)
+	if err != nil {
+		return nil, err
+	}
+	if err := stream.Send(in); err != nil {
+		return nil, err
+	}
+	return &storeServiceWatch{stream}, nil
+}
+
+type Store_WatchService interface {
+	Context() context.Context
+	SendMsg(interface{}) error
+	RecvMsg(interface{}) error
+	Close() error
+	Recv() (*WatchResponse, error)
+}
+
+type storeServiceWatch struct {
+	stream client.Stream
+}
+
+func (x *storeServiceWatch) Close() error {
+	return x.stream.Close()
+}
+
+func (x *storeServiceWatch) Context() context.Context {
+	return x.stream.Context()
+}
+
+func (x *storeServiceWatch) SendMsg(m interface{}) error {
+	return x.stream.Send(m)
+}
+
+func (x *storeServiceWatch) RecvMsg(m interface{}) error {
+	return x.stream.Recv(m)
+}
+
+func (x *storeServiceWatch) Recv() (*WatchResponse, error) {
+	m := new(WatchResponse)
+	err := x.stream.Recv(m)
+	if err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // Server API for Store service
 
 type StoreHandler interface {
@@ -148,6 +209,8 @@ type StoreHandler interface {
 	Read(context.Context, *ReadRequest, *ReadResponse) error
 	Write(context.Context, *WriteRequest, *WriteResponse) error
 	Delete(context.Context, *DeleteRequest, *DeleteResponse) error
+	CompareAndSwap(context.Context, *CompareAndSwapRequest, *CompareAndSwapResponse) error
+	Watch(context.Context, *WatchRequest, Store_WatchStream) error
 }
 
 func RegisterStoreHandler(s server.Server, hdlr StoreHandler, opts ...server.HandlerOption) error {
@@ -156,6 +219,8 @@ func RegisterStoreHandler(s server.Server, hdlr StoreHandler, opts ...server.Han
 		Read(ctx context.Context, in *ReadRequest, out *ReadResponse) error
 		Write(ctx context.Context, in *WriteRequest, out *WriteResponse) error
 		Delete(ctx context.Context, in *DeleteRequest, out *DeleteResponse) error
+		CompareAndSwap(ctx context.Context, in *CompareAndSwapRequest, out *CompareAndSwapResponse) error
+		Watch(ctx context.Context, stream server.Stream) error
 	}
 	type Store struct {
 		store
@@ -219,3 +284,47 @@ func (h *storeHandler) Write(ctx context.Context, in *WriteRequest, out *WriteRe
 func (h *storeHandler) Delete(ctx context.Context, in *DeleteRequest, out *DeleteResponse) error {
 	return h.StoreHandler.Delete(ctx, in, out)
 }
+
+func (h *storeHandler) CompareAndSwap(ctx context.Context, in *CompareAndSwapRequest, out *CompareAndSwapResponse) error {
+	return h.StoreHandler.CompareAndSwap(ctx, in, out)
+}
+
+func (h *storeHandler) Watch(ctx context.Context, stream server.Stream) error {
+	m := new(WatchRequest)
+	if err := stream.Recv(m); err != nil {
+		return err
+	}
+	return h.StoreHandler.Watch(ctx, m, &storeWatchStream{stream})
+}
+
+type Store_WatchStream interface {
+	Context() context.Context
+	SendMsg(interface{}) error
+	RecvMsg(interface{}) error
+	Close() error
+	Send(*WatchResponse) error
+}
+
+type storeWatchStream struct {
+	stream server.Stream
+}
+
+func (x *storeWatchStream) Close() error {
+	return x.stream.Close()
+}
+
+func (x *storeWatchStream) Context() context.Context {
+	return x.stream.Context()
+}
+
+func (x *storeWatchStream) SendMsg(m interface{}) error {
+	return x.stream.Send(m)
+}
+
+func (x *storeWatchStream) RecvMsg(m interface{}) error {
+	return x.stream.Recv(m)
+}
+
+func (x *storeWatchStream) Send(m *WatchResponse) error {
+	return x.stream.Send(m)
+}
@@ -4,6 +4,9 @@ package service
 import (
 	"context"
 	"io"
+	"net/http"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/stack-labs/stack/client/mucp"
@@ -11,6 +14,7 @@ import (
 	"github.com/stack-labs/stack/client"
 	"github.com/stack-labs/stack/store"
 	pb "github.com/stack-labs/stack/store/service/proto"
+	"github.com/stack-labs/stack/util/errors"
 	"github.com/stack-labs/stack/util/options"
 )
 
@@ -22,11 +26,72 @@ type serviceStore struct {
 
 	// store service client
 	Client pb.StoreService
+
+	sync.RWMutex
+	// prefix scopes the keys to the namespace and prefix options
+	prefix string
+}
+
+// Init the options, the keys of a new namespace or prefix are isolated
+// from those written before
+func (s *serviceStore) Init(opts ...options.Option) error {
+	if err := s.Options.Init(opts...); err != nil {
+		return err
+	}
+
+	s.Lock()
+	s.prefix = scope(s.Options)
+	s.Unlock()
+	return nil
+}
+
+// scope returns the key prefix of the namespace and prefix options
+func scope(o options.Options) string {
+	var prefix string
+	if v, ok := o.Values().Get("store.namespace"); ok && len(v.(string)) > 0 {
+		prefix = v.(string) + "/"
+	}
+	if v, ok := o.Values().Get("store.prefix"); ok {
+		prefix += v.(string)
+	}
+	return prefix
+}
+
+func (s *serviceStore) scope() string {
+	s.RLock()
+	defer s.RUnlock()
+	return s.prefix
+}
+
+// notSupported maps the error of a backend which isn't versioned
+func notSupported(err error) error {
+	if err != nil && errors.Parse(err.Error()).Code == http.StatusNotImplemented {
+		return store.ErrNotSupported
+	}
+	return err
 }
 
-// Sync all the known records
-func (s *serviceStore) List() ([]*store.Record, error) {
-	stream, err := s.Client.List(context.Background(), &pb.ListRequest{}, client.WithAddress(s.Nodes...))
+// List the known records
+func (s *serviceStore) List(opts ...store.ListOption) ([]*store.Record, error) {
+	var options store.ListOptions
+	for _, o := range opts {
+		o(&options)
+	}
+
+	return s.list(s.scope(), options)
+}
+
+// list the records of the scope, their keys are returned without it
+func (s *serviceStore) list(prefix string, options store.ListOptions) ([]*store.Record, error) {
+	stream, err := s.Client.List(context.Background(), &pb.ListRequest{
+		Options: &pb.ListOptions{
+			Prefix:   prefix + options.Prefix,
+			Suffix:   options.Suffix,
+			Limit:    uint64(options.Limit),
+			Offset:   uint64(options.Offset),
+			KeysOnly: options.KeysOnly,
+		},
+	}, client.WithAddress(s.Nodes...))
 	if err != nil {
 		return nil, err
 	}
@@ -44,9 +109,10 @@ func (s *serviceStore) List() ([]*store.Record, error) {
 		}
 		for _, record := range rsp.Records {
 			records = append(records, &store.Record{
-				Key:    record.Key,
-				Value:  record.Value,
-				Expiry: time.Duration(record.Expiry) * time.Second,
+				Key:     strings.TrimPrefix(record.Key, prefix),
+				Value:   record.Value,
+				Expiry:  time.Duration(record.Expiry) * time.Second,
+				Version: record.Version,
 			})
 		}
 	}
@@ -55,20 +121,49 @@ func (s *serviceStore) List() ([]*store.Record, error) {
 }
 
 // Read a record with key
-func (s *serviceStore) Read(keys ...string) ([]*store.Record, error) {
+func (s *serviceStore) Read(key string, opts ...store.ReadOption) ([]*store.Record, error) {
+	var options store.ReadOptions
+	for _, o := range opts {
+		o(&options)
+	}
+
+	prefix := s.scope()
+
+	// the suffix of a scoped key is matched by listing the scope
+	if len(prefix) > 0 && options.Suffix {
+		list := store.ListOptions{
+			Suffix: key,
+			Limit:  options.Limit,
+			Offset: options.Offset,
+		}
+		if options.Prefix {
+			list.Prefix = key
+		}
+		return s.list(prefix, list)
+	}
+
 	rsp, err := s.Client.Read(context.Background(), &pb.ReadRequest{
-		Keys: keys,
+		Keys: []string{prefix + key},
+		Options: &pb.ReadOptions{
+			Prefix: options.Prefix,
+			Suffix: options.Suffix,
+			Limit:  uint64(options.Limit),
+			Offset: uint64(options.Offset),
+		},
 	}, client.WithAddress(s.Nodes...))
-	if err != nil {
+	if verr, ok := err.(*errors.Error); ok && verr.Code == 404 {
+		return nil, store.ErrNotFound
+	} else if err != nil {
 		return nil, err
 	}
 
 	records := make([]*store.Record, 0, len(rsp.Records))
 	for _, val := range rsp.Records {
 		records = append(records, &store.Record{
-			Key:    val.Key,
-			Value:  val.Value,
-			Expiry: time.Duration(val.Expiry) * time.Second,
+			Key:     strings.TrimPrefix(val.Key, prefix),
+			Value:   val.Value,
+			Expiry:  time.Duration(val.Expiry) * time.Second,
+			Version: val.Version,
 		})
 	}
 	return records, nil
@@ -76,32 +171,100 @@ func (s *serviceStore) Read(keys ...string) ([]*store.Record, error) {
 
 // Write a record
 func (s *serviceStore) Write(recs ...*store.Record) error {
+	prefix := s.scope()
 	records := make([]*pb.Record, 0, len(recs))
 
 	for _, record := range recs {
 		records = append(records, &pb.Record{
-			Key:    record.Key,
+			Key:    prefix + record.Key,
 			Value:  record.Value,
 			Expiry: int64(record.Expiry.Seconds()),
 		})
 	}
 
-	_, err := s.Client.Write(context.Background(), &pb.WriteRequest{
+	rsp, err := s.Client.Write(context.Background(), &pb.WriteRequest{
+		Records: records,
+	}, client.WithAddress(s.Nodes...))
+	if err != nil {
+		return err
+	}
+
+	setVersions(recs, rsp.Versions)
+	return nil
+}
+
+// CompareAndSwap writes the records if their versions match, it fails with
+// store.ErrNotSupported when the store of the service isn't versioned
+func (s *serviceStore) CompareAndSwap(recs ...*store.Record) error {
+	prefix := s.scope()
+	records := make([]*pb.Record, 0, len(recs))
+
+	for _, record := range recs {
+		records = append(records, &pb.Record{
+			Key:     prefix + record.Key,
+			Value:   record.Value,
+			Expiry:  int64(record.Expiry.Seconds()),
+			Version: record.Version,
+		})
+	}
+
+	rsp, err := s.Client.CompareAndSwap(context.Background(), &pb.CompareAndSwapRequest{
 		Records: records,
 	}, client.WithAddress(s.Nodes...))
+	if err != nil {
+		return notSupported(err)
+	}
 
-	return err
+	if c := rsp.Conflict; c != nil {
+		return &store.ConflictError{
+			Key:      strings.TrimPrefix(c.Key, prefix),
+			Expected: c.Expected,
+			Current:  c.Current,
+		}
+	}
+
+	setVersions(recs, rsp.Versions)
+	return nil
+}
+
+// Watch the records with the prefix, the watcher fails with
+// store.ErrNotSupported when the store of the service isn't versioned
+func (s *serviceStore) Watch(prefix string) (store.Watcher, error) {
+	scope := s.scope()
+	stream, err := s.Client.Watch(context.Background(), &pb.WatchRequest{
+		Prefix: scope + prefix,
+	}, client.WithAddress(s.Nodes...))
+	if err != nil {
+		return nil, notSupported(err)
+	}
+
+	return &serviceWatcher{stream: stream, prefix: scope}, nil
+}
+
+func setVersions(recs []*store.Record, versions []uint64) {
+	for i, v := range versions {
+		if i < len(recs) {
+			recs[i].Version = v
+		}
+	}
 }
 
 // Delete a record with key
 func (s *serviceStore) Delete(keys ...string) error {
+	prefix := s.scope()
+	scoped := make([]string, 0, len(keys))
+	for _, key := range keys {
+		scoped = append(scoped, prefix+key)
+	}
+
 	_, err := s.Client.Delete(context.Background(), &pb.DeleteRequest{
-		Keys: keys,
+		Keys: scoped,
 	}, client.WithAddress(s.Nodes...))
 	return err
 }
 
-// NewStore returns a new store service implementation
+// NewStore returns a new store service implementation, keys are scoped to
+// the namespace and prefix set with store.Namespace and store.Prefix
 func NewStore(opts ...options.Option) store.Store {
 	options := options.NewOptions(opts...)
 
@@ -116,6 +279,7 @@ func NewStore(opts ...options.Option) store.Store {
 		Options: options,
 		Nodes:   nodes,
 		Client:  pb.NewStoreService("stack.rpc.store", mucp.NewClient()),
+		prefix:  scope(options),
 	}
 
 	return service
@@ -0,0 +1,132 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stack-labs/stack/client"
+	cmucp "github.com/stack-labs/stack/client/mucp"
+	"github.com/stack-labs/stack/client/selector"
+	sr "github.com/stack-labs/stack/client/selector/registry"
+	"github.com/stack-labs/stack/registry/memory"
+	"github.com/stack-labs/stack/server"
+	smucp "github.com/stack-labs/stack/server/mucp"
+	"github.com/stack-labs/stack/store"
+	mstore "github.com/stack-labs/stack/store/memory"
+	"github.com/stack-labs/stack/store/service/handler"
+	pb "github.com/stack-labs/stack/store/service/proto"
+	httpt "github.com/stack-labs/stack/transport/http"
+	"github.com/stack-labs/stack/util/options"
+)
+
+// unversioned hides the versioned methods of a store
+type unversioned struct {
+	store.Store
+}
+
+func newTestStore(t *testing.T, backend store.Store) func(opts ...options.Option) store.Store {
+	reg := memory.NewRegistry()
+
+	srv := smucp.NewServer(
+		server.Name("stack.rpc.store"),
+		server.Address("127.0.0.1:0"),
+		server.Registry(reg),
+	)
+	if err := pb.RegisterStoreHandler(srv, &handler.Store{Store: backend}); err != nil {
+		t.Fatal(err)
+	}
+	if err := srv.Start(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { srv.Stop() })
+
+	c := cmucp.NewClient(
+		client.Selector(sr.NewSelector(selector.Registry(reg))),
+		client.Transport(httpt.NewTransport()),
+	)
+
+	return func(opts ...options.Option) store.Store {
+		s := NewStore(opts...)
+		s.(*serviceStore).Client = pb.NewStoreService("stack.rpc.store", c)
+		return s
+	}
+}
+
+func TestNamespace(t *testing.T) {
+	newStore := newTestStore(t, mstore.NewStore())
+
+	a := newStore(store.Namespace("a"))
+	b := newStore(store.Namespace("b"), store.Prefix("p/"))
+
+	for _, s := range []store.Store{a, b} {
+		if err := s.Write(&store.Record{Key: "key", Value: []byte("value")}, &store.Record{Key: "other", Value: []byte("value")}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := a.Delete("other"); err != nil {
+		t.Fatal(err)
+	}
+
+	testData := []struct {
+		s    store.Store
+		keys []string
+	}{
+		{a, []string{"key"}},
+		{b, []string{"key", "other"}},
+	}
+
+	for _, d := range testData {
+		recs, err := d.s.List()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(recs) != len(d.keys) {
+			t.Fatalf("Expected keys %v got %d records", d.keys, len(recs))
+		}
+		for i, r := range recs {
+			if r.Key != d.keys[i] {
+				t.Fatalf("Expected keys %v got %s", d.keys, r.Key)
+			}
+		}
+
+		recs, err = d.s.Read("key")
+		if err != nil || len(recs) != 1 || recs[0].Key != "key" {
+			t.Fatalf("Expected to read key got %v %v", recs, err)
+		}
+
+		recs, err = d.s.Read("ey", store.ReadSuffix())
+		if err != nil || len(recs) != 1 || recs[0].Key != "key" {
+			t.Fatalf("Expected to read the key by suffix got %v %v", recs, err)
+		}
+	}
+
+	// the keys of the namespaces don't overlap
+	if _, err := a.Read("other"); err == nil {
+		t.Fatal("Expected the other key to be deleted from the namespace only")
+	}
+
+	v := a.(store.Versioned)
+	if err := v.CompareAndSwap(&store.Record{Key: "key", Value: []byte("swapped")}); !store.IsConflict(err) {
+		t.Fatalf("Expected a conflict got %v", err)
+	} else if err.(*store.ConflictError).Key != "key" {
+		t.Fatalf("Expected the conflict of key got %v", err)
+	}
+}
+
+func TestNotSupported(t *testing.T) {
+	newStore := newTestStore(t, &unversioned{mstore.NewStore()})
+	s := newStore().(store.Versioned)
+
+	if err := s.CompareAndSwap(&store.Record{Key: "key"}); err != store.ErrNotSupported {
+		t.Fatalf("Expected the compare and swap not to be supported got %v", err)
+	}
+
+	w, err := s.Watch("")
+	if err == nil {
+		defer w.Stop()
+		_, err = w.Next()
+	}
+	if err != store.ErrNotSupported {
+		t.Fatalf("Expected the watch not to be supported got %v", err)
+	}
+}
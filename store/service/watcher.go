@@ -0,0 +1,58 @@
+package service
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/stack-labs/stack/store"
+	pb "github.com/stack-labs/stack/store/service/proto"
+)
+
+type serviceWatcher struct {
+	stream pb.Store_WatchService
+	// prefix of the scope trimmed from the keys
+	prefix string
+
+	sync.Mutex
+	stopped bool
+}
+
+func (s *serviceWatcher) Next() (*store.Event, error) {
+	rsp, err := s.stream.Recv()
+	if err != nil {
+		s.Lock()
+		stopped := s.stopped
+		s.Unlock()
+		if stopped {
+			return nil, store.ErrWatcherStopped
+		}
+		return nil, notSupported(err)
+	}
+
+	e := &store.Event{
+		Type: store.Put,
+		Record: &store.Record{
+			Key:     strings.TrimPrefix(rsp.Record.GetKey(), s.prefix),
+			Value:   rsp.Record.GetValue(),
+			Expiry:  time.Duration(rsp.Record.GetExpiry()) * time.Second,
+			Version: rsp.Record.GetVersion(),
+		},
+	}
+	if rsp.Type == store.Delete.String() {
+		e.Type = store.Delete
+	}
+
+	return e, nil
+}
+
+func (s *serviceWatcher) Stop() {
+	s.Lock()
+	defer s.Unlock()
+
+	if s.stopped {
+		return
+	}
+	s.stopped = true
+	s.stream.Close()
+}
@@ -3,29 +3,66 @@ package store
 
 import (
 	"errors"
+	"fmt"
 	"time"
 )
 
 var (
 	// ErrNotFound is returned when a Read key doesn't exist
 	ErrNotFound = errors.New("not found")
+	// ErrNotSupported is returned by a Versioned store whose backend does not
+	// support versioned writes or watches
+	ErrNotSupported = errors.New("not supported")
 )
 
 // Store is a data storage interface
 type Store interface {
-	// List all the known records
-	List() ([]*Record, error)
-	// Read records with keys
-	Read(key ...string) ([]*Record, error)
+	// List the known records, ordered by key when paginated
+	List(opts ...ListOption) ([]*Record, error)
+	// Read the record with key, or with ReadPrefix or ReadSuffix all matching records
+	Read(key string, opts ...ReadOption) ([]*Record, error)
 	// Write records
 	Write(rec ...*Record) error
 	// Delete records with keys
 	Delete(key ...string) error
 }
 
+// Versioned is implemented by stores supporting optimistic concurrency and watches
+type Versioned interface {
+	Store
+	// CompareAndSwap writes the records only if the stored version of every record
+	// equals its Version, zero requiring that the key does not exist. All records
+	// are written or none, a mismatch returns a *ConflictError.
+	CompareAndSwap(rec ...*Record) error
+	// Watch the records with keys that have the prefix
+	Watch(prefix string) (Watcher, error)
+}
+
 // Record represents a data record
 type Record struct {
 	Key    string
 	Value  []byte
 	Expiry time.Duration
+	// Version is the revision of the store the record was last written at,
+	// it is set on write by stores implementing Versioned
+	Version uint64
+}
+
+// ConflictError is returned by CompareAndSwap when a version does not match
+type ConflictError struct {
+	Key string
+	// Expected is the version the record was written with
+	Expected uint64
+	// Current is the version in the store, zero if the key does not exist
+	Current uint64
+}
+
+func (c *ConflictError) Error() string {
+	return fmt.Sprintf("version conflict on %s: expected %d, current %d", c.Key, c.Expected, c.Current)
+}
+
+// IsConflict reports whether the error is a version conflict
+func IsConflict(err error) bool {
+	_, ok := err.(*ConflictError)
+	return ok
 }
@@ -0,0 +1,46 @@
+package store
+
+import "errors"
+
+var (
+	// ErrWatcherStopped is returned by Next once the watcher is stopped
+	ErrWatcherStopped = errors.New("watcher stopped")
+	// ErrWatcherLagging is returned by Next when the watcher fell too far
+	// behind the changes and was stopped, it should watch again and re-read
+	ErrWatcherLagging = errors.New("watcher is lagging behind the changes")
+)
+
+// Watcher streams changes to records
+type Watcher interface {
+	// Next is a blocking call
+	Next() (*Event, error)
+	Stop()
+}
+
+// EventType is the kind of change to a record
+type EventType int
+
+const (
+	// Put is a record that was written
+	Put EventType = iota
+	// Delete is a record that was deleted
+	Delete
+)
+
+func (t EventType) String() string {
+	switch t {
+	case Put:
+		return "put"
+	case Delete:
+		return "delete"
+	default:
+		return "unknown"
+	}
+}
+
+// Event is a change to a record. The record of a delete only has its key
+// and the version of the store the delete happened at.
+type Event struct {
+	Type   EventType
+	Record *Record
+}
@@ -1,17 +1,56 @@
 package sync
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"math"
+	"net/url"
+	"sort"
+	"sync"
 	"time"
 
+	"github.com/google/uuid"
+	"github.com/stack-labs/stack/store"
+	"github.com/stack-labs/stack/store/memory"
+	"github.com/stack-labs/stack/sync/leader"
+	lstore "github.com/stack-labs/stack/sync/leader/store"
 	"github.com/stack-labs/stack/sync/task"
 	"github.com/stack-labs/stack/sync/task/local"
 	"github.com/stack-labs/stack/util/log"
 )
 
+var (
+	// DefaultCronPrefix is the key prefix of the job history in the store
+	DefaultCronPrefix = "cron/"
+	// DefaultHistory is how many executions are kept per job
+	DefaultHistory = 100
+	// MaxCatchUp is the most missed runs a job catches up on
+	MaxCatchUp = 100
+
+	cronsMu sync.RWMutex
+	crons   []Cron
+)
+
 type syncCron struct {
 	opts Options
+	exit chan bool
+	// ctx cancels the pending elections on Stop
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	sync.RWMutex
+	jobs    map[string]*cronJob
+	stopped bool
+}
+
+type cronJob struct {
+	id       string
+	schedule task.Schedule
+	command  task.Command
+	leader   bool
+	next     time.Time
 }
 
 func backoff(attempts int) time.Duration {
@@ -22,67 +61,383 @@ func backoff(attempts int) time.Duration {
 }
 
 func (c *syncCron) Schedule(s task.Schedule, t task.Command) error {
-	id := fmt.Sprintf("%s-%s", s.String(), t.String())
+	if err := s.Validate(); err != nil {
+		return err
+	}
 
-	go func() {
-		// run the scheduler
-		tc := s.Run()
+	j := &cronJob{
+		id:       fmt.Sprintf("%s-%s", s.String(), t.String()),
+		schedule: s,
+		command:  t,
+	}
+
+	c.Lock()
+	if c.stopped {
+		c.Unlock()
+		return errors.New("cron stopped")
+	}
+	c.jobs[j.id] = j
+	c.Unlock()
 
+	go func() {
 		var i int
 
 		for {
 			// leader election
-			e, err := c.opts.Leader.Elect(id)
+			e, err := c.opts.Leader.Elect(j.id, leader.Context(c.ctx))
 			if err != nil {
+				// the election was cancelled by Stop
+				select {
+				case <-c.exit:
+					return
+				default:
+				}
 				log.Logf("[cron] leader election error: %v", err)
-				time.Sleep(backoff(i))
+				select {
+				case <-c.exit:
+					return
+				case <-time.After(backoff(i)):
+				}
 				i++
 				continue
 			}
 
+			// elected after the cron was stopped
+			select {
+			case <-c.exit:
+				e.Resign()
+				return
+			default:
+			}
+
 			i = 0
-			r := e.Revoked()
+			c.setLeader(j, true)
 
-			// execute the task
-		Tick:
-			for {
-				select {
-				// schedule tick
-				case _, ok := <-tc:
-					// ticked once
-					if !ok {
-						break Tick
-					}
-
-					log.Logf("[cron] executing command %s", t.Name)
-					if err := c.opts.Task.Run(t); err != nil {
-						log.Logf("[cron] error executing command %s: %v", t.Name, err)
-					}
-				// leader revoked
-				case <-r:
-					break Tick
-				}
-			}
+			done := c.lead(j, e.Revoked())
 
 			// resign
+			c.setLeader(j, false)
 			e.Resign()
+
+			if done {
+				return
+			}
+
+			select {
+			case <-c.exit:
+				return
+			default:
+			}
 		}
 	}()
 
 	return nil
 }
 
+// lead runs the job until leadership is revoked or the cron is stopped, it returns true once the
+// schedule has no more runs
+func (c *syncCron) lead(j *cronJob, revoked chan bool) bool {
+	s := j.schedule
+	now := time.Now()
+
+	last, err := c.last(j.id)
+	if err != nil {
+		log.Logf("[cron] error reading history of %s: %v", j.command.Name, err)
+	}
+
+	// a one off run only happens once
+	if s.Interval == 0 && len(s.Cron) == 0 {
+		if last != nil {
+			return true
+		}
+		if s.Time.After(now) {
+			return c.wait(j, s.Time, revoked) && c.run(j, s.Time)
+		}
+		return c.run(j, now)
+	}
+
+	from := now
+	if last != nil {
+		var missed []time.Time
+		for t := s.Next(last.Scheduled); !t.IsZero() && !t.After(now); t = s.Next(t) {
+			if len(missed) == MaxCatchUp {
+				missed = missed[1:]
+			}
+			missed = append(missed, t)
+		}
+
+		switch s.Missed {
+		case task.MissedRunOnce:
+			if len(missed) > 0 {
+				missed = missed[len(missed)-1:]
+			}
+		case task.MissedCatchUp:
+		default:
+			missed = nil
+		}
+
+		for _, t := range missed {
+			select {
+			case <-revoked:
+				return false
+			case <-c.exit:
+				return false
+			default:
+			}
+			c.run(j, t)
+		}
+	}
+
+	for {
+		next := s.Next(from)
+		if next.IsZero() {
+			return true
+		}
+		if !c.wait(j, next, revoked) {
+			return false
+		}
+		c.run(j, next)
+
+		// runs overlapping the last one are skipped
+		from = next
+		if now := time.Now(); now.After(from) {
+			from = now
+		}
+	}
+}
+
+// wait blocks until the run at t is due, it returns false if leadership was revoked or the cron
+// was stopped first
+func (c *syncCron) wait(j *cronJob, t time.Time, revoked chan bool) bool {
+	c.Lock()
+	j.next = t
+	c.Unlock()
+
+	timer := time.NewTimer(time.Until(t) + j.schedule.RandomJitter())
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return true
+	case <-revoked:
+		return false
+	case <-c.exit:
+		return false
+	}
+}
+
+// run executes the job and records the execution, it always returns true
+func (c *syncCron) run(j *cronJob, scheduled time.Time) bool {
+	ex := &Execution{
+		Job:       j.id,
+		Node:      c.opts.Node,
+		Scheduled: scheduled,
+		Started:   time.Now(),
+	}
+
+	log.Logf("[cron] executing command %s", j.command.Name)
+	if err := c.opts.Task.Run(j.command); err != nil {
+		log.Logf("[cron] error executing command %s: %v", j.command.Name, err)
+		ex.Error = err.Error()
+	}
+	ex.Ended = time.Now()
+
+	if err := c.record(ex); err != nil {
+		log.Logf("[cron] error recording execution of %s: %v", j.command.Name, err)
+	}
+
+	return true
+}
+
+func (c *syncCron) setLeader(j *cronJob, leader bool) {
+	c.Lock()
+	j.leader = leader
+	j.next = time.Time{}
+	c.Unlock()
+}
+
+func (c *syncCron) prefix(id string) string {
+	return DefaultCronPrefix + url.PathEscape(id) + "/"
+}
+
+// keys returns the history keys of a job, oldest first
+func (c *syncCron) keys(id string) ([]string, error) {
+	recs, err := c.opts.Store.List(store.ListPrefix(c.prefix(id)), store.ListKeysOnly())
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]string, 0, len(recs))
+	for _, r := range recs {
+		keys = append(keys, r.Key)
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+func (c *syncCron) record(ex *Execution) error {
+	b, err := json.Marshal(ex)
+	if err != nil {
+		return err
+	}
+
+	key := c.prefix(ex.Job) + fmt.Sprintf("%020d", ex.Started.UnixNano())
+	if err := c.opts.Store.Write(&store.Record{Key: key, Value: b}); err != nil {
+		return err
+	}
+
+	keys, err := c.keys(ex.Job)
+	if err != nil {
+		return err
+	}
+
+	// trim the oldest executions
+	for len(keys) > c.opts.History {
+		if err := c.opts.Store.Delete(keys[0]); err != nil && err != store.ErrNotFound {
+			return err
+		}
+		keys = keys[1:]
+	}
+
+	return nil
+}
+
+// last returns the most recent execution of a job, nil if it never ran
+func (c *syncCron) last(id string) (*Execution, error) {
+	hist, err := c.History(id, 1)
+	if err != nil || len(hist) == 0 {
+		return nil, err
+	}
+	return hist[0], nil
+}
+
+func (c *syncCron) History(id string, limit int) ([]*Execution, error) {
+	keys, err := c.keys(id)
+	if err != nil {
+		return nil, err
+	}
+
+	var history []*Execution
+
+	for i := len(keys) - 1; i >= 0; i-- {
+		if limit > 0 && len(history) == limit {
+			break
+		}
+
+		recs, err := c.opts.Store.Read(keys[i])
+		if err == store.ErrNotFound {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+
+		ex := new(Execution)
+		if err := json.Unmarshal(recs[0].Value, ex); err != nil {
+			return nil, err
+		}
+		history = append(history, ex)
+	}
+
+	return history, nil
+}
+
+func (c *syncCron) Jobs() []*Job {
+	c.RLock()
+	defer c.RUnlock()
+
+	jobs := make([]*Job, 0, len(c.jobs))
+	for _, j := range c.jobs {
+		next := j.next
+		if !j.leader {
+			next = j.schedule.Next(time.Now())
+		}
+
+		jobs = append(jobs, &Job{
+			Id:       j.id,
+			Name:     j.command.Name,
+			Schedule: j.schedule.String(),
+			Leader:   j.leader,
+			Next:     next,
+		})
+	}
+
+	sort.Slice(jobs, func(i, k int) bool {
+		return jobs[i].Id < jobs[k].Id
+	})
+
+	return jobs
+}
+
+// Stop ends the jobs and removes the cron from Crons. The pending elections are
+// cancelled, a running command is not interrupted.
+func (c *syncCron) Stop() error {
+	c.Lock()
+	if c.stopped {
+		c.Unlock()
+		return nil
+	}
+	c.stopped = true
+	close(c.exit)
+	c.cancel()
+	c.Unlock()
+
+	cronsMu.Lock()
+	for i, cr := range crons {
+		if cr == Cron(c) {
+			crons = append(crons[:i], crons[i+1:]...)
+			break
+		}
+	}
+	cronsMu.Unlock()
+
+	return nil
+}
+
+// Crons returns the running crons created in this process
+func Crons() []Cron {
+	cronsMu.RLock()
+	defer cronsMu.RUnlock()
+
+	cs := make([]Cron, len(crons))
+	copy(cs, crons)
+	return cs
+}
+
 func NewCron(opts ...Option) Cron {
 	var options Options
 	for _, o := range opts {
 		o(&options)
 	}
 
+	if options.Store == nil {
+		options.Store = memory.NewStore()
+		if options.Leader == nil {
+			log.Warnf("[cron] no store or leader set, every process elects itself and runs the jobs, use a shared store to run them once")
+		}
+	}
+	if len(options.Node) == 0 {
+		options.Node = uuid.New().String()
+	}
+	if options.Leader == nil {
+		options.Leader = lstore.NewLeader(lstore.Store(options.Store), lstore.Node(options.Node))
+	}
 	if options.Task == nil {
 		options.Task = local.NewTask()
 	}
+	if options.History <= 0 {
+		options.History = DefaultHistory
+	}
 
-	return &syncCron{
+	c := &syncCron{
 		opts: options,
+		exit: make(chan bool),
+		jobs: make(map[string]*cronJob),
 	}
+	c.ctx, c.cancel = context.WithCancel(context.Background())
+
+	cronsMu.Lock()
+	crons = append(crons, c)
+	cronsMu.Unlock()
+
+	return c
 }
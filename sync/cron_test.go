@@ -0,0 +1,198 @@
+package sync
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stack-labs/stack/store/memory"
+	"github.com/stack-labs/stack/sync/leader"
+	lstore "github.com/stack-labs/stack/sync/leader/store"
+	"github.com/stack-labs/stack/sync/task"
+)
+
+// testLeader sends the result of every election
+type testLeader struct {
+	leader.Leader
+	elected chan error
+}
+
+func (l *testLeader) Elect(id string, opts ...leader.ElectOption) (leader.Elected, error) {
+	e, err := l.Leader.Elect(id, opts...)
+	l.elected <- err
+	return e, err
+}
+
+func TestCronHistory(t *testing.T) {
+	c := NewCron(WithNode("node-1"), WithHistory(3))
+
+	var runs int
+	err := c.Schedule(task.Schedule{Interval: time.Millisecond * 20}, task.Command{
+		Name: "test",
+		Func: func() error {
+			runs++
+			return errors.New("failed")
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	time.Sleep(time.Millisecond * 200)
+
+	jobs := c.Jobs()
+	if len(jobs) != 1 {
+		t.Fatalf("expected 1 job, got %d", len(jobs))
+	}
+	if !jobs[0].Leader || jobs[0].Next.IsZero() {
+		t.Fatalf("expected the job to be led with a next run, got %+v", jobs[0])
+	}
+
+	history, err := c.History(jobs[0].Id, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history) != 3 {
+		t.Fatalf("expected history trimmed to 3, got %d after %d runs", len(history), runs)
+	}
+	if history[0].Node != "node-1" || history[0].Error != "failed" {
+		t.Fatalf("unexpected execution %+v", history[0])
+	}
+	if !history[0].Started.After(history[1].Started) {
+		t.Fatal("expected newest execution first")
+	}
+}
+
+func TestCronMissed(t *testing.T) {
+	testData := []struct {
+		policy task.MissedPolicy
+		runs   int
+	}{
+		{task.MissedSkip, 0},
+		{task.MissedRunOnce, 1},
+		{task.MissedCatchUp, 5},
+	}
+
+	for _, d := range testData {
+		s := memory.NewStore()
+		sched := task.Schedule{
+			Time:     time.Now().Add(-5*time.Hour - 30*time.Minute),
+			Interval: time.Hour,
+			Missed:   d.policy,
+		}
+		cmd := task.Command{Name: "test", Func: func() error { return nil }}
+
+		// the last run was at the start of the schedule
+		c := NewCron(WithStore(s)).(*syncCron)
+		if err := c.record(&Execution{
+			Job:       sched.String() + "-" + cmd.String(),
+			Scheduled: sched.Time,
+			Started:   sched.Time,
+			Ended:     sched.Time,
+		}); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := c.Schedule(sched, cmd); err != nil {
+			t.Fatal(err)
+		}
+
+		time.Sleep(time.Millisecond * 100)
+
+		history, err := c.History(sched.String()+"-"+cmd.String(), 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := len(history) - 1; got != d.runs {
+			t.Errorf("policy %d: expected %d missed runs, got %d", d.policy, d.runs, got)
+		}
+	}
+}
+
+func TestCronInvalid(t *testing.T) {
+	c := NewCron()
+	if err := c.Schedule(task.Schedule{Cron: "* * *"}, task.Command{Name: "test"}); err == nil {
+		t.Fatal("expected invalid cron expression error")
+	}
+}
+
+func TestCronStop(t *testing.T) {
+	c := NewCron()
+	if err := c.Schedule(task.Schedule{Interval: time.Millisecond * 10}, task.Command{
+		Name: "test",
+		Func: func() error { return nil },
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	registered := func() bool {
+		for _, cr := range Crons() {
+			if cr == c {
+				return true
+			}
+		}
+		return false
+	}
+	if !registered() {
+		t.Fatal("expected cron to be registered")
+	}
+
+	if err := c.Stop(); err != nil {
+		t.Fatal(err)
+	}
+	if registered() {
+		t.Fatal("expected stopped cron to be removed")
+	}
+
+	if err := c.Schedule(task.Schedule{Interval: time.Second}, task.Command{Name: "test"}); err == nil {
+		t.Fatal("expected scheduling on a stopped cron to fail")
+	}
+}
+
+func TestCronStopElection(t *testing.T) {
+	s := memory.NewStore()
+	sched := task.Schedule{Interval: time.Hour}
+	cmd := task.Command{Name: "test", Func: func() error { return nil }}
+
+	c1 := NewCron(WithStore(s))
+	defer c1.Stop()
+	if err := c1.Schedule(sched, cmd); err != nil {
+		t.Fatal(err)
+	}
+
+	// wait for the first cron to lead the job
+	for i := 0; ; i++ {
+		if jobs := c1.Jobs(); len(jobs) == 1 && jobs[0].Leader {
+			break
+		}
+		if i == 100 {
+			t.Fatal("expected the first cron to lead the job")
+		}
+		time.Sleep(time.Millisecond * 10)
+	}
+
+	l := &testLeader{Leader: lstore.NewLeader(lstore.Store(s)), elected: make(chan error, 1)}
+	c2 := NewCron(WithStore(s), WithLeader(l))
+	if err := c2.Schedule(sched, cmd); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case err := <-l.elected:
+		t.Fatalf("expected the second cron to wait for the election, got %v", err)
+	case <-time.After(time.Millisecond * 100):
+	}
+
+	if err := c2.Stop(); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case err := <-l.elected:
+		if err == nil {
+			t.Fatal("expected the election to be cancelled")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected stop to cancel the election")
+	}
+}
@@ -1,6 +1,15 @@
 // Package event provides a distributed log interface
 package event
 
+import "errors"
+
+var (
+	// ErrClosed is returned when using a closed log
+	ErrClosed = errors.New("log is closed")
+	// ErrInvalidOffset is returned when seeking before the start or past the end
+	ErrInvalidOffset = errors.New("invalid offset")
+)
+
 // Event provides a distributed log interface
 type Event interface {
 	// Log retrieves the log with an id/name
@@ -0,0 +1,462 @@
+// Package file is an event log stored in append only segment files
+package file
+
+import (
+	"errors"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/stack-labs/stack/sync/event"
+	"github.com/stack-labs/stack/util/file"
+	"github.com/stack-labs/stack/util/log"
+)
+
+var (
+	// ErrClosed is returned when using a closed log
+	ErrClosed = event.ErrClosed
+	// ErrInvalidOffset is returned when seeking before the start or past the end
+	ErrInvalidOffset = event.ErrInvalidOffset
+)
+
+// Log is an event.Log which also reports its positions
+type Log interface {
+	event.Log
+	// Offset is the offset of the record returned by the next Read
+	Offset() int64
+	// Size is the offset the next written record gets
+	Size() int64
+	// Written is the offset of the last record written through the handle,
+	// -1 before the first write
+	Written() int64
+}
+
+type fileEvent struct {
+	opts Options
+
+	sync.Mutex
+	logs map[string]*segmentLog
+}
+
+// lockName is the file locked while a process writes to a log
+const lockName = "lock"
+
+// segmentLog is the state of a log shared by all of its handles. Several
+// processes may write a log, each write takes the lock file and indexes the
+// records written by others before appending
+type segmentLog struct {
+	id   string
+	dir  string
+	opts Options
+	lock *os.File
+
+	sync.RWMutex
+	segments []*segment
+	size     int64
+	refs     int
+	// dirty is set while writes wait for a periodic sync
+	dirty bool
+	exit  chan bool
+}
+
+// fileLog is a handle with its own read position
+type fileLog struct {
+	event *fileEvent
+	log   *segmentLog
+
+	sync.Mutex
+	offset  int64
+	written int64
+	closed  bool
+}
+
+func (e *fileEvent) Log(id string) (event.Log, error) {
+	if len(id) == 0 || id == "." || id == ".." {
+		return nil, errors.New("invalid log id")
+	}
+
+	e.Lock()
+	defer e.Unlock()
+
+	l, ok := e.logs[id]
+	if !ok {
+		var err error
+		l, err = openLog(id, filepath.Join(e.opts.Dir, url.PathEscape(id)), e.opts)
+		if err != nil {
+			return nil, err
+		}
+		e.logs[id] = l
+	}
+	l.refs++
+
+	return &fileLog{event: e, log: l, written: -1}, nil
+}
+
+func (e *fileEvent) release(l *segmentLog) error {
+	e.Lock()
+	defer e.Unlock()
+
+	l.refs--
+	if l.refs > 0 {
+		return nil
+	}
+	delete(e.logs, l.id)
+	return l.close()
+}
+
+func openLog(id, dir string, opts Options) (*segmentLog, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+
+	lock, err := os.OpenFile(filepath.Join(dir, lockName), os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return nil, err
+	}
+
+	segments, err := openSegments(dir)
+	if err != nil {
+		lock.Close()
+		return nil, err
+	}
+
+	l := &segmentLog{
+		id:       id,
+		dir:      dir,
+		opts:     opts,
+		lock:     lock,
+		segments: segments,
+		exit:     make(chan bool),
+	}
+	l.size = l.end()
+
+	for _, s := range segments {
+		if s.err != nil {
+			log.Errorf("Error reading event log %s: %v", id, s.err)
+		}
+	}
+
+	if opts.Sync == SyncPeriodic {
+		go l.run()
+	}
+
+	return l, nil
+}
+
+// run flushes outstanding writes every sync interval until the log is closed
+func (l *segmentLog) run() {
+	t := time.NewTicker(l.opts.SyncInterval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-l.exit:
+			return
+		case <-t.C:
+			l.Lock()
+			if err := l.sync(); err != nil {
+				log.Errorf("Error syncing event log %s: %v", l.id, err)
+			}
+			l.Unlock()
+		}
+	}
+}
+
+// sync flushes the last segment if it has outstanding writes, called with the lock held
+func (l *segmentLog) sync() error {
+	if !l.dirty || len(l.segments) == 0 {
+		return nil
+	}
+	if err := l.segments[len(l.segments)-1].file.Sync(); err != nil {
+		return err
+	}
+	l.dirty = false
+	return nil
+}
+
+// end is the offset after the last indexed record, called with the lock held
+func (l *segmentLog) end() int64 {
+	n := len(l.segments)
+	if n == 0 {
+		return 0
+	}
+	last := l.segments[n-1]
+	return last.base + last.len()
+}
+
+// refresh indexes the records and segments written by other processes,
+// called with the lock held
+func (l *segmentLog) refresh() error {
+	n := len(l.segments)
+	if n > 0 {
+		last := l.segments[n-1]
+		if _, err := last.scan(); err != nil {
+			return err
+		}
+		l.size = l.end()
+
+		// segments are only rolled once the last one is full
+		if last.err != nil || last.size < l.opts.SegmentSize {
+			return nil
+		}
+	}
+
+	bases, err := segmentBases(l.dir)
+	if err != nil {
+		return err
+	}
+
+	for _, base := range bases {
+		// a segment starts where the previous one ends
+		if n > 0 && base <= l.segments[n-1].base || base != l.size {
+			continue
+		}
+		s, err := openSegment(l.dir, base)
+		if err != nil {
+			return err
+		}
+		l.segments = append(l.segments, s)
+		n++
+		l.size = l.end()
+	}
+
+	return nil
+}
+
+// update indexes the records written by other processes
+func (l *segmentLog) update() error {
+	l.Lock()
+	defer l.Unlock()
+	return l.refresh()
+}
+
+// write appends the record and returns its offset
+func (l *segmentLog) write(r *event.Record) (int64, error) {
+	l.Lock()
+	defer l.Unlock()
+
+	if err := file.Lock(l.lock); err != nil {
+		return 0, err
+	}
+	defer file.Unlock(l.lock)
+
+	if err := l.refresh(); err != nil {
+		return 0, err
+	}
+
+	n := len(l.segments)
+	if n > 0 {
+		// no record is being written while the lock is held, an incomplete
+		// one at the tail was torn by a crash
+		if err := l.segments[n-1].repair(); err != nil {
+			return 0, err
+		}
+	}
+
+	if n == 0 || l.segments[n-1].size >= l.opts.SegmentSize {
+		// flush the segment being rolled, only the last one is synced later
+		if err := l.sync(); err != nil {
+			return 0, err
+		}
+
+		s, err := openSegment(l.dir, l.size)
+		if err != nil {
+			return 0, err
+		}
+		l.segments = append(l.segments, s)
+		n++
+
+		// the new segment file must survive a crash too
+		if l.opts.Sync == SyncEveryWrite {
+			if err := syncDir(l.dir); err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	s := l.segments[n-1]
+	if err := s.append(r); err != nil {
+		return 0, err
+	}
+	offset := l.size
+	l.size++
+
+	switch l.opts.Sync {
+	case SyncEveryWrite:
+		return offset, s.file.Sync()
+	case SyncPeriodic:
+		l.dirty = true
+	}
+
+	return offset, nil
+}
+
+func (l *segmentLog) read(offset int64) (*event.Record, error) {
+	l.RLock()
+	size := l.size
+	l.RUnlock()
+
+	if offset >= size {
+		if err := l.update(); err != nil {
+			return nil, err
+		}
+	}
+
+	l.RLock()
+	defer l.RUnlock()
+
+	if offset >= l.size {
+		// reading stops at a corrupt record
+		if n := len(l.segments); n > 0 && l.segments[n-1].err != nil {
+			return nil, l.segments[n-1].err
+		}
+		return nil, io.EOF
+	}
+
+	// the last segment with a base at or before the offset holds it
+	i := sort.Search(len(l.segments), func(i int) bool {
+		return l.segments[i].base > offset
+	}) - 1
+	if i < 0 {
+		return nil, ErrInvalidOffset
+	}
+
+	return l.segments[i].read(offset)
+}
+
+func (l *segmentLog) len() int64 {
+	l.Lock()
+	defer l.Unlock()
+
+	if err := l.refresh(); err != nil {
+		log.Errorf("Error reading event log %s: %v", l.id, err)
+	}
+	return l.size
+}
+
+func (l *segmentLog) close() error {
+	close(l.exit)
+
+	l.Lock()
+	defer l.Unlock()
+
+	err := l.sync()
+	for _, s := range l.segments {
+		if cerr := s.file.Close(); cerr != nil {
+			err = cerr
+		}
+	}
+	l.segments = nil
+
+	if cerr := l.lock.Close(); cerr != nil {
+		err = cerr
+	}
+	return err
+}
+
+func (f *fileLog) Close() error {
+	f.Lock()
+	defer f.Unlock()
+
+	if f.closed {
+		return nil
+	}
+	f.closed = true
+	return f.event.release(f.log)
+}
+
+func (f *fileLog) Id() string {
+	return f.log.id
+}
+
+// Read returns the next record or io.EOF once the end of the log is reached
+func (f *fileLog) Read() (*event.Record, error) {
+	f.Lock()
+	defer f.Unlock()
+
+	if f.closed {
+		return nil, ErrClosed
+	}
+
+	r, err := f.log.read(f.offset)
+	if err != nil {
+		return nil, err
+	}
+	f.offset++
+	return r, nil
+}
+
+// Seek sets the offset of the next record read, the size of the log seeks to its end
+func (f *fileLog) Seek(offset int64) error {
+	f.Lock()
+	defer f.Unlock()
+
+	if f.closed {
+		return ErrClosed
+	}
+	if offset < 0 || offset > f.log.len() {
+		return ErrInvalidOffset
+	}
+	f.offset = offset
+	return nil
+}
+
+func (f *fileLog) Write(r *event.Record) error {
+	f.Lock()
+	closed := f.closed
+	f.Unlock()
+
+	if closed {
+		return ErrClosed
+	}
+
+	offset, err := f.log.write(r)
+	if err != nil {
+		return err
+	}
+
+	f.Lock()
+	f.written = offset
+	f.Unlock()
+	return nil
+}
+
+func (f *fileLog) Offset() int64 {
+	f.Lock()
+	defer f.Unlock()
+	return f.offset
+}
+
+func (f *fileLog) Size() int64 {
+	return f.log.len()
+}
+
+func (f *fileLog) Written() int64 {
+	f.Lock()
+	defer f.Unlock()
+	return f.written
+}
+
+// NewEvent returns an event.Event whose logs are kept in segment files
+func NewEvent(opts ...Option) event.Event {
+	options := Options{
+		Dir:          DefaultDir,
+		SegmentSize:  DefaultSegmentSize,
+		SyncInterval: DefaultSyncInterval,
+	}
+	for _, o := range opts {
+		o(&options)
+	}
+	if options.SyncInterval <= 0 {
+		options.SyncInterval = DefaultSyncInterval
+	}
+
+	return &fileEvent{
+		opts: options,
+		logs: make(map[string]*segmentLog),
+	}
+}
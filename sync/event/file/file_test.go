@@ -0,0 +1,235 @@
+package file
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stack-labs/stack/sync/event"
+)
+
+func TestLog(t *testing.T) {
+	dir, err := ioutil.TempDir("", "event")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// small segments to roll on every couple of records
+	e := NewEvent(Dir(dir), SegmentSize(64))
+
+	l, err := e.Log("test")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 10; i++ {
+		if err := l.Write(&event.Record{
+			Metadata: map[string]interface{}{"i": float64(i)},
+			Data:     []byte("record"),
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for i := 0; i < 10; i++ {
+		r, err := l.Read()
+		if err != nil {
+			t.Fatalf("Unexpected read error %v", err)
+		}
+		if r.Metadata["i"] != float64(i) || string(r.Data) != "record" {
+			t.Fatalf("Unexpected record %d: %+v", i, r)
+		}
+	}
+	if _, err := l.Read(); err != io.EOF {
+		t.Fatalf("Expected io.EOF got %v", err)
+	}
+
+	if err := l.Seek(7); err != nil {
+		t.Fatal(err)
+	}
+	if r, _ := l.Read(); r.Metadata["i"] != float64(7) {
+		t.Fatalf("Expected record 7 got %+v", r)
+	}
+	if err := l.Seek(11); err != ErrInvalidOffset {
+		t.Fatalf("Expected invalid offset got %v", err)
+	}
+	l.Close()
+
+	segments, _ := filepath.Glob(filepath.Join(dir, "test", "*"+segmentExt))
+	if len(segments) < 2 {
+		t.Fatalf("Expected rolled segments got %d", len(segments))
+	}
+
+	// simulate a torn write at the tail of the last segment
+	last := segments[len(segments)-1]
+	f, err := os.OpenFile(last, os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Write([]byte{0, 0, 1, 0, 1, 2})
+	f.Close()
+
+	// reopen and check the records survived
+	l, err = NewEvent(Dir(dir), SegmentSize(64)).Log("test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	if size := l.(Log).Size(); size != 10 {
+		t.Fatalf("Expected 10 records after reopen got %d", size)
+	}
+	if err := l.Write(&event.Record{Data: []byte("after")}); err != nil {
+		t.Fatal(err)
+	}
+	if err := l.Seek(10); err != nil {
+		t.Fatal(err)
+	}
+	if r, err := l.Read(); err != nil || string(r.Data) != "after" {
+		t.Fatalf("Unexpected record after reopen %+v %v", r, err)
+	}
+}
+
+func TestSyncPolicies(t *testing.T) {
+	for _, p := range []SyncPolicy{SyncNone, SyncEveryWrite, SyncPeriodic} {
+		dir, err := ioutil.TempDir("", "event")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer os.RemoveAll(dir)
+
+		e := NewEvent(Dir(dir), SegmentSize(64), Sync(p), SyncInterval(time.Millisecond*10))
+
+		l, err := e.Log("test")
+		if err != nil {
+			t.Fatal(err)
+		}
+		for i := 0; i < 5; i++ {
+			if err := l.Write(&event.Record{Data: []byte("record")}); err != nil {
+				t.Fatalf("Policy %d: unexpected write error %v", p, err)
+			}
+		}
+		// let the periodic sync run
+		time.Sleep(time.Millisecond * 30)
+		if err := l.Close(); err != nil {
+			t.Fatal(err)
+		}
+
+		// reopened from disk
+		l, err = NewEvent(Dir(dir)).Log("test")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if size := l.(Log).Size(); size != 5 {
+			t.Fatalf("Policy %d: expected 5 records got %d", p, size)
+		}
+		l.Close()
+	}
+}
+
+func TestSharedLog(t *testing.T) {
+	dir, err := ioutil.TempDir("", "event")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	// two events on the same dir are like two processes
+	a, err := NewEvent(Dir(dir), SegmentSize(64)).Log("test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer a.Close()
+	b, err := NewEvent(Dir(dir), SegmentSize(64)).Log("test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer b.Close()
+
+	for i := 0; i < 10; i++ {
+		l := a
+		if i%2 == 1 {
+			l = b
+		}
+		if err := l.Write(&event.Record{Metadata: map[string]interface{}{"i": float64(i)}}); err != nil {
+			t.Fatal(err)
+		}
+		if w := l.(Log).Written(); w != int64(i) {
+			t.Fatalf("Expected the record written at %d got %d", i, w)
+		}
+	}
+
+	for _, l := range []event.Log{a, b} {
+		if size := l.(Log).Size(); size != 10 {
+			t.Fatalf("Expected 10 records got %d", size)
+		}
+		for i := 0; i < 10; i++ {
+			r, err := l.Read()
+			if err != nil {
+				t.Fatalf("Unexpected read error %v", err)
+			}
+			if r.Metadata["i"] != float64(i) {
+				t.Fatalf("Expected record %d got %+v", i, r)
+			}
+		}
+		if _, err := l.Read(); err != io.EOF {
+			t.Fatalf("Expected io.EOF got %v", err)
+		}
+	}
+}
+
+func TestCorruptRecord(t *testing.T) {
+	dir, err := ioutil.TempDir("", "event")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	l, err := NewEvent(Dir(dir)).Log("test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 3; i++ {
+		if err := l.Write(&event.Record{Data: []byte("record")}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	l.Close()
+
+	// flip a byte of the second record
+	path := filepath.Join(dir, "test", segmentName(0))
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	size := len(b)
+	b[size/3+headerSize+1] ^= 0xff
+	if err := ioutil.WriteFile(path, b, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	l, err = NewEvent(Dir(dir)).Log("test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	if _, err := l.Read(); err != nil {
+		t.Fatalf("Unexpected read error %v", err)
+	}
+	if _, err := l.Read(); err == nil || err == io.EOF {
+		t.Fatalf("Expected a corrupt record got %v", err)
+	}
+	if err := l.Write(&event.Record{}); err == nil {
+		t.Fatal("Expected writes to fail after a corrupt record")
+	}
+
+	// the records after the corrupt one are kept
+	if fi, err := os.Stat(path); err != nil || fi.Size() != int64(size) {
+		t.Fatalf("Expected the segment to be kept got %v %v", fi, err)
+	}
+}
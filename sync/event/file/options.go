@@ -0,0 +1,69 @@
+package file
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// SyncPolicy decides when writes are flushed to stable storage
+type SyncPolicy int
+
+const (
+	// SyncNone leaves flushing writes to the operating system
+	SyncNone SyncPolicy = iota
+	// SyncEveryWrite flushes each write before it returns
+	SyncEveryWrite
+	// SyncPeriodic flushes outstanding writes every sync interval
+	SyncPeriodic
+)
+
+var (
+	// DefaultDir is the directory logs are kept in when none is set
+	DefaultDir = filepath.Join(os.TempDir(), "stack", "event")
+	// DefaultSegmentSize is the size in bytes after which a new segment is started
+	DefaultSegmentSize int64 = 64 << 20
+	// DefaultSyncInterval is how often writes are flushed with SyncPeriodic
+	DefaultSyncInterval = time.Second
+)
+
+type Options struct {
+	// Dir is the directory holding a sub directory of segments per log
+	Dir string
+	// SegmentSize is the size in bytes a segment grows to before it is rolled
+	SegmentSize int64
+	// Sync is when writes are flushed to stable storage
+	Sync SyncPolicy
+	// SyncInterval is how often writes are flushed with SyncPeriodic
+	SyncInterval time.Duration
+}
+
+type Option func(o *Options)
+
+// Dir sets the directory the logs are stored in
+func Dir(d string) Option {
+	return func(o *Options) {
+		o.Dir = d
+	}
+}
+
+// SegmentSize sets the size in bytes at which segments are rolled
+func SegmentSize(s int64) Option {
+	return func(o *Options) {
+		o.SegmentSize = s
+	}
+}
+
+// Sync sets when writes are flushed to stable storage, SyncNone by default
+func Sync(p SyncPolicy) Option {
+	return func(o *Options) {
+		o.Sync = p
+	}
+}
+
+// SyncInterval sets how often writes are flushed with SyncPeriodic
+func SyncInterval(d time.Duration) Option {
+	return func(o *Options) {
+		o.SyncInterval = d
+	}
+}
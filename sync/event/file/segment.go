@@ -0,0 +1,225 @@
+package file
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/stack-labs/stack/sync/event"
+)
+
+const (
+	segmentExt = ".log"
+	// frame header is the payload length followed by its crc32
+	headerSize = 8
+)
+
+// segment is a single append only file of records starting at base offset
+type segment struct {
+	base int64
+	file *os.File
+	size int64
+	// positions holds the file position of each record in the segment
+	positions []int64
+	// err is set when a corrupt record is found, the records after it are not read
+	err error
+}
+
+func segmentName(base int64) string {
+	return fmt.Sprintf("%020d%s", base, segmentExt)
+}
+
+// segmentBases returns the bases of the segments in dir in offset order
+func segmentBases(dir string) ([]int64, error) {
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var bases []int64
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasSuffix(name, segmentExt) {
+			continue
+		}
+		base, err := strconv.ParseInt(strings.TrimSuffix(name, segmentExt), 10, 64)
+		if err != nil {
+			continue
+		}
+		bases = append(bases, base)
+	}
+	sort.Slice(bases, func(i, j int) bool { return bases[i] < bases[j] })
+
+	return bases, nil
+}
+
+// openSegments loads the segments in dir in offset order
+func openSegments(dir string) ([]*segment, error) {
+	bases, err := segmentBases(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	segments := make([]*segment, 0, len(bases))
+	for _, base := range bases {
+		s, err := openSegment(dir, base)
+		if err != nil {
+			for _, s := range segments {
+				s.file.Close()
+			}
+			return nil, err
+		}
+		segments = append(segments, s)
+	}
+
+	return segments, nil
+}
+
+// openSegment opens or creates the segment and indexes its records
+func openSegment(dir string, base int64) (*segment, error) {
+	f, err := os.OpenFile(filepath.Join(dir, segmentName(base)), os.O_CREATE|os.O_RDWR|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &segment{base: base, file: f}
+	if _, err := s.scan(); err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// scan indexes the records appended since the last scan, including those
+// written by other processes. It stops at a record which is not complete,
+// which is either being written or torn by a crash, and returns the size of
+// the file. A corrupt record followed by more data sets the segment error.
+func (s *segment) scan() (int64, error) {
+	fi, err := s.file.Stat()
+	if err != nil {
+		return 0, err
+	}
+	end := fi.Size()
+
+	header := make([]byte, headerSize)
+
+	for s.err == nil && end-s.size >= headerSize {
+		if _, err := s.file.ReadAt(header, s.size); err != nil {
+			return 0, err
+		}
+		length := int64(binary.BigEndian.Uint32(header[:4]))
+		if length > end-s.size-headerSize {
+			break
+		}
+		payload := make([]byte, length)
+		if _, err := s.file.ReadAt(payload, s.size+headerSize); err != nil {
+			return 0, err
+		}
+		if crc32.ChecksumIEEE(payload) != binary.BigEndian.Uint32(header[4:]) {
+			// the last record may still be being written
+			if s.size+headerSize+length < end {
+				s.err = fmt.Errorf("corrupt record %d at position %d of segment %s",
+					s.base+s.len(), s.size, segmentName(s.base))
+			}
+			break
+		}
+		s.positions = append(s.positions, s.size)
+		s.size += headerSize + length
+	}
+
+	return end, nil
+}
+
+// repair truncates a torn record left at the tail by a crash mid write, it
+// is called holding the write lock of the log so no record is being written
+func (s *segment) repair() error {
+	end, err := s.scan()
+	if err != nil {
+		return err
+	}
+	if s.err != nil || end == s.size {
+		return s.err
+	}
+	return s.file.Truncate(s.size)
+}
+
+// syncDir flushes the directory entries of dir, such as newly created segments
+func syncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+	return d.Sync()
+}
+
+func (s *segment) len() int64 {
+	return int64(len(s.positions))
+}
+
+func (s *segment) append(r *event.Record) error {
+	payload, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+
+	buf := make([]byte, headerSize+len(payload))
+	binary.BigEndian.PutUint32(buf[:4], uint32(len(payload)))
+	binary.BigEndian.PutUint32(buf[4:headerSize], crc32.ChecksumIEEE(payload))
+	copy(buf[headerSize:], payload)
+
+	if _, err := s.file.Write(buf); err != nil {
+		// drop a partial write so the next append starts on a frame boundary
+		s.file.Truncate(s.size)
+		return err
+	}
+
+	s.positions = append(s.positions, s.size)
+	s.size += int64(len(buf))
+	return nil
+}
+
+func (s *segment) read(offset int64) (*event.Record, error) {
+	i := offset - s.base
+	if i >= s.len() {
+		// the records after a corrupt one are unreadable
+		if s.err != nil {
+			return nil, s.err
+		}
+		return nil, io.EOF
+	}
+
+	pos := s.positions[i]
+	end := s.size
+	if i+1 < s.len() {
+		end = s.positions[i+1]
+	}
+
+	header := make([]byte, headerSize)
+	if _, err := s.file.ReadAt(header, pos); err != nil {
+		return nil, err
+	}
+	length := int64(binary.BigEndian.Uint32(header[:4]))
+	if length != end-pos-headerSize {
+		return nil, fmt.Errorf("record %d of segment %s changed size", offset, segmentName(s.base))
+	}
+	payload := make([]byte, length)
+	if _, err := s.file.ReadAt(payload, pos+headerSize); err != nil {
+		return nil, err
+	}
+
+	var r event.Record
+	if err := json.Unmarshal(payload, &r); err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
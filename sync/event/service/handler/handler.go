@@ -0,0 +1,245 @@
+// Package handler implements the event service, register it with
+// pb.RegisterEventHandler(server, &handler.Event{Event: file.NewEvent()})
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/stack-labs/stack/sync/event"
+	pb "github.com/stack-labs/stack/sync/event/service/proto"
+	"github.com/stack-labs/stack/util/errors"
+)
+
+var (
+	// DefaultBatchSize is the most records returned by a Read
+	DefaultBatchSize int64 = 100
+	// PollInterval is how often a tail checks the end of the log for new records
+	PollInterval = 100 * time.Millisecond
+)
+
+// sizer is a log which reports the offset of its end
+type sizer interface {
+	Size() int64
+}
+
+// writer is a log which reports the offset of the last record it wrote
+type writer interface {
+	Written() int64
+}
+
+// handle is an open log shared by the appends and reads of its id
+type handle struct {
+	sync.Mutex
+	log event.Log
+}
+
+type Event struct {
+	Event event.Event
+
+	sync.Mutex
+	logs map[string]*handle
+}
+
+func logError(err error) error {
+	switch err {
+	case event.ErrInvalidOffset:
+		return errors.BadRequest("stack.rpc.event", err.Error())
+	default:
+		return errors.InternalServerError("stack.rpc.event", err.Error())
+	}
+}
+
+func (e *Event) open(id string) (event.Log, error) {
+	if len(id) == 0 {
+		return nil, errors.BadRequest("stack.rpc.event", "log id is required")
+	}
+	l, err := e.Event.Log(id)
+	if err != nil {
+		return nil, logError(err)
+	}
+	return l, nil
+}
+
+// handle returns the cached log of the id, it is opened once
+func (e *Event) handle(id string) (*handle, error) {
+	e.Lock()
+	defer e.Unlock()
+
+	if h, ok := e.logs[id]; ok {
+		return h, nil
+	}
+
+	l, err := e.open(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if e.logs == nil {
+		e.logs = make(map[string]*handle)
+	}
+	h := &handle{log: l}
+	e.logs[id] = h
+
+	return h, nil
+}
+
+// Close the cached logs
+func (e *Event) Close() error {
+	e.Lock()
+	defer e.Unlock()
+
+	var err error
+	for id, h := range e.logs {
+		h.Lock()
+		if cerr := h.log.Close(); cerr != nil {
+			err = cerr
+		}
+		h.Unlock()
+		delete(e.logs, id)
+	}
+	return err
+}
+
+func toProto(offset int64, r *event.Record) (*pb.Record, error) {
+	rec := &pb.Record{
+		Offset: offset,
+		Data:   r.Data,
+	}
+	if len(r.Metadata) > 0 {
+		md, err := json.Marshal(r.Metadata)
+		if err != nil {
+			return nil, err
+		}
+		rec.Metadata = md
+	}
+	return rec, nil
+}
+
+// Append writes the records in order and returns the offset of each, a
+// failed write leaves the records before it appended
+func (e *Event) Append(ctx context.Context, req *pb.AppendRequest, rsp *pb.AppendResponse) error {
+	h, err := e.handle(req.Id)
+	if err != nil {
+		return err
+	}
+
+	records := make([]*event.Record, 0, len(req.Records))
+	for _, r := range req.Records {
+		rec := &event.Record{Data: r.Data}
+		if len(r.Metadata) > 0 {
+			if err := json.Unmarshal(r.Metadata, &rec.Metadata); err != nil {
+				return errors.BadRequest("stack.rpc.event", "invalid metadata: %v", err)
+			}
+		}
+		records = append(records, rec)
+	}
+
+	h.Lock()
+	defer h.Unlock()
+
+	// the records are written one at a time so the offset of every record
+	// is returned, -1 for the first if the log doesn't report them
+	rsp.Offset = -1
+	for _, r := range records {
+		if err := h.log.Write(r); err != nil {
+			return logError(err)
+		}
+		if w, ok := h.log.(writer); ok {
+			rsp.Offsets = append(rsp.Offsets, w.Written())
+		}
+	}
+	if len(rsp.Offsets) > 0 {
+		rsp.Offset = rsp.Offsets[0]
+	}
+
+	return nil
+}
+
+func (e *Event) Read(ctx context.Context, req *pb.ReadRequest, rsp *pb.ReadResponse) error {
+	h, err := e.handle(req.Id)
+	if err != nil {
+		return err
+	}
+
+	h.Lock()
+	defer h.Unlock()
+
+	l := h.log
+	if err := l.Seek(req.Offset); err != nil {
+		return logError(err)
+	}
+
+	count := req.Count
+	if count <= 0 || count > DefaultBatchSize {
+		count = DefaultBatchSize
+	}
+
+	offset := req.Offset
+	for int64(len(rsp.Records)) < count {
+		r, err := l.Read()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return logError(err)
+		}
+
+		rec, err := toProto(offset, r)
+		if err != nil {
+			return logError(err)
+		}
+		rsp.Records = append(rsp.Records, rec)
+		offset++
+	}
+	rsp.Next = offset
+
+	return nil
+}
+
+func (e *Event) Tail(ctx context.Context, req *pb.TailRequest, stream pb.Event_TailStream) error {
+	l, err := e.open(req.Id)
+	if err != nil {
+		return err
+	}
+	defer l.Close()
+
+	offset := req.Offset
+	if offset < 0 {
+		s, ok := l.(sizer)
+		if !ok {
+			return errors.BadRequest("stack.rpc.event", "log does not support tailing from its end")
+		}
+		offset = s.Size()
+	}
+	if err := l.Seek(offset); err != nil {
+		return logError(err)
+	}
+
+	for {
+		r, err := l.Read()
+		if err == io.EOF {
+			select {
+			case <-stream.Context().Done():
+				return nil
+			case <-ctx.Done():
+				return nil
+			case <-time.After(PollInterval):
+			}
+			continue
+		} else if err != nil {
+			return logError(err)
+		}
+
+		rec, err := toProto(offset, r)
+		if err != nil {
+			return logError(err)
+		}
+		if err := stream.Send(&pb.TailResponse{Record: rec}); err != nil {
+			return err
+		}
+		offset++
+	}
+}
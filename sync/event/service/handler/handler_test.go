@@ -0,0 +1,133 @@
+package handler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stack-labs/stack/store/memory"
+	pb "github.com/stack-labs/stack/sync/event/service/proto"
+	"github.com/stack-labs/stack/sync/event/store"
+)
+
+type tailStream struct {
+	pb.Event_TailStream
+	ctx     context.Context
+	records chan *pb.Record
+}
+
+func (t *tailStream) Context() context.Context {
+	return t.ctx
+}
+
+func (t *tailStream) Send(rsp *pb.TailResponse) error {
+	t.records <- rsp.Record
+	return nil
+}
+
+func TestAppendRead(t *testing.T) {
+	h := &Event{Event: store.NewEvent()}
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		rsp := new(pb.AppendResponse)
+		err := h.Append(ctx, &pb.AppendRequest{
+			Id: "audit",
+			Records: []*pb.Record{
+				{Metadata: []byte(`{"user":"a"}`), Data: []byte("one")},
+				{Data: []byte("two")},
+			},
+		}, rsp)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if rsp.Offset != int64(i*2) {
+			t.Fatalf("expected append at offset %d, got %d", i*2, rsp.Offset)
+		}
+		if len(rsp.Offsets) != 2 || rsp.Offsets[0] != int64(i*2) || rsp.Offsets[1] != int64(i*2+1) {
+			t.Fatalf("expected the offsets of both records, got %v", rsp.Offsets)
+		}
+	}
+
+	rsp := new(pb.ReadResponse)
+	if err := h.Read(ctx, &pb.ReadRequest{Id: "audit", Offset: 1, Count: 2}, rsp); err != nil {
+		t.Fatal(err)
+	}
+	if len(rsp.Records) != 2 || rsp.Next != 3 {
+		t.Fatalf("expected 2 records up to offset 3, got %d up to %d", len(rsp.Records), rsp.Next)
+	}
+	if rsp.Records[1].Offset != 2 || string(rsp.Records[1].Metadata) != `{"user":"a"}` {
+		t.Fatalf("unexpected record %+v", rsp.Records[1])
+	}
+
+	if err := h.Read(ctx, &pb.ReadRequest{Id: "audit", Offset: 10}, new(pb.ReadResponse)); err == nil {
+		t.Fatal("expected invalid offset error")
+	}
+}
+
+func TestAppendOffset(t *testing.T) {
+	s := memory.NewStore()
+	ctx := context.Background()
+
+	// handlers of two processes sharing a store
+	h := &Event{Event: store.NewEvent(store.Store(s))}
+	other := &Event{Event: store.NewEvent(store.Store(s))}
+	defer h.Close()
+	defer other.Close()
+
+	for i, d := range []struct {
+		h      *Event
+		offset int64
+	}{{h, 0}, {other, 1}, {h, 2}} {
+		rsp := new(pb.AppendResponse)
+		if err := d.h.Append(ctx, &pb.AppendRequest{Id: "audit", Records: []*pb.Record{{Data: []byte("data")}}}, rsp); err != nil {
+			t.Fatal(err)
+		}
+		if rsp.Offset != d.offset {
+			t.Fatalf("%d: expected append at offset %d, got %d", i, d.offset, rsp.Offset)
+		}
+	}
+}
+
+func TestTail(t *testing.T) {
+	PollInterval = time.Millisecond * 10
+	h := &Event{Event: store.NewEvent()}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	write := func(data string) {
+		err := h.Append(ctx, &pb.AppendRequest{
+			Id:      "audit",
+			Records: []*pb.Record{{Data: []byte(data)}},
+		}, new(pb.AppendResponse))
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write("before")
+
+	stream := &tailStream{ctx: ctx, records: make(chan *pb.Record, 10)}
+	done := make(chan error)
+	go func() {
+		done <- h.Tail(ctx, &pb.TailRequest{Id: "audit", Offset: -1}, stream)
+	}()
+
+	time.Sleep(time.Millisecond * 50)
+	write("after")
+
+	select {
+	case r := <-stream.records:
+		if string(r.Data) != "after" || r.Offset != 1 {
+			t.Fatalf("unexpected record %+v", r)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("record appended after the tail started not received")
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,35 @@
+package service
+
+import (
+	"github.com/stack-labs/stack/client"
+)
+
+var (
+	// DefaultName is the name of the event service
+	DefaultName = "stack.rpc.event"
+	// DefaultBatchSize is how many records a log reads per request
+	DefaultBatchSize int64 = 100
+)
+
+type Options struct {
+	// Name of the event service
+	Name string
+	// Client used to call the service
+	Client client.Client
+}
+
+type Option func(o *Options)
+
+// Name sets the name of the event service
+func Name(n string) Option {
+	return func(o *Options) {
+		o.Name = n
+	}
+}
+
+// Client sets the client used to call the event service
+func Client(c client.Client) Option {
+	return func(o *Options) {
+		o.Client = c
+	}
+}
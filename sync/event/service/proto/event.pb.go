@@ -0,0 +1,407 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: event.proto
+
+package stack_rpc_event
+
+import (
+	fmt "fmt"
+	proto "github.com/golang/protobuf/proto"
+	math "math"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+type Record struct {
+	// offset of the record in the log
+	Offset int64 `protobuf:"varint,1,opt,name=offset,proto3" json:"offset,omitempty"`
+	// json encoded metadata
+	Metadata []byte `protobuf:"bytes,2,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	// record data
+	Data                 []byte   `protobuf:"bytes,3,opt,name=data,proto3" json:"data,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Record) Reset()         { *m = Record{} }
+func (m *Record) String() string { return proto.CompactTextString(m) }
+func (*Record) ProtoMessage()    {}
+func (*Record) Descriptor() ([]byte, []int) {
+	return fileDescriptor_2d17a9d3f0ddf27e, []int{0}
+}
+
+func (m *Record) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Record.Unmarshal(m, b)
+}
+func (m *Record) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Record.Marshal(b, m, deterministic)
+}
+func (m *Record) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Record.Merge(m, src)
+}
+func (m *Record) XXX_Size() int {
+	return xxx_messageInfo_Record.Size(m)
+}
+func (m *Record) XXX_DiscardUnknown() {
+	xxx_messageInfo_Record.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Record proto.InternalMessageInfo
+
+func (m *Record) GetOffset() int64 {
+	if m != nil {
+		return m.Offset
+	}
+	return 0
+}
+
+func (m *Record) GetMetadata() []byte {
+	if m != nil {
+		return m.Metadata
+	}
+	return nil
+}
+
+func (m *Record) GetData() []byte {
+	if m != nil {
+		return m.Data
+	}
+	return nil
+}
+
+type AppendRequest struct {
+	// id of the log
+	Id                   string    `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Records              []*Record `protobuf:"bytes,2,rep,name=records,proto3" json:"records,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}  `json:"-"`
+	XXX_unrecognized     []byte    `json:"-"`
+	XXX_sizecache        int32     `json:"-"`
+}
+
+func (m *AppendRequest) Reset()         { *m = AppendRequest{} }
+func (m *AppendRequest) String() string { return proto.CompactTextString(m) }
+func (*AppendRequest) ProtoMessage()    {}
+func (*AppendRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_2d17a9d3f0ddf27e, []int{1}
+}
+
+func (m *AppendRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AppendRequest.Unmarshal(m, b)
+}
+func (m *AppendRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AppendRequest.Marshal(b, m, deterministic)
+}
+func (m *AppendRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AppendRequest.Merge(m, src)
+}
+func (m *AppendRequest) XXX_Size() int {
+	return xxx_messageInfo_AppendRequest.Size(m)
+}
+func (m *AppendRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_AppendRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AppendRequest proto.InternalMessageInfo
+
+func (m *AppendRequest) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *AppendRequest) GetRecords() []*Record {
+	if m != nil {
+		return m.Records
+	}
+	return nil
+}
+
+type AppendResponse struct {
+	// offset of the first record appended
+	Offset int64 `protobuf:"varint,1,opt,name=offset,proto3" json:"offset,omitempty"`
+	// offsets of the records appended in the order of the request
+	Offsets              []int64  `protobuf:"varint,2,rep,packed,name=offsets,proto3" json:"offsets,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AppendResponse) Reset()         { *m = AppendResponse{} }
+func (m *AppendResponse) String() string { return proto.CompactTextString(m) }
+func (*AppendResponse) ProtoMessage()    {}
+func (*AppendResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_2d17a9d3f0ddf27e, []int{2}
+}
+
+func (m *AppendResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AppendResponse.Unmarshal(m, b)
+}
+func (m *AppendResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AppendResponse.Marshal(b, m, deterministic)
+}
+func (m *AppendResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AppendResponse.Merge(m, src)
+}
+func (m *AppendResponse) XXX_Size() int {
+	return xxx_messageInfo_AppendResponse.Size(m)
+}
+func (m *AppendResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_AppendResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AppendResponse proto.InternalMessageInfo
+
+func (m *AppendResponse) GetOffset() int64 {
+	if m != nil {
+		return m.Offset
+	}
+	return 0
+}
+
+func (m *AppendResponse) GetOffsets() []int64 {
+	if m != nil {
+		return m.Offsets
+	}
+	return nil
+}
+
+type ReadRequest struct {
+	// id of the log
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// offset of the first record to read
+	Offset int64 `protobuf:"varint,2,opt,name=offset,proto3" json:"offset,omitempty"`
+	// maximum number of records to return
+	Count                int64    `protobuf:"varint,3,opt,name=count,proto3" json:"count,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ReadRequest) Reset()         { *m = ReadRequest{} }
+func (m *ReadRequest) String() string { return proto.CompactTextString(m) }
+func (*ReadRequest) ProtoMessage()    {}
+func (*ReadRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_2d17a9d3f0ddf27e, []int{3}
+}
+
+func (m *ReadRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReadRequest.Unmarshal(m, b)
+}
+func (m *ReadRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReadRequest.Marshal(b, m, deterministic)
+}
+func (m *ReadRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReadRequest.Merge(m, src)
+}
+func (m *ReadRequest) XXX_Size() int {
+	return xxx_messageInfo_ReadRequest.Size(m)
+}
+func (m *ReadRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReadRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReadRequest proto.InternalMessageInfo
+
+func (m *ReadRequest) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *ReadRequest) GetOffset() int64 {
+	if m != nil {
+		return m.Offset
+	}
+	return 0
+}
+
+func (m *ReadRequest) GetCount() int64 {
+	if m != nil {
+		return m.Count
+	}
+	return 0
+}
+
+type ReadResponse struct {
+	Records []*Record `protobuf:"bytes,1,rep,name=records,proto3" json:"records,omitempty"`
+	// offset of the next record to read
+	Next                 int64    `protobuf:"varint,2,opt,name=next,proto3" json:"next,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ReadResponse) Reset()         { *m = ReadResponse{} }
+func (m *ReadResponse) String() string { return proto.CompactTextString(m) }
+func (*ReadResponse) ProtoMessage()    {}
+func (*ReadResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_2d17a9d3f0ddf27e, []int{4}
+}
+
+func (m *ReadResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ReadResponse.Unmarshal(m, b)
+}
+func (m *ReadResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ReadResponse.Marshal(b, m, deterministic)
+}
+func (m *ReadResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ReadResponse.Merge(m, src)
+}
+func (m *ReadResponse) XXX_Size() int {
+	return xxx_messageInfo_ReadResponse.Size(m)
+}
+func (m *ReadResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ReadResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ReadResponse proto.InternalMessageInfo
+
+func (m *ReadResponse) GetRecords() []*Record {
+	if m != nil {
+		return m.Records
+	}
+	return nil
+}
+
+func (m *ReadResponse) GetNext() int64 {
+	if m != nil {
+		return m.Next
+	}
+	return 0
+}
+
+type TailRequest struct {
+	// id of the log
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// offset to start from, negative to start at the end
+	Offset               int64    `protobuf:"varint,2,opt,name=offset,proto3" json:"offset,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *TailRequest) Reset()         { *m = TailRequest{} }
+func (m *TailRequest) String() string { return proto.CompactTextString(m) }
+func (*TailRequest) ProtoMessage()    {}
+func (*TailRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_2d17a9d3f0ddf27e, []int{5}
+}
+
+func (m *TailRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TailRequest.Unmarshal(m, b)
+}
+func (m *TailRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TailRequest.Marshal(b, m, deterministic)
+}
+func (m *TailRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TailRequest.Merge(m, src)
+}
+func (m *TailRequest) XXX_Size() int {
+	return xxx_messageInfo_TailRequest.Size(m)
+}
+func (m *TailRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_TailRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TailRequest proto.InternalMessageInfo
+
+func (m *TailRequest) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *TailRequest) GetOffset() int64 {
+	if m != nil {
+		return m.Offset
+	}
+	return 0
+}
+
+type TailResponse struct {
+	Record               *Record  `protobuf:"bytes,1,opt,name=record,proto3" json:"record,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *TailResponse) Reset()         { *m = TailResponse{} }
+func (m *TailResponse) String() string { return proto.CompactTextString(m) }
+func (*TailResponse) ProtoMessage()    {}
+func (*TailResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_2d17a9d3f0ddf27e, []int{6}
+}
+
+func (m *TailResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TailResponse.Unmarshal(m, b)
+}
+func (m *TailResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TailResponse.Marshal(b, m, deterministic)
+}
+func (m *TailResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TailResponse.Merge(m, src)
+}
+func (m *TailResponse) XXX_Size() int {
+	return xxx_messageInfo_TailResponse.Size(m)
+}
+func (m *TailResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_TailResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TailResponse proto.InternalMessageInfo
+
+func (m *TailResponse) GetRecord() *Record {
+	if m != nil {
+		return m.Record
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterType((*Record)(nil), "stack.rpc.event.Record")
+	proto.RegisterType((*AppendRequest)(nil), "stack.rpc.event.AppendRequest")
+	proto.RegisterType((*AppendResponse)(nil), "stack.rpc.event.AppendResponse")
+	proto.RegisterType((*ReadRequest)(nil), "stack.rpc.event.ReadRequest")
+	proto.RegisterType((*ReadResponse)(nil), "stack.rpc.event.ReadResponse")
+	proto.RegisterType((*TailRequest)(nil), "stack.rpc.event.TailRequest")
+	proto.RegisterType((*TailResponse)(nil), "stack.rpc.event.TailResponse")
+}
+
+func init() { proto.RegisterFile("event.proto", fileDescriptor_2d17a9d3f0ddf27e) }
+
+var fileDescriptor_2d17a9d3f0ddf27e = []byte{
+	// 329 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x93, 0xcd, 0x4a, 0xc3, 0x40,
+	0x10, 0xc7, 0xdd, 0xa4, 0x4d, 0x75, 0x52, 0x2b, 0x2c, 0xa2, 0x21, 0x58, 0x2d, 0x7b, 0xca, 0x29,
+	0x6a, 0xc5, 0xb3, 0x28, 0x14, 0x0f, 0xbd, 0xc8, 0xa2, 0x0f, 0xb0, 0x26, 0x53, 0x08, 0x6a, 0x36,
+	0x66, 0xb7, 0xe2, 0x03, 0xfb, 0x20, 0x92, 0xdd, 0x24, 0xb6, 0xda, 0x88, 0xde, 0xe6, 0x63, 0xe7,
+	0x3f, 0xbf, 0x99, 0x61, 0xc1, 0xc7, 0x37, 0xcc, 0x75, 0x5c, 0x94, 0x52, 0x4b, 0xba, 0xa7, 0xb4,
+	0x48, 0x9e, 0xe2, 0xb2, 0x48, 0x62, 0x13, 0x66, 0x77, 0xe0, 0x71, 0x4c, 0x64, 0x99, 0xd2, 0x03,
+	0xf0, 0xe4, 0x62, 0xa1, 0x50, 0x07, 0x64, 0x42, 0x22, 0x97, 0xd7, 0x1e, 0x0d, 0x61, 0xfb, 0x05,
+	0xb5, 0x48, 0x85, 0x16, 0x81, 0x33, 0x21, 0xd1, 0x90, 0xb7, 0x3e, 0xa5, 0xd0, 0x33, 0x71, 0xd7,
+	0xc4, 0x8d, 0xcd, 0x38, 0xec, 0x5e, 0x17, 0x05, 0xe6, 0x29, 0xc7, 0xd7, 0x25, 0x2a, 0x4d, 0x47,
+	0xe0, 0x64, 0xa9, 0x11, 0xdd, 0xe1, 0x4e, 0x96, 0xd2, 0x73, 0x18, 0x94, 0xa6, 0xa5, 0x0a, 0x9c,
+	0x89, 0x1b, 0xf9, 0xd3, 0xc3, 0xf8, 0x1b, 0x55, 0x6c, 0x91, 0x78, 0xf3, 0x8e, 0xdd, 0xc0, 0xa8,
+	0xd1, 0x54, 0x85, 0xcc, 0x15, 0x76, 0xd2, 0x06, 0x30, 0xb0, 0x96, 0x15, 0x77, 0x79, 0xe3, 0xb2,
+	0x39, 0xf8, 0x1c, 0x45, 0x27, 0xd5, 0x97, 0xa0, 0xb3, 0x26, 0xb8, 0x0f, 0xfd, 0x44, 0x2e, 0x73,
+	0x6d, 0x66, 0x74, 0xb9, 0x75, 0xd8, 0x03, 0x0c, 0xad, 0x58, 0x8d, 0xb3, 0x32, 0x13, 0xf9, 0xdb,
+	0x4c, 0xd5, 0xee, 0x72, 0x7c, 0x6f, 0xda, 0x19, 0x9b, 0x5d, 0x82, 0x7f, 0x2f, 0xb2, 0xe7, 0x7f,
+	0x32, 0xb2, 0x2b, 0x18, 0xda, 0xb2, 0x9a, 0xe6, 0x14, 0x3c, 0xdb, 0xc5, 0xd4, 0xfe, 0x02, 0x53,
+	0x3f, 0x9b, 0x7e, 0x10, 0xe8, 0xcf, 0xaa, 0x04, 0x9d, 0x83, 0x67, 0x37, 0x4d, 0x8f, 0x7f, 0x14,
+	0xad, 0x9d, 0x35, 0x3c, 0xe9, 0xcc, 0x5b, 0x0a, 0xb6, 0x45, 0x67, 0xd0, 0xab, 0xb6, 0x44, 0x8f,
+	0x36, 0xf4, 0x6f, 0x2f, 0x11, 0x8e, 0x3b, 0xb2, 0xad, 0xcc, 0x2d, 0xf4, 0xaa, 0xf1, 0x36, 0xc8,
+	0xac, 0x2c, 0x2b, 0x1c, 0x77, 0x64, 0x1b, 0x99, 0x33, 0xf2, 0xe8, 0x99, 0x4f, 0x70, 0xf1, 0x39,
+	0x00, 0xd3, 0x40, 0x33, 0x07, 0x13, 0x03, 0x00, 0x00,
+}
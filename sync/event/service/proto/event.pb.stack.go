@@ -0,0 +1,204 @@
+// Code generated by protoc-gen-stack. DO NOT EDIT.
+// source: event.proto
+
+package stack_rpc_event
+
+import (
+	fmt "fmt"
+	math "math"
+
+	context "context"
+
+	proto "github.com/golang/protobuf/proto"
+
+	api "github.com/stack-labs/stack/api"
+
+	client "github.com/stack-labs/stack/client"
+
+	server "github.com/stack-labs/stack/server"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ api.Endpoint
+var _ context.Context
+var _ client.Option
+var _ server.Option
+
+// Api Endpoints for Event service
+
+func NewEventEndpoints() []*api.Endpoint {
+	return []*api.Endpoint{}
+}
+
+// Client API for Event service
+
+type EventService interface {
+	Append(ctx context.Context, in *AppendRequest, opts ...client.CallOption) (*AppendResponse, error)
+	Read(ctx context.Context, in *ReadRequest, opts ...client.CallOption) (*ReadResponse, error)
+	Tail(ctx context.Context, in *TailRequest, opts ...client.CallOption) (Event_TailService, error)
+}
+
+type eventService struct {
+	c    client.Client
+	name string
+}
+
+func NewEventService(name string, c client.Client) EventService {
+	return &eventService{
+		c:    c,
+		name: name,
+	}
+}
+
+func (c *eventService) Append(ctx context.Context, in *AppendRequest, opts ...client.CallOption) (*AppendResponse, error) {
+	req := c.c.NewRequest(c.name, "Event.Append", in)
+	out := new(AppendResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *eventService) Read(ctx context.Context, in *ReadRequest, opts ...client.CallOption) (*ReadResponse, error) {
+	req := c.c.NewRequest(c.name, "Event.Read", in)
+	out := new(ReadResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *eventService) Tail(ctx context.Context, in *TailRequest, opts ...client.CallOption) (Event_TailService, error) {
+	req := c.c.NewRequest(c.name, "Event.Tail", &TailRequest{})
+	stream, err := c.c.Stream(ctx, req, opts...)
+	if err != nil {
+		return nil, err
+	}
+	if err := stream.Send(in); err != nil {
+		return nil, err
+	}
+	return &eventServiceTail{stream}, nil
+}
+
+type Event_TailService interface {
+	Context() context.Context
+	SendMsg(interface{}) error
+	RecvMsg(interface{}) error
+	Close() error
+	Recv() (*TailResponse, error)
+}
+
+type eventServiceTail struct {
+	stream client.Stream
+}
+
+func (x *eventServiceTail) Close() error {
+	return x.stream.Close()
+}
+
+func (x *eventServiceTail) Context() context.Context {
+	return x.stream.Context()
+}
+
+func (x *eventServiceTail) SendMsg(m interface{}) error {
+	return x.stream.Send(m)
+}
+
+func (x *eventServiceTail) RecvMsg(m interface{}) error {
+	return x.stream.Recv(m)
+}
+
+func (x *eventServiceTail) Recv() (*TailResponse, error) {
+	m := new(TailResponse)
+	err := x.stream.Recv(m)
+	if err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// Server API for Event service
+
+type EventHandler interface {
+	Append(context.Context, *AppendRequest, *AppendResponse) error
+	Read(context.Context, *ReadRequest, *ReadResponse) error
+	Tail(context.Context, *TailRequest, Event_TailStream) error
+}
+
+func RegisterEventHandler(s server.Server, hdlr EventHandler, opts ...server.HandlerOption) error {
+	type event interface {
+		Append(ctx context.Context, in *AppendRequest, out *AppendResponse) error
+		Read(ctx context.Context, in *ReadRequest, out *ReadResponse) error
+		Tail(ctx context.Context, stream server.Stream) error
+	}
+	type Event struct {
+		event
+	}
+	h := &eventHandler{hdlr}
+	return s.Handle(s.NewHandler(&Event{h}, opts...))
+}
+
+type eventHandler struct {
+	EventHandler
+}
+
+func (h *eventHandler) Append(ctx context.Context, in *AppendRequest, out *AppendResponse) error {
+	return h.EventHandler.Append(ctx, in, out)
+}
+
+func (h *eventHandler) Read(ctx context.Context, in *ReadRequest, out *ReadResponse) error {
+	return h.EventHandler.Read(ctx, in, out)
+}
+
+func (h *eventHandler) Tail(ctx context.Context, stream server.Stream) error {
+	m := new(TailRequest)
+	if err := stream.Recv(m); err != nil {
+		return err
+	}
+	return h.EventHandler.Tail(ctx, m, &eventTailStream{stream})
+}
+
+type Event_TailStream interface {
+	Context() context.Context
+	SendMsg(interface{}) error
+	RecvMsg(interface{}) error
+	Close() error
+	Send(*TailResponse) error
+}
+
+type eventTailStream struct {
+	stream server.Stream
+}
+
+func (x *eventTailStream) Close() error {
+	return x.stream.Close()
+}
+
+func (x *eventTailStream) Context() context.Context {
+	return x.stream.Context()
+}
+
+func (x *eventTailStream) SendMsg(m interface{}) error {
+	return x.stream.Send(m)
+}
+
+func (x *eventTailStream) RecvMsg(m interface{}) error {
+	return x.stream.Recv(m)
+}
+
+func (x *eventTailStream) Send(m *TailResponse) error {
+	return x.stream.Send(m)
+}
@@ -0,0 +1,171 @@
+// Package service is an event.Event backed by the event service
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"sync"
+
+	"github.com/stack-labs/stack/client/mucp"
+	"github.com/stack-labs/stack/sync/event"
+	pb "github.com/stack-labs/stack/sync/event/service/proto"
+	"github.com/stack-labs/stack/util/errors"
+)
+
+type serviceEvent struct {
+	opts   Options
+	client pb.EventService
+}
+
+type serviceLog struct {
+	client pb.EventService
+	id     string
+
+	sync.Mutex
+	offset int64
+	// records read ahead of the offset
+	buf    []*event.Record
+	closed bool
+}
+
+func (s *serviceEvent) Log(id string) (event.Log, error) {
+	return &serviceLog{
+		client: s.client,
+		id:     id,
+	}, nil
+}
+
+func toRecord(r *pb.Record) (*event.Record, error) {
+	rec := &event.Record{Data: r.Data}
+	if len(r.Metadata) > 0 {
+		if err := json.Unmarshal(r.Metadata, &rec.Metadata); err != nil {
+			return nil, err
+		}
+	}
+	return rec, nil
+}
+
+func parseError(err error) error {
+	if err == nil {
+		return nil
+	}
+	if e := errors.Parse(err.Error()); e.Code == 400 && e.Detail == event.ErrInvalidOffset.Error() {
+		return event.ErrInvalidOffset
+	}
+	return err
+}
+
+// fetch reads ahead from offset, called with the lock held
+func (l *serviceLog) fetch(offset int64) error {
+	rsp, err := l.client.Read(context.Background(), &pb.ReadRequest{
+		Id:     l.id,
+		Offset: offset,
+		Count:  DefaultBatchSize,
+	})
+	if err != nil {
+		return parseError(err)
+	}
+
+	buf := make([]*event.Record, 0, len(rsp.Records))
+	for _, r := range rsp.Records {
+		rec, err := toRecord(r)
+		if err != nil {
+			return err
+		}
+		buf = append(buf, rec)
+	}
+
+	l.offset = offset
+	l.buf = buf
+	return nil
+}
+
+func (l *serviceLog) Close() error {
+	l.Lock()
+	defer l.Unlock()
+	l.closed = true
+	l.buf = nil
+	return nil
+}
+
+func (l *serviceLog) Id() string {
+	return l.id
+}
+
+// Read returns the next record or io.EOF once the end of the log is reached
+func (l *serviceLog) Read() (*event.Record, error) {
+	l.Lock()
+	defer l.Unlock()
+
+	if l.closed {
+		return nil, event.ErrClosed
+	}
+
+	if len(l.buf) == 0 {
+		if err := l.fetch(l.offset); err != nil {
+			return nil, err
+		}
+		if len(l.buf) == 0 {
+			return nil, io.EOF
+		}
+	}
+
+	r := l.buf[0]
+	l.buf = l.buf[1:]
+	l.offset++
+	return r, nil
+}
+
+func (l *serviceLog) Seek(offset int64) error {
+	l.Lock()
+	defer l.Unlock()
+
+	if l.closed {
+		return event.ErrClosed
+	}
+	return l.fetch(offset)
+}
+
+func (l *serviceLog) Write(r *event.Record) error {
+	l.Lock()
+	closed := l.closed
+	l.Unlock()
+
+	if closed {
+		return event.ErrClosed
+	}
+
+	rec := &pb.Record{Data: r.Data}
+	if len(r.Metadata) > 0 {
+		md, err := json.Marshal(r.Metadata)
+		if err != nil {
+			return err
+		}
+		rec.Metadata = md
+	}
+
+	_, err := l.client.Append(context.Background(), &pb.AppendRequest{
+		Id:      l.id,
+		Records: []*pb.Record{rec},
+	})
+	return parseError(err)
+}
+
+// NewEvent returns an event.Event whose logs are kept by the event service
+func NewEvent(opts ...Option) event.Event {
+	options := Options{
+		Name: DefaultName,
+	}
+	for _, o := range opts {
+		o(&options)
+	}
+	if options.Client == nil {
+		options.Client = mucp.NewClient()
+	}
+
+	return &serviceEvent{
+		opts:   options,
+		client: pb.NewEventService(options.Name, options.Client),
+	}
+}
@@ -0,0 +1,33 @@
+package store
+
+import (
+	st "github.com/stack-labs/stack/store"
+)
+
+var (
+	// DefaultPrefix is the key prefix of event logs in the store
+	DefaultPrefix = "event/"
+)
+
+type Options struct {
+	// Store holding the records, an in memory store by default
+	Store st.Store
+	// Prefix of the keys of every log
+	Prefix string
+}
+
+type Option func(o *Options)
+
+// Store sets the store the logs are kept in
+func Store(s st.Store) Option {
+	return func(o *Options) {
+		o.Store = s
+	}
+}
+
+// Prefix sets the key prefix of the logs
+func Prefix(p string) Option {
+	return func(o *Options) {
+		o.Prefix = p
+	}
+}
@@ -0,0 +1,291 @@
+// Package store is an event log kept in a store.Store with a record per
+// event. Logs written from several processes get distinct offsets when the
+// store implements store.Versioned, otherwise only within a process.
+package store
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	st "github.com/stack-labs/stack/store"
+	"github.com/stack-labs/stack/store/memory"
+	"github.com/stack-labs/stack/sync/event"
+)
+
+var (
+	// ErrClosed is returned when using a closed log
+	ErrClosed = event.ErrClosed
+	// ErrInvalidOffset is returned when seeking before the start or past the end
+	ErrInvalidOffset = event.ErrInvalidOffset
+)
+
+type storeEvent struct {
+	opts Options
+
+	// serialises writes within the process
+	sync.Mutex
+	locks map[string]*logLock
+}
+
+// logLock serialises the writes of a log, it is removed once unused
+type logLock struct {
+	sync.Mutex
+	refs int
+}
+
+type storeLog struct {
+	event  *storeEvent
+	id     string
+	prefix string
+
+	sync.Mutex
+	offset int64
+	// size is the known size, records written elsewhere are found on read
+	size    int64
+	written int64
+	closed  bool
+}
+
+func (e *storeEvent) Log(id string) (event.Log, error) {
+	if len(id) == 0 {
+		return nil, errors.New("invalid log id")
+	}
+
+	l := &storeLog{
+		event:   e,
+		id:      id,
+		prefix:  e.opts.Prefix + url.PathEscape(id) + "/",
+		written: -1,
+	}
+
+	size, err := l.last()
+	if err != nil {
+		return nil, err
+	}
+	l.size = size
+
+	return l, nil
+}
+
+// lock the writes of a log and return the func unlocking them
+func (e *storeEvent) lock(id string) func() {
+	e.Lock()
+	m, ok := e.locks[id]
+	if !ok {
+		m = new(logLock)
+		e.locks[id] = m
+	}
+	m.refs++
+	e.Unlock()
+
+	m.Lock()
+
+	return func() {
+		m.Unlock()
+
+		e.Lock()
+		m.refs--
+		if m.refs == 0 {
+			delete(e.locks, id)
+		}
+		e.Unlock()
+	}
+}
+
+func (l *storeLog) key(offset int64) string {
+	return fmt.Sprintf("%s%020d", l.prefix, offset)
+}
+
+// last returns the offset after the last record in the store
+func (l *storeLog) last() (int64, error) {
+	recs, err := l.event.opts.Store.List(st.ListPrefix(l.prefix), st.ListKeysOnly())
+	if err != nil {
+		return 0, err
+	}
+	if len(recs) == 0 {
+		return 0, nil
+	}
+
+	keys := make([]string, 0, len(recs))
+	for _, r := range recs {
+		keys = append(keys, r.Key)
+	}
+	sort.Strings(keys)
+
+	offset, err := strconv.ParseInt(strings.TrimPrefix(keys[len(keys)-1], l.prefix), 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	return offset + 1, nil
+}
+
+// exists reports whether a record is stored at offset
+func (l *storeLog) exists(offset int64) (bool, error) {
+	_, err := l.event.opts.Store.Read(l.key(offset))
+	if err == st.ErrNotFound {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (l *storeLog) Close() error {
+	l.Lock()
+	defer l.Unlock()
+	l.closed = true
+	return nil
+}
+
+func (l *storeLog) Id() string {
+	return l.id
+}
+
+// Read returns the next record or io.EOF once the end of the log is reached
+func (l *storeLog) Read() (*event.Record, error) {
+	l.Lock()
+	defer l.Unlock()
+
+	if l.closed {
+		return nil, ErrClosed
+	}
+
+	recs, err := l.event.opts.Store.Read(l.key(l.offset))
+	if err == st.ErrNotFound || (err == nil && len(recs) == 0) {
+		return nil, io.EOF
+	} else if err != nil {
+		return nil, err
+	}
+
+	r := new(event.Record)
+	if err := json.Unmarshal(recs[0].Value, r); err != nil {
+		return nil, err
+	}
+
+	l.offset++
+	if l.offset > l.size {
+		l.size = l.offset
+	}
+
+	return r, nil
+}
+
+// Seek sets the offset of the next record read, the size of the log seeks to its end
+func (l *storeLog) Seek(offset int64) error {
+	l.Lock()
+	defer l.Unlock()
+
+	if l.closed {
+		return ErrClosed
+	}
+	if offset < 0 {
+		return ErrInvalidOffset
+	}
+	if offset > l.size {
+		// the record before it may have been written elsewhere
+		ok, err := l.exists(offset - 1)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return ErrInvalidOffset
+		}
+		l.size = offset
+	}
+
+	l.offset = offset
+	return nil
+}
+
+func (l *storeLog) Write(r *event.Record) error {
+	l.Lock()
+	defer l.Unlock()
+
+	if l.closed {
+		return ErrClosed
+	}
+
+	b, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+
+	unlock := l.event.lock(l.id)
+	defer unlock()
+
+	s := l.event.opts.Store
+	vs, versioned := s.(st.Versioned)
+
+	for {
+		rec := &st.Record{Key: l.key(l.size), Value: b}
+
+		if versioned {
+			// a zero version only swaps in a record that does not exist
+			err = vs.CompareAndSwap(rec)
+			if st.IsConflict(err) {
+				l.size++
+				continue
+			}
+		} else {
+			var ok bool
+			if ok, err = l.exists(l.size); err == nil && ok {
+				l.size++
+				continue
+			}
+			if err == nil {
+				err = s.Write(rec)
+			}
+		}
+		if err != nil {
+			return err
+		}
+
+		l.written = l.size
+		l.size++
+		return nil
+	}
+}
+
+func (l *storeLog) Offset() int64 {
+	l.Lock()
+	defer l.Unlock()
+	return l.offset
+}
+
+// Size is the offset after the last record written or read through this handle
+func (l *storeLog) Size() int64 {
+	l.Lock()
+	defer l.Unlock()
+	return l.size
+}
+
+// Written is the offset of the last record written through this handle, -1
+// before the first write
+func (l *storeLog) Written() int64 {
+	l.Lock()
+	defer l.Unlock()
+	return l.written
+}
+
+// NewEvent returns an event.Event whose logs are kept in a store
+func NewEvent(opts ...Option) event.Event {
+	options := Options{
+		Prefix: DefaultPrefix,
+	}
+	for _, o := range opts {
+		o(&options)
+	}
+	if options.Store == nil {
+		options.Store = memory.NewStore()
+	}
+
+	return &storeEvent{
+		opts:  options,
+		locks: make(map[string]*logLock),
+	}
+}
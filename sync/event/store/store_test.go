@@ -0,0 +1,86 @@
+package store
+
+import (
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/stack-labs/stack/store/memory"
+	"github.com/stack-labs/stack/sync/event"
+)
+
+func TestLog(t *testing.T) {
+	s := memory.NewStore()
+	e := NewEvent(Store(s))
+
+	l, err := e.Log("test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	for i := 0; i < 5; i++ {
+		if err := l.Write(&event.Record{Data: []byte(fmt.Sprintf("record-%d", i))}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for i := 0; i < 5; i++ {
+		r, err := l.Read()
+		if err != nil {
+			t.Fatalf("Unexpected read error %v", err)
+		}
+		if string(r.Data) != fmt.Sprintf("record-%d", i) {
+			t.Fatalf("Unexpected record %d: %s", i, r.Data)
+		}
+	}
+	if _, err := l.Read(); err != io.EOF {
+		t.Fatalf("Expected io.EOF got %v", err)
+	}
+
+	if err := l.Seek(6); err != ErrInvalidOffset {
+		t.Fatalf("Expected invalid offset got %v", err)
+	}
+	if err := l.Seek(3); err != nil {
+		t.Fatal(err)
+	}
+	if r, err := l.Read(); err != nil || string(r.Data) != "record-3" {
+		t.Fatalf("Unexpected record after seek %v %v", r, err)
+	}
+
+	// a writer in another process gets the next offsets
+	other, err := NewEvent(Store(s)).Log("test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if size := other.(interface{ Size() int64 }).Size(); size != 5 {
+		t.Fatalf("Expected size 5 got %d", size)
+	}
+	if err := other.Write(&event.Record{Data: []byte("record-5")}); err != nil {
+		t.Fatal(err)
+	}
+
+	// and a stale writer does not overwrite it
+	if err := l.Write(&event.Record{Data: []byte("record-6")}); err != nil {
+		t.Fatal(err)
+	}
+	if w := l.(interface{ Written() int64 }).Written(); w != 6 {
+		t.Fatalf("Expected the record written at 6 got %d", w)
+	}
+	if n := len(e.(*storeEvent).locks); n != 0 {
+		t.Fatalf("Expected the unused locks to be removed, got %d", n)
+	}
+
+	if err := l.Seek(5); err != nil {
+		t.Fatal(err)
+	}
+	for i := 5; i < 7; i++ {
+		r, err := l.Read()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(r.Data) != fmt.Sprintf("record-%d", i) {
+			t.Fatalf("Unexpected record %d: %s", i, r.Data)
+		}
+	}
+}
@@ -0,0 +1,296 @@
+// Package lease holds exclusive leases on store records for the store
+// backed lock and leader. A lease is acquired and renewed with compare and
+// swap so the holder is always known, and its version is a fencing token.
+package lease
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/stack-labs/stack/store"
+	"github.com/stack-labs/stack/sync/lock"
+	"github.com/stack-labs/stack/util/log"
+)
+
+var (
+	// PollInterval is the longest a waiter sleeps between attempts
+	PollInterval = 100 * time.Millisecond
+	// ReleaseExpiry is how long a released lease record is kept
+	ReleaseExpiry = time.Second
+)
+
+// value is the record stored for a lease
+type value struct {
+	Owner string `json:"owner"`
+	// Expires in unix nanoseconds, zero if the lease has no ttl
+	Expires  int64 `json:"expires,omitempty"`
+	Released bool  `json:"released,omitempty"`
+}
+
+func (v *value) free() bool {
+	return v.Released || (v.Expires > 0 && time.Now().UnixNano() > v.Expires)
+}
+
+// Lease is an acquired lease, renewed in the background when it has a ttl
+type Lease struct {
+	store store.Versioned
+	key   string
+	owner string
+	ttl   time.Duration
+	token uint64
+
+	sync.Mutex
+	version  uint64
+	expires  time.Time
+	released bool
+	lost     chan bool
+	exit     chan bool
+	done     chan bool
+}
+
+// Holder returns the owner of the lease at key or false if it is free
+func Holder(s store.Store, key string) (string, bool, error) {
+	recs, err := s.Read(key)
+	if err == store.ErrNotFound {
+		return "", false, nil
+	} else if err != nil {
+		return "", false, err
+	}
+
+	v, err := decode(recs[0].Value)
+	if err != nil || v.free() {
+		return "", false, err
+	}
+	return v.Owner, true, nil
+}
+
+// Acquire blocks until the lease at key is held by owner or ctx is done. A wait of zero
+// waits forever, otherwise lock.ErrLockTimeout is returned once it passes.
+func Acquire(ctx context.Context, s store.Versioned, key, owner string, ttl, wait time.Duration) (*Lease, error) {
+	var deadline <-chan time.Time
+	if wait > 0 {
+		deadline = time.After(wait)
+	}
+
+	// wake up as soon as the lease record changes
+	changed := make(chan bool, 1)
+	if w, err := s.Watch(key); err == nil {
+		defer w.Stop()
+		go func() {
+			for {
+				if _, err := w.Next(); err != nil {
+					return
+				}
+				select {
+				case changed <- true:
+				default:
+				}
+			}
+		}()
+	}
+
+	for {
+		var version uint64
+		sleep := PollInterval
+
+		recs, err := s.Read(key)
+		if err != nil && err != store.ErrNotFound {
+			return nil, err
+		}
+		if err == nil {
+			v, err := decode(recs[0].Value)
+			if err != nil {
+				return nil, err
+			}
+			version = recs[0].Version
+
+			if !v.free() {
+				// sleep no longer than the holder's lease has left
+				if v.Expires > 0 {
+					if left := time.Until(time.Unix(0, v.Expires)); left < sleep {
+						sleep = left + time.Millisecond
+					}
+				}
+				select {
+				case <-changed:
+				case <-time.After(sleep):
+				case <-deadline:
+					return nil, lock.ErrLockTimeout
+				case <-ctx.Done():
+					return nil, ctx.Err()
+				}
+				continue
+			}
+		}
+
+		l := &Lease{
+			store: s,
+			key:   key,
+			owner: owner,
+			ttl:   ttl,
+			lost:  make(chan bool),
+			exit:  make(chan bool),
+			done:  make(chan bool),
+		}
+
+		rec := l.record(version)
+		err = s.CompareAndSwap(rec)
+		if store.IsConflict(err) {
+			// someone else took it first
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		l.token = rec.Version
+		l.version = rec.Version
+		l.expires = time.Now().Add(ttl)
+
+		if ttl > 0 {
+			go l.run()
+		} else {
+			close(l.done)
+		}
+
+		return l, nil
+	}
+}
+
+func decode(b []byte) (*value, error) {
+	v := new(value)
+	if err := json.Unmarshal(b, v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// record returns the lease record to swap in for the version
+func (l *Lease) record(version uint64) *store.Record {
+	v := &value{Owner: l.owner}
+	if l.ttl > 0 {
+		v.Expires = time.Now().Add(l.ttl).UnixNano()
+	}
+	b, _ := json.Marshal(v)
+
+	return &store.Record{
+		Key:     l.key,
+		Value:   b,
+		Expiry:  l.ttl,
+		Version: version,
+	}
+}
+
+// Token is the fencing token of the lease, it increases with every acquisition
+func (l *Lease) Token() uint64 {
+	return l.token
+}
+
+// Lost is closed once the lease has been lost, it is not closed by Release
+func (l *Lease) Lost() <-chan bool {
+	return l.lost
+}
+
+// Renew extends the lease by its ttl
+func (l *Lease) Renew() error {
+	l.Lock()
+	defer l.Unlock()
+
+	if l.released {
+		return errors.New("lease released")
+	}
+	select {
+	case <-l.lost:
+		return errors.New("lease lost")
+	default:
+	}
+
+	rec := l.record(l.version)
+	err := l.store.CompareAndSwap(rec)
+	if store.IsConflict(err) {
+		l.setLost()
+		return err
+	}
+	if err != nil {
+		// the lease still holds until it expires
+		if l.ttl > 0 && time.Now().After(l.expires) {
+			l.setLost()
+		}
+		return err
+	}
+
+	l.version = rec.Version
+	l.expires = time.Now().Add(l.ttl)
+	return nil
+}
+
+// setLost is called with the lock held
+func (l *Lease) setLost() {
+	select {
+	case <-l.lost:
+	default:
+		close(l.lost)
+	}
+}
+
+func (l *Lease) run() {
+	defer close(l.done)
+
+	t := time.NewTicker(l.ttl / 3)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-l.exit:
+			return
+		case <-l.lost:
+			return
+		case <-t.C:
+			if err := l.Renew(); err != nil {
+				log.Errorf("Error renewing lease %s: %v", l.key, err)
+			}
+		}
+	}
+}
+
+// Release gives up the lease unless it has already been lost
+func (l *Lease) Release() error {
+	l.Lock()
+	select {
+	case <-l.exit:
+	default:
+		close(l.exit)
+	}
+	l.Unlock()
+
+	<-l.done
+
+	l.Lock()
+	defer l.Unlock()
+
+	if l.released {
+		return nil
+	}
+	l.released = true
+
+	select {
+	case <-l.lost:
+		return nil
+	default:
+	}
+
+	b, _ := json.Marshal(&value{Owner: l.owner, Released: true})
+	err := l.store.CompareAndSwap(&store.Record{
+		Key:     l.key,
+		Value:   b,
+		Expiry:  ReleaseExpiry,
+		Version: l.version,
+	})
+	if store.IsConflict(err) {
+		return nil
+	}
+	return err
+}
@@ -1,11 +1,19 @@
 package leader
 
+import "context"
+
 type Options struct {
 	Nodes []string
 	Group string
+	// Other options for implementations of the interface
+	// can be stored in a context
+	Context context.Context
 }
 
-type ElectOptions struct{}
+type ElectOptions struct {
+	// Context cancels the election while waiting to be elected
+	Context context.Context
+}
 
 // Nodes sets the addresses of the underlying systems
 func Nodes(a ...string) Option {
@@ -14,6 +22,14 @@ func Nodes(a ...string) Option {
 	}
 }
 
+// Context sets a context which cancels the election when done, Elect then
+// returns the error of the context
+func Context(ctx context.Context) ElectOption {
+	return func(o *ElectOptions) {
+		o.Context = ctx
+	}
+}
+
 // Group sets the group name for coordinating leadership
 func Group(g string) Option {
 	return func(o *Options) {
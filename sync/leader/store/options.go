@@ -0,0 +1,38 @@
+package store
+
+import (
+	"context"
+	"time"
+
+	st "github.com/stack-labs/stack/store"
+	"github.com/stack-labs/stack/sync/leader"
+)
+
+type storeKey struct{}
+type ttlKey struct{}
+type nodeKey struct{}
+
+// Store sets the store leadership is held in, it must implement store.Versioned
+func Store(s st.Store) leader.Option {
+	return setOption(storeKey{}, s)
+}
+
+// TTL sets how long leadership outlives a leader that stopped renewing it
+func TTL(d time.Duration) leader.Option {
+	return setOption(ttlKey{}, d)
+}
+
+// Node sets the id of this node, held as the owner of the leases it wins
+// and streamed by Follow. A random id is used if none is set.
+func Node(id string) leader.Option {
+	return setOption(nodeKey{}, id)
+}
+
+func setOption(k, v interface{}) leader.Option {
+	return func(o *leader.Options) {
+		if o.Context == nil {
+			o.Context = context.Background()
+		}
+		o.Context = context.WithValue(o.Context, k, v)
+	}
+}
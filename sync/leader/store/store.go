@@ -0,0 +1,223 @@
+// Package store is leader election over a lease on a store record. The
+// leader renews the lease in the background and Revoked fires as soon as
+// a renewal finds the lease lost or it expires without being renewed.
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	st "github.com/stack-labs/stack/store"
+	"github.com/stack-labs/stack/store/memory"
+	"github.com/stack-labs/stack/sync/internal/lease"
+	"github.com/stack-labs/stack/sync/leader"
+)
+
+var (
+	// DefaultPrefix is the key prefix of election records
+	DefaultPrefix = "leader/"
+	// DefaultTTL is how long leadership outlives a leader that stopped renewing it
+	DefaultTTL = 10 * time.Second
+)
+
+type storeLeader struct {
+	opts  leader.Options
+	store st.Store
+	ttl   time.Duration
+	// node is the owner of the leases won by this leader
+	node string
+}
+
+type storeElected struct {
+	leader *storeLeader
+	id     string
+
+	sync.Mutex
+	lease    *lease.Lease
+	revoked  chan bool
+	resigned chan bool
+}
+
+func (s *storeLeader) key(id string) string {
+	return DefaultPrefix + s.opts.Group + id
+}
+
+func (s *storeLeader) versioned() (st.Versioned, error) {
+	vs, ok := s.store.(st.Versioned)
+	if !ok {
+		return nil, errors.New("leader store does not support versioned writes")
+	}
+	return vs, nil
+}
+
+// Elect blocks until id is the leader of the election or the context set
+// with leader.Context is done
+func (s *storeLeader) Elect(id string, opts ...leader.ElectOption) (leader.Elected, error) {
+	var options leader.ElectOptions
+	for _, o := range opts {
+		o(&options)
+	}
+	if options.Context == nil {
+		options.Context = context.Background()
+	}
+
+	e := &storeElected{
+		leader: s,
+		id:     id,
+	}
+	if err := e.elect(options.Context); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// Follow streams the node ids of the leaders elected in the group
+func (s *storeLeader) Follow() chan string {
+	ch := make(chan string)
+
+	vs, err := s.versioned()
+	if err != nil {
+		close(ch)
+		return ch
+	}
+
+	w, err := vs.Watch(DefaultPrefix + s.opts.Group)
+	if err != nil {
+		close(ch)
+		return ch
+	}
+
+	go func() {
+		defer close(ch)
+		defer w.Stop()
+
+		for {
+			e, err := w.Next()
+			if err != nil {
+				return
+			}
+			if e.Type != st.Put {
+				continue
+			}
+
+			var v struct {
+				Owner    string `json:"owner"`
+				Released bool   `json:"released"`
+			}
+			if err := json.Unmarshal(e.Record.Value, &v); err != nil || v.Released {
+				continue
+			}
+			ch <- v.Owner
+		}
+	}()
+
+	return ch
+}
+
+func (s *storeElected) elect(ctx context.Context) error {
+	vs, err := s.leader.versioned()
+	if err != nil {
+		return err
+	}
+
+	l, err := lease.Acquire(ctx, vs, s.leader.key(s.id), s.leader.node, s.leader.ttl, 0)
+	if err != nil {
+		return err
+	}
+
+	revoked := make(chan bool)
+	resigned := make(chan bool)
+
+	s.lease = l
+	s.revoked = revoked
+	s.resigned = resigned
+
+	go func() {
+		select {
+		case <-l.Lost():
+			close(revoked)
+		case <-resigned:
+		}
+	}()
+
+	return nil
+}
+
+func (s *storeElected) Id() string {
+	return s.id
+}
+
+// Reelect renews leadership or, once revoked, blocks until elected again
+func (s *storeElected) Reelect() error {
+	s.Lock()
+	defer s.Unlock()
+
+	select {
+	case <-s.revoked:
+	case <-s.resigned:
+	default:
+		if err := s.lease.Renew(); err == nil {
+			return nil
+		}
+	}
+
+	s.stop()
+	return s.elect(context.Background())
+}
+
+func (s *storeElected) Resign() error {
+	s.Lock()
+	defer s.Unlock()
+
+	s.stop()
+	return s.lease.Release()
+}
+
+// stop watching the current lease, called with the lock held
+func (s *storeElected) stop() {
+	select {
+	case <-s.resigned:
+	default:
+		close(s.resigned)
+	}
+}
+
+func (s *storeElected) Revoked() chan bool {
+	s.Lock()
+	defer s.Unlock()
+	return s.revoked
+}
+
+// NewLeader returns leader election held in the store set with the Store
+// option, an in memory store is used if none is set
+func NewLeader(opts ...leader.Option) leader.Leader {
+	var options leader.Options
+	for _, o := range opts {
+		o(&options)
+	}
+
+	s := &storeLeader{
+		opts: options,
+		ttl:  DefaultTTL,
+		node: uuid.New().String(),
+	}
+
+	if options.Context != nil {
+		s.store, _ = options.Context.Value(storeKey{}).(st.Store)
+		if d, ok := options.Context.Value(ttlKey{}).(time.Duration); ok {
+			s.ttl = d
+		}
+		if id, ok := options.Context.Value(nodeKey{}).(string); ok && len(id) > 0 {
+			s.node = id
+		}
+	}
+	if s.store == nil {
+		s.store = memory.NewStore()
+	}
+
+	return s
+}
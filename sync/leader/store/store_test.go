@@ -0,0 +1,104 @@
+package store
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stack-labs/stack/store/memory"
+	"github.com/stack-labs/stack/sync/leader"
+)
+
+func TestElect(t *testing.T) {
+	s := memory.NewStore()
+	l1 := NewLeader(Store(s), TTL(time.Millisecond*150))
+	l2 := NewLeader(Store(s), TTL(time.Millisecond*150))
+
+	e1, err := l1.Elect("test")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	elected := make(chan leader.Elected, 1)
+	go func() {
+		e, err := l2.Elect("test")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		elected <- e
+	}()
+
+	// the leader renews its lease so the second candidate waits
+	select {
+	case <-elected:
+		t.Fatal("elected while another leader holds the lease")
+	case <-e1.Revoked():
+		t.Fatal("leadership revoked while renewing")
+	case <-time.After(time.Millisecond * 500):
+	}
+
+	if err := e1.Resign(); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case e2 := <-elected:
+		e2.Resign()
+	case <-time.After(time.Second):
+		t.Fatal("not elected after the leader resigned")
+	}
+}
+
+func TestRevoked(t *testing.T) {
+	s := memory.NewStore()
+	l := NewLeader(Store(s), TTL(time.Millisecond*150))
+
+	e, err := l.Elect("test")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// take the lease away from the leader
+	if err := s.Delete(DefaultPrefix + "test"); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case <-e.Revoked():
+	case <-time.After(time.Second):
+		t.Fatal("leadership not revoked after the lease was lost")
+	}
+
+	// reelection campaigns again
+	if err := e.Reelect(); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-e.Revoked():
+		t.Fatal("leadership revoked after reelection")
+	default:
+	}
+	e.Resign()
+}
+
+func TestFollow(t *testing.T) {
+	s := memory.NewStore()
+	l := NewLeader(Store(s), Node("node-1"))
+
+	ch := NewLeader(Store(s)).Follow()
+
+	e, err := l.Elect("test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer e.Resign()
+
+	select {
+	case id := <-ch:
+		if id != "node-1" {
+			t.Fatalf("Expected the leader node-1 got %s", id)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("leader not followed")
+	}
+}
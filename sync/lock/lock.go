@@ -2,6 +2,7 @@
 package lock
 
 import (
+	"context"
 	"errors"
 	"time"
 )
@@ -21,11 +22,16 @@ type Lock interface {
 type Options struct {
 	Nodes  []string
 	Prefix string
+	// Other options for implementations of the interface
+	// can be stored in a context
+	Context context.Context
 }
 
 type AcquireOptions struct {
 	TTL  time.Duration
 	Wait time.Duration
+	// Token receives the fencing token of the acquired lock
+	Token *uint64
 }
 
 type Option func(o *Options)
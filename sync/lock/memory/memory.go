@@ -11,6 +11,8 @@ import (
 type memoryLock struct {
 	sync.RWMutex
 	locks map[string]*mlock
+	// token is the fencing token of the last acquisition
+	token uint64
 }
 
 type mlock struct {
@@ -37,6 +39,7 @@ func (m *memoryLock) Acquire(id string, opts ...lock.AcquireOption) error {
 			ttl:     options.TTL,
 			release: make(chan bool),
 		}
+		m.setToken(options)
 		// unlock
 		m.Unlock()
 		return nil
@@ -88,6 +91,7 @@ lockLoop:
 				ttl:     options.TTL,
 				release: make(chan bool),
 			}
+			m.setToken(options)
 
 			m.Unlock()
 
@@ -107,6 +111,14 @@ lockLoop:
 	return nil
 }
 
+// setToken is called with the lock held on every acquisition
+func (m *memoryLock) setToken(options lock.AcquireOptions) {
+	m.token++
+	if options.Token != nil {
+		*options.Token = m.token
+	}
+}
+
 func (m *memoryLock) Release(id string) error {
 	m.Lock()
 	defer m.Unlock()
@@ -31,3 +31,12 @@ func Wait(t time.Duration) AcquireOption {
 		o.Wait = t
 	}
 }
+
+// FencingToken sets where the fencing token of the acquired lock is written.
+// Tokens increase with every acquisition so a resource guarded by the lock
+// can reject writes carrying a token older than one it has already seen.
+func FencingToken(t *uint64) AcquireOption {
+	return func(o *AcquireOptions) {
+		o.Token = t
+	}
+}
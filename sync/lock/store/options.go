@@ -0,0 +1,20 @@
+package store
+
+import (
+	"context"
+
+	st "github.com/stack-labs/stack/store"
+	"github.com/stack-labs/stack/sync/lock"
+)
+
+type storeKey struct{}
+
+// Store sets the store the locks are held in, it must implement store.Versioned
+func Store(s st.Store) lock.Option {
+	return func(o *lock.Options) {
+		if o.Context == nil {
+			o.Context = context.Background()
+		}
+		o.Context = context.WithValue(o.Context, storeKey{}, s)
+	}
+}
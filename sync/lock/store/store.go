@@ -0,0 +1,101 @@
+// Package store is a lock held as a lease on a store record. Locks are
+// renewed in the background until released, so the TTL only frees the lock
+// when its holder dies.
+package store
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	st "github.com/stack-labs/stack/store"
+	"github.com/stack-labs/stack/store/memory"
+	"github.com/stack-labs/stack/sync/internal/lease"
+	"github.com/stack-labs/stack/sync/lock"
+)
+
+var (
+	// DefaultPrefix is the key prefix of lock records
+	DefaultPrefix = "lock/"
+	// DefaultTTL is the ttl of locks acquired without one
+	DefaultTTL = 30 * time.Second
+)
+
+type storeLock struct {
+	opts  lock.Options
+	store st.Store
+
+	sync.Mutex
+	leases map[string]*lease.Lease
+}
+
+func (s *storeLock) Acquire(id string, opts ...lock.AcquireOption) error {
+	var options lock.AcquireOptions
+	for _, o := range opts {
+		o(&options)
+	}
+	if options.TTL <= 0 {
+		options.TTL = DefaultTTL
+	}
+
+	vs, ok := s.store.(st.Versioned)
+	if !ok {
+		return errors.New("lock store does not support versioned writes")
+	}
+
+	l, err := lease.Acquire(context.Background(), vs, s.opts.Prefix+id, uuid.New().String(), options.TTL, options.Wait)
+	if err != nil {
+		return err
+	}
+
+	s.Lock()
+	s.leases[id] = l
+	s.Unlock()
+
+	if options.Token != nil {
+		*options.Token = l.Token()
+	}
+
+	return nil
+}
+
+func (s *storeLock) Release(id string) error {
+	s.Lock()
+	l, ok := s.leases[id]
+	delete(s.leases, id)
+	s.Unlock()
+
+	// not held by us
+	if !ok {
+		return nil
+	}
+
+	return l.Release()
+}
+
+// NewLock returns a lock held in the store set with the Store option,
+// an in memory store is used if none is set
+func NewLock(opts ...lock.Option) lock.Lock {
+	options := lock.Options{
+		Prefix: DefaultPrefix,
+	}
+	for _, o := range opts {
+		o(&options)
+	}
+
+	var s st.Store
+	if options.Context != nil {
+		s, _ = options.Context.Value(storeKey{}).(st.Store)
+	}
+	if s == nil {
+		s = memory.NewStore()
+	}
+
+	return &storeLock{
+		opts:   options,
+		store:  s,
+		leases: make(map[string]*lease.Lease),
+	}
+}
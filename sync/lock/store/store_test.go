@@ -0,0 +1,78 @@
+package store
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stack-labs/stack/store/memory"
+	"github.com/stack-labs/stack/sync/lock"
+)
+
+func TestAcquireRelease(t *testing.T) {
+	l := NewLock(Store(memory.NewStore()))
+
+	var t1, t2 uint64
+	if err := l.Acquire("test", lock.FencingToken(&t1)); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := l.Acquire("test", lock.Wait(time.Millisecond*200)); err != lock.ErrLockTimeout {
+		t.Fatalf("expected lock timeout, got %v", err)
+	}
+
+	if err := l.Release("test"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := l.Acquire("test", lock.FencingToken(&t2), lock.Wait(time.Second)); err != nil {
+		t.Fatal(err)
+	}
+	defer l.Release("test")
+
+	if t2 <= t1 {
+		t.Fatalf("expected fencing token to increase, got %d then %d", t1, t2)
+	}
+}
+
+func TestTTLRenewal(t *testing.T) {
+	s := memory.NewStore()
+	l1 := NewLock(Store(s))
+	l2 := NewLock(Store(s))
+
+	if err := l1.Acquire("test", lock.TTL(time.Millisecond*150)); err != nil {
+		t.Fatal(err)
+	}
+
+	// the holder renews the lease so it outlives its ttl
+	if err := l2.Acquire("test", lock.Wait(time.Millisecond*500)); err != lock.ErrLockTimeout {
+		t.Fatalf("expected lock timeout, got %v", err)
+	}
+
+	if err := l1.Release("test"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := l2.Acquire("test", lock.Wait(time.Second)); err != nil {
+		t.Fatal(err)
+	}
+	l2.Release("test")
+}
+
+func TestDefaultTTL(t *testing.T) {
+	s := memory.NewStore()
+	l := NewLock(Store(s))
+
+	if err := l.Acquire("test"); err != nil {
+		t.Fatal(err)
+	}
+	defer l.Release("test")
+
+	// a lock acquired without a ttl still expires if its holder dies
+	recs, err := s.Read(DefaultPrefix + "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if recs[0].Expiry <= 0 || recs[0].Expiry > DefaultTTL {
+		t.Fatalf("expected lock record to expire within %v, got %v", DefaultTTL, recs[0].Expiry)
+	}
+}
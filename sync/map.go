@@ -0,0 +1,184 @@
+package sync
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"time"
+
+	"github.com/stack-labs/stack/store"
+	"github.com/stack-labs/stack/store/memory"
+	"github.com/stack-labs/stack/sync/lock"
+	lstore "github.com/stack-labs/stack/sync/lock/store"
+)
+
+var (
+	// DefaultMapPrefix is the key prefix of map entries in the store
+	DefaultMapPrefix = "map/"
+	// DefaultMapLockTTL frees the lock of an entry whose holder died
+	DefaultMapLockTTL = 30 * time.Second
+)
+
+type syncMap struct {
+	opts Options
+}
+
+func ekey(k interface{}) (string, error) {
+	b, err := json.Marshal(k)
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(b), nil
+}
+
+func (m *syncMap) Read(key, val interface{}) error {
+	kstr, err := ekey(key)
+	if err != nil {
+		return err
+	}
+
+	// lock
+	if err := m.opts.Lock.Acquire(kstr, lock.TTL(DefaultMapLockTTL)); err != nil {
+		return err
+	}
+	defer m.opts.Lock.Release(kstr)
+
+	// get key
+	recs, err := m.opts.Store.Read(DefaultMapPrefix + kstr)
+	if err != nil {
+		return err
+	}
+	if len(recs) == 0 {
+		return store.ErrNotFound
+	}
+
+	// decode value
+	return json.Unmarshal(recs[0].Value, val)
+}
+
+func (m *syncMap) Write(key, val interface{}) error {
+	kstr, err := ekey(key)
+	if err != nil {
+		return err
+	}
+
+	// lock
+	if err := m.opts.Lock.Acquire(kstr, lock.TTL(DefaultMapLockTTL)); err != nil {
+		return err
+	}
+	defer m.opts.Lock.Release(kstr)
+
+	// encode value
+	b, err := json.Marshal(val)
+	if err != nil {
+		return err
+	}
+
+	// set key
+	return m.opts.Store.Write(&store.Record{
+		Key:   DefaultMapPrefix + kstr,
+		Value: b,
+	})
+}
+
+func (m *syncMap) Delete(key interface{}) error {
+	kstr, err := ekey(key)
+	if err != nil {
+		return err
+	}
+
+	// lock
+	if err := m.opts.Lock.Acquire(kstr, lock.TTL(DefaultMapLockTTL)); err != nil {
+		return err
+	}
+	defer m.opts.Lock.Release(kstr)
+
+	return m.opts.Store.Delete(DefaultMapPrefix + kstr)
+}
+
+func (m *syncMap) Iterate(fn func(key, val interface{}) error) error {
+	keys, err := m.opts.Store.List(store.ListPrefix(DefaultMapPrefix), store.ListKeysOnly())
+	if err != nil {
+		return err
+	}
+
+	for _, k := range keys {
+		kstr := k.Key[len(DefaultMapPrefix):]
+
+		kb, err := base64.StdEncoding.DecodeString(kstr)
+		if err != nil {
+			return err
+		}
+		var key interface{}
+		if err := json.Unmarshal(kb, &key); err != nil {
+			return err
+		}
+
+		if err := m.iterate(kstr, key, fn); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// iterate calls fn for a single entry under its lock and saves the value if it changed
+func (m *syncMap) iterate(kstr string, key interface{}, fn func(key, val interface{}) error) error {
+	if err := m.opts.Lock.Acquire(kstr, lock.TTL(DefaultMapLockTTL)); err != nil {
+		return err
+	}
+	defer m.opts.Lock.Release(kstr)
+
+	recs, err := m.opts.Store.Read(DefaultMapPrefix + kstr)
+	if err == store.ErrNotFound || (err == nil && len(recs) == 0) {
+		// deleted since listed
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	var val interface{}
+	if err := json.Unmarshal(recs[0].Value, &val); err != nil {
+		return err
+	}
+
+	if err := fn(key, val); err != nil {
+		return err
+	}
+
+	b, err := json.Marshal(val)
+	if err != nil {
+		return err
+	}
+	if bytes.Equal(b, recs[0].Value) {
+		return nil
+	}
+
+	return m.opts.Store.Write(&store.Record{
+		Key:   DefaultMapPrefix + kstr,
+		Value: b,
+	})
+}
+
+// NewMap returns a Map held in the store option, guarded by the lock option.
+// Both default to a lock held in an in memory store.
+func NewMap(opts ...Option) Map {
+	var options Options
+	for _, o := range opts {
+		o(&options)
+	}
+
+	if options.Store == nil {
+		options.Store = memory.NewStore()
+	}
+	if options.Lock == nil {
+		options.Lock = lstore.NewLock(
+			lstore.Store(options.Store),
+			lock.Prefix(lstore.DefaultPrefix+DefaultMapPrefix),
+		)
+	}
+
+	return &syncMap{
+		opts: options,
+	}
+}
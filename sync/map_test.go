@@ -0,0 +1,45 @@
+package sync
+
+import (
+	"testing"
+
+	"github.com/stack-labs/stack/store"
+)
+
+func TestMap(t *testing.T) {
+	m := NewMap()
+
+	if err := m.Write("foo", map[string]int{"count": 1}); err != nil {
+		t.Fatal(err)
+	}
+
+	var v map[string]int
+	if err := m.Read("foo", &v); err != nil {
+		t.Fatal(err)
+	}
+	if v["count"] != 1 {
+		t.Fatalf("expected count 1, got %v", v)
+	}
+
+	// changes made while iterating are saved
+	err := m.Iterate(func(key, val interface{}) error {
+		val.(map[string]interface{})["count"] = 2
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Read("foo", &v); err != nil {
+		t.Fatal(err)
+	}
+	if v["count"] != 2 {
+		t.Fatalf("expected count 2, got %v", v)
+	}
+
+	if err := m.Delete("foo"); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Read("foo", &v); err != store.ErrNotFound {
+		t.Fatalf("expected not found, got %v", err)
+	}
+}
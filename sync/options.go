@@ -4,6 +4,7 @@ import (
 	"github.com/stack-labs/stack/store"
 	"github.com/stack-labs/stack/sync/leader"
 	"github.com/stack-labs/stack/sync/lock"
+	"github.com/stack-labs/stack/sync/task"
 	"github.com/stack-labs/stack/sync/time"
 )
 
@@ -34,3 +35,24 @@ func WithTime(t time.Time) Option {
 		o.Time = t
 	}
 }
+
+// WithTask sets the task runner implementation option
+func WithTask(t task.Task) Option {
+	return func(o *Options) {
+		o.Task = t
+	}
+}
+
+// WithNode sets the node id recorded with cron executions
+func WithNode(id string) Option {
+	return func(o *Options) {
+		o.Node = id
+	}
+}
+
+// WithHistory sets how many executions the cron keeps per job
+func WithHistory(n int) Option {
+	return func(o *Options) {
+		o.History = n
+	}
+}
@@ -2,11 +2,13 @@
 package sync
 
 import (
+	"time"
+
 	"github.com/stack-labs/stack/store"
 	"github.com/stack-labs/stack/sync/leader"
 	"github.com/stack-labs/stack/sync/lock"
 	"github.com/stack-labs/stack/sync/task"
-	"github.com/stack-labs/stack/sync/time"
+	stime "github.com/stack-labs/stack/sync/time"
 )
 
 // Map provides synchronized access to key-value storage.
@@ -28,6 +30,37 @@ type Map interface {
 // task interfaces.
 type Cron interface {
 	Schedule(task.Schedule, task.Command) error
+	// Jobs returns the scheduled jobs
+	Jobs() []*Job
+	// History returns the last executions of a job, newest first
+	History(id string, limit int) ([]*Execution, error)
+	// Stop ends the scheduled jobs
+	Stop() error
+}
+
+// Job is a command scheduled on a cron
+type Job struct {
+	// Id of the job, unique to its schedule and command
+	Id string
+	// Name of the command
+	Name string
+	// Schedule of the job
+	Schedule string
+	// Leader is true while this node runs the job
+	Leader bool
+	// Next run, zero if not known or there are no more runs
+	Next time.Time
+}
+
+// Execution is a recorded run of a job
+type Execution struct {
+	Job  string `json:"job"`
+	Node string `json:"node"`
+	// Scheduled is when the run was due
+	Scheduled time.Time `json:"scheduled"`
+	Started   time.Time `json:"started"`
+	Ended     time.Time `json:"ended"`
+	Error     string    `json:"error,omitempty"`
 }
 
 type Options struct {
@@ -35,7 +68,11 @@ type Options struct {
 	Lock   lock.Lock
 	Store  store.Store
 	Task   task.Task
-	Time   time.Time
+	Time   stime.Time
+	// Node is the id recorded with executions
+	Node string
+	// History is how many executions are kept per job
+	History int
 }
 
 type Option func(o *Options)
@@ -6,7 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"math/rand"
-	"strings"
+	"strconv"
 	"sync"
 	"time"
 
@@ -26,79 +26,101 @@ type Task struct {
 	// Options
 	Options task.Options
 
-	mtx    sync.RWMutex
-	status string
+	// workers bounds the commands this runner executes at once to the pool size
+	workers chan bool
+
+	mtx     sync.RWMutex
+	status  string
+	running map[string]*run
 }
 
-func returnError(err error, ch chan error) {
-	select {
-	case ch <- err:
-	default:
-	}
+// run is an execution being worked on by this runner
+type run struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	// workers running it
+	refs int
 }
 
 func (t *Task) Run(c task.Command) error {
-	// connect
-	t.Broker.Connect()
+	return t.Start(context.Background(), c).Wait()
+}
+
+// Start publishes the command to pool size workers and reports each of their results
+func (t *Task) Start(ctx context.Context, c task.Command) task.Handle {
+	ctx, cancel := context.WithCancel(ctx)
 	// unique id for this runner
 	id := uuid.New().String()
 	// topic of the command
 	topic := fmt.Sprintf("task.%s", c.Name)
+	started := time.Now()
+
+	h := task.NewRunHandle(id, t.Options.Pool, cancel)
+
+	if err := t.start(ctx, h, c, topic); err != nil {
+		h.Abort(c.Name, started, err)
+		return h
+	}
+
+	go func() {
+		select {
+		case <-h.Done():
+			return
+		case <-ctx.Done():
+		}
+
+		// cancelled before all workers reported
+		select {
+		case <-h.Done():
+			return
+		default:
+		}
+
+		if err := t.publish(topic, c.Name, id, "cancel", nil); err != nil {
+			t.setStatus(fmt.Sprintf("command [%s] cancel error: %v", c.Name, err))
+		}
+		h.Abort(c.Name, started, ctx.Err())
+	}()
+
+	return h
+}
+
+func (t *Task) start(ctx context.Context, h *task.RunHandle, c task.Command, topic string) error {
+	// connect
+	if err := t.Broker.Connect(); err != nil {
+		return err
+	}
 
-	// global error
-	errCh := make(chan error, t.Options.Pool)
+	id := h.Id()
 
 	// subscribe for distributed work
 	workFn := func(p broker.Event) error {
 		msg := p.Message()
 
-		// get command name
-		command := msg.Header["Command"]
-
 		// check the command is what we expect
-		if command != c.Name {
-			returnError(errors.New("received unknown command: "+command), errCh)
+		if msg.Header["Command"] != c.Name {
 			return nil
 		}
 
-		// new task created
 		switch msg.Header["Status"] {
+		// new task created
 		case "start":
-			// artificially delay start of processing
-			time.Sleep(time.Millisecond * time.Duration(10+rand.Intn(100)))
-
-			// execute the function
-			err := c.Func()
-
-			status := "done"
-			errors := ""
-
-			if err != nil {
-				status = "error"
-				errors = err.Error()
-			}
-
-			// create response
-			msg := &broker.Message{
-				Header: map[string]string{
-					"Command":   c.Name,
-					"Error":     errors,
-					"Id":        id,
-					"Status":    status,
-					"Timestamp": fmt.Sprintf("%d", time.Now().Unix()),
-				},
-				// Body is nil, may be used in future
-			}
-
-			// publish end of task
-			if err := t.Broker.Publish(topic, msg); err != nil {
-				returnError(err, errCh)
+			go t.work(msg, c, topic)
+		// the runner gave up
+		case "cancel":
+			t.mtx.RLock()
+			r, ok := t.running[msg.Header["Id"]]
+			t.mtx.RUnlock()
+			if ok {
+				r.cancel()
 			}
 		}
 
 		return nil
 	}
 
+	var subs []broker.Subscriber
+
 	// subscribe for the pool size
 	for i := 0; i < t.Options.Pool; i++ {
 		// subscribe to work
@@ -106,31 +128,21 @@ func (t *Task) Run(c task.Command) error {
 		if err != nil {
 			return err
 		}
-
-		// unsubscribe on completion
-		defer subWork.Unsubscribe()
+		subs = append(subs, subWork)
 	}
 
 	// subscribe to all status messages
 	subStatus, err := t.Broker.Subscribe(topic, func(p broker.Event) error {
 		msg := p.Message()
 
-		// get command name
-		command := msg.Header["Command"]
-
-		// check the command is what we expect
-		if command != c.Name {
+		// check the result belongs to this run
+		if msg.Header["Command"] != c.Name || msg.Header["Id"] != id {
 			return nil
 		}
 
-		// check task status
-		switch msg.Header["Status"] {
-		// task is complete
-		case "done":
-			errCh <- nil
-		// someone failed
-		case "error":
-			returnError(errors.New(msg.Header["Error"]), errCh)
+		switch task.Status(msg.Header["Status"]) {
+		case task.StatusDone, task.StatusError, task.StatusCancelled, task.StatusTimeout:
+			h.Report(result(msg))
 		}
 
 		return nil
@@ -138,45 +150,151 @@ func (t *Task) Run(c task.Command) error {
 	if err != nil {
 		return err
 	}
-	defer subStatus.Unsubscribe()
+	subs = append(subs, subStatus)
+
+	// unsubscribe on completion
+	go func() {
+		<-h.Done()
+		for _, sub := range subs {
+			sub.Unsubscribe()
+		}
+	}()
+
+	// artificially delay the start of the task
+	time.Sleep(time.Millisecond * time.Duration(10+rand.Intn(100)))
+
+	// a new task, the deadline is carried to the workers
+	header := map[string]string{}
+	if d, ok := ctx.Deadline(); ok {
+		header["Deadline"] = fmt.Sprintf("%d", d.UnixNano())
+	}
+
+	// publish the task
+	return t.publish(topic, c.Name, id, "start", header)
+}
+
+// work executes a command for a start message and publishes its result
+func (t *Task) work(msg *broker.Message, c task.Command, topic string) {
+	id := msg.Header["Id"]
+
+	t.mtx.Lock()
+	r, ok := t.running[id]
+	if !ok {
+		r = new(run)
+		r.ctx, r.cancel = context.WithCancel(context.Background())
+		t.running[id] = r
+	}
+	r.refs++
+	t.mtx.Unlock()
+
+	defer func() {
+		t.mtx.Lock()
+		if r.refs--; r.refs == 0 {
+			r.cancel()
+			delete(t.running, id)
+		}
+		t.mtx.Unlock()
+	}()
+
+	ctx := r.ctx
+	if d, err := strconv.ParseInt(msg.Header["Deadline"], 10, 64); err == nil {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithDeadline(ctx, time.Unix(0, d))
+		defer cancel()
+	}
 
-	// a new task
+	// wait for a free worker
+	select {
+	case t.workers <- true:
+	case <-ctx.Done():
+		return
+	}
+
+	// artificially delay start of processing
+	time.Sleep(time.Millisecond * time.Duration(10+rand.Intn(100)))
+
+	worker := uuid.New().String()
+	started := time.Now()
+
+	// execute the function, the worker is busy until the command returned
+	// even past its deadline
+	done, err := c.Run(ctx)
+	go func() {
+		<-done
+		<-t.workers
+	}()
+
+	res := task.NewResult(id, c.Name, worker, started, err)
+	if res.Error != nil {
+		t.setStatus(res.String())
+	}
+
+	errs := ""
+	if res.Error != nil {
+		errs = res.Error.Error()
+	}
+
+	// publish end of task
+	err = t.publish(topic, c.Name, id, string(res.Status), map[string]string{
+		"Error":    errs,
+		"Worker":   worker,
+		"Started":  fmt.Sprintf("%d", started.UnixNano()),
+		"Duration": fmt.Sprintf("%d", res.Duration),
+	})
+	if err != nil {
+		t.setStatus(fmt.Sprintf("command [%s] publish error: %v", c.Name, err))
+	}
+}
+
+func (t *Task) publish(topic, command, id, status string, header map[string]string) error {
 	msg := &broker.Message{
 		Header: map[string]string{
-			"Command":   c.Name,
+			"Command":   command,
 			"Id":        id,
-			"Status":    "start",
+			"Status":    status,
 			"Timestamp": fmt.Sprintf("%d", time.Now().Unix()),
 		},
+		// Body is nil, may be used in future
 	}
-
-	// artificially delay the start of the task
-	time.Sleep(time.Millisecond * time.Duration(10+rand.Intn(100)))
-
-	// publish the task
-	if err := t.Broker.Publish(topic, msg); err != nil {
-		return err
+	for k, v := range header {
+		msg.Header[k] = v
 	}
 
-	var gerrors []string
+	return t.Broker.Publish(topic, msg)
+}
 
-	// wait for all responses
-	for i := 0; i < t.Options.Pool; i++ {
-		// check errors
-		err := <-errCh
+// result decodes the result published by a worker
+func result(msg *broker.Message) task.Result {
+	r := task.Result{
+		Id:      msg.Header["Id"],
+		Command: msg.Header["Command"],
+		Worker:  msg.Header["Worker"],
+		Status:  task.Status(msg.Header["Status"]),
+	}
 
-		// append to errors
-		if err != nil {
-			gerrors = append(gerrors, err.Error())
-		}
+	if v, err := strconv.ParseInt(msg.Header["Started"], 10, 64); err == nil {
+		r.Started = time.Unix(0, v)
+	}
+	if v, err := strconv.ParseInt(msg.Header["Duration"], 10, 64); err == nil {
+		r.Duration = time.Duration(v)
 	}
 
-	// return the errors
-	if len(gerrors) > 0 {
-		return errors.New("errors: " + strings.Join(gerrors, "\n"))
+	switch r.Status {
+	case task.StatusTimeout:
+		r.Error = context.DeadlineExceeded
+	case task.StatusCancelled:
+		r.Error = context.Canceled
+	case task.StatusError:
+		r.Error = errors.New(msg.Header["Error"])
 	}
 
-	return nil
+	return r
+}
+
+func (t *Task) setStatus(status string) {
+	t.mtx.Lock()
+	t.status = status
+	t.mtx.Unlock()
 }
 
 func (t *Task) Status() string {
@@ -217,5 +335,7 @@ func NewTask(opts ...task.Option) task.Task {
 	return &Task{
 		Broker:  b,
 		Options: options,
+		workers: make(chan bool, options.Pool),
+		running: make(map[string]*run),
 	}
 }
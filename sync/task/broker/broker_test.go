@@ -0,0 +1,123 @@
+package broker
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stack-labs/stack/broker/memory"
+	"github.com/stack-labs/stack/sync/task"
+)
+
+func TestRun(t *testing.T) {
+	tk := NewTask(WithBroker(memory.NewBroker()), task.WithPool(3))
+
+	var runs int32
+	err := tk.Run(task.Command{
+		Name: "count",
+		Func: func() error {
+			atomic.AddInt32(&runs, 1)
+			return nil
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if runs != 3 {
+		t.Fatalf("expected 3 runs, got %d", runs)
+	}
+
+	err = tk.Run(task.Command{Name: "fail", Func: func() error { return errors.New("failed") }})
+	if err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func TestPool(t *testing.T) {
+	tk := NewTask(WithBroker(memory.NewBroker()), task.WithPool(2))
+
+	var running, max int32
+	cmd := task.Command{
+		Name: "pool",
+		Func: func() error {
+			n := atomic.AddInt32(&running, 1)
+			defer atomic.AddInt32(&running, -1)
+			for {
+				m := atomic.LoadInt32(&max)
+				if n <= m || atomic.CompareAndSwapInt32(&max, m, n) {
+					break
+				}
+			}
+			time.Sleep(time.Millisecond * 50)
+			return nil
+		},
+	}
+
+	// two executions of pool size two share the same two workers
+	h1 := tk.Start(context.Background(), cmd)
+	h2 := tk.Start(context.Background(), cmd)
+	if err := h1.Wait(); err != nil {
+		t.Fatal(err)
+	}
+	if err := h2.Wait(); err != nil {
+		t.Fatal(err)
+	}
+
+	if max > 2 {
+		t.Fatalf("expected at most 2 concurrent runs, got %d", max)
+	}
+}
+
+func TestCancel(t *testing.T) {
+	tk := NewTask(WithBroker(memory.NewBroker()))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	h := tk.Start(ctx, task.Command{
+		Name: "blocked",
+		Handler: func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		},
+	})
+
+	time.Sleep(time.Millisecond * 200)
+	cancel()
+
+	r, ok := <-h.Results()
+	if !ok || r.Status != task.StatusCancelled {
+		t.Fatalf("expected cancelled result, got %+v", r)
+	}
+}
+
+func TestTimeoutHoldsWorker(t *testing.T) {
+	tk := NewTask(WithBroker(memory.NewBroker()), task.WithPool(1))
+
+	var running, max int32
+	cmd := task.Command{
+		Name: "slow",
+		// the func does not stop at the timeout
+		Func: func() error {
+			n := atomic.AddInt32(&running, 1)
+			defer atomic.AddInt32(&running, -1)
+			if n > atomic.LoadInt32(&max) {
+				atomic.StoreInt32(&max, n)
+			}
+			time.Sleep(time.Millisecond * 300)
+			return nil
+		},
+		Timeout: time.Millisecond * 50,
+	}
+
+	h1 := tk.Start(context.Background(), cmd)
+	h2 := tk.Start(context.Background(), cmd)
+	h1.Wait()
+	h2.Wait()
+
+	// the second run waits for the first func to return
+	time.Sleep(time.Millisecond * 400)
+	if m := atomic.LoadInt32(&max); m != 1 {
+		t.Fatalf("expected 1 concurrent run, got %d", m)
+	}
+}
@@ -0,0 +1,292 @@
+package task
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Expression is a parsed cron expression
+type Expression struct {
+	second, minute, hour, dom, month, dow uint64
+	// day of month or week is restricted
+	domStar, dowStar bool
+	// location the expression is evaluated in, nil for that of the time given
+	location *time.Location
+}
+
+type bounds struct {
+	min, max uint
+	names    map[string]uint
+}
+
+var (
+	seconds = bounds{0, 59, nil}
+	minutes = bounds{0, 59, nil}
+	hours   = bounds{0, 23, nil}
+	dom     = bounds{1, 31, nil}
+	months  = bounds{1, 12, map[string]uint{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+		"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	}}
+	dow = bounds{0, 7, map[string]uint{
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+	}}
+
+	descriptors = map[string]string{
+		"@yearly":   "0 0 0 1 1 *",
+		"@annually": "0 0 0 1 1 *",
+		"@monthly":  "0 0 0 1 * *",
+		"@weekly":   "0 0 0 * * 0",
+		"@daily":    "0 0 0 * * *",
+		"@midnight": "0 0 0 * * *",
+		"@hourly":   "0 0 * * * *",
+	}
+)
+
+// Parse parses a standard 5 field cron expression, a 6 field expression with
+// a leading seconds field or a descriptor such as @daily. The expression may
+// be prefixed with a time zone e.g "CRON_TZ=Europe/London 0 9 * * MON-FRI".
+func Parse(spec string) (*Expression, error) {
+	spec = strings.TrimSpace(spec)
+	e := new(Expression)
+
+	if strings.HasPrefix(spec, "CRON_TZ=") || strings.HasPrefix(spec, "TZ=") {
+		i := strings.Index(spec, " ")
+		if i < 0 {
+			return nil, fmt.Errorf("missing cron expression after time zone in %q", spec)
+		}
+		name := spec[strings.Index(spec, "=")+1 : i]
+		loc, err := time.LoadLocation(name)
+		if err != nil {
+			return nil, fmt.Errorf("invalid time zone %q: %v", name, err)
+		}
+		e.location = loc
+		spec = strings.TrimSpace(spec[i:])
+	}
+
+	if strings.HasPrefix(spec, "@") {
+		d, ok := descriptors[strings.ToLower(spec)]
+		if !ok {
+			return nil, fmt.Errorf("unknown cron descriptor %q", spec)
+		}
+		spec = d
+	}
+
+	fields := strings.Fields(spec)
+	switch len(fields) {
+	case 5:
+		fields = append([]string{"0"}, fields...)
+	case 6:
+	default:
+		return nil, fmt.Errorf("expected 5 or 6 fields in cron expression %q, found %d", spec, len(fields))
+	}
+
+	var err error
+	if e.second, err = parseField(fields[0], seconds); err != nil {
+		return nil, err
+	}
+	if e.minute, err = parseField(fields[1], minutes); err != nil {
+		return nil, err
+	}
+	if e.hour, err = parseField(fields[2], hours); err != nil {
+		return nil, err
+	}
+	if e.dom, err = parseField(fields[3], dom); err != nil {
+		return nil, err
+	}
+	if e.month, err = parseField(fields[4], months); err != nil {
+		return nil, err
+	}
+	if e.dow, err = parseField(fields[5], dow); err != nil {
+		return nil, err
+	}
+
+	// 7 is sunday too
+	if e.dow&(1<<7) > 0 {
+		e.dow |= 1
+	}
+
+	e.domStar = isStar(fields[3])
+	e.dowStar = isStar(fields[5])
+
+	return e, nil
+}
+
+func isStar(field string) bool {
+	return field == "*" || field == "?"
+}
+
+// parseField returns the bits set for a comma separated list of values, ranges and steps
+func parseField(field string, b bounds) (uint64, error) {
+	var bits uint64
+
+	for _, expr := range strings.Split(field, ",") {
+		rangeAndStep := strings.Split(expr, "/")
+		if len(rangeAndStep) > 2 {
+			return 0, fmt.Errorf("too many slashes in %q", expr)
+		}
+
+		var start, end uint
+		step := uint(1)
+
+		lowAndHigh := strings.Split(rangeAndStep[0], "-")
+		switch {
+		case isStar(rangeAndStep[0]):
+			start, end = b.min, b.max
+		case len(lowAndHigh) == 1:
+			v, err := parseValue(lowAndHigh[0], b)
+			if err != nil {
+				return 0, err
+			}
+			start, end = v, v
+			// a single value with a step runs to the end of the range
+			if len(rangeAndStep) == 2 {
+				end = b.max
+			}
+		case len(lowAndHigh) == 2:
+			var err error
+			if start, err = parseValue(lowAndHigh[0], b); err != nil {
+				return 0, err
+			}
+			if end, err = parseValue(lowAndHigh[1], b); err != nil {
+				return 0, err
+			}
+		default:
+			return 0, fmt.Errorf("too many hyphens in %q", expr)
+		}
+
+		if len(rangeAndStep) == 2 {
+			v, err := strconv.Atoi(rangeAndStep[1])
+			if err != nil || v <= 0 {
+				return 0, fmt.Errorf("invalid step in %q", expr)
+			}
+			step = uint(v)
+		}
+
+		if start > end {
+			return 0, fmt.Errorf("beginning of range after end in %q", expr)
+		}
+
+		for i := start; i <= end; i += step {
+			bits |= 1 << i
+		}
+	}
+
+	return bits, nil
+}
+
+func parseValue(v string, b bounds) (uint, error) {
+	if n, ok := b.names[strings.ToLower(v)]; ok {
+		return n, nil
+	}
+
+	i, err := strconv.Atoi(v)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %q", v)
+	}
+	if i < int(b.min) || i > int(b.max) {
+		return 0, fmt.Errorf("value %d out of range [%d, %d]", i, b.min, b.max)
+	}
+	return uint(i), nil
+}
+
+// Next returns the first time matching the expression after t, or the
+// zero time if nothing matches within five years
+func (e *Expression) Next(t time.Time) time.Time {
+	orig := t.Location()
+	loc := orig
+	if e.location != nil {
+		loc = e.location
+	}
+	t = t.In(loc)
+
+	// start at the next whole second
+	t = t.Add(time.Second - time.Duration(t.Nanosecond()))
+
+	// whether a field has been incremented, the fields below it are then reset
+	added := false
+	yearLimit := t.Year() + 5
+
+wrap:
+	if t.Year() > yearLimit {
+		return time.Time{}
+	}
+
+	for 1<<uint(t.Month())&e.month == 0 {
+		if !added {
+			added = true
+			t = time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, loc)
+		}
+		t = t.AddDate(0, 1, 0)
+		if t.Month() == time.January {
+			goto wrap
+		}
+	}
+
+	for !e.dayMatches(t) {
+		if !added {
+			added = true
+			t = time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc)
+		}
+		t = t.AddDate(0, 0, 1)
+		// a daylight saving change may leave midnight on another hour
+		if t.Hour() != 0 {
+			if t.Hour() > 12 {
+				t = t.Add(time.Duration(24-t.Hour()) * time.Hour)
+			} else {
+				t = t.Add(time.Duration(-t.Hour()) * time.Hour)
+			}
+		}
+		if t.Day() == 1 {
+			goto wrap
+		}
+	}
+
+	for 1<<uint(t.Hour())&e.hour == 0 {
+		if !added {
+			added = true
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), 0, 0, 0, loc)
+		}
+		t = t.Add(time.Hour)
+		if t.Hour() == 0 {
+			goto wrap
+		}
+	}
+
+	for 1<<uint(t.Minute())&e.minute == 0 {
+		if !added {
+			added = true
+			t = t.Truncate(time.Minute)
+		}
+		t = t.Add(time.Minute)
+		if t.Minute() == 0 {
+			goto wrap
+		}
+	}
+
+	for 1<<uint(t.Second())&e.second == 0 {
+		if !added {
+			added = true
+			t = t.Truncate(time.Second)
+		}
+		t = t.Add(time.Second)
+		if t.Second() == 0 {
+			goto wrap
+		}
+	}
+
+	return t.In(orig)
+}
+
+// dayMatches follows cron in matching either day field when both are restricted
+func (e *Expression) dayMatches(t time.Time) bool {
+	domMatch := 1<<uint(t.Day())&e.dom > 0
+	dowMatch := 1<<uint(t.Weekday())&e.dow > 0
+
+	if e.domStar || e.dowStar {
+		return domMatch && dowMatch
+	}
+	return domMatch || dowMatch
+}
@@ -0,0 +1,89 @@
+package task
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	for _, spec := range []string{
+		"60 * * * *",
+		"* * * *",
+		"* * * * * * *",
+		"*/0 * * * *",
+		"5-1 * * * *",
+		"* * * FOO *",
+		"@fortnightly",
+		"CRON_TZ=Nowhere/Land * * * * *",
+	} {
+		if _, err := Parse(spec); err == nil {
+			t.Errorf("expected error parsing %q", spec)
+		}
+	}
+}
+
+func TestNext(t *testing.T) {
+	ny, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip(err)
+	}
+
+	testData := []struct {
+		spec string
+		from string
+		next string
+	}{
+		{"* * * * *", "2020-01-01T00:00:30Z", "2020-01-01T00:01:00Z"},
+		{"*/15 * * * * *", "2020-01-01T00:00:01Z", "2020-01-01T00:00:15Z"},
+		{"0 9 * * MON-FRI", "2020-01-03T10:00:00Z", "2020-01-06T09:00:00Z"},
+		{"30 2 1,15 * *", "2020-01-02T00:00:00Z", "2020-01-15T02:30:00Z"},
+		{"0 0 29 2 *", "2020-03-01T00:00:00Z", "2024-02-29T00:00:00Z"},
+		{"@monthly", "2020-12-15T00:00:00Z", "2021-01-01T00:00:00Z"},
+		{"@weekly", "2020-01-01T00:00:00Z", "2020-01-05T00:00:00Z"},
+		// either day field matches when both are restricted
+		{"0 0 13 * 5", "2020-01-01T00:00:00Z", "2020-01-03T00:00:00Z"},
+		// sunday as 7
+		{"0 0 * * 7", "2020-01-01T00:00:00Z", "2020-01-05T00:00:00Z"},
+		{"CRON_TZ=America/New_York 0 9 * * *", "2020-01-01T00:00:00Z", "2020-01-01T14:00:00Z"},
+	}
+
+	for _, d := range testData {
+		e, err := Parse(d.spec)
+		if err != nil {
+			t.Fatalf("error parsing %q: %v", d.spec, err)
+		}
+		from, _ := time.Parse(time.RFC3339, d.from)
+		next, _ := time.Parse(time.RFC3339, d.next)
+		if got := e.Next(from); !got.Equal(next) {
+			t.Errorf("%q from %s: expected %s, got %s", d.spec, d.from, d.next, got)
+		}
+	}
+
+	// the schedule location applies unless the expression has its own
+	s := Schedule{Cron: "0 9 * * *", Location: ny}
+	from, _ := time.Parse(time.RFC3339, "2020-07-01T00:00:00Z")
+	if got := s.Next(from); !got.Equal(time.Date(2020, 7, 1, 13, 0, 0, 0, time.UTC)) {
+		t.Errorf("expected 09:00 in New York, got %s", got)
+	}
+}
+
+func TestScheduleNext(t *testing.T) {
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	s := Schedule{Time: start, Interval: time.Hour}
+
+	if got := s.Next(start.Add(-time.Minute)); !got.Equal(start) {
+		t.Errorf("expected the start time, got %s", got)
+	}
+	if got := s.Next(start); !got.Equal(start.Add(time.Hour)) {
+		t.Errorf("expected an hour after start, got %s", got)
+	}
+	if got := s.Next(start.Add(90 * time.Minute)); !got.Equal(start.Add(2 * time.Hour)) {
+		t.Errorf("expected two hours after start, got %s", got)
+	}
+
+	// cron runs do not start before the schedule time
+	s = Schedule{Time: start.Add(time.Hour), Cron: "0 * * * *"}
+	if got := s.Next(start); !got.Equal(start.Add(time.Hour)) {
+		t.Errorf("expected the start time, got %s", got)
+	}
+}
@@ -2,9 +2,12 @@
 package local
 
 import (
+	"context"
 	"fmt"
 	"sync"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/stack-labs/stack/sync/task"
 )
 
@@ -15,28 +18,31 @@ type localTask struct {
 }
 
 func (l *localTask) Run(t task.Command) error {
-	ch := make(chan error, l.opts.Pool)
+	return l.Start(context.Background(), t).Wait()
+}
+
+// Start runs the command on pool size goroutines
+func (l *localTask) Start(ctx context.Context, t task.Command) task.Handle {
+	ctx, cancel := context.WithCancel(ctx)
+	h := task.NewRunHandle(uuid.New().String(), l.opts.Pool, cancel)
 
 	for i := 0; i < l.opts.Pool; i++ {
-		go func() {
-			ch <- t.Execute()
-		}()
-	}
+		go func(i int) {
+			started := time.Now()
+			err := t.ExecuteContext(ctx)
+			r := task.NewResult(h.Id(), t.Name, fmt.Sprintf("%d", i), started, err)
 
-	var err error
+			if err != nil {
+				l.mtx.Lock()
+				l.status = r.String()
+				l.mtx.Unlock()
+			}
 
-	for i := 0; i < l.opts.Pool; i++ {
-		er := <-ch
-		if err != nil {
-			err = er
-			l.mtx.Lock()
-			l.status = fmt.Sprintf("command [%s] status: %s", t.Name, err.Error())
-			l.mtx.Unlock()
-		}
+			h.Report(r)
+		}(i)
 	}
 
-	close(ch)
-	return err
+	return h
 }
 
 func (l *localTask) Status() string {
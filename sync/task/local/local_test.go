@@ -0,0 +1,81 @@
+package local
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stack-labs/stack/sync/task"
+)
+
+func TestRun(t *testing.T) {
+	tk := NewTask(task.WithPool(3))
+
+	if err := tk.Run(task.Command{Name: "ok", Func: func() error { return nil }}); err != nil {
+		t.Fatal(err)
+	}
+
+	err := tk.Run(task.Command{Name: "fail", Func: func() error { return errors.New("failed") }})
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if len(tk.Status()) == 0 {
+		t.Fatal("expected status of the failed command")
+	}
+}
+
+func TestStartResults(t *testing.T) {
+	tk := NewTask(task.WithPool(2))
+
+	h := tk.Start(context.Background(), task.Command{
+		Name:    "slow",
+		Timeout: time.Millisecond * 50,
+		Handler: func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		},
+	})
+
+	var results int
+	for r := range h.Results() {
+		results++
+		if r.Status != task.StatusTimeout {
+			t.Fatalf("expected timeout, got %s", r.Status)
+		}
+		if r.Duration < time.Millisecond*50 {
+			t.Fatalf("expected run to last until its deadline, got %v", r.Duration)
+		}
+	}
+	if results != 2 {
+		t.Fatalf("expected 2 results, got %d", results)
+	}
+}
+
+func TestCancel(t *testing.T) {
+	tk := NewTask()
+
+	h := tk.Start(context.Background(), task.Command{
+		Name: "blocked",
+		// does not honour cancellation
+		Func: func() error {
+			time.Sleep(time.Second)
+			return nil
+		},
+	})
+	h.Cancel()
+
+	done := make(chan error)
+	go func() {
+		done <- h.Wait()
+	}()
+
+	select {
+	case err := <-done:
+		if task.StatusOf(err) != task.StatusCancelled {
+			t.Fatalf("expected cancelled, got %v", err)
+		}
+	case <-time.After(time.Millisecond * 500):
+		t.Fatal("cancelled run did not return")
+	}
+}
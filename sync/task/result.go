@@ -0,0 +1,174 @@
+package task
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Status of a finished run
+type Status string
+
+const (
+	StatusDone      Status = "done"
+	StatusError     Status = "error"
+	StatusCancelled Status = "cancelled"
+	StatusTimeout   Status = "timeout"
+)
+
+// Result of a single run of a command
+type Result struct {
+	// Id of the execution the run belongs to
+	Id string
+	// Command name
+	Command string
+	// Worker that ran the command
+	Worker string
+	Status Status
+	// Started is when the run started
+	Started  time.Time
+	Duration time.Duration
+	Error    error
+}
+
+// NewResult returns the result of a run which started at the given time and returned err
+func NewResult(id, command, worker string, started time.Time, err error) Result {
+	return Result{
+		Id:       id,
+		Command:  command,
+		Worker:   worker,
+		Status:   StatusOf(err),
+		Started:  started,
+		Duration: time.Since(started),
+		Error:    err,
+	}
+}
+
+// StatusOf returns the status of a run that returned err
+func StatusOf(err error) Status {
+	switch {
+	case err == nil:
+		return StatusDone
+	case errors.Is(err, context.DeadlineExceeded):
+		return StatusTimeout
+	case errors.Is(err, context.Canceled):
+		return StatusCancelled
+	default:
+		return StatusError
+	}
+}
+
+func (r Result) String() string {
+	if r.Error != nil {
+		return fmt.Sprintf("command [%s] status: %s after %v: %v", r.Command, r.Status, r.Duration, r.Error)
+	}
+	return fmt.Sprintf("command [%s] status: %s after %v", r.Command, r.Status, r.Duration)
+}
+
+// RunHandle is a Handle for a fixed number of runs, it is used by Task
+// implementations which Report the result of every run
+type RunHandle struct {
+	id      string
+	cancel  context.CancelFunc
+	results chan Result
+
+	sync.Mutex
+	runs int
+	errs []error
+	done chan bool
+}
+
+// NewRunHandle returns a handle expecting the given number of run results,
+// cancel is called by Cancel and once all runs reported
+func NewRunHandle(id string, runs int, cancel context.CancelFunc) *RunHandle {
+	h := &RunHandle{
+		id:      id,
+		cancel:  cancel,
+		results: make(chan Result, runs),
+		runs:    runs,
+		done:    make(chan bool),
+	}
+	if runs == 0 {
+		h.finish()
+	}
+	return h
+}
+
+// Report records the result of a run, it returns false if the handle does not expect any more results
+func (h *RunHandle) Report(r Result) bool {
+	h.Lock()
+	defer h.Unlock()
+
+	if h.runs == 0 {
+		return false
+	}
+
+	if r.Error != nil {
+		h.errs = append(h.errs, r.Error)
+	}
+	h.results <- r
+
+	h.runs--
+	if h.runs == 0 {
+		h.finish()
+	}
+
+	return true
+}
+
+// Abort reports the runs still in flight as failed with err
+func (h *RunHandle) Abort(command string, started time.Time, err error) {
+	for h.Report(NewResult(h.id, command, "", started, err)) {
+	}
+}
+
+// finish is called with the lock held
+func (h *RunHandle) finish() {
+	close(h.results)
+	close(h.done)
+	if h.cancel != nil {
+		h.cancel()
+	}
+}
+
+func (h *RunHandle) Id() string {
+	return h.id
+}
+
+func (h *RunHandle) Results() <-chan Result {
+	return h.results
+}
+
+// Done is closed once all runs reported
+func (h *RunHandle) Done() <-chan bool {
+	return h.done
+}
+
+func (h *RunHandle) Wait() error {
+	<-h.done
+
+	h.Lock()
+	defer h.Unlock()
+
+	switch len(h.errs) {
+	case 0:
+		return nil
+	case 1:
+		return h.errs[0]
+	}
+
+	msgs := make([]string, 0, len(h.errs))
+	for _, err := range h.errs {
+		msgs = append(msgs, err.Error())
+	}
+	return errors.New("errors: " + strings.Join(msgs, "\n"))
+}
+
+func (h *RunHandle) Cancel() {
+	if h.cancel != nil {
+		h.cancel()
+	}
+}
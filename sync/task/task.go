@@ -4,6 +4,7 @@ package task
 import (
 	"context"
 	"fmt"
+	"math/rand"
 	"time"
 )
 
@@ -11,22 +12,64 @@ import (
 type Task interface {
 	// Run runs a command immediately until completion
 	Run(Command) error
+	// Start runs a command in the background until completion or the
+	// context is done, the handle reports the result of every run
+	Start(context.Context, Command) Handle
 	// Status provides status of last execution
 	Status() string
 }
 
+// Handle is a command started in the background
+type Handle interface {
+	// Id of the execution
+	Id() string
+	// Results receives the result of each run and is closed once all finished
+	Results() <-chan Result
+	// Wait blocks until all runs finished and returns their errors
+	Wait() error
+	// Cancel stops the runs still in flight
+	Cancel()
+}
+
 // Command to be executed
 type Command struct {
 	Name string
+	// Func is run when there is no Handler. It is not cancellable, a run
+	// which times out or is cancelled keeps going until Func returns
 	Func func() error
+	// Handler is run with a context done when the run is cancelled or times out
+	Handler func(context.Context) error
+	// Timeout is the deadline of each run, zero for none
+	Timeout time.Duration
 }
 
+// MissedPolicy decides what happens to runs missed while a task was not scheduled
+type MissedPolicy int
+
+const (
+	// MissedSkip drops missed runs and waits for the next one
+	MissedSkip MissedPolicy = iota
+	// MissedRunOnce runs once straight away if any runs were missed
+	MissedRunOnce
+	// MissedCatchUp runs every missed run straight away
+	MissedCatchUp
+)
+
 // Schedule represents a time or interval at which a task should run
 type Schedule struct {
 	// When to start the schedule. Zero time means immediately
 	Time time.Time
 	// Non zero interval dictates an ongoing schedule
 	Interval time.Duration
+	// Cron is a cron expression, see Parse. It replaces Interval when set
+	// and runs are not scheduled before Time.
+	Cron string
+	// Location the cron expression is evaluated in, local time by default
+	Location *time.Location
+	// Jitter delays every run by a random duration up to it
+	Jitter time.Duration
+	// Missed is what happens to runs missed while no one ran the schedule
+	Missed MissedPolicy
 }
 
 type Options struct {
@@ -39,7 +82,48 @@ type Options struct {
 type Option func(o *Options)
 
 func (c Command) Execute() error {
-	return c.Func()
+	return c.ExecuteContext(context.Background())
+}
+
+// ExecuteContext runs the command, returning the context error as soon as the
+// context is done even if the command does not honour it. A Func is not
+// cancellable and keeps running in the background, see Run
+func (c Command) ExecuteContext(ctx context.Context) error {
+	_, err := c.Run(ctx)
+	return err
+}
+
+// Run is ExecuteContext which also returns a channel closed once the command
+// returned, a runner bounding the commands in flight waits for it before it
+// runs another command
+func (c Command) Run(ctx context.Context) (<-chan struct{}, error) {
+	done := make(chan struct{})
+
+	var cancel context.CancelFunc
+	if c.Timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, c.Timeout)
+	}
+
+	ch := make(chan error, 1)
+	go func() {
+		defer close(done)
+		if cancel != nil {
+			defer cancel()
+		}
+
+		if c.Handler != nil {
+			ch <- c.Handler(ctx)
+			return
+		}
+		ch <- c.Func()
+	}()
+
+	select {
+	case err := <-ch:
+		return done, err
+	case <-ctx.Done():
+		return done, ctx.Err()
+	}
 }
 
 func (c Command) String() string {
@@ -47,13 +131,36 @@ func (c Command) String() string {
 }
 
 func (s Schedule) Run() <-chan time.Time {
-	d := s.Time.Sub(time.Now())
-
 	ch := make(chan time.Time, 1)
 
+	if len(s.Cron) > 0 {
+		e, err := s.expression()
+		if err != nil {
+			close(ch)
+			return ch
+		}
+
+		go func() {
+			defer close(ch)
+
+			t := time.Now()
+			for {
+				if t = s.next(e, t); t.IsZero() {
+					return
+				}
+				time.Sleep(time.Until(t) + s.RandomJitter())
+				ch <- t
+			}
+		}()
+
+		return ch
+	}
+
+	d := s.Time.Sub(time.Now())
+
 	go func() {
 		// wait for start time
-		<-time.After(d)
+		<-time.After(d + s.RandomJitter())
 
 		// zero interval
 		if s.Interval == time.Duration(0) {
@@ -66,6 +173,7 @@ func (s Schedule) Run() <-chan time.Time {
 		ticker := time.NewTicker(s.Interval)
 		defer ticker.Stop()
 		for t := range ticker.C {
+			time.Sleep(s.RandomJitter())
 			ch <- t
 		}
 	}()
@@ -73,7 +181,72 @@ func (s Schedule) Run() <-chan time.Time {
 	return ch
 }
 
+// Next returns the first run of the schedule after t, or the zero time if
+// there are no more runs. A schedule without a start time or cron
+// expression has its runs counted from t.
+func (s Schedule) Next(t time.Time) time.Time {
+	if len(s.Cron) > 0 {
+		e, err := s.expression()
+		if err != nil {
+			return time.Time{}
+		}
+		return s.next(e, t)
+	}
+
+	if t.Before(s.Time) {
+		return s.Time
+	}
+	if s.Interval <= 0 {
+		return time.Time{}
+	}
+	if s.Time.IsZero() {
+		return t.Add(s.Interval)
+	}
+
+	n := t.Sub(s.Time)/s.Interval + 1
+	return s.Time.Add(n * s.Interval)
+}
+
+func (s Schedule) next(e *Expression, t time.Time) time.Time {
+	if t.Before(s.Time) {
+		// the start time itself is a run if it matches
+		t = s.Time.Add(-time.Nanosecond)
+	}
+	return e.Next(t)
+}
+
+func (s Schedule) expression() (*Expression, error) {
+	e, err := Parse(s.Cron)
+	if err != nil {
+		return nil, err
+	}
+	if e.location == nil && s.Location != nil {
+		e.location = s.Location
+	}
+	return e, nil
+}
+
+// Validate checks the cron expression of the schedule
+func (s Schedule) Validate() error {
+	if len(s.Cron) == 0 {
+		return nil
+	}
+	_, err := s.expression()
+	return err
+}
+
+// RandomJitter returns a random delay up to the schedule jitter
+func (s Schedule) RandomJitter() time.Duration {
+	if s.Jitter <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(s.Jitter)))
+}
+
 func (s Schedule) String() string {
+	if len(s.Cron) > 0 {
+		return s.Cron
+	}
 	return fmt.Sprintf("%d-%d", s.Time.Unix(), s.Interval)
 }
 
@@ -103,6 +103,10 @@ func (t *grpcTransport) Dial(addr string, opts ...transport.DialOption) (transpo
 				InsecureSkipVerify: true,
 			}
 		}
+		if len(dopts.ServerName) > 0 && len(config.ServerName) == 0 {
+			config = config.Clone()
+			config.ServerName = dopts.ServerName
+		}
 		creds := credentials.NewTLS(config)
 		options = append(options, grpc.WithTransportCredentials(creds))
 	} else {
@@ -1,9 +1,13 @@
 package grpc
 
 import (
-	"google.golang.org/grpc"
+	"crypto/tls"
+
 	"github.com/stack-labs/stack/transport"
 	pb "github.com/stack-labs/stack/transport/grpc/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
 )
 
 type grpcTransportClient struct {
@@ -66,6 +70,18 @@ func (g *grpcTransportSocket) Remote() string {
 	return g.remote
 }
 
+func (g *grpcTransportSocket) ConnectionState() *tls.ConnectionState {
+	p, ok := peer.FromContext(g.stream.Context())
+	if !ok {
+		return nil
+	}
+	info, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok {
+		return nil
+	}
+	return &info.State
+}
+
 func (g *grpcTransportSocket) Recv(m *transport.Message) error {
 	if m == nil {
 		return nil
@@ -189,6 +189,10 @@ func (h *httpTransportSocket) Remote() string {
 	return h.remote
 }
 
+func (h *httpTransportSocket) ConnectionState() *tls.ConnectionState {
+	return h.r.TLS
+}
+
 func (h *httpTransportSocket) Recv(m *transport.Message) error {
 	if m == nil {
 		return errors.New("message passed in is nil")
@@ -498,6 +502,10 @@ func (h *httpTransport) Dial(addr string, opts ...transport.DialOption) (transpo
 				InsecureSkipVerify: true,
 			}
 		}
+		if len(dopts.ServerName) > 0 && len(config.ServerName) == 0 {
+			config = config.Clone()
+			config.ServerName = dopts.ServerName
+		}
 		config.NextProtos = []string{"http/1.1"}
 		conn, err = newConn(func(addr string) (net.Conn, error) {
 			return tls.DialWithDialer(&net.Dialer{Timeout: dopts.Timeout}, "tcp", addr, config)
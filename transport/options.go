@@ -35,9 +35,9 @@ type DialOptions struct {
 	Stream bool
 	// Timeout for dialing
 	Timeout time.Duration
-
-	// TODO: add tls options when dialling
-	// Currently set in global options
+	// ServerName the certificate of the server is verified against
+	// when the tls config doesn't set one, the service dialled
+	ServerName string
 
 	// Other options for implementations of the interface
 	// can be stored in a context
@@ -109,3 +109,10 @@ func WithTimeout(d time.Duration) DialOption {
 		o.Timeout = d
 	}
 }
+
+// WithServerName sets the name the server certificate is verified against
+func WithServerName(name string) DialOption {
+	return func(o *DialOptions) {
+		o.ServerName = name
+	}
+}
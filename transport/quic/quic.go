@@ -63,6 +63,11 @@ func (q *quicSocket) Remote() string {
 	return q.s.RemoteAddr().String()
 }
 
+func (q *quicSocket) ConnectionState() *tls.ConnectionState {
+	cs := q.s.ConnectionState().ConnectionState
+	return &cs
+}
+
 func (q *quicListener) Addr() string {
 	return q.l.Addr().String()
 }
@@ -117,6 +122,14 @@ func (q *quicTransport) Dial(addr string, opts ...transport.DialOption) (transpo
 			InsecureSkipVerify: true,
 			NextProtos:         []string{"http/1.1"},
 		}
+	} else if len(config.NextProtos) == 0 {
+		// quic requires a protocol to be negotiated
+		config = config.Clone()
+		config.NextProtos = []string{"http/1.1"}
+	}
+	if len(options.ServerName) > 0 && len(config.ServerName) == 0 {
+		config = config.Clone()
+		config.ServerName = options.ServerName
 	}
 	s, err := quic.DialAddr(addr, config, &quic.Config{
 		// TODO check
@@ -163,6 +176,9 @@ func (q *quicTransport) Listen(addr string, opts ...transport.ListenOption) (tra
 			Certificates: []tls.Certificate{cfg},
 			NextProtos:   []string{"http/1.1"},
 		}
+	} else if len(config.NextProtos) == 0 {
+		config = config.Clone()
+		config.NextProtos = []string{"http/1.1"}
 	}
 
 	l, err := quic.ListenAddr(addr, config, &quic.Config{KeepAlive: true})
@@ -0,0 +1,30 @@
+package tcp
+
+import (
+	"context"
+
+	"github.com/stack-labs/stack/transport"
+)
+
+type networkKey struct{}
+type maxMessageSizeKey struct{}
+
+// Network sets the network of the transport, tcp or unix
+func Network(n string) transport.Option {
+	return func(o *transport.Options) {
+		if o.Context == nil {
+			o.Context = context.Background()
+		}
+		o.Context = context.WithValue(o.Context, networkKey{}, n)
+	}
+}
+
+// MaxMessageSize sets the largest message sent or received in bytes
+func MaxMessageSize(s int) transport.Option {
+	return func(o *transport.Options) {
+		if o.Context == nil {
+			o.Context = context.Background()
+		}
+		o.Context = context.WithValue(o.Context, maxMessageSizeKey{}, s)
+	}
+}
@@ -0,0 +1,395 @@
+// Package tcp provides a raw TCP transport, messages are sent as length
+// prefixed frames without the overhead of http
+package tcp
+
+import (
+	"bufio"
+	"crypto/tls"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stack-labs/stack/transport"
+	maddr "github.com/stack-labs/stack/util/addr"
+	mnet "github.com/stack-labs/stack/util/net"
+	mls "github.com/stack-labs/stack/util/tls"
+)
+
+var (
+	// DefaultMaxMessageSize is the largest message sent or received in bytes
+	DefaultMaxMessageSize = 1024 * 1024 * 32
+
+	// ErrMessageTooLarge is returned for the messages above the max message size
+	ErrMessageTooLarge = errors.New("tcp: message too large")
+)
+
+type tcpTransport struct {
+	opts transport.Options
+}
+
+type tcpSocket struct {
+	conn    net.Conn
+	timeout time.Duration
+	maxSize int
+
+	// serialise the reads and writes of the frames
+	rmtx sync.Mutex
+	r    *bufio.Reader
+	wmtx sync.Mutex
+	w    *bufio.Writer
+
+	local  string
+	remote string
+}
+
+type tcpListener struct {
+	t        *tcpTransport
+	listener net.Listener
+}
+
+// A frame is the length of the message followed by the number of headers,
+// the length prefixed key and value of each header and then the body. The
+// lengths in the message are uvarints
+func (t *tcpSocket) Recv(m *transport.Message) error {
+	if m == nil {
+		return errors.New("message passed in is nil")
+	}
+
+	t.rmtx.Lock()
+	defer t.rmtx.Unlock()
+
+	if t.timeout > time.Duration(0) {
+		t.conn.SetReadDeadline(time.Now().Add(t.timeout))
+	}
+
+	var size [4]byte
+	if _, err := io.ReadFull(t.r, size[:]); err != nil {
+		return err
+	}
+
+	n := binary.BigEndian.Uint32(size[:])
+	if int64(n) > int64(t.maxSize) {
+		return ErrMessageTooLarge
+	}
+
+	b := make([]byte, n)
+	if _, err := io.ReadFull(t.r, b); err != nil {
+		return err
+	}
+
+	return decode(b, m)
+}
+
+func (t *tcpSocket) Send(m *transport.Message) error {
+	b := encode(m)
+	if len(b) > t.maxSize {
+		return ErrMessageTooLarge
+	}
+
+	t.wmtx.Lock()
+	defer t.wmtx.Unlock()
+
+	if t.timeout > time.Duration(0) {
+		t.conn.SetWriteDeadline(time.Now().Add(t.timeout))
+	}
+
+	var size [4]byte
+	binary.BigEndian.PutUint32(size[:], uint32(len(b)))
+
+	if _, err := t.w.Write(size[:]); err != nil {
+		return err
+	}
+	if _, err := t.w.Write(b); err != nil {
+		return err
+	}
+	return t.w.Flush()
+}
+
+func (t *tcpSocket) Close() error {
+	return t.conn.Close()
+}
+
+func (t *tcpSocket) Local() string {
+	return t.local
+}
+
+func (t *tcpSocket) Remote() string {
+	return t.remote
+}
+
+func (t *tcpSocket) ConnectionState() *tls.ConnectionState {
+	c, ok := t.conn.(*tls.Conn)
+	if !ok {
+		return nil
+	}
+	cs := c.ConnectionState()
+	return &cs
+}
+
+func encode(m *transport.Message) []byte {
+	size := binary.MaxVarintLen64 * (1 + 2*len(m.Header))
+	for k, v := range m.Header {
+		size += len(k) + len(v)
+	}
+	size += len(m.Body)
+
+	b := make([]byte, size)
+	i := binary.PutUvarint(b, uint64(len(m.Header)))
+	for k, v := range m.Header {
+		i += binary.PutUvarint(b[i:], uint64(len(k)))
+		i += copy(b[i:], k)
+		i += binary.PutUvarint(b[i:], uint64(len(v)))
+		i += copy(b[i:], v)
+	}
+	i += copy(b[i:], m.Body)
+
+	return b[:i]
+}
+
+var errInvalidFrame = errors.New("tcp: invalid frame")
+
+func decode(b []byte, m *transport.Message) error {
+	// read returns the next length prefixed string
+	read := func() (string, error) {
+		n, i := binary.Uvarint(b)
+		if i <= 0 || n > uint64(len(b)-i) {
+			return "", errInvalidFrame
+		}
+		s := string(b[i : i+int(n)])
+		b = b[i+int(n):]
+		return s, nil
+	}
+
+	count, i := binary.Uvarint(b)
+	if i <= 0 || count > uint64(len(b)) {
+		return errInvalidFrame
+	}
+	b = b[i:]
+
+	m.Header = make(map[string]string, count)
+	for j := uint64(0); j < count; j++ {
+		k, err := read()
+		if err != nil {
+			return err
+		}
+		v, err := read()
+		if err != nil {
+			return err
+		}
+		m.Header[k] = v
+	}
+
+	m.Body = b
+	return nil
+}
+
+func (t *tcpListener) Addr() string {
+	return t.listener.Addr().String()
+}
+
+func (t *tcpListener) Close() error {
+	return t.listener.Close()
+}
+
+func (t *tcpListener) Accept(fn func(transport.Socket)) error {
+	var tempDelay time.Duration
+
+	for {
+		c, err := t.listener.Accept()
+		if err != nil {
+			if ne, ok := err.(net.Error); ok && ne.Temporary() {
+				if tempDelay == 0 {
+					tempDelay = 5 * time.Millisecond
+				} else {
+					tempDelay *= 2
+				}
+				if max := 1 * time.Second; tempDelay > max {
+					tempDelay = max
+				}
+				time.Sleep(tempDelay)
+				continue
+			}
+			return err
+		}
+		tempDelay = 0
+
+		go fn(t.t.newSocket(c))
+	}
+}
+
+func (t *tcpTransport) network() string {
+	if t.opts.Context != nil {
+		if n, ok := t.opts.Context.Value(networkKey{}).(string); ok && len(n) > 0 {
+			return n
+		}
+	}
+	return "tcp"
+}
+
+func (t *tcpTransport) newSocket(c net.Conn) *tcpSocket {
+	maxSize := DefaultMaxMessageSize
+	if t.opts.Context != nil {
+		if s, ok := t.opts.Context.Value(maxMessageSizeKey{}).(int); ok && s > 0 {
+			maxSize = s
+		}
+	}
+
+	return &tcpSocket{
+		conn:    c,
+		timeout: t.opts.Timeout,
+		maxSize: maxSize,
+		r:       bufio.NewReader(c),
+		w:       bufio.NewWriter(c),
+		local:   c.LocalAddr().String(),
+		remote:  c.RemoteAddr().String(),
+	}
+}
+
+func (t *tcpTransport) Init(opts ...transport.Option) error {
+	for _, o := range opts {
+		o(&t.opts)
+	}
+	return nil
+}
+
+func (t *tcpTransport) Options() transport.Options {
+	return t.opts
+}
+
+func (t *tcpTransport) Dial(addr string, opts ...transport.DialOption) (transport.Client, error) {
+	dopts := transport.DialOptions{
+		Timeout: transport.DefaultDialTimeout,
+	}
+
+	for _, opt := range opts {
+		opt(&dopts)
+	}
+
+	var conn net.Conn
+	var err error
+
+	dialer := &net.Dialer{Timeout: dopts.Timeout}
+
+	if t.opts.Secure || t.opts.TLSConfig != nil {
+		config := t.opts.TLSConfig
+		if config == nil {
+			config = &tls.Config{
+				InsecureSkipVerify: true,
+			}
+		}
+		if len(dopts.ServerName) > 0 && len(config.ServerName) == 0 {
+			config = config.Clone()
+			config.ServerName = dopts.ServerName
+		}
+		conn, err = tls.DialWithDialer(dialer, t.network(), addr, config)
+	} else {
+		conn, err = dialer.Dial(t.network(), addr)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return t.newSocket(conn), nil
+}
+
+// listenAddr returns the path of a unix socket, a temporary one is used for
+// host:port addresses such as the default server address
+func listenAddr(addr string) string {
+	if _, _, err := net.SplitHostPort(addr); err != nil && len(addr) > 0 {
+		return addr
+	}
+	return filepath.Join(os.TempDir(), fmt.Sprintf("stack-%s.sock", uuid.New().String()))
+}
+
+// removeStale removes a unix socket no process is listening on
+func removeStale(path string) {
+	if _, err := os.Stat(path); err != nil {
+		return
+	}
+	if c, err := net.DialTimeout("unix", path, time.Second); err == nil {
+		c.Close()
+		return
+	}
+	os.Remove(path)
+}
+
+func (t *tcpTransport) Listen(addr string, opts ...transport.ListenOption) (transport.Listener, error) {
+	var options transport.ListenOptions
+	for _, o := range opts {
+		o(&options)
+	}
+
+	network := t.network()
+
+	fn := func(addr string) (net.Listener, error) {
+		return net.Listen(network, addr)
+	}
+
+	if t.opts.Secure || t.opts.TLSConfig != nil {
+		config := t.opts.TLSConfig
+
+		fn = func(addr string) (net.Listener, error) {
+			if config == nil {
+				hosts := []string{addr}
+
+				// check if its a valid host:port
+				if host, _, err := net.SplitHostPort(addr); err == nil {
+					if len(host) == 0 {
+						hosts = maddr.IPs()
+					} else {
+						hosts = []string{host}
+					}
+				}
+
+				// generate a certificate
+				cert, err := mls.Certificate(hosts...)
+				if err != nil {
+					return nil, err
+				}
+				config = &tls.Config{Certificates: []tls.Certificate{cert}}
+			}
+			return tls.Listen(network, addr, config)
+		}
+	}
+
+	var l net.Listener
+	var err error
+
+	if network == "unix" {
+		addr = listenAddr(addr)
+		removeStale(addr)
+		l, err = fn(addr)
+	} else {
+		l, err = mnet.Listen(addr, fn)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &tcpListener{
+		t:        t,
+		listener: l,
+	}, nil
+}
+
+func (t *tcpTransport) String() string {
+	return t.network()
+}
+
+// NewTransport returns a raw TCP transport
+func NewTransport(opts ...transport.Option) transport.Transport {
+	var options transport.Options
+	for _, o := range opts {
+		o(&options)
+	}
+	return &tcpTransport{opts: options}
+}
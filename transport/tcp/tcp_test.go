@@ -0,0 +1,280 @@
+package tcp
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stack-labs/stack/client/pool"
+	"github.com/stack-labs/stack/transport"
+	mls "github.com/stack-labs/stack/util/tls"
+	"github.com/stack-labs/stack/util/tls/ca"
+)
+
+func echo(t *testing.T, l transport.Listener) {
+	go func() {
+		l.Accept(func(sock transport.Socket) {
+			defer sock.Close()
+			for {
+				var m transport.Message
+				if err := sock.Recv(&m); err != nil {
+					return
+				}
+				if err := sock.Send(&m); err != nil {
+					return
+				}
+			}
+		})
+	}()
+}
+
+func call(t *testing.T, c transport.Client, i int) {
+	body := []byte(fmt.Sprintf("ping %d", i))
+	if err := c.Send(&transport.Message{
+		Header: map[string]string{"Content-Type": "application/json", "X-Id": fmt.Sprint(i), "Empty": ""},
+		Body:   body,
+	}); err != nil {
+		t.Fatalf("Unexpected send err: %v", err)
+	}
+
+	var m transport.Message
+	if err := c.Recv(&m); err != nil {
+		t.Fatalf("Unexpected recv err: %v", err)
+	}
+	if string(m.Body) != string(body) {
+		t.Fatalf("Expected body %s got %s", body, m.Body)
+	}
+	if len(m.Header) != 3 || m.Header["X-Id"] != fmt.Sprint(i) || m.Header["Content-Type"] != "application/json" {
+		t.Fatalf("Unexpected headers %v", m.Header)
+	}
+}
+
+func testTransport(t *testing.T, tr transport.Transport, addr string) {
+	l, err := tr.Listen(addr)
+	if err != nil {
+		t.Fatalf("Unexpected listen err: %v", err)
+	}
+	defer l.Close()
+
+	echo(t, l)
+
+	c, err := tr.Dial(l.Addr())
+	if err != nil {
+		t.Fatalf("Unexpected dial err: %v", err)
+	}
+	defer c.Close()
+
+	for i := 0; i < 3; i++ {
+		call(t, c, i)
+	}
+}
+
+func TestTCPTransport(t *testing.T) {
+	tr := NewTransport()
+	if tr.String() != "tcp" {
+		t.Fatalf("Expected tcp got %s", tr.String())
+	}
+	testTransport(t, tr, "127.0.0.1:0")
+}
+
+func TestTCPTransportSecure(t *testing.T) {
+	tr := NewTransport(transport.Secure(true))
+
+	l, err := tr.Listen("127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Unexpected listen err: %v", err)
+	}
+	defer l.Close()
+
+	echo(t, l)
+
+	c, err := tr.Dial(l.Addr())
+	if err != nil {
+		t.Fatalf("Unexpected dial err: %v", err)
+	}
+	defer c.Close()
+
+	call(t, c, 0)
+
+	s, ok := c.(transport.SecureSocket)
+	if !ok || s.ConnectionState() == nil {
+		t.Fatal("Expected a secure socket")
+	}
+}
+
+func TestTCPTransportServerName(t *testing.T) {
+	dir, err := ioutil.TempDir("", "tcp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	a, err := ca.NewAuthority(ca.KeyPair(filepath.Join(dir, "ca.pem"), filepath.Join(dir, "ca-key.pem")))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	csr, key, err := ca.NewRequest("stack.rpc.greeter")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, _, err := a.Sign("stack.rpc.greeter", csr, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for file, data := range map[string][]byte{"cert.pem": cert, "key.pem": key} {
+		if err := ca.WriteFile(filepath.Join(dir, file), data); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	creds, err := mls.NewCredentials(
+		mls.CA(filepath.Join(dir, "ca.pem")),
+		mls.KeyPair(filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")),
+		mls.ReloadInterval(-1),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer creds.Stop()
+
+	tr := NewTransport(transport.TLSConfig(creds.Config()))
+
+	l, err := tr.Listen("127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Unexpected listen err: %v", err)
+	}
+	defer l.Close()
+
+	echo(t, l)
+
+	// the certificate carries the service name rather than the address
+	if c, err := tr.Dial(l.Addr(), transport.WithServerName("stack.rpc.other")); err == nil {
+		c.Close()
+		t.Fatal("Expected the server to be rejected for another service")
+	}
+
+	c, err := tr.Dial(l.Addr(), transport.WithServerName("stack.rpc.greeter"))
+	if err != nil {
+		t.Fatalf("Unexpected dial err: %v", err)
+	}
+	defer c.Close()
+
+	call(t, c, 0)
+}
+
+func TestUnixTransport(t *testing.T) {
+	dir, err := ioutil.TempDir("", "tcp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	tr := NewTransport(Network("unix"))
+	if tr.String() != "unix" {
+		t.Fatalf("Expected unix got %s", tr.String())
+	}
+
+	path := filepath.Join(dir, "stack.sock")
+
+	// a stale socket is replaced
+	if err := ioutil.WriteFile(path, nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	testTransport(t, tr, path)
+
+	// a temporary socket is used for host:port
+	l, err := tr.Listen(":0")
+	if err != nil {
+		t.Fatalf("Unexpected listen err: %v", err)
+	}
+	defer l.Close()
+
+	if !strings.HasSuffix(l.Addr(), ".sock") {
+		t.Fatalf("Expected a socket path got %s", l.Addr())
+	}
+}
+
+func TestTransportPool(t *testing.T) {
+	tr := NewTransport()
+
+	l, err := tr.Listen("127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Unexpected listen err: %v", err)
+	}
+	defer l.Close()
+
+	echo(t, l)
+
+	p := pool.NewPool(pool.Size(1), pool.TTL(time.Minute), pool.Transport(tr))
+	defer p.Close()
+
+	var id string
+
+	for i := 0; i < 3; i++ {
+		c, err := p.Get(l.Addr())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		call(t, c, i)
+
+		if len(id) > 0 && c.Id() != id {
+			t.Fatal("Expected the connection to be reused")
+		}
+		id = c.Id()
+
+		if err := p.Release(c, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestMaxMessageSize(t *testing.T) {
+	tr := NewTransport(MaxMessageSize(16))
+
+	l, err := tr.Listen("127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Unexpected listen err: %v", err)
+	}
+	defer l.Close()
+
+	echo(t, l)
+
+	c, err := tr.Dial(l.Addr())
+	if err != nil {
+		t.Fatalf("Unexpected dial err: %v", err)
+	}
+	defer c.Close()
+
+	if err := c.Send(&transport.Message{Body: make([]byte, 32)}); err != ErrMessageTooLarge {
+		t.Fatalf("Expected the message to be too large got %v", err)
+	}
+}
+
+func TestDecode(t *testing.T) {
+	m := &transport.Message{
+		Header: map[string]string{"a": "b"},
+		Body:   []byte("body"),
+	}
+
+	b := encode(m)
+
+	var d transport.Message
+	if err := decode(b, &d); err != nil {
+		t.Fatal(err)
+	}
+	if d.Header["a"] != "b" || string(d.Body) != "body" {
+		t.Fatalf("Unexpected message %v", d)
+	}
+
+	// truncated header
+	if err := decode(b[:3], &d); err == nil {
+		t.Fatal("Expected an invalid frame")
+	}
+}
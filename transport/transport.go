@@ -2,12 +2,13 @@
 package transport
 
 import (
+	"crypto/tls"
 	"time"
 )
 
 // Transport is an interface which is used for communication between
 // services. It uses connection based socket send/recv semantics and
-// has various implementations; http, grpc, quic.
+// has various implementations; http, grpc, quic, tcp, unix.
 type Transport interface {
 	Init(...Option) error
 	Options() Options
@@ -29,6 +30,13 @@ type Socket interface {
 	Remote() string
 }
 
+// SecureSocket is implemented by the sockets of TLS connections
+type SecureSocket interface {
+	Socket
+	// ConnectionState of the TLS connection, nil when the connection is insecure
+	ConnectionState() *tls.ConnectionState
+}
+
 type Client interface {
 	Socket
 }
@@ -0,0 +1,15 @@
+// Package unix provides a unix domain socket transport for sidecars and
+// services on the same host, the messages are framed like the tcp transport.
+// The address is the path of the socket, a temporary one is used for host:port
+// addresses such as the default server address
+package unix
+
+import (
+	"github.com/stack-labs/stack/transport"
+	"github.com/stack-labs/stack/transport/tcp"
+)
+
+// NewTransport returns a unix domain socket transport
+func NewTransport(opts ...transport.Option) transport.Transport {
+	return tcp.NewTransport(append([]transport.Option{tcp.Network("unix")}, opts...)...)
+}
@@ -0,0 +1,6 @@
+package file
+
+import "errors"
+
+// ErrLocked is returned by TryLock when another process holds the lock
+var ErrLocked = errors.New("file is locked by another process")
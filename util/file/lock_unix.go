@@ -0,0 +1,38 @@
+//go:build !windows
+// +build !windows
+
+package file
+
+import (
+	"os"
+	"syscall"
+)
+
+// Lock takes an exclusive lock on f, waiting for other processes to release it
+func Lock(f *os.File) error {
+	return flock(f, syscall.LOCK_EX)
+}
+
+// TryLock takes an exclusive lock on f or returns ErrLocked if another
+// process holds it
+func TryLock(f *os.File) error {
+	err := flock(f, syscall.LOCK_EX|syscall.LOCK_NB)
+	if err == syscall.EWOULDBLOCK {
+		return ErrLocked
+	}
+	return err
+}
+
+// Unlock releases the lock on f
+func Unlock(f *os.File) error {
+	return flock(f, syscall.LOCK_UN)
+}
+
+func flock(f *os.File, how int) error {
+	for {
+		err := syscall.Flock(int(f.Fd()), how)
+		if err != syscall.EINTR {
+			return err
+		}
+	}
+}
@@ -0,0 +1,21 @@
+//go:build windows
+// +build windows
+
+package file
+
+import "os"
+
+// Lock is a no-op on windows, files must only be written by one process
+func Lock(f *os.File) error {
+	return nil
+}
+
+// TryLock is a no-op on windows
+func TryLock(f *os.File) error {
+	return nil
+}
+
+// Unlock is a no-op on windows
+func Unlock(f *os.File) error {
+	return nil
+}
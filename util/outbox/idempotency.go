@@ -0,0 +1,127 @@
+package outbox
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/stack-labs/stack/pkg/metadata"
+	"github.com/stack-labs/stack/server"
+	"github.com/stack-labs/stack/store"
+	"github.com/stack-labs/stack/store/memory"
+	"github.com/stack-labs/stack/util/log"
+)
+
+var (
+	// ErrProcessing is returned for a message another subscriber is processing, so
+	// the broker redelivers it in case that subscriber fails
+	ErrProcessing = errors.New("message is being processed")
+)
+
+// claimed is the value of the record of a message id being processed
+var claimed = []byte("claimed")
+
+// NewSubscriberWrapper skips messages whose id has already been processed. The id is
+// taken from IdHeader, falling back to the Stack-Id set by the client on publish so
+// broker redeliveries of any message are caught too. With a store.Versioned store the
+// id is claimed with compare and swap before the subscriber runs, so concurrent
+// deliveries run it once. A versioned store returning store.ErrNotSupported, such as
+// a service store whose backend has no compare and swap, is only read like any other
+// store. Ids are kept for the processed TTL once the subscriber returns without error
+// and released when it fails.
+func NewSubscriberWrapper(opts ...Option) server.SubscriberWrapper {
+	options := newOptions(opts...)
+	if options.Store == nil {
+		options.Store = memory.NewStore()
+	}
+	vs, versioned := options.Store.(store.Versioned)
+	// set once the store reports it has no compare and swap
+	var unsupported int32
+
+	return func(fn server.SubscriberFunc) server.SubscriberFunc {
+		return func(ctx context.Context, msg server.Message) error {
+			id := messageId(ctx, msg)
+			if len(id) == 0 {
+				return fn(ctx, msg)
+			}
+
+			key := options.ProcessedPrefix + msg.Topic() + "/" + id
+
+			claim := versioned && atomic.LoadInt32(&unsupported) == 0
+			if claim {
+				// a zero version only swaps in a record that does not exist
+				err := vs.CompareAndSwap(&store.Record{
+					Key:    key,
+					Value:  claimed,
+					Expiry: options.ClaimTTL,
+				})
+				if err == store.ErrNotSupported {
+					atomic.StoreInt32(&unsupported, 1)
+					claim = false
+				} else if store.IsConflict(err) {
+					return processed(vs, key)
+				} else if err != nil {
+					return err
+				}
+			}
+
+			if !claim {
+				if _, err := options.Store.Read(key); err == nil {
+					return nil
+				} else if err != store.ErrNotFound {
+					return err
+				}
+			}
+
+			if err := fn(ctx, msg); err != nil {
+				if claim {
+					if derr := options.Store.Delete(key); derr != nil {
+						log.Errorf("Error releasing message %s: %v", key, derr)
+					}
+				}
+				return err
+			}
+
+			return options.Store.Write(&store.Record{
+				Key:    key,
+				Value:  []byte(strconv.FormatInt(time.Now().Unix(), 10)),
+				Expiry: options.ProcessedTTL,
+			})
+		}
+	}
+}
+
+// processed returns nil if the message id has been processed and ErrProcessing
+// while it is claimed
+func processed(s store.Store, key string) error {
+	recs, err := s.Read(key)
+	if err == store.ErrNotFound {
+		// released since the claim was attempted
+		return ErrProcessing
+	} else if err != nil {
+		return err
+	}
+	if bytes.Equal(recs[0].Value, claimed) {
+		return ErrProcessing
+	}
+	return nil
+}
+
+func messageId(ctx context.Context, msg server.Message) string {
+	for _, k := range []string{IdHeader, "Stack-Id"} {
+		// servers differ in how they case header keys
+		for hk, v := range msg.Header() {
+			if strings.EqualFold(hk, k) && len(v) > 0 {
+				return v
+			}
+		}
+		if v, ok := metadata.Get(ctx, k); ok && len(v) > 0 {
+			return v
+		}
+	}
+	return ""
+}
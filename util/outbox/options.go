@@ -0,0 +1,117 @@
+package outbox
+
+import (
+	"time"
+
+	"github.com/stack-labs/stack/client"
+	"github.com/stack-labs/stack/store"
+)
+
+var (
+	// DefaultPrefix is the key prefix queued messages are stored under
+	DefaultPrefix = "outbox/"
+	// DefaultProcessedPrefix is the key prefix processed message ids are stored under
+	DefaultProcessedPrefix = "processed/"
+	// DefaultInterval is how often the relay drains the outbox
+	DefaultInterval = time.Second
+	// DefaultBatchSize is the most messages published in a single drain
+	DefaultBatchSize = 100
+	// DefaultProcessedTTL is how long processed message ids are remembered
+	DefaultProcessedTTL = 24 * time.Hour
+	// DefaultClaimTTL is how long a message id is claimed by a subscriber processing it
+	DefaultClaimTTL = time.Minute
+)
+
+type Options struct {
+	// Store holds the queued messages and processed ids, it must write all
+	// the records of a write or none for the outbox writes to be atomic
+	Store store.Store
+	// Client publishes the queued messages
+	Client client.Client
+	// Prefix of the keys of queued messages
+	Prefix string
+	// ProcessedPrefix of the keys of processed message ids
+	ProcessedPrefix string
+	// Interval between drains of the outbox
+	Interval time.Duration
+	// BatchSize is the most messages published in a single drain
+	BatchSize int
+	// ProcessedTTL is how long processed message ids are remembered
+	ProcessedTTL time.Duration
+	// ClaimTTL frees the claim of a subscriber which died processing a message
+	ClaimTTL time.Duration
+}
+
+type Option func(o *Options)
+
+// Store sets the store messages are recorded in
+func Store(s store.Store) Option {
+	return func(o *Options) {
+		o.Store = s
+	}
+}
+
+// Client sets the client the relay publishes with
+func Client(c client.Client) Option {
+	return func(o *Options) {
+		o.Client = c
+	}
+}
+
+// Prefix sets the key prefix queued messages are stored under
+func Prefix(p string) Option {
+	return func(o *Options) {
+		o.Prefix = p
+	}
+}
+
+// ProcessedPrefix sets the key prefix processed message ids are stored under
+func ProcessedPrefix(p string) Option {
+	return func(o *Options) {
+		o.ProcessedPrefix = p
+	}
+}
+
+// Interval sets how often the relay drains the outbox
+func Interval(d time.Duration) Option {
+	return func(o *Options) {
+		o.Interval = d
+	}
+}
+
+// BatchSize sets the most messages published in a single drain
+func BatchSize(n int) Option {
+	return func(o *Options) {
+		o.BatchSize = n
+	}
+}
+
+// ProcessedTTL sets how long processed message ids are remembered
+func ProcessedTTL(d time.Duration) Option {
+	return func(o *Options) {
+		o.ProcessedTTL = d
+	}
+}
+
+// ClaimTTL sets how long a message id is claimed by a subscriber processing it,
+// it should outlast the subscriber
+func ClaimTTL(d time.Duration) Option {
+	return func(o *Options) {
+		o.ClaimTTL = d
+	}
+}
+
+func newOptions(opts ...Option) Options {
+	options := Options{
+		Prefix:          DefaultPrefix,
+		ProcessedPrefix: DefaultProcessedPrefix,
+		Interval:        DefaultInterval,
+		BatchSize:       DefaultBatchSize,
+		ProcessedTTL:    DefaultProcessedTTL,
+		ClaimTTL:        DefaultClaimTTL,
+	}
+	for _, o := range opts {
+		o(&options)
+	}
+	return options
+}
@@ -0,0 +1,256 @@
+// Package outbox records outgoing messages in a store as part of the write they
+// belong to and relays them to the broker afterwards, so a message is never lost
+// when the process dies between writing to the store and publishing. Messages
+// carry a dedup id which the subscriber wrapper uses to skip redeliveries.
+//
+// The records and messages are written with a single Store.Write, the store must
+// write all the records of a call or none for the write to be atomic. The memory
+// and file stores do, the service store does when the store of the service does.
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stack-labs/stack/client"
+	"github.com/stack-labs/stack/codec"
+	raw "github.com/stack-labs/stack/codec/bytes"
+	"github.com/stack-labs/stack/pkg/metadata"
+	"github.com/stack-labs/stack/store"
+	"github.com/stack-labs/stack/store/memory"
+	"github.com/stack-labs/stack/util/buf"
+	codecu "github.com/stack-labs/stack/util/codec"
+	"github.com/stack-labs/stack/util/log"
+)
+
+const (
+	// IdHeader carries the dedup id of a message relayed from the outbox
+	IdHeader = "Stack-Message-Id"
+)
+
+// Outbox queues messages in a store and relays them to the broker
+type Outbox interface {
+	// Write stores the records and queues the messages in a single store write,
+	// it is atomic when the store writes all records of a write or none
+	Write(ctx context.Context, msgs []client.Message, recs ...*store.Record) error
+	// Publish queues a message on its own
+	Publish(ctx context.Context, msg client.Message) error
+	// Flush publishes the queued messages in the order they were written
+	Flush() error
+	// Start relaying queued messages in the background
+	Start() error
+	// Stop the relay
+	Stop() error
+}
+
+type outbox struct {
+	opts Options
+
+	// flush is held while draining so relays don't publish twice
+	flush sync.Mutex
+
+	sync.Mutex
+	running bool
+	exit    chan bool
+	done    chan bool
+}
+
+// entry is a queued message as stored in the outbox
+type entry struct {
+	Id          string            `json:"id"`
+	Topic       string            `json:"topic"`
+	ContentType string            `json:"content_type"`
+	Metadata    map[string]string `json:"metadata,omitempty"`
+	Body        []byte            `json:"body"`
+}
+
+func (o *outbox) Write(ctx context.Context, msgs []client.Message, recs ...*store.Record) error {
+	records := make([]*store.Record, 0, len(recs)+len(msgs))
+	records = append(records, recs...)
+
+	for _, msg := range msgs {
+		rec, err := o.newRecord(ctx, msg)
+		if err != nil {
+			return err
+		}
+		records = append(records, rec)
+	}
+
+	return o.opts.Store.Write(records...)
+}
+
+func (o *outbox) Publish(ctx context.Context, msg client.Message) error {
+	return o.Write(ctx, []client.Message{msg})
+}
+
+func (o *outbox) newRecord(ctx context.Context, msg client.Message) (*store.Record, error) {
+	body, err := encode(msg)
+	if err != nil {
+		return nil, err
+	}
+
+	md, _ := metadata.FromContext(ctx)
+
+	e := &entry{
+		Id:          uuid.New().String(),
+		Topic:       msg.Topic(),
+		ContentType: msg.ContentType(),
+		Metadata:    metadata.Copy(md),
+		Body:        body,
+	}
+
+	b, err := json.Marshal(e)
+	if err != nil {
+		return nil, err
+	}
+
+	// keys sort in the order messages were queued
+	return &store.Record{
+		Key:   fmt.Sprintf("%s%020d-%s", o.opts.Prefix, time.Now().UnixNano(), e.Id),
+		Value: b,
+	}, nil
+}
+
+// encode the payload as the client would when publishing it
+func encode(msg client.Message) ([]byte, error) {
+	if f, ok := msg.Payload().(*raw.Frame); ok {
+		return f.Data, nil
+	}
+
+	cf, ok := codecu.DefaultCodecs[msg.ContentType()]
+	if !ok {
+		return nil, fmt.Errorf("unsupported content type %s", msg.ContentType())
+	}
+
+	b := buf.New(nil)
+	if err := cf(b).Write(&codec.Message{
+		Target: msg.Topic(),
+		Type:   codec.Event,
+		Header: map[string]string{
+			"Stack-Topic": msg.Topic(),
+		},
+	}, msg.Payload()); err != nil {
+		return nil, err
+	}
+
+	return b.Bytes(), nil
+}
+
+func (o *outbox) Flush() error {
+	if o.opts.Client == nil {
+		return errors.New("outbox requires a client to publish with")
+	}
+
+	o.flush.Lock()
+	defer o.flush.Unlock()
+
+	opts := []store.ListOption{store.ListPrefix(o.opts.Prefix)}
+	if o.opts.BatchSize > 0 {
+		opts = append(opts, store.ListLimit(uint(o.opts.BatchSize)))
+	}
+
+	queued, err := o.opts.Store.List(opts...)
+	if err != nil {
+		return err
+	}
+	sort.Slice(queued, func(i, j int) bool {
+		return queued[i].Key < queued[j].Key
+	})
+
+	for _, rec := range queued {
+		var e entry
+		if err := json.Unmarshal(rec.Value, &e); err != nil {
+			log.Errorf("Dropping invalid outbox record %s: %v", rec.Key, err)
+			o.opts.Store.Delete(rec.Key)
+			continue
+		}
+
+		md := metadata.Copy(e.Metadata)
+		md[IdHeader] = e.Id
+		ctx := metadata.NewContext(context.Background(), md)
+
+		msg := o.opts.Client.NewMessage(e.Topic, &raw.Frame{Data: e.Body}, client.WithMessageContentType(e.ContentType))
+
+		// stop at the first failure to keep messages in order
+		if err := o.opts.Client.Publish(ctx, msg); err != nil {
+			return err
+		}
+
+		// a failed delete republishes the message with the same id
+		if err := o.opts.Store.Delete(rec.Key); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (o *outbox) Start() error {
+	if o.opts.Client == nil {
+		return errors.New("outbox requires a client to publish with")
+	}
+
+	o.Lock()
+	defer o.Unlock()
+
+	if o.running {
+		return nil
+	}
+	o.running = true
+	o.exit = make(chan bool)
+	o.done = make(chan bool)
+
+	go o.run(o.exit, o.done)
+
+	return nil
+}
+
+func (o *outbox) run(exit, done chan bool) {
+	defer close(done)
+
+	t := time.NewTicker(o.opts.Interval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-exit:
+			return
+		case <-t.C:
+			if err := o.Flush(); err != nil {
+				log.Errorf("Error relaying outbox: %v", err)
+			}
+		}
+	}
+}
+
+func (o *outbox) Stop() error {
+	o.Lock()
+	defer o.Unlock()
+
+	if !o.running {
+		return nil
+	}
+	o.running = false
+	close(o.exit)
+	<-o.done
+
+	return nil
+}
+
+// NewOutbox returns an outbox queueing messages in the store
+func NewOutbox(opts ...Option) Outbox {
+	options := newOptions(opts...)
+	if options.Store == nil {
+		options.Store = memory.NewStore()
+	}
+
+	return &outbox{
+		opts: options,
+	}
+}
@@ -0,0 +1,206 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stack-labs/stack/broker"
+	bmemory "github.com/stack-labs/stack/broker/memory"
+	"github.com/stack-labs/stack/client"
+	"github.com/stack-labs/stack/client/mucp"
+	"github.com/stack-labs/stack/codec"
+	"github.com/stack-labs/stack/server"
+	"github.com/stack-labs/stack/store"
+	"github.com/stack-labs/stack/store/memory"
+)
+
+type testMessage struct {
+	topic  string
+	header map[string]string
+}
+
+func (m *testMessage) Topic() string             { return m.topic }
+func (m *testMessage) Payload() interface{}      { return nil }
+func (m *testMessage) ContentType() string       { return "application/json" }
+func (m *testMessage) Header() map[string]string { return m.header }
+func (m *testMessage) Body() []byte              { return nil }
+func (m *testMessage) Codec() codec.Reader       { return nil }
+
+func TestOutbox(t *testing.T) {
+	b := bmemory.NewBroker()
+	if err := b.Connect(); err != nil {
+		t.Fatal(err)
+	}
+
+	var got []*broker.Message
+	if _, err := b.Subscribe("test", func(e broker.Event) error {
+		got = append(got, e.Message())
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	s := memory.NewStore()
+	c := mucp.NewClient(client.Broker(b))
+	o := NewOutbox(Store(s), Client(c))
+
+	msgs := []client.Message{
+		c.NewMessage("test", map[string]string{"n": "1"}, client.WithMessageContentType("application/json")),
+		c.NewMessage("test", map[string]string{"n": "2"}, client.WithMessageContentType("application/json")),
+	}
+	if err := o.Write(context.TODO(), msgs, &store.Record{Key: "order/1", Value: []byte("paid")}); err != nil {
+		t.Fatal(err)
+	}
+
+	recs, _ := s.List()
+	if len(recs) != 3 {
+		t.Fatalf("Expected the record and 2 queued messages got %d records", len(recs))
+	}
+	if len(got) != 0 {
+		t.Fatal("Expected nothing published before the relay runs")
+	}
+
+	if err := o.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(got) != 2 {
+		t.Fatalf("Expected 2 published messages got %d", len(got))
+	}
+	for i, m := range got {
+		if len(m.Header[IdHeader]) == 0 {
+			t.Fatalf("Expected message %d to carry a dedup id", i)
+		}
+		if want := `{"n":"` + string(rune('1'+i)) + `"}`; string(m.Body[:len(want)]) != want {
+			t.Fatalf("Expected body %s got %s", want, m.Body)
+		}
+	}
+
+	if recs, _ := s.List(); len(recs) != 1 || recs[0].Key != "order/1" {
+		t.Fatalf("Expected the outbox to be drained got %v", recs)
+	}
+}
+
+func TestSubscriberWrapper(t *testing.T) {
+	var calls int
+	fn := NewSubscriberWrapper(Store(memory.NewStore()))(func(ctx context.Context, msg server.Message) error {
+		calls++
+		return nil
+	})
+
+	msg := &testMessage{topic: "test", header: map[string]string{IdHeader: "1"}}
+	for i := 0; i < 3; i++ {
+		if err := fn(context.TODO(), msg); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if calls != 1 {
+		t.Fatalf("Expected duplicates to be skipped got %d calls", calls)
+	}
+
+	// lower cased header keys as the grpc server passes them
+	if err := fn(context.TODO(), &testMessage{topic: "test", header: map[string]string{"stack-message-id": "2"}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := fn(context.TODO(), &testMessage{topic: "test", header: map[string]string{"stack-message-id": "2"}}); err != nil {
+		t.Fatal(err)
+	}
+	if calls != 2 {
+		t.Fatalf("Expected 2 calls got %d", calls)
+	}
+}
+
+func TestSubscriberWrapperConcurrent(t *testing.T) {
+	var calls int32
+	release := make(chan bool)
+	fn := NewSubscriberWrapper(Store(memory.NewStore()))(func(ctx context.Context, msg server.Message) error {
+		atomic.AddInt32(&calls, 1)
+		<-release
+		return nil
+	})
+
+	msg := &testMessage{topic: "test", header: map[string]string{IdHeader: "1"}}
+
+	done := make(chan error)
+	go func() {
+		done <- fn(context.TODO(), msg)
+	}()
+	for atomic.LoadInt32(&calls) == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	// a concurrent delivery is nacked while the first one is processed
+	if err := fn(context.TODO(), msg); err != ErrProcessing {
+		t.Fatalf("Expected ErrProcessing got %v", err)
+	}
+
+	close(release)
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+	if err := fn(context.TODO(), msg); err != nil {
+		t.Fatal(err)
+	}
+	if calls != 1 {
+		t.Fatalf("Expected 1 call got %d", calls)
+	}
+}
+
+func TestSubscriberWrapperRelease(t *testing.T) {
+	var calls int
+	fail := errors.New("failed")
+	fn := NewSubscriberWrapper(Store(memory.NewStore()))(func(ctx context.Context, msg server.Message) error {
+		calls++
+		if calls == 1 {
+			return fail
+		}
+		return nil
+	})
+
+	// a failed message is released for its redelivery
+	msg := &testMessage{topic: "test", header: map[string]string{IdHeader: "1"}}
+	if err := fn(context.TODO(), msg); err != fail {
+		t.Fatalf("Expected the subscriber error got %v", err)
+	}
+	if err := fn(context.TODO(), msg); err != nil {
+		t.Fatal(err)
+	}
+	if calls != 2 {
+		t.Fatalf("Expected 2 calls got %d", calls)
+	}
+}
+
+// noSwapStore is versioned but its backend has no compare and swap
+type noSwapStore struct {
+	store.Store
+}
+
+func (n *noSwapStore) CompareAndSwap(recs ...*store.Record) error {
+	return store.ErrNotSupported
+}
+
+func (n *noSwapStore) Watch(prefix string) (store.Watcher, error) {
+	return nil, store.ErrNotSupported
+}
+
+func TestSubscriberWrapperNotSupported(t *testing.T) {
+	var calls int
+	fn := NewSubscriberWrapper(Store(&noSwapStore{memory.NewStore()}))(func(ctx context.Context, msg server.Message) error {
+		calls++
+		return nil
+	})
+
+	// the ids are read rather than claimed
+	msg := &testMessage{topic: "test", header: map[string]string{IdHeader: "1"}}
+	for i := 0; i < 3; i++ {
+		if err := fn(context.TODO(), msg); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if calls != 1 {
+		t.Fatalf("Expected duplicates to be skipped got %d calls", calls)
+	}
+}
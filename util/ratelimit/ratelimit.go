@@ -0,0 +1,206 @@
+// Package ratelimit provides token bucket and concurrency limiting
+package ratelimit
+
+import (
+	"sync"
+	"time"
+)
+
+// Limit describes the rate and concurrency allowed
+type Limit struct {
+	// Rate of requests per second, 0 is unlimited
+	Rate float64
+	// Burst is the size of the bucket, defaults to the rate
+	Burst int
+	// MaxInFlight is the max number of concurrent requests, 0 is unlimited
+	MaxInFlight int
+}
+
+// Bucket is a token bucket refilled at a fixed rate
+type Bucket struct {
+	rate  float64
+	burst float64
+
+	sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+// NewBucket returns a full bucket refilled with rate tokens a second
+func NewBucket(rate float64, burst int) *Bucket {
+	b := float64(burst)
+	if b < 1 {
+		b = rate
+	}
+	if b < 1 {
+		b = 1
+	}
+
+	return &Bucket{
+		rate:   rate,
+		burst:  b,
+		tokens: b,
+		last:   time.Now(),
+	}
+}
+
+// Allow takes a token from the bucket if there is one
+func (b *Bucket) Allow() bool {
+	b.Lock()
+	defer b.Unlock()
+
+	now := time.Now()
+	b.tokens += now.Sub(b.last).Seconds() * b.rate
+	if b.tokens > b.burst {
+		b.tokens = b.burst
+	}
+	b.last = now
+
+	if b.tokens < 1 {
+		return false
+	}
+
+	b.tokens--
+	return true
+}
+
+// Limiter enforces a Limit
+type Limiter struct {
+	limit  Limit
+	bucket *Bucket
+
+	sync.Mutex
+	inFlight int
+	used     time.Time
+}
+
+// NewLimiter returns a limiter for the given limit
+func NewLimiter(l Limit) *Limiter {
+	lm := &Limiter{limit: l}
+	if l.Rate > 0 {
+		lm.bucket = NewBucket(l.Rate, l.Burst)
+	}
+	return lm
+}
+
+// Acquire reports whether a request may proceed. The returned func must
+// be called once the request is done to release its concurrency slot.
+func (l *Limiter) Acquire() (func(), bool) {
+	l.Lock()
+	l.used = time.Now()
+	if l.limit.MaxInFlight > 0 {
+		if l.inFlight >= l.limit.MaxInFlight {
+			l.Unlock()
+			return nil, false
+		}
+		l.inFlight++
+	}
+	l.Unlock()
+
+	if l.bucket != nil && !l.bucket.Allow() {
+		l.release()
+		return nil, false
+	}
+
+	return l.release, true
+}
+
+func (l *Limiter) release() {
+	if l.limit.MaxInFlight <= 0 {
+		return
+	}
+
+	l.Lock()
+	if l.inFlight > 0 {
+		l.inFlight--
+	}
+	l.Unlock()
+}
+
+// InFlight returns the number of requests in progress
+func (l *Limiter) InFlight() int {
+	l.Lock()
+	defer l.Unlock()
+	return l.inFlight
+}
+
+// Limiters holds a limiter per key e.g an endpoint or client address
+type Limiters struct {
+	// Default limit of a key
+	Default Limit
+	// Limits overrides the default for a key
+	Limits map[string]Limit
+	// Idle is how long a limiter is kept unused, the idle limiters are
+	// removed as new keys are added. They are kept when zero
+	Idle time.Duration
+
+	sync.RWMutex
+	limiters map[string]*Limiter
+	pruned   time.Time
+}
+
+// NewLimiters returns a set of limiters using the default limit for any key without an override
+func NewLimiters(def Limit, limits map[string]Limit) *Limiters {
+	return &Limiters{
+		Default:  def,
+		Limits:   limits,
+		limiters: make(map[string]*Limiter),
+		pruned:   time.Now(),
+	}
+}
+
+// Get returns the limiter of a key
+func (l *Limiters) Get(key string) *Limiter {
+	l.RLock()
+	lm, ok := l.limiters[key]
+	l.RUnlock()
+	if ok {
+		return lm
+	}
+
+	l.Lock()
+	defer l.Unlock()
+
+	if lm, ok = l.limiters[key]; ok {
+		return lm
+	}
+
+	limit, ok := l.Limits[key]
+	if !ok {
+		limit = l.Default
+	}
+
+	if l.Idle > 0 && time.Since(l.pruned) > l.Idle {
+		l.prune(l.Idle)
+		l.pruned = time.Now()
+	}
+
+	lm = NewLimiter(limit)
+	l.limiters[key] = lm
+
+	return lm
+}
+
+// Acquire acquires the limiter of a key
+func (l *Limiters) Acquire(key string) (func(), bool) {
+	return l.Get(key).Acquire()
+}
+
+// Prune removes the limiters of keys which were not used within idle
+func (l *Limiters) Prune(idle time.Duration) {
+	l.Lock()
+	defer l.Unlock()
+	l.prune(idle)
+}
+
+func (l *Limiters) prune(idle time.Duration) {
+	for key, lm := range l.limiters {
+		lm.Lock()
+		expired := lm.inFlight == 0 && time.Since(lm.used) > idle
+		lm.Unlock()
+
+		if expired {
+			delete(l.limiters, key)
+		}
+	}
+}
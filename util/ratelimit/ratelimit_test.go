@@ -0,0 +1,82 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBucket(t *testing.T) {
+	b := NewBucket(100, 2)
+
+	if !b.Allow() || !b.Allow() {
+		t.Fatal("Expected the burst to be allowed")
+	}
+	if b.Allow() {
+		t.Fatal("Expected an empty bucket to reject")
+	}
+
+	time.Sleep(time.Millisecond * 20)
+
+	if !b.Allow() {
+		t.Fatal("Expected the bucket to refill")
+	}
+}
+
+func TestLimiterInFlight(t *testing.T) {
+	l := NewLimiter(Limit{MaxInFlight: 1})
+
+	release, ok := l.Acquire()
+	if !ok {
+		t.Fatal("Expected the first request to be allowed")
+	}
+	if _, ok := l.Acquire(); ok {
+		t.Fatal("Expected the second concurrent request to be rejected")
+	}
+
+	release()
+
+	if l.InFlight() != 0 {
+		t.Fatalf("Expected no requests in flight, got %d", l.InFlight())
+	}
+	if _, ok := l.Acquire(); !ok {
+		t.Fatal("Expected a request to be allowed once released")
+	}
+}
+
+func TestLimiters(t *testing.T) {
+	l := NewLimiters(Limit{}, map[string]Limit{
+		"foo": {Rate: 1, Burst: 1},
+	})
+
+	for i := 0; i < 10; i++ {
+		if _, ok := l.Acquire("bar"); !ok {
+			t.Fatal("Expected the default limit to be unlimited")
+		}
+	}
+
+	if _, ok := l.Acquire("foo"); !ok {
+		t.Fatal("Expected the first request to foo to be allowed")
+	}
+	if _, ok := l.Acquire("foo"); ok {
+		t.Fatal("Expected the second request to foo to be rejected")
+	}
+
+	l.Prune(0)
+	if len(l.limiters) != 0 {
+		t.Fatalf("Expected idle limiters to be pruned, got %d", len(l.limiters))
+	}
+}
+
+func TestLimitersIdle(t *testing.T) {
+	l := NewLimiters(Limit{Rate: 1}, nil)
+	l.Idle = time.Millisecond * 10
+
+	l.Acquire("foo")
+	time.Sleep(time.Millisecond * 20)
+
+	// adding a key removes the idle limiters
+	l.Acquire("bar")
+	if _, ok := l.limiters["foo"]; ok || len(l.limiters) != 1 {
+		t.Fatalf("Expected the idle limiter to be removed, got %d limiters", len(l.limiters))
+	}
+}
@@ -0,0 +1,125 @@
+package cron
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"text/tabwriter"
+	"time"
+
+	"github.com/stack-labs/stack"
+	pb "github.com/stack-labs/stack/debug/proto"
+	"github.com/stack-labs/stack/pkg/cli"
+	"github.com/stack-labs/stack/util/log"
+	"github.com/stack-labs/stack/util/stackctl/internal/util"
+)
+
+func query(c *cli.Context, service string, req *pb.CronRequest) (*pb.CronResponse, error) {
+	svc := stack.NewService(stack.Name("stack.rpc.stackctl"))
+	if err := svc.Init(); err != nil {
+		log.Fatal("stackctl client init err: %s", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), c.Duration("timeout"))
+	defer cancel()
+
+	return pb.NewDebugService(service, svc.Client()).Cron(ctx, req)
+}
+
+func formatTime(t int64) string {
+	if t == 0 {
+		return "-"
+	}
+	return time.Unix(0, t).Format(time.RFC3339)
+}
+
+func jobs(c *cli.Context, args []string) ([]byte, error) {
+	if len(args) == 0 {
+		return nil, fmt.Errorf("require the service to list jobs of")
+	}
+
+	rsp, err := query(c, args[0], &pb.CronRequest{History: 1})
+	if err != nil {
+		return nil, err
+	}
+
+	buf := new(bytes.Buffer)
+	w := tabwriter.NewWriter(buf, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tSCHEDULE\tLEADER\tNEXT\tLAST\tERROR")
+	for _, j := range rsp.Jobs {
+		last, lastErr := "-", ""
+		if len(j.History) > 0 {
+			last = formatTime(j.History[0].Started)
+			lastErr = j.History[0].Error
+		}
+		fmt.Fprintf(w, "%s\t%s\t%t\t%s\t%s\t%s\n", j.Id, j.Schedule, j.Leader, formatTime(j.Next*int64(time.Second)), last, lastErr)
+	}
+	w.Flush()
+
+	return bytes.TrimRight(buf.Bytes(), "\n"), nil
+}
+
+func history(c *cli.Context, args []string) ([]byte, error) {
+	if len(args) < 2 {
+		return nil, fmt.Errorf("require the service and job id to show the history of")
+	}
+
+	rsp, err := query(c, args[0], &pb.CronRequest{
+		Job:     args[1],
+		History: c.Int64("limit"),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(rsp.Jobs) == 0 {
+		return nil, fmt.Errorf("job %s not found in %s", args[1], args[0])
+	}
+
+	buf := new(bytes.Buffer)
+	w := tabwriter.NewWriter(buf, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "SCHEDULED\tSTARTED\tDURATION\tNODE\tERROR")
+	for _, e := range rsp.Jobs[0].History {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", formatTime(e.Scheduled), formatTime(e.Started), time.Duration(e.Ended-e.Started), e.Node, e.Error)
+	}
+	w.Flush()
+
+	return bytes.TrimRight(buf.Bytes(), "\n"), nil
+}
+
+func Commands() []cli.Command {
+	timeout := &cli.DurationFlag{
+		Name:  "timeout",
+		Usage: "Request timeout",
+		Value: 10 * time.Second,
+	}
+
+	return []cli.Command{
+		{
+			Name:  "cron",
+			Usage: "Inspect the cron jobs of a service",
+			Subcommands: []cli.Command{
+				{
+					Name:      "jobs",
+					Usage:     "List the jobs of a service with their next and last runs",
+					ArgsUsage: "[service]",
+					Flags:     []cli.Flag{timeout},
+					Action:    util.Print(jobs),
+				},
+				{
+					Name:      "history",
+					Usage:     "Show the recent executions of a job",
+					ArgsUsage: "[service] [job id]",
+					Flags: []cli.Flag{
+						timeout,
+						&cli.Int64Flag{
+							Name:  "limit",
+							Usage: "Number of executions to show",
+							Value: 20,
+						},
+					},
+					Action: util.Print(history),
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,148 @@
+package dlq
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/stack-labs/stack"
+	"github.com/stack-labs/stack/broker"
+	"github.com/stack-labs/stack/pkg/cli"
+	"github.com/stack-labs/stack/util/log"
+	"github.com/stack-labs/stack/util/stackctl/internal/util"
+)
+
+func replay(c *cli.Context, args []string) ([]byte, error) {
+	if len(args) == 0 {
+		return nil, fmt.Errorf("require the dead letter topic to replay")
+	}
+	dlq := args[0]
+
+	svc := stack.NewService(stack.Name("stack.rpc.stackctl"))
+	if err := svc.Init(); err != nil {
+		log.Fatal("stackctl client init err: %s", err)
+	}
+
+	b := svc.Options().Broker
+	if err := b.Connect(); err != nil {
+		return nil, err
+	}
+	defer b.Disconnect()
+
+	var (
+		mu       sync.Mutex
+		replayed int
+		failed   int
+		done     = make(chan bool)
+		once     sync.Once
+		max      = c.Int("max")
+		to       = c.String("topic")
+	)
+
+	handler := func(e broker.Event) error {
+		topic, msg := broker.NewReplayMessage(e.Message())
+		if len(to) > 0 {
+			topic = to
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		// delivered before the subscriber stopped, it is not acked so
+		// brokers which acknowledge messages deliver it again
+		if max > 0 && replayed+failed >= max {
+			return nil
+		}
+
+		// messages are only acked once republished, an error would have
+		// the broker retry the message rather than leave it queued
+		if len(topic) == 0 {
+			failed++
+			log.Errorf("skipping message without %s header", broker.DeadLetterTopicHeader)
+		} else if err := b.Publish(topic, msg); err != nil {
+			failed++
+			log.Errorf("replay to %s err: %s", topic, err)
+		} else if err := e.Ack(); err != nil {
+			failed++
+			log.Errorf("ack of message replayed to %s err: %s", topic, err)
+		} else {
+			replayed++
+		}
+
+		if max > 0 && replayed+failed >= max {
+			once.Do(func() { close(done) })
+		}
+		return nil
+	}
+
+	opts := []broker.SubscribeOption{broker.DisableAutoAck()}
+	if queue := c.String("queue"); len(queue) > 0 {
+		opts = append(opts, broker.Queue(queue))
+	}
+
+	sub, err := b.Subscribe(dlq, handler, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, syscall.SIGTERM, syscall.SIGINT)
+
+	var timeout <-chan time.Time
+	if d := c.Duration("timeout"); d > 0 {
+		timeout = time.After(d)
+	}
+
+	select {
+	case <-done:
+	case <-ch:
+	case <-timeout:
+	}
+
+	// stop consuming as soon as the max is reached
+	if err := sub.Unsubscribe(); err != nil {
+		log.Errorf("unsubscribe from %s err: %s", dlq, err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	return []byte(fmt.Sprintf("replayed %d messages from %s, %d failed", replayed, dlq, failed)), nil
+}
+
+func Commands() []cli.Command {
+	return []cli.Command{
+		{
+			Name:  "dlq",
+			Usage: "Manage dead letter topics",
+			Subcommands: []cli.Command{
+				{
+					Name:      "replay",
+					Usage:     "Republish dead lettered messages to the topic they failed on",
+					ArgsUsage: "[dead letter topic]",
+					Flags: []cli.Flag{
+						&cli.StringFlag{
+							Name:  "topic",
+							Usage: "Republish to this topic instead of the original one",
+						},
+						&cli.StringFlag{
+							Name:  "queue",
+							Usage: "Queue to share the dead letter topic with other consumers",
+						},
+						&cli.IntFlag{
+							Name:  "max",
+							Usage: "Stop after this many messages, 0 replays until interrupted. Messages which are not republished are not acked",
+						},
+						&cli.DurationFlag{
+							Name:  "timeout",
+							Usage: "Stop after this long e.g 30s, 0 replays until interrupted",
+						},
+					},
+					Action: util.Print(replay),
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,90 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/stack-labs/stack"
+	"github.com/stack-labs/stack/client"
+	gc "github.com/stack-labs/stack/client/grpc"
+	"github.com/stack-labs/stack/client/grpc/dynamic"
+	"github.com/stack-labs/stack/pkg/cli"
+	"github.com/stack-labs/stack/util/log"
+	"github.com/stack-labs/stack/util/stackctl/internal/util"
+)
+
+func dynamicClient() dynamic.Client {
+	svc := stack.NewService(stack.Name("stack.rpc.stackctl"))
+	if err := svc.Init(); err != nil {
+		log.Fatal("stackctl client init err: %s", err)
+	}
+
+	return dynamic.NewClient(dynamic.WithClient(gc.NewClient(
+		client.Registry(svc.Client().Options().Registry),
+	)))
+}
+
+func list(c *cli.Context, args []string) ([]byte, error) {
+	if len(args) == 0 {
+		return nil, fmt.Errorf("require the service to list the grpc services of")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), c.Duration("timeout"))
+	defer cancel()
+
+	services, err := dynamicClient().Services(ctx, args[0])
+	if err != nil {
+		return nil, err
+	}
+
+	return []byte(strings.Join(services, "\n")), nil
+}
+
+func call(c *cli.Context, args []string) ([]byte, error) {
+	if len(args) < 2 {
+		return nil, fmt.Errorf("require the service and method to call")
+	}
+
+	req := "{}"
+	if len(args) > 2 {
+		req = args[2]
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), c.Duration("timeout"))
+	defer cancel()
+
+	return dynamicClient().Call(ctx, args[0], args[1], []byte(req), client.WithRequestTimeout(c.Duration("timeout")))
+}
+
+func Commands() []cli.Command {
+	timeout := &cli.DurationFlag{
+		Name:  "timeout",
+		Usage: "Request timeout",
+		Value: 10 * time.Second,
+	}
+
+	return []cli.Command{
+		{
+			Name:  "grpc",
+			Usage: "Call grpc services with JSON using server reflection",
+			Subcommands: []cli.Command{
+				{
+					Name:      "list",
+					Usage:     "List the grpc services of a service",
+					ArgsUsage: "[service]",
+					Flags:     []cli.Flag{timeout},
+					Action:    util.Print(list),
+				},
+				{
+					Name:      "call",
+					Usage:     "Call a method with a JSON request",
+					ArgsUsage: "[service] [method] [json]",
+					Flags:     []cli.Flag{timeout},
+					Action:    util.Print(call),
+				},
+			},
+		},
+	}
+}
@@ -4,6 +4,9 @@ import (
 	"os"
 
 	"github.com/stack-labs/stack/pkg/cli"
+	"github.com/stack-labs/stack/util/stackctl/cron"
+	"github.com/stack-labs/stack/util/stackctl/dlq"
+	"github.com/stack-labs/stack/util/stackctl/grpc"
 	"github.com/stack-labs/stack/util/stackctl/new"
 	"github.com/stack-labs/stack/util/stackctl/service"
 )
@@ -14,6 +17,9 @@ func main() {
 
 	app.Commands = append(app.Commands, new.Commands()...)
 	app.Commands = append(app.Commands, service.Commands()...)
+	app.Commands = append(app.Commands, dlq.Commands()...)
+	app.Commands = append(app.Commands, cron.Commands()...)
+	app.Commands = append(app.Commands, grpc.Commands()...)
 
 	app.Run(os.Args)
 }
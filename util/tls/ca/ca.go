@@ -0,0 +1,281 @@
+// Package ca is a local certificate authority issuing short lived certificates
+// bound to service names, it gives the services mutual TLS identities without
+// an external PKI
+package ca
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/stack-labs/stack/auth"
+	mls "github.com/stack-labs/stack/util/tls"
+)
+
+var (
+	// ErrInvalidRequest is returned when a certificate signing request can not be parsed or verified
+	ErrInvalidRequest = errors.New("ca: invalid certificate signing request")
+)
+
+// Authority signs the certificates of the services
+type Authority struct {
+	opts Options
+
+	cert *x509.Certificate
+	key  crypto.Signer
+	// PEM bundle of the authority
+	bundle []byte
+}
+
+// NewAuthority loads the authority from its files, it is generated when the
+// files do not exist
+func NewAuthority(opts ...Option) (*Authority, error) {
+	options := Options{
+		Name:   DefaultName,
+		TTL:    DefaultTTL,
+		MaxTTL: DefaultMaxTTL,
+	}
+	for _, o := range opts {
+		o(&options)
+	}
+
+	a := &Authority{opts: options}
+
+	if len(options.CertFile) > 0 && len(options.KeyFile) > 0 {
+		if _, err := os.Stat(options.CertFile); err == nil {
+			return a, a.load()
+		}
+	}
+
+	if err := a.generate(); err != nil {
+		return nil, err
+	}
+
+	return a, nil
+}
+
+func (a *Authority) load() error {
+	pair, err := tls.LoadX509KeyPair(a.opts.CertFile, a.opts.KeyFile)
+	if err != nil {
+		return err
+	}
+
+	cert, err := x509.ParseCertificate(pair.Certificate[0])
+	if err != nil {
+		return err
+	}
+	if !cert.IsCA {
+		return fmt.Errorf("ca: %s is not a certificate authority", a.opts.CertFile)
+	}
+
+	key, ok := pair.PrivateKey.(crypto.Signer)
+	if !ok {
+		return fmt.Errorf("ca: unsupported key in %s", a.opts.KeyFile)
+	}
+
+	a.cert = cert
+	a.key = key
+	a.bundle = pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw})
+	return nil
+}
+
+// generate a self signed authority, it is written to the files when set
+func (a *Authority) generate() error {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return err
+	}
+
+	serial, err := serialNumber()
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	template := &x509.Certificate{
+		SerialNumber:          serial,
+		Subject:               pkix.Name{CommonName: a.opts.Name},
+		NotBefore:             now.Add(-time.Minute),
+		NotAfter:              now.Add(DefaultValidity),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return err
+	}
+
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return err
+	}
+
+	a.cert = cert
+	a.key = key
+	a.bundle = pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+
+	if len(a.opts.CertFile) == 0 || len(a.opts.KeyFile) == 0 {
+		return nil
+	}
+
+	keyPEM, err := EncodeKey(key)
+	if err != nil {
+		return err
+	}
+	if err := WriteFile(a.opts.KeyFile, keyPEM); err != nil {
+		return err
+	}
+	return WriteFile(a.opts.CertFile, a.bundle)
+}
+
+// Bundle returns the PEM certificate of the authority the peers are verified with
+func (a *Authority) Bundle() []byte {
+	return a.bundle
+}
+
+// Sign issues a certificate to the named service for the key of a PEM
+// certificate signing request. The names requested are ignored, the
+// certificate carries the service name as its common and DNS name and no IP
+// addresses, the clients verify a server against the name of the service
+// called rather than the address dialled. The ttl defaults to the TTL of the
+// authority and is capped by its MaxTTL
+func (a *Authority) Sign(name string, csr []byte, ttl time.Duration) ([]byte, time.Time, error) {
+	if len(name) == 0 {
+		return nil, time.Time{}, errors.New("ca: service name is required")
+	}
+
+	block, _ := pem.Decode(csr)
+	if block == nil || block.Type != "CERTIFICATE REQUEST" {
+		return nil, time.Time{}, ErrInvalidRequest
+	}
+
+	req, err := x509.ParseCertificateRequest(block.Bytes)
+	if err != nil {
+		return nil, time.Time{}, ErrInvalidRequest
+	}
+	if err := req.CheckSignature(); err != nil {
+		return nil, time.Time{}, ErrInvalidRequest
+	}
+
+	if ttl <= 0 {
+		ttl = a.opts.TTL
+	}
+	if a.opts.MaxTTL > 0 && ttl > a.opts.MaxTTL {
+		ttl = a.opts.MaxTTL
+	}
+
+	serial, err := serialNumber()
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	now := time.Now()
+	expiry := now.Add(ttl)
+	// never outlive the authority
+	if expiry.After(a.cert.NotAfter) {
+		expiry = a.cert.NotAfter
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: name},
+		DNSNames:     []string{name},
+		// allow for clock skew between the services
+		NotBefore:   now.Add(-time.Minute),
+		NotAfter:    expiry,
+		KeyUsage:    x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, a.cert, req.PublicKey, a.key)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), expiry, nil
+}
+
+func serialNumber() (*big.Int, error) {
+	return rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+}
+
+// NewRequest generates a key and a PEM certificate signing request for the
+// named service, it returns the request and the PEM key
+func NewRequest(name string) ([]byte, []byte, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	der, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{
+		Subject:  pkix.Name{CommonName: name},
+		DNSNames: []string{name},
+	}, key)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	keyPEM, err := EncodeKey(key)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: der}), keyPEM, nil
+}
+
+// EncodeKey returns the PEM encoding of an ECDSA key
+func EncodeKey(key *ecdsa.PrivateKey) ([]byte, error) {
+	b, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: b}), nil
+}
+
+// WriteFile replaces a file with data readable by the owner only, the data is
+// renamed into place so readers never see a partial file
+func WriteFile(file string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(file), 0700); err != nil {
+		return err
+	}
+
+	tmp := file + ".tmp"
+	if err := ioutil.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, file)
+}
+
+// Account returns the auth account of a peer identified by a certificate of
+// the authority, so its requests are verified with the auth rules like those
+// of any other service account. The account has the service scope
+func Account(p *mls.Peer) *auth.Account {
+	if p == nil {
+		return nil
+	}
+
+	acc := &auth.Account{
+		ID:     p.Name,
+		Type:   "service",
+		Scopes: []string{"service"},
+	}
+	if p.Certificate != nil {
+		acc.Issuer = p.Certificate.Issuer.CommonName
+	}
+
+	return acc
+}
@@ -0,0 +1,110 @@
+package ca
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func parse(t *testing.T, b []byte) *x509.Certificate {
+	block, _ := pem.Decode(b)
+	if block == nil {
+		t.Fatal("Expected a PEM certificate")
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cert
+}
+
+func TestSign(t *testing.T) {
+	a, err := NewAuthority(TTL(time.Hour), MaxTTL(time.Hour*2))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	csr, _, err := NewRequest("other.name")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b, expiry, err := a.Sign("stack.rpc.greeter", csr, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cert := parse(t, b)
+	if cert.Subject.CommonName != "stack.rpc.greeter" {
+		t.Fatalf("Expected the service name got %s", cert.Subject.CommonName)
+	}
+	if len(cert.DNSNames) != 1 || cert.DNSNames[0] != "stack.rpc.greeter" {
+		t.Fatalf("Expected only the service name got %v", cert.DNSNames)
+	}
+	if d := time.Until(expiry); d > time.Hour || d < time.Minute*59 {
+		t.Fatalf("Expected the default ttl got %v", d)
+	}
+
+	pool := x509.NewCertPool()
+	pool.AppendCertsFromPEM(a.Bundle())
+	if _, err := cert.Verify(x509.VerifyOptions{
+		Roots:     pool,
+		KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}); err != nil {
+		t.Fatalf("Expected the certificate to be signed by the authority: %v", err)
+	}
+
+	// the ttl is capped
+	_, expiry, err = a.Sign("stack.rpc.greeter", csr, time.Hour*24)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := time.Until(expiry); d > time.Hour*2 {
+		t.Fatalf("Expected the ttl to be capped got %v", d)
+	}
+
+	if _, _, err := a.Sign("stack.rpc.greeter", []byte("invalid"), 0); err != ErrInvalidRequest {
+		t.Fatalf("Expected an invalid request got %v", err)
+	}
+	if _, _, err := a.Sign("", csr, 0); err == nil {
+		t.Fatal("Expected the service name to be required")
+	}
+}
+
+func TestAuthorityFiles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ca")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	certFile := filepath.Join(dir, "ca.crt")
+	keyFile := filepath.Join(dir, "ca.key")
+
+	a, err := NewAuthority(KeyPair(certFile, keyFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the generated authority is loaded again
+	b, err := NewAuthority(KeyPair(certFile, keyFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(a.Bundle()) != string(b.Bundle()) {
+		t.Fatal("Expected the authority to be loaded from its files")
+	}
+
+	fi, err := os.Stat(keyFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fi.Mode().Perm() != 0600 {
+		t.Fatalf("Expected the key to be readable by the owner only got %v", fi.Mode().Perm())
+	}
+}
@@ -0,0 +1,60 @@
+package ca
+
+import (
+	"time"
+)
+
+var (
+	// DefaultName is the common name of a generated authority
+	DefaultName = "stack ca"
+	// DefaultTTL is the lifetime of the certificates issued
+	DefaultTTL = time.Hour * 24
+	// DefaultMaxTTL is the longest lifetime a service may request
+	DefaultMaxTTL = time.Hour * 24 * 7
+	// DefaultValidity is the lifetime of a generated authority
+	DefaultValidity = time.Hour * 24 * 365 * 10
+)
+
+type Options struct {
+	// Name is the common name of a generated authority
+	Name string
+	// CertFile and KeyFile are the PEM key pair of the authority, they are
+	// generated when missing. The authority lives in memory when not set
+	CertFile string
+	KeyFile  string
+	// TTL is the lifetime of the certificates issued
+	TTL time.Duration
+	// MaxTTL is the longest lifetime a service may request
+	MaxTTL time.Duration
+}
+
+type Option func(o *Options)
+
+// Name sets the common name of a generated authority
+func Name(n string) Option {
+	return func(o *Options) {
+		o.Name = n
+	}
+}
+
+// KeyPair sets the files the authority is loaded from or generated in
+func KeyPair(certFile, keyFile string) Option {
+	return func(o *Options) {
+		o.CertFile = certFile
+		o.KeyFile = keyFile
+	}
+}
+
+// TTL sets the lifetime of the certificates issued
+func TTL(d time.Duration) Option {
+	return func(o *Options) {
+		o.TTL = d
+	}
+}
+
+// MaxTTL sets the longest lifetime a service may request
+func MaxTTL(d time.Duration) Option {
+	return func(o *Options) {
+		o.MaxTTL = d
+	}
+}
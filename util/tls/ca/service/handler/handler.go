@@ -0,0 +1,68 @@
+// Package handler implements the ca service, register it with
+// pb.RegisterCAHandler(server, &handler.CA{Authority: authority, Tokens: tokens})
+package handler
+
+import (
+	"context"
+	"crypto/subtle"
+	"time"
+
+	"github.com/stack-labs/stack/auth"
+	"github.com/stack-labs/stack/util/errors"
+	mls "github.com/stack-labs/stack/util/tls"
+	"github.com/stack-labs/stack/util/tls/ca"
+	pb "github.com/stack-labs/stack/util/tls/ca/service/proto"
+)
+
+type CA struct {
+	Authority *ca.Authority
+	// Tokens are the bootstrap tokens of the service names, a service is
+	// issued its first certificate with the token of its name. A peer renews
+	// the certificate of its own name over mutual TLS without it, as does a
+	// caller authenticated with the account of the name. No other service is
+	// issued a certificate
+	Tokens map[string]string
+}
+
+// allowed reports whether the caller may be issued a certificate of the name
+func (c *CA) allowed(ctx context.Context, req *pb.SignRequest) bool {
+	if p, ok := mls.FromContext(ctx); ok && p != nil && p.Name == req.Name {
+		return true
+	}
+	if acc, ok := auth.AccountFromContext(ctx); ok && acc != nil && acc.ID == req.Name {
+		return true
+	}
+
+	token, ok := c.Tokens[req.Name]
+	if !ok || len(token) == 0 || len(req.Token) == 0 {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(token), []byte(req.Token)) == 1
+}
+
+func (c *CA) Sign(ctx context.Context, req *pb.SignRequest, rsp *pb.SignResponse) error {
+	if len(req.Name) == 0 {
+		return errors.BadRequest("stack.rpc.ca", "service name is required")
+	}
+
+	if !c.allowed(ctx, req) {
+		return errors.Forbidden("stack.rpc.ca", "not allowed to sign a certificate for %s", req.Name)
+	}
+
+	cert, expiry, err := c.Authority.Sign(req.Name, req.Csr, time.Duration(req.Ttl)*time.Second)
+	if err == ca.ErrInvalidRequest {
+		return errors.BadRequest("stack.rpc.ca", err.Error())
+	} else if err != nil {
+		return errors.InternalServerError("stack.rpc.ca", err.Error())
+	}
+
+	rsp.Certificate = cert
+	rsp.Bundle = c.Authority.Bundle()
+	rsp.Expiry = expiry.Unix()
+	return nil
+}
+
+func (c *CA) Bundle(ctx context.Context, req *pb.BundleRequest, rsp *pb.BundleResponse) error {
+	rsp.Bundle = c.Authority.Bundle()
+	return nil
+}
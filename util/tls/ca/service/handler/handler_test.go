@@ -0,0 +1,82 @@
+package handler
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stack-labs/stack/auth"
+	"github.com/stack-labs/stack/util/errors"
+	mls "github.com/stack-labs/stack/util/tls"
+	"github.com/stack-labs/stack/util/tls/ca"
+	pb "github.com/stack-labs/stack/util/tls/ca/service/proto"
+)
+
+func TestSign(t *testing.T) {
+	a, err := ca.NewAuthority()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	h := &CA{Authority: a, Tokens: map[string]string{
+		"stack.rpc.greeter": "secret",
+		"stack.rpc.other":   "other",
+	}}
+
+	csr, _, err := ca.NewRequest("stack.rpc.greeter")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testData := []struct {
+		ctx   context.Context
+		token string
+		code  int32
+	}{
+		// no token
+		{context.Background(), "", 403},
+		// wrong token
+		{context.Background(), "invalid", 403},
+		// bootstrap
+		{context.Background(), "secret", 0},
+		// token of another service
+		{context.Background(), "other", 403},
+		// renewal by the service
+		{mls.NewContext(context.Background(), &mls.Peer{Name: "stack.rpc.greeter"}), "", 0},
+		// another service
+		{mls.NewContext(context.Background(), &mls.Peer{Name: "stack.rpc.other"}), "", 403},
+		// account of the service
+		{auth.ContextWithAccount(context.Background(), &auth.Account{ID: "stack.rpc.greeter"}), "", 0},
+		// account of another service
+		{auth.ContextWithAccount(context.Background(), &auth.Account{ID: "stack.rpc.other"}), "", 403},
+	}
+
+	for i, d := range testData {
+		rsp := new(pb.SignResponse)
+		err := h.Sign(d.ctx, &pb.SignRequest{
+			Name:  "stack.rpc.greeter",
+			Csr:   csr,
+			Token: d.token,
+		}, rsp)
+
+		if d.code == 0 {
+			if err != nil {
+				t.Fatalf("%d: Expected the certificate to be signed: %v", i, err)
+			}
+			if len(rsp.Certificate) == 0 || string(rsp.Bundle) != string(a.Bundle()) {
+				t.Fatalf("%d: Expected a certificate and the bundle", i)
+			}
+			continue
+		}
+
+		if e := errors.Parse(err.Error()); e.Code != d.code {
+			t.Fatalf("%d: Expected code %d got %v", i, d.code, err)
+		}
+	}
+
+	// no tokens configured
+	h = &CA{Authority: a}
+	err = h.Sign(context.Background(), &pb.SignRequest{Name: "stack.rpc.greeter", Csr: csr}, new(pb.SignResponse))
+	if e := errors.Parse(err.Error()); e.Code != 403 {
+		t.Fatalf("Expected code 403 got %v", err)
+	}
+}
@@ -0,0 +1,108 @@
+package service
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/stack-labs/stack/client"
+)
+
+var (
+	// DefaultName is the name of the ca service
+	DefaultName = "stack.rpc.ca"
+	// DefaultRetryInterval is how often a failed renewal is retried
+	DefaultRetryInterval = time.Second * 10
+)
+
+type Options struct {
+	// Name of the ca service
+	Name string
+	// Client used to call the service
+	Client client.Client
+	// Service is the name of the registry.Service the certificate is issued to
+	Service string
+	// Dir the bundle, certificate and key are written to
+	Dir string
+	// Token to bootstrap the first certificate with
+	Token string
+	// TTL requested for the certificates, the authority default when zero
+	TTL time.Duration
+	// AllowedClientSANs are the names of the clients allowed. When empty any
+	// client signed by the authority is allowed
+	AllowedClientSANs []string
+	// AllowedServerSANs are the names of the servers allowed. When empty a
+	// server must carry the name dialled
+	AllowedServerSANs []string
+	// RetryInterval is how often a failed renewal is retried
+	RetryInterval time.Duration
+}
+
+type Option func(o *Options)
+
+// Name sets the name of the ca service
+func Name(n string) Option {
+	return func(o *Options) {
+		o.Name = n
+	}
+}
+
+// Client sets the client used to call the ca service
+func Client(c client.Client) Option {
+	return func(o *Options) {
+		o.Client = c
+	}
+}
+
+// Service sets the name of the service the certificate is issued to
+func Service(s string) Option {
+	return func(o *Options) {
+		o.Service = s
+	}
+}
+
+// Dir sets the dir the bundle, certificate and key are written to
+func Dir(d string) Option {
+	return func(o *Options) {
+		o.Dir = d
+	}
+}
+
+// Token sets the token the first certificate is requested with
+func Token(t string) Option {
+	return func(o *Options) {
+		o.Token = t
+	}
+}
+
+// TTL sets the lifetime requested for the certificates
+func TTL(d time.Duration) Option {
+	return func(o *Options) {
+		o.TTL = d
+	}
+}
+
+// AllowedClientSANs restricts the clients to the services of the names
+func AllowedClientSANs(sans ...string) Option {
+	return func(o *Options) {
+		o.AllowedClientSANs = append(o.AllowedClientSANs, sans...)
+	}
+}
+
+// AllowedServerSANs restricts the servers dialled to the services of the names
+func AllowedServerSANs(sans ...string) Option {
+	return func(o *Options) {
+		o.AllowedServerSANs = append(o.AllowedServerSANs, sans...)
+	}
+}
+
+// RetryInterval sets how often a failed renewal is retried
+func RetryInterval(d time.Duration) Option {
+	return func(o *Options) {
+		o.RetryInterval = d
+	}
+}
+
+func defaultDir(service string) string {
+	return filepath.Join(os.TempDir(), "stack", "ca", service)
+}
@@ -0,0 +1,246 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: ca.proto
+
+package stack_rpc_ca
+
+import (
+	fmt "fmt"
+	proto "github.com/golang/protobuf/proto"
+	math "math"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+type SignRequest struct {
+	// name of the service the certificate is issued to
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// PEM certificate signing request, the key never leaves the service
+	Csr []byte `protobuf:"bytes,2,opt,name=csr,proto3" json:"csr,omitempty"`
+	// requested time to live in seconds, capped by the authority
+	Ttl int64 `protobuf:"varint,3,opt,name=ttl,proto3" json:"ttl,omitempty"`
+	// bootstrap token, not needed to renew over mutual TLS
+	Token                string   `protobuf:"bytes,4,opt,name=token,proto3" json:"token,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SignRequest) Reset()         { *m = SignRequest{} }
+func (m *SignRequest) String() string { return proto.CompactTextString(m) }
+func (*SignRequest) ProtoMessage()    {}
+func (*SignRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_84034e4847c57e85, []int{0}
+}
+
+func (m *SignRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SignRequest.Unmarshal(m, b)
+}
+func (m *SignRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SignRequest.Marshal(b, m, deterministic)
+}
+func (m *SignRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SignRequest.Merge(m, src)
+}
+func (m *SignRequest) XXX_Size() int {
+	return xxx_messageInfo_SignRequest.Size(m)
+}
+func (m *SignRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SignRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SignRequest proto.InternalMessageInfo
+
+func (m *SignRequest) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *SignRequest) GetCsr() []byte {
+	if m != nil {
+		return m.Csr
+	}
+	return nil
+}
+
+func (m *SignRequest) GetTtl() int64 {
+	if m != nil {
+		return m.Ttl
+	}
+	return 0
+}
+
+func (m *SignRequest) GetToken() string {
+	if m != nil {
+		return m.Token
+	}
+	return ""
+}
+
+type SignResponse struct {
+	// PEM certificate
+	Certificate []byte `protobuf:"bytes,1,opt,name=certificate,proto3" json:"certificate,omitempty"`
+	// PEM bundle of the authority
+	Bundle []byte `protobuf:"bytes,2,opt,name=bundle,proto3" json:"bundle,omitempty"`
+	// expiry in unix seconds
+	Expiry               int64    `protobuf:"varint,3,opt,name=expiry,proto3" json:"expiry,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SignResponse) Reset()         { *m = SignResponse{} }
+func (m *SignResponse) String() string { return proto.CompactTextString(m) }
+func (*SignResponse) ProtoMessage()    {}
+func (*SignResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_84034e4847c57e85, []int{1}
+}
+
+func (m *SignResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SignResponse.Unmarshal(m, b)
+}
+func (m *SignResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SignResponse.Marshal(b, m, deterministic)
+}
+func (m *SignResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SignResponse.Merge(m, src)
+}
+func (m *SignResponse) XXX_Size() int {
+	return xxx_messageInfo_SignResponse.Size(m)
+}
+func (m *SignResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_SignResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SignResponse proto.InternalMessageInfo
+
+func (m *SignResponse) GetCertificate() []byte {
+	if m != nil {
+		return m.Certificate
+	}
+	return nil
+}
+
+func (m *SignResponse) GetBundle() []byte {
+	if m != nil {
+		return m.Bundle
+	}
+	return nil
+}
+
+func (m *SignResponse) GetExpiry() int64 {
+	if m != nil {
+		return m.Expiry
+	}
+	return 0
+}
+
+type BundleRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *BundleRequest) Reset()         { *m = BundleRequest{} }
+func (m *BundleRequest) String() string { return proto.CompactTextString(m) }
+func (*BundleRequest) ProtoMessage()    {}
+func (*BundleRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_84034e4847c57e85, []int{2}
+}
+
+func (m *BundleRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BundleRequest.Unmarshal(m, b)
+}
+func (m *BundleRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BundleRequest.Marshal(b, m, deterministic)
+}
+func (m *BundleRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BundleRequest.Merge(m, src)
+}
+func (m *BundleRequest) XXX_Size() int {
+	return xxx_messageInfo_BundleRequest.Size(m)
+}
+func (m *BundleRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_BundleRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BundleRequest proto.InternalMessageInfo
+
+type BundleResponse struct {
+	// PEM bundle of the authority
+	Bundle               []byte   `protobuf:"bytes,1,opt,name=bundle,proto3" json:"bundle,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *BundleResponse) Reset()         { *m = BundleResponse{} }
+func (m *BundleResponse) String() string { return proto.CompactTextString(m) }
+func (*BundleResponse) ProtoMessage()    {}
+func (*BundleResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_84034e4847c57e85, []int{3}
+}
+
+func (m *BundleResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BundleResponse.Unmarshal(m, b)
+}
+func (m *BundleResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BundleResponse.Marshal(b, m, deterministic)
+}
+func (m *BundleResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BundleResponse.Merge(m, src)
+}
+func (m *BundleResponse) XXX_Size() int {
+	return xxx_messageInfo_BundleResponse.Size(m)
+}
+func (m *BundleResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_BundleResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BundleResponse proto.InternalMessageInfo
+
+func (m *BundleResponse) GetBundle() []byte {
+	if m != nil {
+		return m.Bundle
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterType((*SignRequest)(nil), "stack.rpc.ca.SignRequest")
+	proto.RegisterType((*SignResponse)(nil), "stack.rpc.ca.SignResponse")
+	proto.RegisterType((*BundleRequest)(nil), "stack.rpc.ca.BundleRequest")
+	proto.RegisterType((*BundleResponse)(nil), "stack.rpc.ca.BundleResponse")
+}
+
+func init() { proto.RegisterFile("ca.proto", fileDescriptor_84034e4847c57e85) }
+
+var fileDescriptor_84034e4847c57e85 = []byte{
+	// 251 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x6c, 0x51, 0xcb, 0x4e, 0xc3, 0x30,
+	0x10, 0xc4, 0x4d, 0x88, 0x60, 0x1b, 0x1e, 0x5a, 0x21, 0x14, 0x02, 0x87, 0xc8, 0xa7, 0x9c, 0x7c,
+	0x80, 0x0f, 0x40, 0x80, 0xf8, 0x01, 0x73, 0xe4, 0x82, 0x6b, 0x0c, 0x8a, 0x5a, 0x9c, 0x60, 0x6f,
+	0x25, 0xf8, 0x07, 0x3e, 0x1a, 0x39, 0x76, 0xd5, 0x54, 0xea, 0x6d, 0x66, 0x76, 0x3d, 0xb3, 0xde,
+	0x85, 0x23, 0xad, 0xc4, 0xe0, 0x7a, 0xea, 0xb1, 0xf4, 0xa4, 0xf4, 0x52, 0xb8, 0x41, 0x0b, 0xad,
+	0xf8, 0x2b, 0xcc, 0x5f, 0xba, 0x4f, 0x2b, 0xcd, 0xf7, 0xda, 0x78, 0x42, 0x84, 0xdc, 0xaa, 0x2f,
+	0x53, 0xb1, 0x86, 0xb5, 0xc7, 0x72, 0xc4, 0x78, 0x0e, 0x99, 0xf6, 0xae, 0x9a, 0x35, 0xac, 0x2d,
+	0x65, 0x80, 0x41, 0x21, 0x5a, 0x55, 0x59, 0xc3, 0xda, 0x4c, 0x06, 0x88, 0x17, 0x70, 0x48, 0xfd,
+	0xd2, 0xd8, 0x2a, 0x1f, 0x1f, 0x46, 0xc2, 0xdf, 0xa0, 0x8c, 0xe6, 0x7e, 0xe8, 0xad, 0x37, 0xd8,
+	0xc0, 0x5c, 0x1b, 0x47, 0xdd, 0x47, 0xa7, 0x15, 0xc5, 0x90, 0x52, 0x4e, 0x25, 0xbc, 0x84, 0x62,
+	0xb1, 0xb6, 0xef, 0x2b, 0x93, 0xe2, 0x12, 0x0b, 0xba, 0xf9, 0x19, 0x3a, 0xf7, 0x9b, 0x42, 0x13,
+	0xe3, 0x67, 0x70, 0xf2, 0x38, 0x76, 0xa4, 0x0f, 0xf0, 0x16, 0x4e, 0x37, 0x42, 0x0a, 0xdd, 0x5a,
+	0xb2, 0xa9, 0xe5, 0xed, 0x1f, 0x83, 0xd9, 0xd3, 0x03, 0xde, 0x43, 0x1e, 0x66, 0xc4, 0x2b, 0x31,
+	0xdd, 0x8b, 0x98, 0x2c, 0xa5, 0xae, 0xf7, 0x95, 0xa2, 0x3b, 0x3f, 0xc0, 0x67, 0x28, 0x62, 0x22,
+	0x5e, 0xef, 0xf6, 0xed, 0x0c, 0x56, 0xdf, 0xec, 0x2f, 0x6e, 0x6c, 0x16, 0xc5, 0x78, 0x9d, 0xbb,
+	0xff, 0x01, 0x00, 0xdf, 0xe1, 0xb1, 0x96, 0xa9, 0x01, 0x00, 0x00,
+}
@@ -0,0 +1,112 @@
+// Code generated by protoc-gen-stack. DO NOT EDIT.
+// source: ca.proto
+
+package stack_rpc_ca
+
+import (
+	fmt "fmt"
+	math "math"
+
+	context "context"
+
+	proto "github.com/golang/protobuf/proto"
+
+	api "github.com/stack-labs/stack/api"
+
+	client "github.com/stack-labs/stack/client"
+
+	server "github.com/stack-labs/stack/server"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ api.Endpoint
+var _ context.Context
+var _ client.Option
+var _ server.Option
+
+// Api Endpoints for CA service
+
+func NewCAEndpoints() []*api.Endpoint {
+	return []*api.Endpoint{}
+}
+
+// Client API for CA service
+
+type CAService interface {
+	Sign(ctx context.Context, in *SignRequest, opts ...client.CallOption) (*SignResponse, error)
+	Bundle(ctx context.Context, in *BundleRequest, opts ...client.CallOption) (*BundleResponse, error)
+}
+
+type cAService struct {
+	c    client.Client
+	name string
+}
+
+func NewCAService(name string, c client.Client) CAService {
+	return &cAService{
+		c:    c,
+		name: name,
+	}
+}
+
+func (c *cAService) Sign(ctx context.Context, in *SignRequest, opts ...client.CallOption) (*SignResponse, error) {
+	req := c.c.NewRequest(c.name, "CA.Sign", in)
+	out := new(SignResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *cAService) Bundle(ctx context.Context, in *BundleRequest, opts ...client.CallOption) (*BundleResponse, error) {
+	req := c.c.NewRequest(c.name, "CA.Bundle", in)
+	out := new(BundleResponse)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Server API for CA service
+
+type CAHandler interface {
+	Sign(context.Context, *SignRequest, *SignResponse) error
+	Bundle(context.Context, *BundleRequest, *BundleResponse) error
+}
+
+func RegisterCAHandler(s server.Server, hdlr CAHandler, opts ...server.HandlerOption) error {
+	type cA interface {
+		Sign(ctx context.Context, in *SignRequest, out *SignResponse) error
+		Bundle(ctx context.Context, in *BundleRequest, out *BundleResponse) error
+	}
+	type CA struct {
+		cA
+	}
+	h := &cAHandler{hdlr}
+	return s.Handle(s.NewHandler(&CA{h}, opts...))
+}
+
+type cAHandler struct {
+	CAHandler
+}
+
+func (h *cAHandler) Sign(ctx context.Context, in *SignRequest, out *SignResponse) error {
+	return h.CAHandler.Sign(ctx, in, out)
+}
+
+func (h *cAHandler) Bundle(ctx context.Context, in *BundleRequest, out *BundleResponse) error {
+	return h.CAHandler.Bundle(ctx, in, out)
+}
@@ -0,0 +1,191 @@
+// Package service issues the certificate of a service with the ca service and
+// renews it before it expires
+package service
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/stack-labs/stack/client/mucp"
+	"github.com/stack-labs/stack/util/log"
+	mls "github.com/stack-labs/stack/util/tls"
+	"github.com/stack-labs/stack/util/tls/ca"
+	pb "github.com/stack-labs/stack/util/tls/ca/service/proto"
+)
+
+// Renewer keeps the certificate of a service issued by the ca service, it is
+// renewed once half of its lifetime passed
+type Renewer struct {
+	opts   Options
+	client pb.CAService
+
+	sync.Mutex
+	creds  *mls.Credentials
+	expiry time.Time
+
+	exit chan bool
+	once sync.Once
+}
+
+// CAFile returns the file of the authority bundle
+func (r *Renewer) CAFile() string {
+	return filepath.Join(r.opts.Dir, "ca.crt")
+}
+
+// CertFile returns the file of the certificate
+func (r *Renewer) CertFile() string {
+	return filepath.Join(r.opts.Dir, r.opts.Service+".crt")
+}
+
+// KeyFile returns the file of the key
+func (r *Renewer) KeyFile() string {
+	return filepath.Join(r.opts.Dir, r.opts.Service+".key")
+}
+
+// Renew requests a certificate for a new key and writes it to the files
+func (r *Renewer) Renew() error {
+	csr, key, err := ca.NewRequest(r.opts.Service)
+	if err != nil {
+		return err
+	}
+
+	rsp, err := r.client.Sign(context.Background(), &pb.SignRequest{
+		Name:  r.opts.Service,
+		Csr:   csr,
+		Ttl:   int64(r.opts.TTL.Seconds()),
+		Token: r.opts.Token,
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := ca.WriteFile(r.CAFile(), rsp.Bundle); err != nil {
+		return err
+	}
+	if err := ca.WriteFile(r.KeyFile(), key); err != nil {
+		return err
+	}
+	if err := ca.WriteFile(r.CertFile(), rsp.Certificate); err != nil {
+		return err
+	}
+
+	r.Lock()
+	defer r.Unlock()
+
+	r.expiry = time.Unix(rsp.Expiry, 0)
+
+	if r.creds != nil {
+		return r.creds.Reload()
+	}
+
+	// the files are reloaded by the renewer rather than watched
+	creds, err := mls.NewCredentials(
+		mls.CA(r.CAFile()),
+		mls.KeyPair(r.CertFile(), r.KeyFile()),
+		mls.AllowedClientSANs(r.opts.AllowedClientSANs...),
+		mls.AllowedServerSANs(r.opts.AllowedServerSANs...),
+		mls.ReloadInterval(-1),
+	)
+	if err != nil {
+		return err
+	}
+	r.creds = creds
+
+	return nil
+}
+
+// Expiry returns when the current certificate expires
+func (r *Renewer) Expiry() time.Time {
+	r.Lock()
+	defer r.Unlock()
+	return r.expiry
+}
+
+// next returns how long to wait before renewing the certificate
+func (r *Renewer) next() time.Duration {
+	return time.Until(r.Expiry()) / 2
+}
+
+func (r *Renewer) run() {
+	t := time.NewTimer(r.next())
+	defer t.Stop()
+
+	for {
+		select {
+		case <-t.C:
+			if err := r.Renew(); err != nil {
+				log.Warnf("failed to renew the certificate of %s: %v", r.opts.Service, err)
+				wait := r.opts.RetryInterval
+				if until := time.Until(r.Expiry()); until > 0 && until < wait {
+					wait = until
+				}
+				t.Reset(wait)
+				continue
+			}
+			log.Infof("renewed the certificate of %s until %v", r.opts.Service, r.Expiry())
+			t.Reset(r.next())
+		case <-r.exit:
+			return
+		}
+	}
+}
+
+// Start requests the first certificate and renews it in the background
+func (r *Renewer) Start() error {
+	if err := r.Renew(); err != nil {
+		return err
+	}
+	go r.run()
+	return nil
+}
+
+// Config returns the mutual TLS config of the certificate, it is nil until
+// the renewer started
+func (r *Renewer) Config() *tls.Config {
+	r.Lock()
+	defer r.Unlock()
+
+	if r.creds == nil {
+		return nil
+	}
+	return r.creds.Config()
+}
+
+// Stop renewing the certificate
+func (r *Renewer) Stop() {
+	r.once.Do(func() {
+		close(r.exit)
+	})
+}
+
+// NewRenewer returns a renewer of the certificate of a service, the service
+// name is required
+func NewRenewer(opts ...Option) (*Renewer, error) {
+	options := Options{
+		Name:          DefaultName,
+		RetryInterval: DefaultRetryInterval,
+	}
+	for _, o := range opts {
+		o(&options)
+	}
+
+	if len(options.Service) == 0 {
+		return nil, errors.New("ca: service name is required")
+	}
+	if len(options.Dir) == 0 {
+		options.Dir = defaultDir(options.Service)
+	}
+	if options.Client == nil {
+		options.Client = mucp.NewClient()
+	}
+
+	return &Renewer{
+		opts:   options,
+		client: pb.NewCAService(options.Name, options.Client),
+		exit:   make(chan bool),
+	}, nil
+}
@@ -0,0 +1,207 @@
+package service
+
+import (
+	"context"
+	"crypto/tls"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stack-labs/stack/client"
+	mls "github.com/stack-labs/stack/util/tls"
+	"github.com/stack-labs/stack/util/tls/ca"
+	"github.com/stack-labs/stack/util/tls/ca/service/handler"
+	pb "github.com/stack-labs/stack/util/tls/ca/service/proto"
+)
+
+// testClient calls the handler directly
+type testClient struct {
+	h *handler.CA
+}
+
+func (c *testClient) Sign(ctx context.Context, in *pb.SignRequest, opts ...client.CallOption) (*pb.SignResponse, error) {
+	rsp := new(pb.SignResponse)
+	return rsp, c.h.Sign(ctx, in, rsp)
+}
+
+func (c *testClient) Bundle(ctx context.Context, in *pb.BundleRequest, opts ...client.CallOption) (*pb.BundleResponse, error) {
+	rsp := new(pb.BundleResponse)
+	return rsp, c.h.Bundle(ctx, in, rsp)
+}
+
+func newTestRenewer(t *testing.T, h *handler.CA, dir, service string, opts ...Option) *Renewer {
+	opts = append([]Option{Service(service), Dir(dir), Token("secret")}, opts...)
+	r, err := NewRenewer(opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.client = &testClient{h: h}
+	return r
+}
+
+func TestRenewer(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ca")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	a, err := ca.NewAuthority(ca.TTL(time.Second * 4))
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := &handler.CA{Authority: a, Tokens: map[string]string{
+		"stack.rpc.greeter": "secret",
+		"stack.rpc.client":  "secret",
+	}}
+
+	server := newTestRenewer(t, h, dir, "stack.rpc.greeter", AllowedClientSANs("stack.rpc.client"))
+	client := newTestRenewer(t, h, dir, "stack.rpc.client", AllowedServerSANs("stack.rpc.greeter"))
+
+	if server.Config() != nil {
+		t.Fatal("Expected no config before the renewer started")
+	}
+
+	for _, r := range []*Renewer{server, client} {
+		if err := r.Start(); err != nil {
+			t.Fatal(err)
+		}
+		defer r.Stop()
+	}
+
+	l, err := tls.Listen("tcp", "127.0.0.1:0", server.Config())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	peers := make(chan *mls.Peer, 1)
+	go func() {
+		conn, err := l.Accept()
+		if err != nil {
+			peers <- nil
+			return
+		}
+		defer conn.Close()
+
+		tc := conn.(*tls.Conn)
+		if err := tc.Handshake(); err != nil {
+			peers <- nil
+			return
+		}
+		cs := tc.ConnectionState()
+		peers <- mls.NewPeer(&cs)
+	}()
+
+	conn, err := tls.Dial("tcp", l.Addr().String(), client.Config())
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn.Close()
+
+	p := <-peers
+	if p == nil || p.Name != "stack.rpc.client" {
+		t.Fatalf("Expected the client identity got %v", p)
+	}
+
+	// renewed at half of the lifetime
+	expiry := client.Expiry()
+	deadline := time.Now().Add(time.Second * 5)
+	for time.Now().Before(deadline) {
+		if client.Expiry().After(expiry) {
+			return
+		}
+		time.Sleep(time.Millisecond * 100)
+	}
+
+	t.Fatal("Expected the certificate to be renewed")
+}
+
+func TestRenewerServerName(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ca")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	a, err := ca.NewAuthority()
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := &handler.CA{Authority: a, Tokens: map[string]string{
+		"stack.rpc.greeter": "secret",
+		"stack.rpc.client":  "secret",
+	}}
+
+	// no allowed SANs, the server is verified against the service name
+	server := newTestRenewer(t, h, dir, "stack.rpc.greeter")
+	client := newTestRenewer(t, h, dir, "stack.rpc.client")
+
+	for _, r := range []*Renewer{server, client} {
+		if err := r.Start(); err != nil {
+			t.Fatal(err)
+		}
+		defer r.Stop()
+	}
+
+	testData := []struct {
+		serverName string
+		ok         bool
+	}{
+		// the address dialled is not in the certificate
+		{"", false},
+		{"stack.rpc.other", false},
+		{"stack.rpc.greeter", true},
+	}
+
+	for _, d := range testData {
+		l, err := tls.Listen("tcp", "127.0.0.1:0", server.Config())
+		if err != nil {
+			t.Fatal(err)
+		}
+		go func() {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			conn.(*tls.Conn).Handshake()
+			conn.Close()
+		}()
+
+		config := client.Config()
+		config.ServerName = d.serverName
+		conn, err := tls.Dial("tcp", l.Addr().String(), config)
+		if err == nil {
+			conn.Close()
+		}
+		l.Close()
+
+		if (err == nil) != d.ok {
+			t.Fatalf("Expected the server name %q to be allowed %v got %v", d.serverName, d.ok, err)
+		}
+	}
+}
+
+func TestRenewerForbidden(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ca")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	a, err := ca.NewAuthority()
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := &handler.CA{Authority: a, Tokens: map[string]string{"stack.rpc.greeter": "secret"}}
+
+	r := newTestRenewer(t, h, dir, "stack.rpc.greeter", Token("invalid"))
+	if err := r.Start(); err == nil {
+		t.Fatal("Expected the renewer to fail without a valid token")
+	}
+
+	if _, err := NewRenewer(); err == nil {
+		t.Fatal("Expected the service name to be required")
+	}
+}
@@ -0,0 +1,338 @@
+package tls
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/stack-labs/stack/util/log"
+)
+
+var (
+	// DefaultReloadInterval is how often the files of the credentials are checked for changes
+	DefaultReloadInterval = time.Minute
+)
+
+type Options struct {
+	// CAFile is the PEM bundle of the authorities signing the peer certificates
+	CAFile string
+	// CertFile and KeyFile are the PEM key pair presented to the peers
+	CertFile string
+	KeyFile  string
+	// AllowedClientSANs are the identities a client certificate must carry
+	// one of, a DNS name, IP address, URI or email. When empty any client
+	// signed by the CA is allowed
+	AllowedClientSANs []string
+	// AllowedServerSANs are the identities a server certificate must carry
+	// one of. When empty a server must carry the name dialled
+	AllowedServerSANs []string
+	// ReloadInterval is how often the files are checked for changes, a
+	// negative interval disables the reload
+	ReloadInterval time.Duration
+}
+
+type Option func(o *Options)
+
+// CA sets the PEM bundle of the authorities trusted to sign the peer certificates
+func CA(file string) Option {
+	return func(o *Options) {
+		o.CAFile = file
+	}
+}
+
+// KeyPair sets the PEM certificate and key presented to the peers
+func KeyPair(certFile, keyFile string) Option {
+	return func(o *Options) {
+		o.CertFile = certFile
+		o.KeyFile = keyFile
+	}
+}
+
+// AllowedClientSANs restricts the clients to the certificates carrying one of
+// the identities, a DNS name may be a wildcard such as *.svc.local
+func AllowedClientSANs(sans ...string) Option {
+	return func(o *Options) {
+		o.AllowedClientSANs = append(o.AllowedClientSANs, sans...)
+	}
+}
+
+// AllowedServerSANs restricts the servers dialled to the certificates carrying
+// one of the identities rather than the name dialled
+func AllowedServerSANs(sans ...string) Option {
+	return func(o *Options) {
+		o.AllowedServerSANs = append(o.AllowedServerSANs, sans...)
+	}
+}
+
+// ReloadInterval sets how often the files are checked for changes
+func ReloadInterval(d time.Duration) Option {
+	return func(o *Options) {
+		o.ReloadInterval = d
+	}
+}
+
+// Credentials are the CA bundle and key pair of mutual TLS, the files are
+// reloaded when they change so certificates are rotated without a restart
+type Credentials struct {
+	opts Options
+
+	sync.RWMutex
+	cert *tls.Certificate
+	pool *x509.CertPool
+	// modification times of the loaded files
+	mod map[string]time.Time
+
+	exit chan bool
+	once sync.Once
+}
+
+// NewCredentials loads the credentials and watches the files for changes
+func NewCredentials(opts ...Option) (*Credentials, error) {
+	options := Options{
+		ReloadInterval: DefaultReloadInterval,
+	}
+	for _, o := range opts {
+		o(&options)
+	}
+
+	if len(options.CAFile) == 0 || len(options.CertFile) == 0 || len(options.KeyFile) == 0 {
+		return nil, errors.New("tls: a CA and a key pair are required")
+	}
+
+	c := &Credentials{
+		opts: options,
+		exit: make(chan bool),
+	}
+
+	if err := c.Reload(); err != nil {
+		return nil, err
+	}
+
+	if options.ReloadInterval > 0 {
+		go c.watch()
+	}
+
+	return c, nil
+}
+
+// modTimes returns the modification times of the files
+func (c *Credentials) modTimes() (map[string]time.Time, error) {
+	mod := make(map[string]time.Time)
+	for _, file := range []string{c.opts.CAFile, c.opts.CertFile, c.opts.KeyFile} {
+		fi, err := os.Stat(file)
+		if err != nil {
+			return nil, err
+		}
+		mod[file] = fi.ModTime()
+	}
+	return mod, nil
+}
+
+// Reload reads the files, the current credentials are kept on error
+func (c *Credentials) Reload() error {
+	mod, err := c.modTimes()
+	if err != nil {
+		return err
+	}
+
+	ca, err := ioutil.ReadFile(c.opts.CAFile)
+	if err != nil {
+		return err
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(ca) {
+		return fmt.Errorf("tls: no certificates in %s", c.opts.CAFile)
+	}
+
+	cert, err := tls.LoadX509KeyPair(c.opts.CertFile, c.opts.KeyFile)
+	if err != nil {
+		return err
+	}
+
+	c.Lock()
+	c.cert = &cert
+	c.pool = pool
+	c.mod = mod
+	c.Unlock()
+
+	return nil
+}
+
+// changed reports whether any file was modified since it was loaded
+func (c *Credentials) changed() bool {
+	mod, err := c.modTimes()
+	if err != nil {
+		return false
+	}
+
+	c.RLock()
+	defer c.RUnlock()
+
+	for file, t := range mod {
+		if !t.Equal(c.mod[file]) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *Credentials) watch() {
+	t := time.NewTicker(c.opts.ReloadInterval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-t.C:
+			if !c.changed() {
+				continue
+			}
+			if err := c.Reload(); err != nil {
+				log.Warnf("failed to reload the tls credentials: %v", err)
+				continue
+			}
+			log.Infof("reloaded the tls credentials of %s", c.opts.CertFile)
+		case <-c.exit:
+			return
+		}
+	}
+}
+
+func (c *Credentials) certificate() *tls.Certificate {
+	c.RLock()
+	defer c.RUnlock()
+	return c.cert
+}
+
+// Verify checks a client certificate chain is signed by the CA and carries an
+// allowed identity, any client signed by the CA is allowed without
+// AllowedClientSANs
+func (c *Credentials) Verify(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+	certs, err := parseCertificates(rawCerts)
+	if err != nil {
+		return err
+	}
+	return c.verify(certs, x509.ExtKeyUsageClientAuth, "", c.opts.AllowedClientSANs)
+}
+
+// verifyServer checks the certificate of the server dialled, it carries an
+// allowed identity or the server name dialled without AllowedServerSANs
+func (c *Credentials) verifyServer(cs tls.ConnectionState) error {
+	if len(c.opts.AllowedServerSANs) == 0 && len(cs.ServerName) == 0 {
+		return errors.New("tls: a server name or allowed server SANs are required to verify the server")
+	}
+	return c.verify(cs.PeerCertificates, x509.ExtKeyUsageServerAuth, cs.ServerName, c.opts.AllowedServerSANs)
+}
+
+func parseCertificates(rawCerts [][]byte) ([]*x509.Certificate, error) {
+	certs := make([]*x509.Certificate, 0, len(rawCerts))
+	for _, raw := range rawCerts {
+		cert, err := x509.ParseCertificate(raw)
+		if err != nil {
+			return nil, err
+		}
+		certs = append(certs, cert)
+	}
+	return certs, nil
+}
+
+// verify checks the chain is signed by the CA for the usage, and that the
+// leaf carries an allowed identity or else the server name when set
+func (c *Credentials) verify(certs []*x509.Certificate, usage x509.ExtKeyUsage, serverName string, allowed []string) error {
+	if len(certs) == 0 {
+		return errors.New("tls: no peer certificate")
+	}
+
+	intermediates := x509.NewCertPool()
+	for _, cert := range certs[1:] {
+		intermediates.AddCert(cert)
+	}
+
+	c.RLock()
+	pool := c.pool
+	c.RUnlock()
+
+	_, err := certs[0].Verify(x509.VerifyOptions{
+		Roots:         pool,
+		Intermediates: intermediates,
+		KeyUsages:     []x509.ExtKeyUsage{usage},
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(allowed) == 0 {
+		if len(serverName) == 0 {
+			return nil
+		}
+		return certs[0].VerifyHostname(serverName)
+	}
+
+	identities := Identities(certs[0])
+	for _, id := range identities {
+		for _, san := range allowed {
+			if matchSAN(san, id) {
+				return nil
+			}
+		}
+	}
+
+	return fmt.Errorf("tls: peer identities %v are not allowed", identities)
+}
+
+// matchSAN matches an identity, a leading *. matches a single DNS label
+func matchSAN(san, id string) bool {
+	if san == id {
+		return true
+	}
+	if !strings.HasPrefix(san, "*.") {
+		return false
+	}
+	i := strings.Index(id, ".")
+	return i > 0 && id[i:] == san[1:]
+}
+
+// Config returns a config for both the client and server side of mutual TLS
+// connections, the peers are verified with the CA rather than the system
+// roots. A client verifies the server has an allowed identity, or the name it
+// dialled without AllowedServerSANs, the clients dial a service by its name.
+// A server allows any client signed by the CA without AllowedClientSANs
+func (c *Credentials) Config() *tls.Config {
+	server := &tls.Config{
+		GetCertificate: func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+			return c.certificate(), nil
+		},
+		// the clients are verified against the current CA by Verify
+		ClientAuth:            tls.RequireAnyClientCert,
+		VerifyPeerCertificate: c.Verify,
+		MinVersion:            tls.VersionTLS12,
+	}
+
+	return &tls.Config{
+		GetCertificate: server.GetCertificate,
+		GetClientCertificate: func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			return c.certificate(), nil
+		},
+		// only called on the server side of a connection
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			return server, nil
+		},
+		// the server is verified against the current CA by verifyServer
+		InsecureSkipVerify: true,
+		VerifyConnection:   c.verifyServer,
+		MinVersion:         tls.VersionTLS12,
+	}
+}
+
+// Stop watching the files
+func (c *Credentials) Stop() {
+	c.once.Do(func() {
+		close(c.exit)
+	})
+}
@@ -0,0 +1,345 @@
+package tls
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pem  []byte
+}
+
+func newTestCA(t *testing.T) *testCA {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &testCA{
+		cert: cert,
+		key:  key,
+		pem:  pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+	}
+}
+
+// issue writes a key pair signed by the CA to the dir
+func (ca *testCA) issue(t *testing.T, dir, name string, serial int64) (string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: name},
+		DNSNames:     []string{name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, &key.PublicKey, ca.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	kb, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	certFile := filepath.Join(dir, name+".crt")
+	keyFile := filepath.Join(dir, name+".key")
+	if err := ioutil.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: kb}), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	return certFile, keyFile
+}
+
+func (ca *testCA) write(t *testing.T, dir string) string {
+	file := filepath.Join(dir, "ca.crt")
+	if err := ioutil.WriteFile(file, ca.pem, 0600); err != nil {
+		t.Fatal(err)
+	}
+	return file
+}
+
+func newTestCredentials(t *testing.T, caFile, certFile, keyFile string, opts ...Option) *Credentials {
+	opts = append([]Option{CA(caFile), KeyPair(certFile, keyFile), ReloadInterval(-1)}, opts...)
+	c, err := NewCredentials(opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+// handshake connects the client to the server and returns the connection
+// state seen by the server
+func handshake(t *testing.T, server, client *Credentials) (*tls.ConnectionState, error) {
+	l, err := tls.Listen("tcp", "127.0.0.1:0", server.Config())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	type result struct {
+		cs  *tls.ConnectionState
+		err error
+	}
+	ch := make(chan result, 1)
+
+	go func() {
+		conn, err := l.Accept()
+		if err != nil {
+			ch <- result{err: err}
+			return
+		}
+		defer conn.Close()
+
+		tc := conn.(*tls.Conn)
+		if err := tc.Handshake(); err != nil {
+			ch <- result{err: err}
+			return
+		}
+		cs := tc.ConnectionState()
+		ch <- result{cs: &cs}
+	}()
+
+	conn, err := tls.Dial("tcp", l.Addr().String(), client.Config())
+	if err == nil {
+		conn.Close()
+	}
+
+	r := <-ch
+	if err != nil {
+		return nil, err
+	}
+	return r.cs, r.err
+}
+
+func TestCredentialsHandshake(t *testing.T) {
+	dir, err := ioutil.TempDir("", "tls")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	ca := newTestCA(t)
+	caFile := ca.write(t, dir)
+
+	sc, sk := ca.issue(t, dir, "server.svc.local", 2)
+	cc, ck := ca.issue(t, dir, "client.svc.local", 3)
+
+	server := newTestCredentials(t, caFile, sc, sk, AllowedClientSANs("*.svc.local"))
+	client := newTestCredentials(t, caFile, cc, ck, AllowedServerSANs("server.svc.local"))
+
+	cs, err := handshake(t, server, client)
+	if err != nil {
+		t.Fatalf("Expected the handshake to succeed: %v", err)
+	}
+
+	p := NewPeer(cs)
+	if p == nil {
+		t.Fatal("Expected a peer")
+	}
+	if p.Name != "client.svc.local" {
+		t.Fatalf("Expected peer client.svc.local got %s", p.Name)
+	}
+
+	p, ok := FromContext(NewContext(context.Background(), p))
+	if !ok || len(p.Identities) != 1 || p.Identities[0] != "client.svc.local" {
+		t.Fatalf("Expected the peer identities in the context got %v", p)
+	}
+
+	// a client signed by another CA
+	other := newTestCA(t)
+	cert, key := other.issue(t, dir, "other.svc.local", 4)
+	untrusted := newTestCredentials(t, caFile, cert, key)
+
+	if _, err := handshake(t, server, untrusted); err == nil {
+		t.Fatal("Expected a client signed by another CA to be rejected")
+	}
+}
+
+func TestCredentialsAllowedSANs(t *testing.T) {
+	dir, err := ioutil.TempDir("", "tls")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	ca := newTestCA(t)
+	caFile := ca.write(t, dir)
+
+	sc, sk := ca.issue(t, dir, "server.svc.local", 2)
+	cc, ck := ca.issue(t, dir, "client.other.local", 3)
+
+	server := newTestCredentials(t, caFile, sc, sk, AllowedClientSANs("*.svc.local"))
+	client := newTestCredentials(t, caFile, cc, ck, AllowedServerSANs("server.svc.local"))
+
+	if _, err := handshake(t, server, client); err == nil {
+		t.Fatal("Expected a client without an allowed identity to be rejected")
+	}
+
+	// a server without an allowed identity
+	server = newTestCredentials(t, caFile, sc, sk)
+	client = newTestCredentials(t, caFile, cc, ck, AllowedServerSANs("other.svc.local"))
+
+	if _, err := handshake(t, server, client); err == nil {
+		t.Fatal("Expected a server without an allowed identity to be rejected")
+	}
+
+	testData := []struct {
+		san   string
+		id    string
+		match bool
+	}{
+		{"a.svc.local", "a.svc.local", true},
+		{"*.svc.local", "a.svc.local", true},
+		{"*.svc.local", "a.b.svc.local", false},
+		{"*.svc.local", "svc.local", false},
+		{"a.svc.local", "b.svc.local", false},
+	}
+
+	for _, d := range testData {
+		if m := matchSAN(d.san, d.id); m != d.match {
+			t.Fatalf("Expected %s matching %s to be %v", d.san, d.id, d.match)
+		}
+	}
+}
+
+func TestCredentialsServerName(t *testing.T) {
+	dir, err := ioutil.TempDir("", "tls")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	ca := newTestCA(t)
+	caFile := ca.write(t, dir)
+
+	sc, sk := ca.issue(t, dir, "server.svc.local", 2)
+	cc, ck := ca.issue(t, dir, "client.svc.local", 3)
+
+	server := newTestCredentials(t, caFile, sc, sk)
+	client := newTestCredentials(t, caFile, cc, ck)
+
+	// the address dialled is not an identity of the server
+	if _, err := handshake(t, server, client); err == nil {
+		t.Fatal("Expected the server to be rejected without its name")
+	}
+
+	testData := []struct {
+		serverName string
+		ok         bool
+	}{
+		{"server.svc.local", true},
+		{"other.svc.local", false},
+	}
+
+	for _, d := range testData {
+		l, err := tls.Listen("tcp", "127.0.0.1:0", server.Config())
+		if err != nil {
+			t.Fatal(err)
+		}
+		go func() {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			conn.(*tls.Conn).Handshake()
+			conn.Close()
+		}()
+
+		config := client.Config()
+		config.ServerName = d.serverName
+		conn, err := tls.Dial("tcp", l.Addr().String(), config)
+		if err == nil {
+			conn.Close()
+		}
+		l.Close()
+
+		if (err == nil) != d.ok {
+			t.Fatalf("Expected the server name %s to be allowed %v got %v", d.serverName, d.ok, err)
+		}
+	}
+}
+
+func TestCredentialsReload(t *testing.T) {
+	dir, err := ioutil.TempDir("", "tls")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	ca := newTestCA(t)
+	caFile := ca.write(t, dir)
+
+	certFile, keyFile := ca.issue(t, dir, "server.svc.local", 2)
+
+	c, err := NewCredentials(CA(caFile), KeyPair(certFile, keyFile), ReloadInterval(time.Millisecond*10))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Stop()
+
+	// rotate the key pair in place
+	ca.issue(t, dir, "server.svc.local", 5)
+	future := time.Now().Add(time.Minute)
+	for _, f := range []string{certFile, keyFile} {
+		if err := os.Chtimes(f, future, future); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	deadline := time.Now().Add(time.Second * 5)
+	for time.Now().Before(deadline) {
+		cert, err := x509.ParseCertificate(c.certificate().Certificate[0])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if cert.SerialNumber.Int64() == 5 {
+			return
+		}
+		time.Sleep(time.Millisecond * 10)
+	}
+
+	t.Fatal("Expected the rotated key pair to be reloaded")
+}
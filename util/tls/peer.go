@@ -0,0 +1,62 @@
+package tls
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+)
+
+type peerKey struct{}
+
+// Peer is the identity of the remote side of a mutual TLS connection
+type Peer struct {
+	// Name is the common name of the certificate subject
+	Name string
+	// Identities are the subject alternative names of the certificate
+	Identities []string
+	// Certificate presented by the peer
+	Certificate *x509.Certificate
+}
+
+// Identities returns the DNS names, IP addresses, URIs and emails of a certificate
+func Identities(cert *x509.Certificate) []string {
+	var ids []string
+	ids = append(ids, cert.DNSNames...)
+	for _, ip := range cert.IPAddresses {
+		ids = append(ids, ip.String())
+	}
+	for _, uri := range cert.URIs {
+		ids = append(ids, uri.String())
+	}
+	ids = append(ids, cert.EmailAddresses...)
+	return ids
+}
+
+// NewPeer returns the peer of a connection, it is nil when the peer presented
+// no certificate. The certificate is verified when the connection is secured
+// with Credentials or a config requiring and verifying peer certificates
+func NewPeer(cs *tls.ConnectionState) *Peer {
+	if cs == nil || !cs.HandshakeComplete || len(cs.PeerCertificates) == 0 {
+		return nil
+	}
+
+	cert := cs.PeerCertificates[0]
+
+	return &Peer{
+		Name:        cert.Subject.CommonName,
+		Identities:  Identities(cert),
+		Certificate: cert,
+	}
+}
+
+// NewContext returns a context carrying the peer
+func NewContext(ctx context.Context, p *Peer) context.Context {
+	return context.WithValue(ctx, peerKey{}, p)
+}
+
+// FromContext returns the peer of the request, it is set by the servers for
+// the requests received over mutual TLS connections
+func FromContext(ctx context.Context) (*Peer, bool) {
+	p, ok := ctx.Value(peerKey{}).(*Peer)
+	return p, ok
+}
@@ -10,6 +10,8 @@ import (
 
 	"github.com/stack-labs/stack/client"
 	"github.com/stack-labs/stack/pkg/metadata"
+	mls "github.com/stack-labs/stack/util/tls"
+	"github.com/stack-labs/stack/util/tls/ca"
 )
 
 type clientWrapper struct {
@@ -68,6 +70,59 @@ type traceWrapper struct {
 	trace trace.Tracer
 }
 
+func (c *traceWrapper) Call(ctx context.Context, req client.Request, rsp interface{}, opts ...client.CallOption) error {
+	newCtx, s := c.trace.Start(ctx, req.Service()+"."+req.Endpoint())
+	if s == nil {
+		return c.Client.Call(ctx, req, rsp, opts...)
+	}
+	s.Type = trace.SpanTypeRequestOutbound
+	s.SetAttribute("rpc.service", req.Service())
+	s.SetAttribute("rpc.method", req.Endpoint())
+
+	err := c.Client.Call(newCtx, req, rsp, opts...)
+	s.SetError(err)
+
+	c.trace.Finish(s)
+
+	return err
+}
+
+func (c *traceWrapper) Stream(ctx context.Context, req client.Request, opts ...client.CallOption) (client.Stream, error) {
+	newCtx, s := c.trace.Start(ctx, req.Service()+"."+req.Endpoint())
+	if s == nil {
+		return c.Client.Stream(ctx, req, opts...)
+	}
+	s.Type = trace.SpanTypeRequestOutbound
+	s.SetAttribute("rpc.service", req.Service())
+	s.SetAttribute("rpc.method", req.Endpoint())
+
+	// the span only covers opening the stream
+	stream, err := c.Client.Stream(newCtx, req, opts...)
+	s.SetError(err)
+
+	c.trace.Finish(s)
+
+	return stream, err
+}
+
+func (c *traceWrapper) Publish(ctx context.Context, p client.Message, opts ...client.PublishOption) error {
+	newCtx, s := c.trace.Start(ctx, "Publish."+p.Topic())
+	if s == nil {
+		return c.Client.Publish(ctx, p, opts...)
+	}
+	// the consumer spans are children of the producer span through
+	// the trace headers of the message
+	s.Type = trace.SpanTypePublish
+	s.SetAttribute("messaging.destination", p.Topic())
+
+	err := c.Client.Publish(newCtx, p, opts...)
+	s.SetError(err)
+
+	c.trace.Finish(s)
+
+	return err
+}
+
 // TraceCall is a call tracing wrapper
 func TraceCall(name string, t trace.Tracer, c client.Client) client.Client {
 	return &traceWrapper{
@@ -90,12 +145,15 @@ func TraceHandler(t trace.Tracer) server.HandlerWrapper {
 
 			// get the span
 			newCtx, s := t.Start(ctx, req.Service()+"."+req.Endpoint())
+			if s == nil {
+				return h(ctx, req, rsp)
+			}
 			s.Type = trace.SpanTypeRequestInbound
+			s.SetAttribute("rpc.service", req.Service())
+			s.SetAttribute("rpc.method", req.Endpoint())
 
 			err := h(newCtx, req, rsp)
-			if err != nil {
-				s.Metadata["error"] = err.Error()
-			}
+			s.SetError(err)
 
 			// finish
 			t.Finish(s)
@@ -105,6 +163,29 @@ func TraceHandler(t trace.Tracer) server.HandlerWrapper {
 	}
 }
 
+// TraceSubscriber wraps a subscriber to trace the messages consumed. The
+// consumer span continues the trace of the producer from the message headers.
+func TraceSubscriber(t trace.Tracer) server.SubscriberWrapper {
+	return func(fn server.SubscriberFunc) server.SubscriberFunc {
+		return func(ctx context.Context, msg server.Message) error {
+			newCtx, s := t.Start(ctx, "Subscribe."+msg.Topic())
+			if s == nil {
+				return fn(ctx, msg)
+			}
+			s.Type = trace.SpanTypeConsume
+			s.SetAttribute("messaging.destination", msg.Topic())
+			s.SetAttribute("messaging.message_payload_size_bytes", len(msg.Body()))
+
+			err := fn(newCtx, msg)
+			s.SetError(err)
+
+			t.Finish(s)
+
+			return err
+		}
+	}
+}
+
 type authWrapper struct {
 	client.Client
 	auth func() auth.Auth
@@ -146,3 +227,37 @@ func (a *authWrapper) Call(ctx context.Context, req client.Request, rsp interfac
 func AuthClient(auth func() auth.Auth, c client.Client) client.Client {
 	return &authWrapper{c, auth}
 }
+
+// account returns the account of the bearer token, or else of the
+// certificate of a mutual TLS peer
+func account(ctx context.Context, a auth.Auth) *auth.Account {
+	if header, ok := metadata.Get(ctx, "Authorization"); ok && a != nil && strings.HasPrefix(header, auth.BearerScheme) {
+		if acc, err := a.Inspect(strings.TrimPrefix(header, auth.BearerScheme)); err == nil {
+			return acc
+		}
+	}
+
+	if p, ok := mls.FromContext(ctx); ok {
+		return ca.Account(p)
+	}
+
+	return nil
+}
+
+// AuthHandler puts the account of the caller in the context, a peer of mutual
+// TLS is identified by its certificate when no token is sent
+func AuthHandler(fn func() auth.Auth) server.HandlerWrapper {
+	return func(h server.HandlerFunc) server.HandlerFunc {
+		return func(ctx context.Context, req server.Request, rsp interface{}) error {
+			if _, ok := auth.AccountFromContext(ctx); ok {
+				return h(ctx, req, rsp)
+			}
+
+			if acc := account(ctx, fn()); acc != nil {
+				ctx = auth.ContextWithAccount(ctx, acc)
+			}
+
+			return h(ctx, req, rsp)
+		}
+	}
+}
@@ -4,7 +4,13 @@ import (
 	"context"
 	"testing"
 
+	"github.com/stack-labs/stack/auth"
+	"github.com/stack-labs/stack/client"
+	"github.com/stack-labs/stack/debug/trace"
+	"github.com/stack-labs/stack/debug/trace/memory"
 	"github.com/stack-labs/stack/pkg/metadata"
+	"github.com/stack-labs/stack/server"
+	mls "github.com/stack-labs/stack/util/tls"
 )
 
 func TestWrapper(t *testing.T) {
@@ -51,3 +57,111 @@ func TestWrapper(t *testing.T) {
 	}
 
 }
+
+type publishClient struct {
+	client.Client
+	header map[string]string
+}
+
+func (p *publishClient) Publish(ctx context.Context, msg client.Message, opts ...client.PublishOption) error {
+	// the brokers send the metadata as the message headers
+	md, _ := metadata.FromContext(ctx)
+	p.header = metadata.Copy(md)
+	return nil
+}
+
+type publishMessage struct {
+	client.Message
+}
+
+func (publishMessage) Topic() string { return "events" }
+
+type testMessage struct {
+	server.Message
+	topic string
+}
+
+func (m testMessage) Topic() string { return m.topic }
+func (m testMessage) Body() []byte  { return []byte("hello") }
+
+func TestTracePublishConsume(t *testing.T) {
+	tr := memory.NewTracer()
+
+	pc := &publishClient{}
+	c := TraceCall("foo", tr, pc)
+
+	if err := c.Publish(context.TODO(), publishMessage{}); err != nil {
+		t.Fatal(err)
+	}
+
+	sub := TraceSubscriber(tr)(func(ctx context.Context, msg server.Message) error {
+		return nil
+	})
+
+	ctx := metadata.NewContext(context.Background(), pc.header)
+	if err := sub(ctx, testMessage{topic: "events"}); err != nil {
+		t.Fatal(err)
+	}
+
+	spans, err := tr.Read()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(spans) != 2 {
+		t.Fatalf("Expected 2 spans, got %d", len(spans))
+	}
+
+	producer, consumer := spans[0], spans[1]
+	if producer.Type != trace.SpanTypePublish || consumer.Type != trace.SpanTypeConsume {
+		t.Fatalf("Expected a producer and consumer span, got %s and %s", producer.Type, consumer.Type)
+	}
+	if consumer.Trace != producer.Trace || consumer.Parent != producer.Id {
+		t.Fatalf("Expected the consumer span to be a child of the producer span")
+	}
+	if consumer.Attributes["messaging.destination"] != "events" {
+		t.Fatalf("Unexpected attributes %v", consumer.Attributes)
+	}
+}
+
+type tokenAuth struct {
+	auth.Auth
+}
+
+func (tokenAuth) Inspect(token string) (*auth.Account, error) {
+	if token != "secret" {
+		return nil, auth.ErrInvalidToken
+	}
+	return &auth.Account{ID: "user"}, nil
+}
+
+func TestAuthHandler(t *testing.T) {
+	peer := mls.NewContext(context.Background(), &mls.Peer{Name: "stack.rpc.greeter"})
+
+	testData := []struct {
+		ctx context.Context
+		id  string
+	}{
+		{context.Background(), ""},
+		{metadata.Set(context.Background(), "Authorization", auth.BearerScheme+"secret"), "user"},
+		{metadata.Set(context.Background(), "Authorization", auth.BearerScheme+"invalid"), ""},
+		{peer, "stack.rpc.greeter"},
+		{metadata.Set(peer, "Authorization", auth.BearerScheme+"secret"), "user"},
+	}
+
+	for _, d := range testData {
+		var id string
+		h := AuthHandler(func() auth.Auth { return tokenAuth{} })(func(ctx context.Context, req server.Request, rsp interface{}) error {
+			if acc, ok := auth.AccountFromContext(ctx); ok {
+				id = acc.ID
+			}
+			return nil
+		})
+
+		if err := h(d.ctx, nil, nil); err != nil {
+			t.Fatal(err)
+		}
+		if id != d.id {
+			t.Fatalf("Expected the account %q got %q", d.id, id)
+		}
+	}
+}